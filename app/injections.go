@@ -1,12 +1,25 @@
 package app
 
 import (
+	"fmt"
+	"loan-service/config"
+	"loan-service/database"
 	"loan-service/models"
+	apiKeysModule "loan-service/modules/apikeys"
+	kycModule "loan-service/modules/kyc"
 	loansModule "loan-service/modules/loans"
+	notificationsModule "loan-service/modules/notifications"
 	productsModule "loan-service/modules/products"
+	rolesModule "loan-service/modules/roles"
 	usersModule "loan-service/modules/users"
+	webhooksModule "loan-service/modules/webhooks"
+	"loan-service/services/attachment"
+	"loan-service/services/ekyc"
 	"loan-service/services/email"
+	"loan-service/services/pubsub"
+	"loan-service/services/sms"
 	"loan-service/services/upload"
+	"loan-service/services/whatsapp"
 
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
@@ -19,6 +32,8 @@ func SetupInjections(
 	db *gorm.DB,
 	rest *echo.Echo,
 	emailSvc email.EmailService,
+	smsSvc sms.SMSService,
+	whatsAppSvc whatsapp.WhatsAppService,
 	uploadSvc upload.UploadService,
 ) *do.Injector {
 	injector = do.New()
@@ -30,6 +45,10 @@ func SetupInjections(
 		})
 	}
 
+	do.Provide[models.Transactor](injector, func(i *do.Injector) (models.Transactor, error) {
+		return database.NewTransactor(db), nil
+	})
+
 	// Services
 	if emailSvc != nil {
 		do.Provide[email.EmailService](injector, func(i *do.Injector) (email.EmailService, error) {
@@ -43,6 +62,33 @@ func SetupInjections(
 		})
 	}
 
+	do.Provide[attachment.Pipeline](injector, func(i *do.Injector) (attachment.Pipeline, error) {
+		return attachment.NewPipeline(
+			config.Data.AttachmentMaxSize,
+			attachment.NewImageProcessor(attachment.ImageConfig{
+				MaxWidth:      config.Data.AttachmentMaxDimension,
+				MaxHeight:     config.Data.AttachmentMaxDimension,
+				ThumbnailSize: config.Data.AttachmentThumbnailSize,
+			}),
+			attachment.NewPassthroughProcessor(map[string]string{"application/pdf": ".pdf"}),
+		), nil
+	})
+
+	do.Provide[ekyc.Provider](injector, func(i *do.Injector) (ekyc.Provider, error) {
+		switch config.Data.EKYCProvider {
+		case "manual":
+			return ekyc.NewManualProvider(), nil
+		case "fake":
+			return ekyc.NewFakeProvider(), nil
+		default:
+			return nil, fmt.Errorf("unknown e-KYC provider %q", config.Data.EKYCProvider)
+		}
+	})
+
+	do.Provide[models.LoanEventBroker](injector, func(i *do.Injector) (models.LoanEventBroker, error) {
+		return pubsub.NewBroker[uint, models.LoanEvent](pubsub.DefaultBufferSize), nil
+	})
+
 	// Modules
 	// Products module
 	do.Provide[models.ProductRepository](injector, func(i *do.Injector) (models.ProductRepository, error) {
@@ -64,8 +110,28 @@ func SetupInjections(
 		return loansModule.NewLoanUsecase(
 			do.MustInvoke[models.LoanRepository](i),
 			do.MustInvoke[models.UserUsecase](i),
-			do.MustInvoke[email.EmailService](injector),
+			do.MustInvoke[models.KYCUsecase](i),
+			do.MustInvoke[models.Transactor](i),
+			do.MustInvoke[models.NotificationUsecase](i),
+			do.MustInvoke[models.WebhookUsecase](i),
+			do.MustInvoke[models.LoanEventBroker](i),
 			do.MustInvoke[upload.UploadService](injector),
+			do.MustInvoke[attachment.Pipeline](i),
+		), nil
+	})
+
+	// KYC module
+	do.Provide[models.KYCRepository](injector, func(i *do.Injector) (models.KYCRepository, error) {
+		return kycModule.NewKYCRepository(db), nil
+	})
+
+	do.Provide[models.KYCUsecase](injector, func(i *do.Injector) (models.KYCUsecase, error) {
+		return kycModule.NewKYCUsecase(
+			do.MustInvoke[models.KYCRepository](i),
+			do.MustInvoke[models.Transactor](i),
+			do.MustInvoke[ekyc.Provider](i),
+			do.MustInvoke[upload.UploadService](i),
+			do.MustInvoke[attachment.Pipeline](i),
 		), nil
 	})
 
@@ -77,6 +143,70 @@ func SetupInjections(
 	do.Provide[models.UserUsecase](injector, func(i *do.Injector) (models.UserUsecase, error) {
 		return usersModule.NewUserUsecase(
 			do.MustInvoke[models.UserRepository](i),
+			do.MustInvoke[models.Transactor](i),
+			do.MustInvoke[models.OutboxMessageUsecase](i),
+		), nil
+	})
+
+	// Roles module
+	do.Provide[models.RoleRepository](injector, func(i *do.Injector) (models.RoleRepository, error) {
+		return rolesModule.NewRoleRepository(db), nil
+	})
+
+	do.Provide[models.RoleUsecase](injector, func(i *do.Injector) (models.RoleUsecase, error) {
+		return rolesModule.NewRoleUsecase(
+			do.MustInvoke[models.RoleRepository](i),
+			do.MustInvoke[models.UserRepository](i),
+		), nil
+	})
+
+	// API keys module
+	do.Provide[models.APIKeyRepository](injector, func(i *do.Injector) (models.APIKeyRepository, error) {
+		return apiKeysModule.NewAPIKeyRepository(db), nil
+	})
+
+	do.Provide[models.APIKeyUsecase](injector, func(i *do.Injector) (models.APIKeyUsecase, error) {
+		return apiKeysModule.NewAPIKeyUsecase(
+			do.MustInvoke[models.APIKeyRepository](i),
+			do.MustInvoke[models.UserRepository](i),
+			do.MustInvoke[models.RoleRepository](i),
+		), nil
+	})
+
+	// Notifications module
+	do.Provide[models.OutboxMessageRepository](injector, func(i *do.Injector) (models.OutboxMessageRepository, error) {
+		return notificationsModule.NewOutboxMessageRepository(db), nil
+	})
+
+	do.Provide[models.OutboxMessageUsecase](injector, func(i *do.Injector) (models.OutboxMessageUsecase, error) {
+		return notificationsModule.NewOutboxMessageUsecase(
+			do.MustInvoke[models.OutboxMessageRepository](i),
+			// Services may be nil when only queueing messages, as in tests
+			emailSvc,
+			smsSvc,
+			whatsAppSvc,
+		), nil
+	})
+
+	do.Provide[models.NotificationRepository](injector, func(i *do.Injector) (models.NotificationRepository, error) {
+		return notificationsModule.NewNotificationRepository(db), nil
+	})
+
+	do.Provide[models.NotificationUsecase](injector, func(i *do.Injector) (models.NotificationUsecase, error) {
+		return notificationsModule.NewNotificationUsecase(
+			do.MustInvoke[models.NotificationRepository](i),
+			do.MustInvoke[models.OutboxMessageUsecase](i),
+		), nil
+	})
+
+	// Webhooks module
+	do.Provide[models.WebhookRepository](injector, func(i *do.Injector) (models.WebhookRepository, error) {
+		return webhooksModule.NewWebhookRepository(db), nil
+	})
+
+	do.Provide[models.WebhookUsecase](injector, func(i *do.Injector) (models.WebhookUsecase, error) {
+		return webhooksModule.NewWebhookUsecase(
+			do.MustInvoke[models.WebhookRepository](i),
 		), nil
 	})
 
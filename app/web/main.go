@@ -1,23 +1,37 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"loan-service/app"
 	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/config"
 	"loan-service/database"
 	"loan-service/models"
+	"loan-service/modules/loans"
+	"loan-service/modules/notifications"
 	"loan-service/services/auth"
 	"loan-service/services/email"
+	"loan-service/services/oidc"
+	"loan-service/services/sms"
 	"loan-service/services/upload"
+	"loan-service/services/whatsapp"
 	"loan-service/utils/resp"
 	"loan-service/utils/tern"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 
+	_apiKeyHandlers "loan-service/modules/apikeys/handlers"
+	_kycHandlers "loan-service/modules/kyc/handlers"
 	_loanHandlers "loan-service/modules/loans/handlers"
+	_notificationHandlers "loan-service/modules/notifications/handlers"
 	_productHandlers "loan-service/modules/products/handlers"
+	_roleHandlers "loan-service/modules/roles/handlers"
 	_userHandlers "loan-service/modules/users/handlers"
+	"loan-service/modules/webhooks"
+	_webhookHandlers "loan-service/modules/webhooks/handlers"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -49,7 +63,7 @@ func main() {
 	e.HideBanner = true
 	e.Logger.SetLevel(_log.DEBUG)
 	e.Validator = &CustomValidator{validator: validator.New()}
-	e.Use(middleware.Static("/tmp"))
+	e.IPExtractor = newIPExtractor()
 
 	e.Pre(middleware.RemoveTrailingSlash())
 
@@ -63,26 +77,42 @@ func main() {
 	)))
 
 	// Services
-	emailSvc := email.NewEmailService(config.Data.EmailSendGridAPIKey, config.Data.DefaultSenderAddress, config.Data.DefaultSenderName)
-	uploadSvc := upload.NewUploadService()
+	emailSvc := newEmailService()
+	smsSvc := newSMSService()
+	whatsAppSvc := newWhatsAppService()
+	uploadSvc := newUploadService()
+
+	var oidcRoleMapping []oidc.RoleMapping
+	for _, mapping := range config.Data.OIDCRoleMapping {
+		group, roleType, ok := strings.Cut(mapping, ":")
+		if !ok {
+			panic(fmt.Sprintf("invalid OIDC role mapping %q, expected group:role_type", mapping))
+		}
+		oidcRoleMapping = append(oidcRoleMapping, oidc.RoleMapping{Group: group, RoleType: auth.RoleType(roleType)})
+	}
+
+	oidcProvider := oidc.NewProvider(oidc.Config{
+		IssuerURL:    config.Data.OIDCIssuerURL,
+		ClientID:     config.Data.OIDCClientID,
+		ClientSecret: config.Data.OIDCClientSecret,
+		RedirectURL:  config.Data.OIDCRedirectURL,
+		GroupsClaim:  config.Data.OIDCGroupsClaim,
+		RoleMapping:  oidcRoleMapping,
+	}, nil)
 
 	// Dependency injection
-	injector := app.SetupInjections(db, e, emailSvc, uploadSvc)
+	injector := app.SetupInjections(db, e, emailSvc, smsSvc, whatsAppSvc, uploadSvc)
 
 	// Register router groups
-	mg := e.Group("/app", authMiddleware.JWTAuth(do.MustInvoke[models.UserRepository](injector)))
-	staffGroup := mg.Group("/admin", authMiddleware.AllowOnlyRoles(
-		auth.RoleTypeSuperuser, auth.RoleTypeStaff,
-	))
-	fieldValidatorGroup := mg.Group("/field-validation", authMiddleware.AllowOnlyRoles(
-		auth.RoleTypeSuperuser, auth.RoleTypeStaff, auth.RoleTypeFieldValidator,
-	))
-	investorGroup := mg.Group("/invest", authMiddleware.AllowOnlyRoles(
-		auth.RoleTypeSuperuser, auth.RoleTypeInvestor,
-	))
-	borrowGroup := mg.Group("/user", authMiddleware.AllowOnlyRoles(
-		auth.RoleTypeSuperuser, auth.RoleTypeBorrower,
+	// Access to each endpoint is checked against the user's role permissions upon registration
+	mg := e.Group("/app", authMiddleware.JWTOrAPIKeyAuth(
+		authMiddleware.JWTAuth(do.MustInvoke[models.UserRepository](injector)),
+		authMiddleware.APIKeyAuth(do.MustInvoke[models.APIKeyUsecase](injector)),
 	))
+	staffGroup := mg.Group("/admin")
+	fieldValidatorGroup := mg.Group("/field-validation")
+	investorGroup := mg.Group("/invest")
+	borrowGroup := mg.Group("/user")
 
 	// Healthcheck
 	e.GET("/ping", func(c echo.Context) error {
@@ -96,11 +126,109 @@ func main() {
 		authMiddleware.JWTAuth(do.MustInvoke[models.UserRepository](injector)),
 	)
 
+	_notificationHandlers.NewNotificationPreferenceHandler(
+		borrowGroup,
+		do.MustInvoke[models.NotificationUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_notificationHandlers.NewInboxHandler(
+		mg,
+		do.MustInvoke[models.NotificationUsecase](injector),
+	)
+
+	if oidcProvider.Enabled() {
+		_userHandlers.NewOIDCHandler(
+			e,
+			do.MustInvoke[models.UserUsecase](injector),
+			oidcProvider,
+		)
+	}
+
+	_userHandlers.NewAdminUserHandler(
+		staffGroup,
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_apiKeyHandlers.NewAPIKeyHandler(
+		staffGroup,
+		do.MustInvoke[models.APIKeyUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_roleHandlers.NewRoleHandler(
+		staffGroup,
+		do.MustInvoke[models.RoleUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_notificationHandlers.NewEmailTemplateHandler(
+		staffGroup,
+	)
+
+	_notificationHandlers.NewOutboxHandler(
+		staffGroup,
+		do.MustInvoke[models.OutboxMessageUsecase](injector),
+	)
+
+	_webhookHandlers.NewWebhookHandler(
+		staffGroup,
+		do.MustInvoke[models.WebhookUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	// Deliver queued notifications and webhooks in the background
+	go notifications.RunDispatcher(
+		context.Background(),
+		do.MustInvoke[models.OutboxMessageUsecase](injector),
+		config.Data.NotificationDispatchInterval,
+	)
+
+	go webhooks.RunDispatcher(
+		context.Background(),
+		do.MustInvoke[models.WebhookUsecase](injector),
+		config.Data.WebhookDispatchInterval,
+	)
+
+	go loans.RunInstallmentReminders(
+		context.Background(),
+		do.MustInvoke[models.LoanUsecase](injector),
+		config.Data.InstallmentReminderInterval,
+	)
+
+	go loans.RunOverdueVisitReports(
+		context.Background(),
+		do.MustInvoke[models.LoanUsecase](injector),
+		config.Data.VisitOverdueCheckInterval,
+	)
+
 	_productHandlers.NewProductHandler(
 		borrowGroup,
 		do.MustInvoke[models.ProductUsecase](injector),
 	)
 
+	_productHandlers.NewStaffProductHandler(
+		staffGroup,
+		do.MustInvoke[models.ProductUsecase](injector),
+	)
+
+	_kycHandlers.NewBorrowerKYCHandler(
+		borrowGroup,
+		do.MustInvoke[models.KYCUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_kycHandlers.NewStaffKYCHandler(
+		staffGroup,
+		do.MustInvoke[models.KYCUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
+	_kycHandlers.NewDocumentKYCHandler(
+		mg,
+		do.MustInvoke[models.KYCUsecase](injector),
+	)
+
 	do.Provide[*_loanHandlers.CommonLoanHandler](injector, func(i *do.Injector) (*_loanHandlers.CommonLoanHandler, error) {
 		return _loanHandlers.NewCommonLoanHandler(
 			do.MustInvoke[models.LoanUsecase](injector),
@@ -129,6 +257,12 @@ func main() {
 		do.MustInvoke[*_loanHandlers.CommonLoanHandler](injector),
 	)
 
+	_loanHandlers.NewDocumentHandler(
+		mg,
+		do.MustInvoke[models.LoanUsecase](injector),
+		do.MustInvoke[models.UserUsecase](injector),
+	)
+
 	_loanHandlers.NewBorrowerHandler(
 		borrowGroup,
 		do.MustInvoke[models.LoanUsecase](injector),
@@ -141,6 +275,139 @@ func main() {
 	e.Logger.Fatal(e.Start(fmt.Sprintf(":%s", config.Data.AppPort)))
 }
 
+// newEmailService returns the email backend selected in config
+func newEmailService() email.EmailService {
+	switch config.Data.EmailBackend {
+	case "sendgrid":
+		if config.Data.EmailSendGridAPIKey == "" {
+			panic("EMAIL_SENDGRID_API_KEY is required for the sendgrid email backend")
+		}
+
+		return email.NewEmailService(config.Data.EmailSendGridAPIKey, config.Data.DefaultSenderAddress, config.Data.DefaultSenderName)
+	case "smtp":
+		return email.NewSMTPEmailService(email.SMTPConfig{
+			Host:     config.Data.SMTPHost,
+			Port:     config.Data.SMTPPort,
+			Username: config.Data.SMTPUsername,
+			Password: config.Data.SMTPPassword,
+			TLSMode:  email.SMTPTLSMode(config.Data.SMTPTLSMode),
+		}, config.Data.DefaultSenderAddress, config.Data.DefaultSenderName)
+	case "file":
+		return email.NewFileDropEmailService(config.Data.EmailFileDropDir, config.Data.DefaultSenderAddress, config.Data.DefaultSenderName)
+	default:
+		panic(fmt.Sprintf("unknown email backend %q", config.Data.EmailBackend))
+	}
+}
+
+// newSMSService returns the SMS backend selected in config
+func newSMSService() sms.SMSService {
+	switch config.Data.SMSBackend {
+	case "log":
+		return sms.NewLogSMSService(os.Stdout)
+	case "twilio":
+		return sms.NewTwilioSMSService(sms.TwilioConfig{
+			AccountSID: config.Data.TwilioAccountSID,
+			AuthToken:  config.Data.TwilioAuthToken,
+			From:       config.Data.TwilioFromNumber,
+		}, nil)
+	default:
+		panic(fmt.Sprintf("unknown sms backend %q", config.Data.SMSBackend))
+	}
+}
+
+// newWhatsAppService returns the WhatsApp backend selected in config
+func newWhatsAppService() whatsapp.WhatsAppService {
+	switch config.Data.WhatsAppBackend {
+	case "log":
+		return whatsapp.NewLogWhatsAppService(os.Stdout)
+	case "cloudapi":
+		return whatsapp.NewCloudAPIWhatsAppService(whatsapp.CloudAPIConfig{
+			PhoneNumberID: config.Data.WhatsAppPhoneNumberID,
+			AccessToken:   config.Data.WhatsAppAccessToken,
+		}, nil)
+	default:
+		panic(fmt.Sprintf("unknown whatsapp backend %q", config.Data.WhatsAppBackend))
+	}
+}
+
+// newUploadService returns the file storage backend selected in config, scanning files with the selected scanner
+func newUploadService() upload.UploadService {
+	return upload.NewScanningUploadService(
+		newStorageService(config.Data.UploadPrefix),
+		newStorageService(config.Data.UploadQuarantinePrefix),
+		newScanner(),
+	)
+}
+
+// newStorageService returns the file storage backend selected in config, storing files under the prefix
+func newStorageService(prefix string) upload.UploadService {
+	switch config.Data.UploadBackend {
+	case "disk":
+		return upload.NewDiskUploadService(config.Data.UploadDir, prefix)
+	case "s3":
+		if config.Data.S3Bucket == "" {
+			panic("S3_BUCKET is required for the s3 upload backend")
+		}
+
+		return upload.NewS3UploadService(upload.S3Config{
+			Endpoint:        config.Data.S3Endpoint,
+			Region:          config.Data.S3Region,
+			Bucket:          config.Data.S3Bucket,
+			AccessKeyID:     config.Data.S3AccessKeyID,
+			SecretAccessKey: config.Data.S3SecretAccessKey,
+			Prefix:          prefix,
+			UsePathStyle:    config.Data.S3UsePathStyle,
+		}, nil)
+	default:
+		panic(fmt.Sprintf("unknown upload backend %q", config.Data.UploadBackend))
+	}
+}
+
+// newScanner returns the malware scanner selected in config
+func newScanner() upload.Scanner {
+	switch config.Data.ScanBackend {
+	case "none":
+		return upload.NewNoopScanner()
+	case "clamd":
+		return upload.NewClamdScanner(upload.ClamdConfig{
+			Address: config.Data.ClamdAddress,
+			Timeout: config.Data.ClamdTimeout,
+		})
+	default:
+		panic(fmt.Sprintf("unknown scan backend %q", config.Data.ScanBackend))
+	}
+}
+
+// newIPExtractor returns how the client IP is found, which login attempts are throttled by
+func newIPExtractor() echo.IPExtractor {
+	var ranges []*net.IPNet
+	for _, cidr := range config.Data.TrustedProxies {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("invalid trusted proxy range %q: %v", cidr, err))
+		}
+
+		ranges = append(ranges, ipRange)
+	}
+
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// Only the configured proxies are trusted, not every private network
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, ipRange := range ranges {
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 type CustomValidator struct {
 	validator *validator.Validate
 }
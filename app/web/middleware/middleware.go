@@ -39,8 +39,8 @@ func JWTAuth(userRepo models.UserRepository) echo.MiddlewareFunc {
 			}
 
 			// Validate user from auth claims
-			_, err = userRepo.FetchUserByID(c.Request().Context(), authClaims.UserID, nil)
-			if err != nil {
+			user, err := userRepo.FetchUserByID(c.Request().Context(), authClaims.UserID, nil)
+			if err != nil || !user.IsActive {
 				return resp.HTTPUnauthorized(c)
 			}
 
@@ -62,6 +62,9 @@ func JWTAuth(userRepo models.UserRepository) echo.MiddlewareFunc {
 				c.Response().Header().Set("Authorization", newAccessToken)
 			}
 
+			// Permissions are always read from the user's current role, not from the token
+			authClaims.Permissions = user.Role.PermissionCodes()
+
 			c.Set(auth.AuthClaimsCtxKey, *authClaims)
 
 			return next(c)
@@ -69,7 +72,52 @@ func JWTAuth(userRepo models.UserRepository) echo.MiddlewareFunc {
 	}
 }
 
-func AllowOnlyRoles(allowedRoleTypes ...auth.RoleType) echo.MiddlewareFunc {
+// APIKeyAuth authenticates service accounts by the API key in the X-API-Key header
+func APIKeyAuth(apiKeyUC models.APIKeyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rawKey := c.Request().Header.Get(auth.APIKeyHeader)
+			if rawKey == "" {
+				return resp.HTTPUnauthorized(c)
+			}
+
+			apiKey, err := apiKeyUC.Authenticate(c.Request().Context(), rawKey)
+			if err != nil {
+				return resp.HTTPUnauthorized(c)
+			}
+
+			serviceAccount := apiKey.ServiceAccount
+			c.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+				UserID:      serviceAccount.ID,
+				Email:       serviceAccount.Email,
+				Name:        serviceAccount.Name,
+				RoleType:    serviceAccount.Role.RoleType,
+				Permissions: apiKey.EffectivePermissions(),
+			})
+
+			return next(c)
+		}
+	}
+}
+
+// JWTOrAPIKeyAuth uses API key authentication if the request has an X-API-Key header, else JWT authentication
+func JWTOrAPIKeyAuth(jwtAuth, apiKeyAuth echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtNext := jwtAuth(next)
+		apiKeyNext := apiKeyAuth(next)
+
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(auth.APIKeyHeader) != "" {
+				return apiKeyNext(c)
+			}
+
+			return jwtNext(c)
+		}
+	}
+}
+
+// RequirePermission allows the request if the user holds at least one of the given permissions
+func RequirePermission(permissions ...auth.Permission) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Get claims from request context
@@ -78,15 +126,11 @@ func AllowOnlyRoles(allowedRoleTypes ...auth.RoleType) echo.MiddlewareFunc {
 				return resp.HTTPUnauthorized(c)
 			}
 
-			// If role type from claims is one of the allowed role types, call next middleware
-			for _, roleType := range allowedRoleTypes {
-				if claims.RoleType == roleType {
-					return next(c)
-				}
+			if !auth.HasPermission(claims.Permissions, permissions...) {
+				return resp.HTTPForbidden(c, resp.Forbidden, "You do not have permission to perform this action")
 			}
 
-			// Role type from claims is not one of allowed
-			return resp.HTTPUnauthorized(c)
+			return next(c)
 		}
 	}
 }
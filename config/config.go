@@ -2,6 +2,7 @@ package config
 
 import (
 	"loan-service/utils/errs"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -20,11 +21,101 @@ type Config struct {
 	DBPassword string `env:"DB_PASSWORD" env-default:"LoanService@2024!"`
 	DBName     string `env:"DB_NAME" env-default:"loanservice_db"`
 
-	AppSecret string `env:"APP_SECRET" env-required:"true"`
+	AppSecret  string `env:"APP_SECRET" env-required:"true"`
+	AppBaseURL string `env:"APP_BASE_URL" env-default:"http://localhost:8080"`
+	// CIDR ranges of the reverse proxies the client IP is taken from X-Forwarded-For behind, e.g. "10.0.0.0/8". The
+	// connection's address is used when empty, forwarded headers cannot be spoofed then.
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
 
-	EmailSendGridAPIKey  string `env:"EMAIL_SENDGRID_API_KEY" env-required:"true"`
+	// Email delivery backend, one of sendgrid, smtp, or file (writes .eml files for local development)
+	EmailBackend         string `env:"EMAIL_BACKEND" env-default:"sendgrid"`
+	EmailSendGridAPIKey  string `env:"EMAIL_SENDGRID_API_KEY"`
 	DefaultSenderAddress string `env:"DEFAULT_SENDER_ADDRESS" env-required:"true"`
 	DefaultSenderName    string `env:"DEFAULT_SENDER_NAME" env-required:"true"`
+
+	SMTPHost     string `env:"SMTP_HOST" env-default:"localhost"`
+	SMTPPort     string `env:"SMTP_PORT" env-default:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPTLSMode  string `env:"SMTP_TLS_MODE" env-default:"starttls"` // starttls, implicit, or none
+
+	EmailFileDropDir string `env:"EMAIL_FILE_DROP_DIR" env-default:"tmp/mail"`
+	// Overrides the built-in email templates, files are named <template>.<locale>.html and <template>.<locale>.txt
+	EmailTemplateDir string `env:"EMAIL_TEMPLATE_DIR"`
+	// How often queued notifications are picked up for delivery
+	NotificationDispatchInterval time.Duration `env:"NOTIFICATION_DISPATCH_INTERVAL" env-default:"10s"`
+
+	// SMS delivery backend, one of log (writes messages to STDOUT for local development) or twilio
+	SMSBackend       string `env:"SMS_BACKEND" env-default:"log"`
+	TwilioAccountSID string `env:"TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken  string `env:"TWILIO_AUTH_TOKEN"`
+	TwilioFromNumber string `env:"TWILIO_FROM_NUMBER"`
+
+	// WhatsApp delivery backend, one of log or cloudapi (WhatsApp Business Cloud API)
+	WhatsAppBackend       string `env:"WHATSAPP_BACKEND" env-default:"log"`
+	WhatsAppPhoneNumberID string `env:"WHATSAPP_PHONE_NUMBER_ID"`
+	WhatsAppAccessToken   string `env:"WHATSAPP_ACCESS_TOKEN"`
+
+	// File storage backend for uploads, one of disk (writes to UPLOAD_DIR for local development) or s3
+	UploadBackend string `env:"UPLOAD_BACKEND" env-default:"disk"`
+	UploadDir     string `env:"UPLOAD_DIR" env-default:"tmp"`
+	// Prepended to the key of every uploaded file
+	UploadPrefix string `env:"UPLOAD_PREFIX" env-default:"attachments"`
+	// Infected uploads are kept under this prefix for review instead
+	UploadQuarantinePrefix string `env:"UPLOAD_QUARANTINE_PREFIX" env-default:"quarantine"`
+	// Malware scanner run on every upload before it is stored, one of none or clamd
+	ScanBackend  string        `env:"SCAN_BACKEND" env-default:"none"`
+	ClamdAddress string        `env:"CLAMD_ADDRESS" env-default:"tcp://localhost:3310"`
+	ClamdTimeout time.Duration `env:"CLAMD_TIMEOUT" env-default:"30s"`
+	// Uploads above these are rejected, larger images are checked before being decoded
+	AttachmentMaxSize      int64 `env:"ATTACHMENT_MAX_SIZE" env-default:"10485760"`
+	AttachmentMaxDimension int   `env:"ATTACHMENT_MAX_DIMENSION" env-default:"8000"`
+	// Thumbnails of images fit in a square of this many pixels
+	AttachmentThumbnailSize int `env:"ATTACHMENT_THUMBNAIL_SIZE" env-default:"320"`
+	// How long signed download links of attachments stay valid
+	AttachmentURLTTL time.Duration `env:"ATTACHMENT_URL_TTL" env-default:"15m"`
+
+	// Any S3-compatible storage, e.g. MinIO with S3_ENDPOINT=http://localhost:9000 and S3_USE_PATH_STYLE=true
+	S3Endpoint        string `env:"S3_ENDPOINT"`
+	S3Region          string `env:"S3_REGION" env-default:"us-east-1"`
+	S3Bucket          string `env:"S3_BUCKET"`
+	S3AccessKeyID     string `env:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `env:"S3_SECRET_ACCESS_KEY"`
+	S3UsePathStyle    bool   `env:"S3_USE_PATH_STYLE" env-default:"false"`
+
+	// Proofs of visit taken further than this many meters from the borrower's address, or longer ago than the max age,
+	// are flagged for review before the loan can be approved
+	VisitMaxDistance float64       `env:"VISIT_MAX_DISTANCE" env-default:"500"`
+	VisitMaxAge      time.Duration `env:"VISIT_MAX_AGE" env-default:"24h"`
+
+	// Field validators must visit the borrower within the SLA of being assigned, in slots of the slot duration.
+	// Visits assigned without a field validator are given to one picked by the strategy, round_robin or region.
+	VisitSLA                  time.Duration `env:"VISIT_SLA" env-default:"72h"`
+	VisitSlotDuration         time.Duration `env:"VISIT_SLOT_DURATION" env-default:"1h"`
+	VisitAssignmentStrategy   string        `env:"VISIT_ASSIGNMENT_STRATEGY" env-default:"region"`
+	VisitOverdueCheckInterval time.Duration `env:"VISIT_OVERDUE_CHECK_INTERVAL" env-default:"15m"`
+
+	// Provider checking borrower identities against their ID card and selfie, manual or fake. With manual, every
+	// identity waits for staff review.
+	EKYCProvider string `env:"EKYC_PROVIDER" env-default:"manual"`
+
+	// How often disbursed loans are checked for upcoming installments to remind borrowers of
+	InstallmentReminderInterval time.Duration `env:"INSTALLMENT_REMINDER_INTERVAL" env-default:"1h"`
+
+	// How often queued webhook deliveries are picked up and sent to partners
+	WebhookDispatchInterval time.Duration `env:"WEBHOOK_DISPATCH_INTERVAL" env-default:"10s"`
+	// Local development only, allows plain http webhook URLs and receivers on loopback or private networks
+	WebhookAllowInsecureURLs bool `env:"WEBHOOK_ALLOW_INSECURE_URLS" env-default:"false"`
+
+	// Staff single sign-on, disabled unless the issuer URL and client ID are set
+	OIDCIssuerURL    string `env:"OIDC_ISSUER_URL"`
+	OIDCClientID     string `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string `env:"OIDC_REDIRECT_URL" env-default:"http://localhost:8080/oidc/callback"`
+	OIDCGroupsClaim  string `env:"OIDC_GROUPS_CLAIM" env-default:"groups"`
+	// Maps identity provider groups to role types, e.g. "loan-admins:superuser,loan-staff:staff". Earlier entries win
+	// between custom roles when a user is in several mapped groups.
+	OIDCRoleMapping []string `env:"OIDC_ROLE_MAPPING"`
 }
 
 var (
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"loan-service/config"
 	database "loan-service/database"
 	"loan-service/models"
+	"loan-service/modules/loans"
 	"loan-service/services/auth"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -25,17 +28,58 @@ func main() {
 		panic(err)
 	}
 
+	// Documents created before labels existed have none, they are filled in before the column becomes not null
+	if db.Migrator().HasColumn(&models.LoanDocument{}, "label") {
+		err = db.Exec(`UPDATE loan_documents SET label = '' WHERE label IS NULL`).Error
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	err = db.AutoMigrate(
+		&models.Permission{},
 		&models.Role{},
 		&models.User{},
 		&models.Product{},
+		&models.VisitChecklist{},
 		&models.Loan{},
+		&models.LoanDocument{},
+		&models.KYCDocument{},
+		&models.KYCProfile{},
+		&models.LoanAssignment{},
+		&models.VisitSync{},
+		&models.ApprovalPolicy{},
+		&models.LoanApproval{},
+		&models.CreditScorecard{},
+		&models.CreditAssessment{},
+		&models.LoanPricing{},
 		&models.Investment{},
+		&models.APIKey{},
+		&models.LoginAttempt{},
+		&models.OutboxMessage{},
+		&models.NotificationPreference{},
+		&models.InboxNotification{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryLog{},
 	)
 	if err != nil {
 		panic(err)
 	}
 
+	err = loans.MigrateLoanDocuments(context.Background(), db)
+	if err != nil {
+		panic(err)
+	}
+
+	// Responses of webhook receivers are no longer logged, drop the ones stored before
+	if db.Migrator().HasColumn(&models.WebhookDeliveryLog{}, "response_body") {
+		err = db.Migrator().DropColumn(&models.WebhookDeliveryLog{}, "response_body")
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	products := []models.Product{
 		{
 			Model:           gorm.Model{ID: 1},
@@ -76,6 +120,31 @@ func main() {
 		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
 	}
 
+	var permissions []models.Permission
+	for _, code := range auth.AllPermissions {
+		permissions = append(permissions, models.Permission{Code: code, Description: auth.PermissionDescriptions[code]})
+	}
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description"}),
+	}).Create(&permissions).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert permissions: %v", err))
+	}
+
+	// Grant default permissions to built-in roles, existing grants are kept
+	for i := range roles {
+		var rolePermissions []models.Permission
+		err := db.Where("code IN (?)", auth.DefaultRolePermissions[roles[i].RoleType]).Find(&rolePermissions).Error
+		if err != nil {
+			panic(fmt.Errorf("cannot fetch permissions for role %s: %v", roles[i].RoleType, err))
+		}
+
+		if err := db.Model(&roles[i]).Association("Permissions").Append(rolePermissions); err != nil {
+			panic(fmt.Errorf("cannot grant permissions to role %s: %v", roles[i].RoleType, err))
+		}
+	}
+
 	users := []models.User{
 		{
 			Name:     "Angela Merkel",
@@ -150,6 +219,32 @@ func main() {
 		panic(fmt.Errorf("cannot bulk insert users: %v", err))
 	}
 
+	// Borrowers are verified up front, so they can apply for loans without going through e-KYC
+	profiles := []models.KYCProfile{}
+	for i, user := range users {
+		if user.RoleID != 5 || user.ID == 0 {
+			continue
+		}
+
+		profiles = append(profiles, models.KYCProfile{
+			UserID:        user.ID,
+			NIK:           fmt.Sprintf("31710100010%05d", i),
+			DateOfBirth:   time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Address:       "Jl. Medan Merdeka Selatan No. 8-9, Jakarta Pusat",
+			PhoneNumber:   "+6281234567890",
+			Occupation:    "Civil servant",
+			MonthlyIncome: "25000000.00",
+			Status:        models.KYCStatusVerified,
+			SubmittedAt:   time.Now(),
+		})
+	}
+
+	if len(profiles) > 0 {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&profiles).Error; err != nil {
+			panic(fmt.Errorf("cannot bulk insert KYC profiles: %v", err))
+		}
+	}
+
 	// NOTE: Uncomment if you'd like to test with pre-existing loans and investments
 	// loans := []models.Loan{
 	// 	{
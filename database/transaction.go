@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"loan-service/config"
+	"loan-service/models"
+
+	"gorm.io/gorm"
+)
+
+// Conn returns the transaction carried by the context, or the database connection if there is none.
+// Repositories should use it so their queries take part in transactions started by usecases.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(config.ContextKeyDBTransaction).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+// Transaction implements models.Transactor.
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	return Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, config.ContextKeyDBTransaction, tx))
+	}, opts...)
+}
+
+func NewTransactor(db *gorm.DB) models.Transactor {
+	return &transactor{db}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"loan-service/services/auth"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey authenticates a service account for machine-to-machine integrations
+type APIKey struct {
+	gorm.Model
+	Name             string       `json:"name"`
+	Prefix           string       `json:"prefix" gorm:"uniqueIndex"` // visible part of the key, used for lookup
+	HashedKey        string       `json:"-" gorm:"column:hashed_key"`
+	ServiceAccountID uint         `json:"service_account_id"`
+	ServiceAccount   User         `json:"service_account" gorm:"foreignKey:ServiceAccountID"`
+	CreatedByID      uint         `json:"created_by_id"`
+	Permissions      []Permission `json:"permissions" gorm:"many2many:api_key_permissions"`
+	ExpiresAt        *time.Time   `json:"expires_at"`
+	LastUsedAt       *time.Time   `json:"last_used_at"`
+	RevokedAt        *time.Time   `json:"revoked_at"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsUsable returns true if the key has been neither revoked nor expired
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// EffectivePermissions returns the key's scopes that are still granted to the service account's role
+func (k *APIKey) EffectivePermissions() []auth.Permission {
+	rolePermissions := k.ServiceAccount.Role.PermissionCodes()
+
+	var permissions []auth.Permission
+	for _, permission := range k.Permissions {
+		if auth.HasPermission(rolePermissions, permission.Code) {
+			permissions = append(permissions, permission.Code)
+		}
+	}
+
+	return permissions
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, apiKey *APIKey) error
+	FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]APIKey, error)
+	FetchAPIKeyByID(ctx context.Context, apiKeyID uint) (*APIKey, error)
+	FetchAPIKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	UpdateAPIKeyLastUsedAt(ctx context.Context, apiKey *APIKey, lastUsedAt time.Time) error
+	RevokeAPIKey(ctx context.Context, apiKey *APIKey, revokedAt time.Time) error
+}
+
+type APIKeyUsecase interface {
+	CreateServiceAccount(ctx context.Context, actor *User, name string, roleType auth.RoleType) (*User, error)
+	CreateAPIKey(ctx context.Context, serviceAccountID uint, actor *User, name string, permissions []auth.Permission, expiresAt *time.Time) (*APIKey, string, error)
+	FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]APIKey, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID uint) (*APIKey, error)
+	Authenticate(ctx context.Context, rawKey string) (*APIKey, error)
+}
@@ -0,0 +1,483 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"loan-service/utils/money"
+	"loan-service/utils/ptr"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RiskGrade string
+
+// Risk grades from the least to the most risky
+const (
+	RiskGradeA RiskGrade = "A"
+	RiskGradeB RiskGrade = "B"
+	RiskGradeC RiskGrade = "C"
+	RiskGradeD RiskGrade = "D"
+	RiskGradeE RiskGrade = "E"
+)
+
+var RiskGrades = []RiskGrade{RiskGradeA, RiskGradeB, RiskGradeC, RiskGradeD, RiskGradeE}
+
+type CreditFactor string
+
+const (
+	// Age of the borrower in years, from their verified identity
+	CreditFactorAge           CreditFactor = "age"
+	CreditFactorMonthlyIncome CreditFactor = "monthly_income"
+	// Monthly installment of principal and interest, as a percentage of the monthly income
+	CreditFactorInstallmentToIncome CreditFactor = "installment_to_income"
+	CreditFactorPrincipalAmount     CreditFactor = "principal_amount"
+	// Loan term in months
+	CreditFactorLoanTerm CreditFactor = "loan_term"
+	// Other loans of the borrower that were disbursed and ran their full term
+	CreditFactorPreviousLoans CreditFactor = "previous_loans"
+)
+
+var CreditFactors = []CreditFactor{
+	CreditFactorAge,
+	CreditFactorMonthlyIncome,
+	CreditFactorInstallmentToIncome,
+	CreditFactorPrincipalAmount,
+	CreditFactorLoanTerm,
+	CreditFactorPreviousLoans,
+}
+
+var creditFactorDescriptions = map[CreditFactor]string{
+	CreditFactorAge:                 "Age",
+	CreditFactorMonthlyIncome:       "Monthly income",
+	CreditFactorInstallmentToIncome: "Monthly installment to income",
+	CreditFactorPrincipalAmount:     "Principal amount",
+	CreditFactorLoanTerm:            "Loan term",
+	CreditFactorPreviousLoans:       "Previously completed loans",
+}
+
+// Display formats a value of the factor, e.g. an amount in Rupiahs
+func (f CreditFactor) Display(value float64) string {
+	switch f {
+	case CreditFactorAge:
+		return fmt.Sprintf("%.0f years", value)
+	case CreditFactorMonthlyIncome, CreditFactorPrincipalAmount:
+		return money.DisplayMoney(strconv.FormatFloat(value, 'f', 2, 64))
+	case CreditFactorInstallmentToIncome:
+		return fmt.Sprintf("%.1f%%", value)
+	case CreditFactorLoanTerm:
+		return fmt.Sprintf("%.0f months", value)
+	default:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
+
+// ScoreBand gives points to values from its minimum, up to but excluding its maximum. Either bound may be left open.
+type ScoreBand struct {
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Points int      `json:"points"`
+	// Shown in explanations instead of the bounds, e.g. "stable income"
+	Label string `json:"label,omitempty"`
+}
+
+// Contains returns true if the value is within the band
+func (b ScoreBand) Contains(value float64) bool {
+	return (b.Min == nil || value >= *b.Min) && (b.Max == nil || value < *b.Max)
+}
+
+func (b ScoreBand) describe(factor CreditFactor) string {
+	switch {
+	case b.Label != "":
+		return b.Label
+	case b.Min != nil && b.Max != nil:
+		return fmt.Sprintf("from %s to below %s", factor.Display(*b.Min), factor.Display(*b.Max))
+	case b.Min != nil:
+		return "at least " + factor.Display(*b.Min)
+	case b.Max != nil:
+		return "below " + factor.Display(*b.Max)
+	default:
+		return "any value"
+	}
+}
+
+// ScorecardFactor scores a factor by the band its value falls in
+type ScorecardFactor struct {
+	Factor CreditFactor `json:"factor"`
+	Bands  []ScoreBand  `json:"bands"`
+}
+
+// ScorecardFactors are stored as JSON, in the order they are explained
+type ScorecardFactors []ScorecardFactor
+
+// Value implements driver.Valuer.
+func (f ScorecardFactors) Value() (driver.Value, error) {
+	if f == nil {
+		return json.Marshal([]ScorecardFactor{})
+	}
+
+	return json.Marshal([]ScorecardFactor(f))
+}
+
+// Scan implements sql.Scanner.
+func (f *ScorecardFactors) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, f)
+	case string:
+		return json.Unmarshal([]byte(value), f)
+	default:
+		return fmt.Errorf("cannot scan %T into scorecard factors", value)
+	}
+}
+
+// GradeThreshold is the minimum score of a risk grade
+type GradeThreshold struct {
+	Grade    RiskGrade `json:"grade"`
+	MinScore int       `json:"min_score"`
+}
+
+// GradeThresholds are stored as JSON
+type GradeThresholds []GradeThreshold
+
+// Value implements driver.Valuer.
+func (t GradeThresholds) Value() (driver.Value, error) {
+	if t == nil {
+		return json.Marshal([]GradeThreshold{})
+	}
+
+	return json.Marshal([]GradeThreshold(t))
+}
+
+// Scan implements sql.Scanner.
+func (t *GradeThresholds) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	default:
+		return fmt.Errorf("cannot scan %T into grade thresholds", value)
+	}
+}
+
+// CreditScorecard scores loan applications: the base score plus the points of every factor, graded by the highest
+// threshold the score reaches. Every change is a new version, assessments refer to the version they were scored with.
+type CreditScorecard struct {
+	gorm.Model
+	Version   int              `json:"version" gorm:"uniqueIndex"`
+	BaseScore int              `json:"base_score"`
+	Factors   ScorecardFactors `json:"factors" gorm:"type:jsonb"`
+	Grades    GradeThresholds  `json:"grades" gorm:"type:jsonb"`
+}
+
+func (CreditScorecard) TableName() string {
+	return "credit_scorecards"
+}
+
+// DefaultCreditScorecard applies until a scorecard is stored, as version 0
+var DefaultCreditScorecard = CreditScorecard{
+	BaseScore: 500,
+	Factors: ScorecardFactors{
+		{Factor: CreditFactorAge, Bands: []ScoreBand{
+			{Max: ptr.NewFloat64Ptr(21), Points: -20},
+			{Min: ptr.NewFloat64Ptr(21), Max: ptr.NewFloat64Ptr(25), Points: 0},
+			{Min: ptr.NewFloat64Ptr(25), Max: ptr.NewFloat64Ptr(55), Points: 30},
+			{Min: ptr.NewFloat64Ptr(55), Points: 0},
+		}},
+		{Factor: CreditFactorMonthlyIncome, Bands: []ScoreBand{
+			{Max: ptr.NewFloat64Ptr(3000000), Points: -50},
+			{Min: ptr.NewFloat64Ptr(3000000), Max: ptr.NewFloat64Ptr(10000000), Points: 20},
+			{Min: ptr.NewFloat64Ptr(10000000), Max: ptr.NewFloat64Ptr(25000000), Points: 50},
+			{Min: ptr.NewFloat64Ptr(25000000), Points: 80},
+		}},
+		{Factor: CreditFactorInstallmentToIncome, Bands: []ScoreBand{
+			{Max: ptr.NewFloat64Ptr(20), Points: 80},
+			{Min: ptr.NewFloat64Ptr(20), Max: ptr.NewFloat64Ptr(35), Points: 30},
+			{Min: ptr.NewFloat64Ptr(35), Max: ptr.NewFloat64Ptr(50), Points: -40},
+			{Min: ptr.NewFloat64Ptr(50), Points: -120},
+		}},
+		{Factor: CreditFactorLoanTerm, Bands: []ScoreBand{
+			{Max: ptr.NewFloat64Ptr(6), Points: 20},
+			{Min: ptr.NewFloat64Ptr(6), Max: ptr.NewFloat64Ptr(12), Points: 10},
+			{Min: ptr.NewFloat64Ptr(12), Points: 0},
+		}},
+		{Factor: CreditFactorPreviousLoans, Bands: []ScoreBand{
+			{Max: ptr.NewFloat64Ptr(1), Points: 0, Label: "no loan history"},
+			{Min: ptr.NewFloat64Ptr(1), Max: ptr.NewFloat64Ptr(3), Points: 40},
+			{Min: ptr.NewFloat64Ptr(3), Points: 60},
+		}},
+	},
+	Grades: GradeThresholds{
+		{Grade: RiskGradeA, MinScore: 650},
+		{Grade: RiskGradeB, MinScore: 580},
+		{Grade: RiskGradeC, MinScore: 500},
+		{Grade: RiskGradeD, MinScore: 420},
+		{Grade: RiskGradeE, MinScore: 0},
+	},
+}
+
+const maxScoreBands = 20
+
+// Validate checks the factors are known and scored once, with bands that do not overlap, and the grades are known and
+// ordered: a less risky grade needs a higher score
+func (s *CreditScorecard) Validate() error {
+	if len(s.Factors) == 0 {
+		return NewInvalidCreditScorecardError("a scorecard needs at least one factor")
+	}
+
+	factors := map[CreditFactor]bool{}
+	for _, factor := range s.Factors {
+		if !slices.Contains(CreditFactors, factor.Factor) {
+			return NewInvalidCreditScorecardError("unknown factor `%s`", factor.Factor)
+		}
+
+		if factors[factor.Factor] {
+			return NewInvalidCreditScorecardError("factor `%s` is scored twice", factor.Factor)
+		}
+		factors[factor.Factor] = true
+
+		if len(factor.Bands) == 0 || len(factor.Bands) > maxScoreBands {
+			return NewInvalidCreditScorecardError("factor `%s`: needs between 1 and %d bands", factor.Factor, maxScoreBands)
+		}
+
+		bands := append([]ScoreBand{}, factor.Bands...)
+		sort.Slice(bands, func(i, j int) bool { return lowerBound(bands[i]) < lowerBound(bands[j]) })
+		for i, band := range bands {
+			if band.Min != nil && band.Max != nil && *band.Min >= *band.Max {
+				return NewInvalidCreditScorecardError("factor `%s`: band minimum must be below its maximum", factor.Factor)
+			}
+
+			if i > 0 && upperBound(bands[i-1]) > lowerBound(band) {
+				return NewInvalidCreditScorecardError("factor `%s`: bands overlap", factor.Factor)
+			}
+		}
+	}
+
+	if len(s.Grades) == 0 {
+		return NewInvalidCreditScorecardError("a scorecard needs at least one grade")
+	}
+
+	grades := map[RiskGrade]bool{}
+	for _, threshold := range s.Grades {
+		if !slices.Contains(RiskGrades, threshold.Grade) {
+			return NewInvalidCreditScorecardError("unknown grade `%s`", threshold.Grade)
+		}
+
+		if grades[threshold.Grade] {
+			return NewInvalidCreditScorecardError("grade `%s` is given twice", threshold.Grade)
+		}
+		grades[threshold.Grade] = true
+	}
+
+	thresholds := s.sortedGrades()
+	for i := 1; i < len(thresholds); i++ {
+		if thresholds[i].MinScore >= thresholds[i-1].MinScore {
+			return NewInvalidCreditScorecardError("grade `%s` must need a lower score than grade `%s`",
+				thresholds[i].Grade, thresholds[i-1].Grade)
+		}
+	}
+
+	return nil
+}
+
+func lowerBound(b ScoreBand) float64 {
+	if b.Min == nil {
+		return math.Inf(-1)
+	}
+
+	return *b.Min
+}
+
+func upperBound(b ScoreBand) float64 {
+	if b.Max == nil {
+		return math.Inf(1)
+	}
+
+	return *b.Max
+}
+
+// sortedGrades returns the thresholds from the least to the most risky grade
+func (s *CreditScorecard) sortedGrades() []GradeThreshold {
+	thresholds := append([]GradeThreshold{}, s.Grades...)
+	sort.Slice(thresholds, func(i, j int) bool {
+		return slices.Index(RiskGrades, thresholds[i].Grade) < slices.Index(RiskGrades, thresholds[j].Grade)
+	})
+
+	return thresholds
+}
+
+// Grade returns the least risky grade the score reaches, or the most risky grade below every threshold
+func (s *CreditScorecard) Grade(score int) RiskGrade {
+	thresholds := s.sortedGrades()
+	for _, threshold := range thresholds {
+		if score >= threshold.MinScore {
+			return threshold.Grade
+		}
+	}
+
+	return thresholds[len(thresholds)-1].Grade
+}
+
+// CreditFactorValues are the values of the factors for an application, factors without a value score no points
+type CreditFactorValues map[CreditFactor]float64
+
+// NewCreditFactorValues gathers the factors of the loan application. The identity may be nil, e.g. for loans applied
+// for before identities were verified. The history is the borrower's other disbursed loans, only those that ran their
+// full term count as completed.
+func NewCreditFactorValues(
+	loan *Loan,
+	product *Product,
+	profile *KYCProfile,
+	history []Loan,
+	now time.Time,
+) CreditFactorValues {
+	previousLoans := 0
+	for i := range history {
+		if history[i].ID != loan.ID && !history[i].IsOpen(now) {
+			previousLoans++
+		}
+	}
+
+	values := CreditFactorValues{
+		CreditFactorLoanTerm:      float64(loan.LoanTerm),
+		CreditFactorPreviousLoans: float64(previousLoans),
+	}
+
+	principal, err := strconv.ParseFloat(loan.PrincipalAmount, 64)
+	if err == nil {
+		values[CreditFactorPrincipalAmount] = principal
+	}
+
+	if profile == nil {
+		return values
+	}
+
+	age := now.Year() - profile.DateOfBirth.Year()
+	if now.Month() < profile.DateOfBirth.Month() ||
+		(now.Month() == profile.DateOfBirth.Month() && now.Day() < profile.DateOfBirth.Day()) {
+		age--
+	}
+	values[CreditFactorAge] = float64(age)
+
+	income, err := strconv.ParseFloat(profile.MonthlyIncome, 64)
+	if err != nil || income <= 0 {
+		return values
+	}
+	values[CreditFactorMonthlyIncome] = income
+
+	// Affordability is judged at the product's base rate, not at the rate a previous grade priced the loan at
+	_, baseInterest := money.CalculateROI(loan.PrincipalAmount, product.InterestRate, loan.LoanTerm)
+	totalInterest, err := strconv.ParseFloat(baseInterest, 64)
+	if _, ok := values[CreditFactorPrincipalAmount]; ok && err == nil && loan.LoanTerm > 0 {
+		installment := (principal + totalInterest) / float64(loan.LoanTerm)
+		values[CreditFactorInstallmentToIncome] = math.Round(installment/income*1000) / 10
+	}
+
+	return values
+}
+
+// CreditFactorScore is how a factor contributed to the score
+type CreditFactorScore struct {
+	Factor CreditFactor `json:"factor"`
+	// Nil if the factor had no value
+	Value       *float64 `json:"value"`
+	Points      int      `json:"points"`
+	Explanation string   `json:"explanation"`
+}
+
+// CreditFactorScores are stored as JSON, in the order of the scorecard's factors
+type CreditFactorScores []CreditFactorScore
+
+// Value implements driver.Valuer.
+func (s CreditFactorScores) Value() (driver.Value, error) {
+	if s == nil {
+		return json.Marshal([]CreditFactorScore{})
+	}
+
+	return json.Marshal([]CreditFactorScore(s))
+}
+
+// Scan implements sql.Scanner.
+func (s *CreditFactorScores) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, s)
+	case string:
+		return json.Unmarshal([]byte(value), s)
+	default:
+		return fmt.Errorf("cannot scan %T into credit factor scores", value)
+	}
+}
+
+// Assess scores the values, explaining the points given for each factor
+func (s *CreditScorecard) Assess(values CreditFactorValues) *CreditAssessment {
+	assessment := &CreditAssessment{
+		ScorecardVersion: s.Version,
+		Score:            s.BaseScore,
+		Factors:          CreditFactorScores{},
+	}
+
+	for _, factor := range s.Factors {
+		description := creditFactorDescriptions[factor.Factor]
+		factorScore := CreditFactorScore{Factor: factor.Factor}
+
+		value, ok := values[factor.Factor]
+		if !ok {
+			factorScore.Explanation = fmt.Sprintf("%s: not available, no points", description)
+			assessment.Factors = append(assessment.Factors, factorScore)
+			continue
+		}
+
+		factorScore.Value = &value
+		factorScore.Explanation = fmt.Sprintf("%s: %s, outside every band, no points",
+			description, factor.Factor.Display(value))
+		for _, band := range factor.Bands {
+			if band.Contains(value) {
+				factorScore.Points = band.Points
+				factorScore.Explanation = fmt.Sprintf("%s: %s, %s, %+d points",
+					description, factor.Factor.Display(value), band.describe(factor.Factor), band.Points)
+				break
+			}
+		}
+
+		assessment.Score += factorScore.Points
+		assessment.Factors = append(assessment.Factors, factorScore)
+	}
+
+	assessment.Grade = s.Grade(assessment.Score)
+
+	return assessment
+}
+
+// CreditAssessment is the credit score of a loan application, with how each factor contributed to it. Loans keep
+// every assessment, the latest one sets the loan's risk grade.
+type CreditAssessment struct {
+	gorm.Model
+	LoanID           uint               `json:"loan_id" gorm:"index"`
+	ScorecardVersion int                `json:"scorecard_version"`
+	Score            int                `json:"score"`
+	Grade            RiskGrade          `json:"grade"`
+	Factors          CreditFactorScores `json:"factors" gorm:"type:jsonb"`
+	// Staff who asked for the loan to be assessed again, nil when assessed on application
+	AssessorID *uint `json:"assessor_id"`
+	Assessor   *User `json:"assessor" gorm:"foreignKey:AssessorID"`
+}
+
+func (CreditAssessment) TableName() string {
+	return "credit_assessments"
+}
@@ -21,3 +21,51 @@ func NewNextStateError(currentState, nextState LoanStatus, action string) errs.G
 		Err:        fmt.Errorf("cannot transition from state `%s` to `%s` for action `%s`", currentState, nextState, action),
 	}
 }
+
+func NewInvalidVisitChecklistError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidVisitChecklist",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
+
+func NewInvalidVisitReportError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidVisitReport",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
+
+func NewInvalidApprovalPolicyError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidApprovalPolicy",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
+
+func NewInvalidKYCProfileError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidKYCProfile",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
+
+func NewInvalidCreditScorecardError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidCreditScorecard",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
+
+func NewInvalidRateGridError(format string, a ...any) errs.GeneralError {
+	return errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidRateGrid",
+		Err:        fmt.Errorf(format, a...),
+	}
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"loan-service/services/auth"
+	"loan-service/services/upload"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type KYCStatus string
+
+const (
+	// Waiting for staff review, either no e-KYC provider is configured or it could not decide
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusVerified KYCStatus = "verified"
+	// The borrower may submit their identity again
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// KYC profiles are only kept for adults, as required for an ID card (KTP)
+const (
+	kycMinAge = 17
+	kycMaxAge = 100
+)
+
+// KYCProfile is the identity of a borrower, checked against their ID card and a selfie before they can borrow. The
+// address is the one on the ID card, and may differ from the one visits are checked against.
+type KYCProfile struct {
+	gorm.Model
+	UserID uint  `json:"user_id" gorm:"uniqueIndex"`
+	User   *User `json:"user" gorm:"foreignKey:UserID"`
+	// Nomor Induk Kependudukan, the 16 digit national identity number
+	NIK           string    `json:"nik" gorm:"index"`
+	DateOfBirth   time.Time `json:"date_of_birth" gorm:"type:date"`
+	Address       string    `json:"address"`
+	PhoneNumber   string    `json:"phone_number"`
+	Occupation    string    `json:"occupation"`
+	MonthlyIncome string    `json:"monthly_income"`
+
+	IDCardID *uint        `json:"id_card_id"`
+	IDCard   *KYCDocument `json:"id_card" gorm:"foreignKey:IDCardID"`
+	SelfieID *uint        `json:"selfie_id"`
+	Selfie   *KYCDocument `json:"selfie" gorm:"foreignKey:SelfieID"`
+
+	Status      KYCStatus `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+
+	// Outcome of the e-KYC provider for the last submission
+	ProviderName      string `json:"provider_name"`
+	ProviderReference string `json:"provider_reference"`
+	ProviderDecision  string `json:"provider_decision"`
+	ProviderReason    string `json:"provider_reason"`
+
+	ReviewerID *uint      `json:"reviewer_id"`
+	Reviewer   *User      `json:"reviewer" gorm:"foreignKey:ReviewerID"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	ReviewNote string     `json:"review_note"`
+}
+
+func (KYCProfile) TableName() string {
+	return "kyc_profiles"
+}
+
+// Validate checks the borrower is of age
+func (p *KYCProfile) Validate(now time.Time) error {
+	if p.DateOfBirth.After(now.AddDate(-kycMinAge, 0, 0)) {
+		return NewInvalidKYCProfileError("borrowers must be at least %d years old", kycMinAge)
+	}
+
+	if p.DateOfBirth.Before(now.AddDate(-kycMaxAge, 0, 0)) {
+		return NewInvalidKYCProfileError("date of birth is too far in the past")
+	}
+
+	return nil
+}
+
+func (p *KYCProfile) Verified() bool {
+	return p != nil && p.Status == KYCStatusVerified
+}
+
+type KYCDocumentType string
+
+const (
+	KYCDocumentIDCard KYCDocumentType = "id_card"
+	KYCDocumentSelfie KYCDocumentType = "selfie"
+)
+
+// KYCDocument is an identity photo of a borrower. Every submission uploads new ones, the profile refers to the
+// latest.
+type KYCDocument struct {
+	gorm.Model
+	UserID       uint            `json:"user_id" gorm:"index"`
+	DocumentType KYCDocumentType `json:"document_type"`
+	FileKey      string          `json:"file_key"`
+	ThumbnailKey string          `json:"thumbnail_key"`
+}
+
+func (KYCDocument) TableName() string {
+	return "kyc_documents"
+}
+
+// Key returns the storage key of the variant, or an empty string if the document has none
+func (d *KYCDocument) Key(variant LoanDocumentVariant) string {
+	switch variant {
+	case LoanDocumentFile:
+		return d.FileKey
+	case LoanDocumentThumbnail:
+		return d.ThumbnailKey
+	default:
+		return ""
+	}
+}
+
+// DownloadPath returns the path of the variant's download endpoint
+func (d *KYCDocument) DownloadPath(variant LoanDocumentVariant) string {
+	return fmt.Sprintf("/app/kyc/documents/%d/%s", d.ID, variant)
+}
+
+// KYCDocumentVisibleTo returns true if the user may see the borrower's identity photos, i.e. the borrower themself
+// or staff reviewing identities
+func KYCDocumentVisibleTo(document *KYCDocument, userID uint, permissions []auth.Permission) bool {
+	return document.UserID == userID || auth.HasPermission(permissions, auth.PermissionKYCReview)
+}
+
+// KYCSubmission is the identity submitted by the borrower, along with their ID card and selfie
+type KYCSubmission struct {
+	NIK           string
+	DateOfBirth   time.Time
+	Address       string
+	PhoneNumber   string
+	Occupation    string
+	MonthlyIncome string
+	IDCard        io.Reader
+	Selfie        io.Reader
+}
+
+type FetchKYCProfilesOpts struct {
+	Status []KYCStatus
+	NIK    string
+}
+
+type KYCRepository interface {
+	FetchKYCProfiles(ctx context.Context, opts *FetchKYCProfilesOpts) ([]KYCProfile, error)
+	FetchKYCProfileByUserID(ctx context.Context, userID uint) (*KYCProfile, error)
+	SaveKYCProfile(ctx context.Context, profile *KYCProfile) error
+	FetchKYCDocumentByID(ctx context.Context, documentID uint) (*KYCDocument, error)
+	CreateKYCDocument(ctx context.Context, document *KYCDocument) error
+}
+
+type KYCUsecase interface {
+	FetchKYCProfile(ctx context.Context, userID uint) (*KYCProfile, error)
+	// SubmitKYCProfile stores the borrower's identity and has the e-KYC provider check it, submissions the provider
+	// cannot decide on wait for staff review. Identities can be submitted again until verified.
+	SubmitKYCProfile(ctx context.Context, borrower *User, submission *KYCSubmission) (*KYCProfile, error)
+	// FetchKYCReviews lists the identities waiting for staff review, oldest submission first
+	FetchKYCReviews(ctx context.Context) ([]KYCProfile, error)
+	ReviewKYCProfile(ctx context.Context, profile *KYCProfile, reviewer *User, approve bool, note string) error
+	DownloadKYCDocument(
+		ctx context.Context,
+		documentID uint,
+		userID uint,
+		permissions []auth.Permission,
+		variant LoanDocumentVariant,
+	) (io.ReadCloser, *upload.UploadedFile, error)
+}
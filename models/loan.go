@@ -4,7 +4,9 @@ import (
 	"context"
 	"io"
 	"loan-service/services/auth"
+	"loan-service/services/upload"
 	"loan-service/utils/money"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -20,34 +22,53 @@ const (
 
 type Loan struct {
 	gorm.Model
-	Name                       string       `json:"name"`
-	Status                     LoanStatus   `json:"status"`
-	BorrowerID                 uint         `json:"borrower_id"`
-	Borrower                   User         `json:"borrower" gorm:"foreignKey:BorrowerID"`
-	ProductID                  uint         `json:"product_id" gorm:"foreignKey:ProductID"`
-	Product                    Product      `json:"product"`
-	PrincipalAmount            string       `json:"principal_amount"`
-	RemainingAmount            string       `json:"remaining_amount"`
-	InterestRate               float64      `json:"interest_rate"` // in per annum
-	TotalInterest              string       `json:"total_interest"`
-	ROI                        string       `json:"roi"`
-	LoanTerm                   int          `json:"loan_term"`                                                                                                             // in months
-	Investors                  []User       `json:"investors" gorm:"->;many2many:investments;foreignKey:ID;joinForeignKey:LoanID;references:ID;joinReferences:InvestorID"` //nolint:lll
-	Investments                []Investment `json:"investments" gorm:"->;foreignKey:LoanID"`
-	VisitorID                  *uint        `json:"visitor_id"`
-	Visitor                    *User        `json:"visitor" gorm:"foreignKey:VisitorID;default:null"`
-	ApproverID                 *uint        `json:"approver_id"`
-	Approver                   *User        `json:"approver" gorm:"foreignKey:ApproverID;default:null"`
-	DisburserID                *uint        `json:"disburser_id"`
-	Disburser                  *User        `json:"disburser" gorm:"foreignKey:DisburserID;default:null"`
-	ProofOfVisitAttachmentFile string       `json:"proof_of_visit_attachment_file"`
-	AgreementAttachmentFile    string       `json:"agreement_attachment_file"`
+	Name            string       `json:"name"`
+	Status          LoanStatus   `json:"status"`
+	BorrowerID      uint         `json:"borrower_id"`
+	Borrower        User         `json:"borrower" gorm:"foreignKey:BorrowerID"`
+	ProductID       uint         `json:"product_id" gorm:"foreignKey:ProductID"`
+	Product         Product      `json:"product"`
+	PrincipalAmount string       `json:"principal_amount"`
+	RemainingAmount string       `json:"remaining_amount"`
+	InterestRate    float64      `json:"interest_rate"` // in per annum
+	TotalInterest   string       `json:"total_interest"`
+	ROI             string       `json:"roi"`
+	LoanTerm        int          `json:"loan_term"`                                                                                                             // in months
+	Investors       []User       `json:"investors" gorm:"->;many2many:investments;foreignKey:ID;joinForeignKey:LoanID;references:ID;joinReferences:InvestorID"` //nolint:lll
+	Investments     []Investment `json:"investments" gorm:"->;foreignKey:LoanID"`
+	VisitorID       *uint        `json:"visitor_id"`
+	Visitor         *User        `json:"visitor" gorm:"foreignKey:VisitorID;default:null"`
+	ApproverID      *uint        `json:"approver_id"`
+	Approver        *User        `json:"approver" gorm:"foreignKey:ApproverID;default:null"`
+	DisburserID     *uint        `json:"disburser_id"`
+	Disburser       *User        `json:"disburser" gorm:"foreignKey:DisburserID;default:null"`
+	VisitedAt       *time.Time   `json:"visited_at"`
+	Visit           LoanVisit    `json:"visit" gorm:"embedded;embeddedPrefix:visit_"`
+	// Current version of each document, see LoanRepository.FetchLoanDocuments for the history
+	Documents []LoanDocument `json:"-" gorm:"->;foreignKey:LoanID"`
+	// Active visit assignment, see LoanRepository.FetchLoanAssignments for the history
+	Assignments []LoanAssignment `json:"-" gorm:"->;foreignKey:LoanID"`
+	// Approval votes of every round, oldest first. A rejection starts the next round.
+	Approvals     []LoanApproval `json:"-" gorm:"->;foreignKey:LoanID"`
+	ApprovalRound int            `json:"-"`
+	// From the latest credit assessment, see LoanRepository.FetchCreditAssessments for the explanation
+	CreditScore *int      `json:"credit_score"`
+	RiskGrade   RiskGrade `json:"risk_grade"`
+
+	// Installments are due monthly from disbursement, for the loan term
+	DisbursedAt              *time.Time `json:"disbursed_at"`
+	InstallmentRemindersSent int        `json:"-"`
 }
 
 func (Loan) TableName() string {
 	return "loans"
 }
 
+// IsOpen returns true until the loan was disbursed and its last installment has come due
+func (l *Loan) IsOpen(now time.Time) bool {
+	return l.Status != LoanStatusDisbursed || l.DisbursedAt == nil || now.Before(l.InstallmentDueAt(l.LoanTerm))
+}
+
 // state machine, state can only move forward
 func (l *Loan) AdvanceState(nextState LoanStatus, action string) error {
 	currentState := l.Status
@@ -55,7 +76,7 @@ func (l *Loan) AdvanceState(nextState LoanStatus, action string) error {
 	case LoanStatusProposed:
 		return NewNextStateError(currentState, nextState, action)
 	case LoanStatusApproved:
-		requirementsValid := l.VisitorID != nil && l.ProofOfVisitAttachmentFile != ""
+		requirementsValid := l.VisitorID != nil && l.Document(LoanDocumentProofOfVisit) != nil && l.Visit.Approvable()
 		if currentState != LoanStatusProposed || !requirementsValid {
 			return NewNextStateError(currentState, nextState, action)
 		}
@@ -79,6 +100,51 @@ func (l *Loan) AdvanceState(nextState LoanStatus, action string) error {
 	return nil
 }
 
+// Document returns the current version of the document type, or nil if the loan has none
+func (l *Loan) Document(documentType LoanDocumentType) *LoanDocument {
+	for i := range l.Documents {
+		if l.Documents[i].DocumentType == documentType && l.Documents[i].SupersededByID == nil {
+			return &l.Documents[i]
+		}
+	}
+
+	return nil
+}
+
+// HideDocuments drops the documents a user with the permissions may not see, so the loan never links to them
+func (l *Loan) HideDocuments(permissions []auth.Permission) {
+	visible := []LoanDocument{}
+	for _, document := range l.Documents {
+		if document.DocumentType.VisibleTo(permissions) {
+			visible = append(visible, document)
+		}
+	}
+
+	l.Documents = visible
+}
+
+// DocumentByID returns the current document with the ID, or nil if it is not one of the loan's current documents
+func (l *Loan) DocumentByID(documentID uint) *LoanDocument {
+	for i := range l.Documents {
+		if l.Documents[i].ID == documentID {
+			return &l.Documents[i]
+		}
+	}
+
+	return nil
+}
+
+// ActiveAssignment returns the field validator's task to visit the borrower, or nil if the loan is not assigned
+func (l *Loan) ActiveAssignment() *LoanAssignment {
+	for i := range l.Assignments {
+		if l.Assignments[i].Status == LoanAssignmentActive {
+			return &l.Assignments[i]
+		}
+	}
+
+	return nil
+}
+
 // factory
 func NewLoan(name string, product *Product, borrower *User) *Loan {
 	roi, totalInterest := money.CalculateROI(product.PrincipalAmount, product.InterestRate, int(product.Term))
@@ -98,9 +164,13 @@ func NewLoan(name string, product *Product, borrower *User) *Loan {
 }
 
 type FetchLoanOpts struct {
-	UserID   uint
-	RoleType auth.RoleType
-	Status   []LoanStatus
+	UserID      uint
+	Permissions []auth.Permission // loans are scoped by the broadest loan view permission
+	Status      []LoanStatus
+	// e.g. visits pending review, for the staff review queue
+	VisitReviewStatus []VisitReviewStatus
+	// Visited loans the visit of which does not block the approval, for the staff approval queue
+	AwaitingApproval bool
 }
 
 type LoanRepository interface {
@@ -108,6 +178,35 @@ type LoanRepository interface {
 	FetchLoanByID(ctx context.Context, loanID uint, opts *FetchLoanOpts) (*Loan, error)
 	CreateLoan(ctx context.Context, loan *Loan) error
 	UpdateLoan(ctx context.Context, loan *Loan) error
+	FetchLoanDocuments(ctx context.Context, loanID uint, opts *FetchLoanDocumentsOpts) ([]LoanDocument, error)
+	FetchLoanDocumentByID(ctx context.Context, loanID, documentID uint) (*LoanDocument, error)
+	// CreateLoanDocument stores the document as the next version of its type, superseding the current one
+	CreateLoanDocument(ctx context.Context, document *LoanDocument) error
+	FetchLoanAssignments(ctx context.Context, opts *FetchLoanAssignmentsOpts) ([]LoanAssignment, error)
+	FetchLoanAssignmentByID(ctx context.Context, assignmentID uint) (*LoanAssignment, error)
+	// CreateLoanAssignment makes the assignment the active one of its loan, the previous one is reassigned
+	CreateLoanAssignment(ctx context.Context, assignment *LoanAssignment) error
+	UpdateLoanAssignment(ctx context.Context, assignment *LoanAssignment) error
+	FetchFieldValidatorWorkloads(ctx context.Context) ([]FieldValidatorWorkload, error)
+	FetchVisitSyncs(ctx context.Context, fieldValidatorID uint, clientIDs []string) ([]VisitSync, error)
+	CreateVisitSync(ctx context.Context, sync *VisitSync) error
+	FetchApprovalPolicies(ctx context.Context) ([]ApprovalPolicy, error)
+	// ReplaceApprovalPolicies replaces every tier of the approval policy
+	ReplaceApprovalPolicies(ctx context.Context, policies []ApprovalPolicy) error
+	// LockLoanApprovals reloads the status, approval round, votes, visit, credit grade and product of the loan, and
+	// locks it until the transaction ends so concurrent votes and visit reviews are applied one after the other
+	LockLoanApprovals(ctx context.Context, loan *Loan) error
+	CreateLoanApproval(ctx context.Context, approval *LoanApproval) error
+	// FetchCreditScorecard returns the latest version of the scorecard
+	FetchCreditScorecard(ctx context.Context) (*CreditScorecard, error)
+	// CreateCreditScorecard stores the scorecard as the next version
+	CreateCreditScorecard(ctx context.Context, scorecard *CreditScorecard) error
+	// FetchCreditAssessments lists the assessments of the loan, latest first
+	FetchCreditAssessments(ctx context.Context, loanID uint) ([]CreditAssessment, error)
+	CreateCreditAssessment(ctx context.Context, assessment *CreditAssessment) error
+	// FetchLoanPricings lists the pricing decisions of the loan, latest first
+	FetchLoanPricings(ctx context.Context, loanID uint) ([]LoanPricing, error)
+	CreateLoanPricing(ctx context.Context, pricing *LoanPricing) error
 	InvestInLoan(ctx context.Context, loan *Loan, investor *User, amount float64) error
 	GetTotalInvestedAmount(ctx context.Context, investorID *uint) (float64, error)
 }
@@ -117,8 +216,76 @@ type LoanUsecase interface {
 	FetchLoansByUserID(ctx context.Context, userID uint) ([]Loan, error)
 	FetchLoanByID(ctx context.Context, loanID uint, opts *FetchLoanOpts) (*Loan, error)
 	StartLoan(ctx context.Context, name string, product *Product, borrower *User) (*Loan, error)
-	MarkLoanBorrowerVisited(ctx context.Context, loan *Loan, visitor *User, attachment io.Reader) error
-	ApproveLoan(ctx context.Context, loan *Loan, approver *User) error
+	// MarkLoanBorrowerVisited verifies the visit against the borrower's address, the location reported by the device
+	// is used when the photo has none. The report answers the visit checklist of the loan's product, if it has one.
+	MarkLoanBorrowerVisited(
+		ctx context.Context,
+		loan *Loan,
+		visitor *User,
+		attachment io.Reader,
+		reported *VisitLocation,
+		report *VisitReportInput,
+	) error
+	// SyncVisits applies visits queued offline by the field validator, in order. Visits already synced are not applied
+	// again, the outcome recorded for them is returned instead.
+	SyncVisits(ctx context.Context, fieldValidator *User, items []VisitSyncItem) ([]VisitSyncResult, error)
+	// ReviewLoanVisit accepts a visit flagged for review, or rejects it so the borrower is visited again
+	ReviewLoanVisit(ctx context.Context, loan *Loan, reviewer *User, accept bool, note string) error
+	// Document methods take a loan the caller has already fetched with its scope, and the caller's permissions
+	FetchLoanDocuments(ctx context.Context, loan *Loan, permissions []auth.Permission, opts *FetchLoanDocumentsOpts) ([]LoanDocument, error)
+	UploadLoanDocument(
+		ctx context.Context,
+		loan *Loan,
+		uploader *User,
+		permissions []auth.Permission,
+		documentType LoanDocumentType,
+		file io.Reader,
+	) (*LoanDocument, error)
+	DownloadLoanDocument(
+		ctx context.Context,
+		loan *Loan,
+		permissions []auth.Permission,
+		documentID uint,
+		variant LoanDocumentVariant,
+	) (io.ReadCloser, *upload.UploadedFile, error)
+	// AssignLoan assigns the visit to the field validator, or to one picked by the configured strategy if no ID is
+	// given. An assigned loan is reassigned.
+	AssignLoan(
+		ctx context.Context,
+		loan *Loan,
+		actor *User,
+		fieldValidatorID uint,
+		scheduledAt *time.Time,
+		note string,
+	) (*LoanAssignment, error)
+	// AutoAssignLoans assigns every proposed loan still waiting for a visit and a field validator
+	AutoAssignLoans(ctx context.Context) ([]LoanAssignment, error)
+	FetchLoanAssignments(ctx context.Context, opts *FetchLoanAssignmentsOpts) ([]LoanAssignment, error)
+	// ScheduleLoanVisit books a visit slot for one of the field validator's active assignments
+	ScheduleLoanVisit(ctx context.Context, fieldValidator *User, assignmentID uint, scheduledAt time.Time) (*LoanAssignment, error)
+	// ReportOverdueVisits notifies field validators of visits past their due time, once for each assignment
+	ReportOverdueVisits(ctx context.Context) (int, error)
+	// ApproveLoan records the approver's vote, the loan is approved once the votes satisfy the approval policy that
+	// applies to it
+	ApproveLoan(ctx context.Context, loan *Loan, approver *User, comment string) (*ApprovalStatus, error)
+	// RejectLoan records the approver's rejection, the approval starts over and earlier votes no longer count
+	RejectLoan(ctx context.Context, loan *Loan, approver *User, comment string) (*ApprovalStatus, error)
+	FetchLoanApprovalStatus(ctx context.Context, loan *Loan) (*ApprovalStatus, error)
+	// FetchPendingApprovals lists the loans waiting for approval votes, with how far each is from approval
+	FetchPendingApprovals(ctx context.Context, opts *FetchLoanOpts) ([]PendingApproval, error)
+	FetchApprovalPolicies(ctx context.Context) ([]ApprovalPolicy, error)
+	SetApprovalPolicies(ctx context.Context, policies []ApprovalPolicy) ([]ApprovalPolicy, error)
+	// AssessLoanCredit scores the proposed loan again with the latest scorecard, e.g. once the scorecard changed
+	AssessLoanCredit(ctx context.Context, loan *Loan, assessor *User) (*CreditAssessment, error)
+	FetchCreditAssessments(ctx context.Context, loan *Loan) ([]CreditAssessment, error)
+	// FetchCreditScorecard returns the latest scorecard, or DefaultCreditScorecard if none was stored
+	FetchCreditScorecard(ctx context.Context) (*CreditScorecard, error)
+	// SetCreditScorecard stores the scorecard as the next version, loans already assessed keep their assessment
+	SetCreditScorecard(ctx context.Context, scorecard *CreditScorecard) (*CreditScorecard, error)
+	FetchLoanPricings(ctx context.Context, loan *Loan) ([]LoanPricing, error)
 	InvestInLoan(ctx context.Context, loan *Loan, investor *User, amount float64) error
 	DisburseLoan(ctx context.Context, loan *Loan, disburser *User) error
+	RemindDueInstallments(ctx context.Context) (int, error)
+	// SubscribeLoanEvents follows changes to the loan as they are committed, until unsubscribed
+	SubscribeLoanEvents(loanID uint) (<-chan LoanEvent, func())
 }
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"loan-service/services/auth"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const maxRequiredApprovals = 5
+
+// ApprovalPolicy is a tier of the approval policy, it applies to loans of at least its minimum principal amount. A
+// loan follows the tier with the highest minimum it reaches, or DefaultApprovalPolicy below every tier.
+type ApprovalPolicy struct {
+	gorm.Model
+	Name               string  `json:"name"`
+	MinPrincipalAmount float64 `json:"min_principal_amount"`
+	// Distinct approvers needed at the first level
+	RequiredApprovals int `json:"required_approvals"`
+	// The field validator who visited the borrower may approve the loan too
+	VisitorMayApprove bool `json:"visitor_may_approve"`
+	// A superuser approves once the first level is done, as a second level
+	RequireSuperuser bool `json:"require_superuser"`
+}
+
+func (ApprovalPolicy) TableName() string {
+	return "approval_policies"
+}
+
+// DefaultApprovalPolicy applies when no tier does: a single approval by anyone but the visitor
+var DefaultApprovalPolicy = ApprovalPolicy{Name: "default", RequiredApprovals: 1}
+
+// ValidateApprovalPolicies checks each tier needs between one and five approvals, and has a name and minimum amount of
+// its own
+func ValidateApprovalPolicies(policies []ApprovalPolicy) error {
+	names := map[string]bool{}
+	amounts := map[float64]bool{}
+	for i, policy := range policies {
+		name := strings.TrimSpace(policy.Name)
+		if name == "" {
+			return NewInvalidApprovalPolicyError("tier %d: name is required", i+1)
+		}
+
+		if names[name] {
+			return NewInvalidApprovalPolicyError("tier %d: duplicate name `%s`", i+1, name)
+		}
+		names[name] = true
+
+		if policy.MinPrincipalAmount < 0 {
+			return NewInvalidApprovalPolicyError("tier `%s`: minimum principal amount cannot be negative", name)
+		}
+
+		if amounts[policy.MinPrincipalAmount] {
+			return NewInvalidApprovalPolicyError("tier `%s`: another tier starts at the same amount", name)
+		}
+		amounts[policy.MinPrincipalAmount] = true
+
+		if policy.RequiredApprovals < 1 || policy.RequiredApprovals > maxRequiredApprovals {
+			return NewInvalidApprovalPolicyError("tier `%s`: required approvals must be between 1 and %d", name, maxRequiredApprovals)
+		}
+	}
+
+	return nil
+}
+
+// ApprovalPolicyFor returns the tier that applies to the loan
+func ApprovalPolicyFor(policies []ApprovalPolicy, loan *Loan) ApprovalPolicy {
+	principal, _ := strconv.ParseFloat(loan.PrincipalAmount, 64)
+
+	sorted := append([]ApprovalPolicy{}, policies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinPrincipalAmount > sorted[j].MinPrincipalAmount })
+	for _, policy := range sorted {
+		if principal >= policy.MinPrincipalAmount {
+			return policy
+		}
+	}
+
+	return DefaultApprovalPolicy
+}
+
+type ApprovalDecision string
+
+const (
+	ApprovalDecisionApprove ApprovalDecision = "approve"
+	// A rejection sends the loan back, the votes cast so far no longer count
+	ApprovalDecisionReject ApprovalDecision = "reject"
+)
+
+type ApprovalLevel int
+
+const (
+	ApprovalLevelFirst ApprovalLevel = 1
+	// Superuser sign-off, for tiers that require it
+	ApprovalLevelSecond ApprovalLevel = 2
+)
+
+// LoanApproval is a vote on the approval of a loan. Votes belong to the loan's approval round, a rejection starts a
+// new round.
+type LoanApproval struct {
+	gorm.Model
+	LoanID     uint             `json:"loan_id" gorm:"index"`
+	Round      int              `json:"round"`
+	ApproverID uint             `json:"approver_id"`
+	Approver   *User            `json:"approver" gorm:"foreignKey:ApproverID"`
+	Level      ApprovalLevel    `json:"level"`
+	Decision   ApprovalDecision `json:"decision"`
+	Comment    string           `json:"comment"`
+}
+
+func (LoanApproval) TableName() string {
+	return "loan_approvals"
+}
+
+// ApprovalStatus is how far the loan's current round is from satisfying its policy
+type ApprovalStatus struct {
+	Policy ApprovalPolicy
+	Round  int
+	// Approve votes of the round, in the order they were cast
+	Votes             []LoanApproval
+	Approvals         int
+	SuperuserApproved bool
+}
+
+// NewApprovalStatus counts the approve votes of the round
+func NewApprovalStatus(policy ApprovalPolicy, round int, votes []LoanApproval) *ApprovalStatus {
+	status := &ApprovalStatus{Policy: policy, Round: round, Votes: []LoanApproval{}}
+	for _, vote := range votes {
+		if vote.Round != round || vote.Decision != ApprovalDecisionApprove {
+			continue
+		}
+
+		status.Votes = append(status.Votes, vote)
+		switch vote.Level {
+		case ApprovalLevelFirst:
+			status.Approvals++
+		case ApprovalLevelSecond:
+			status.SuperuserApproved = true
+		}
+	}
+
+	return status
+}
+
+// NextLevel is the level the next approve vote counts for
+func (s *ApprovalStatus) NextLevel() ApprovalLevel {
+	if s.Approvals >= s.Policy.RequiredApprovals && s.Policy.RequireSuperuser {
+		return ApprovalLevelSecond
+	}
+
+	return ApprovalLevelFirst
+}
+
+// Satisfied returns true once every level of the policy is done
+func (s *ApprovalStatus) Satisfied() bool {
+	return s.Approvals >= s.Policy.RequiredApprovals && (!s.Policy.RequireSuperuser || s.SuperuserApproved)
+}
+
+// HasVoted returns true if the user already approved in this round
+func (s *ApprovalStatus) HasVoted(userID uint) bool {
+	for _, vote := range s.Votes {
+		if vote.ApproverID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsVisitorBarred returns true if the user visited the borrower, and the policy does not let visitors vote
+func (s *ApprovalStatus) IsVisitorBarred(loan *Loan, voter *User) bool {
+	return loan.VisitorID != nil && *loan.VisitorID == voter.ID && !s.Policy.VisitorMayApprove
+}
+
+// MayVoteAt returns false if the level needs a superuser and the user is not one
+func (s *ApprovalStatus) MayVoteAt(voter *User, level ApprovalLevel) bool {
+	return level != ApprovalLevelSecond || voter.Role.RoleType == auth.RoleTypeSuperuser
+}
+
+type PendingApproval struct {
+	Loan   Loan
+	Status ApprovalStatus
+}
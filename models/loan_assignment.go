@@ -0,0 +1,181 @@
+package models
+
+import (
+	"loan-service/services/email"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoanAssignmentStatus string
+
+const (
+	// Waiting for the field validator to visit the borrower
+	LoanAssignmentActive LoanAssignmentStatus = "active"
+	// The borrower was visited
+	LoanAssignmentCompleted LoanAssignmentStatus = "completed"
+	// Handed over to another field validator
+	LoanAssignmentReassigned LoanAssignmentStatus = "reassigned"
+)
+
+// AssignmentStrategy is how a field validator is picked when staff do not choose one
+type AssignmentStrategy string
+
+const (
+	AssignmentManual AssignmentStrategy = "manual"
+	// The field validator who was assigned a loan the longest time ago
+	AssignmentRoundRobin AssignmentStrategy = "round_robin"
+	// The least busy field validator covering the borrower's region, or anyone if no one covers it
+	AssignmentRegion AssignmentStrategy = "region"
+)
+
+const (
+	DefaultVisitSLA          = 72 * time.Hour
+	DefaultVisitSlotDuration = time.Hour
+)
+
+// LoanAssignment is a field validator's task to visit the borrower of a proposed loan. A loan has at most one active
+// assignment, previous ones are kept as its history.
+type LoanAssignment struct {
+	gorm.Model
+	LoanID           uint                 `json:"loan_id" gorm:"index"`
+	Loan             *Loan                `json:"loan,omitempty"`
+	FieldValidatorID uint                 `json:"field_validator_id" gorm:"index"`
+	FieldValidator   *User                `json:"field_validator,omitempty"`
+	Status           LoanAssignmentStatus `json:"status" gorm:"index"`
+	Strategy         AssignmentStrategy   `json:"strategy"`
+	// Nil when the assignment was automatic
+	AssignedByID *uint  `json:"assigned_by_id"`
+	AssignedBy   *User  `json:"assigned_by,omitempty"`
+	Note         string `json:"note"`
+	// Visit slot, the field validator schedules it unless staff did
+	ScheduledAt    *time.Time `json:"scheduled_at"`
+	ScheduledUntil *time.Time `json:"scheduled_until"`
+	// The visit is overdue past this time
+	DueAt time.Time `json:"due_at"`
+	// Set once the overdue visit was reported, so it is reported only once
+	OverdueAt   *time.Time `json:"overdue_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (LoanAssignment) TableName() string {
+	return "loan_assignments"
+}
+
+// Overdue returns true if the visit is still to be done past its due time
+func (a *LoanAssignment) Overdue(now time.Time) bool {
+	return a.Status == LoanAssignmentActive && now.After(a.DueAt)
+}
+
+// Schedule books the visit slot starting at the time
+func (a *LoanAssignment) Schedule(scheduledAt time.Time, slotDuration time.Duration) {
+	scheduledUntil := scheduledAt.Add(slotDuration)
+	a.ScheduledAt = &scheduledAt
+	a.ScheduledUntil = &scheduledUntil
+}
+
+// SlotOverlaps returns true if both assignments are scheduled in overlapping visit slots
+func (a *LoanAssignment) SlotOverlaps(other *LoanAssignment) bool {
+	if a.ScheduledAt == nil || a.ScheduledUntil == nil || other.ScheduledAt == nil || other.ScheduledUntil == nil {
+		return false
+	}
+
+	return a.ScheduledAt.Before(*other.ScheduledUntil) && other.ScheduledAt.Before(*a.ScheduledUntil)
+}
+
+// Notification to the field validator of a new visit to make
+func (a *LoanAssignment) NewAssignedNotification(fieldValidator *User) *Notification {
+	data := email.VisitAssignedData{
+		Name:         fieldValidator.Name,
+		LoanName:     a.Loan.Name,
+		BorrowerName: a.Loan.Borrower.Name,
+		Address:      a.Loan.Borrower.Address,
+		DueAt:        a.DueAt,
+	}
+
+	if a.ScheduledAt != nil {
+		data.ScheduledAt = *a.ScheduledAt
+	}
+
+	return &Notification{Event: NotificationEventVisitAssigned, LoanID: a.LoanID, Data: data}
+}
+
+// Notification to the field validator that the visit is past its due time
+func (a *LoanAssignment) NewOverdueNotification() *Notification {
+	return &Notification{
+		Event:  NotificationEventVisitOverdue,
+		LoanID: a.LoanID,
+		Data: email.VisitOverdueData{
+			Name:         a.FieldValidator.Name,
+			LoanName:     a.Loan.Name,
+			BorrowerName: a.Loan.Borrower.Name,
+			DueAt:        a.DueAt,
+		},
+	}
+}
+
+// FieldValidatorWorkload is an active field validator with their current assignments, to pick one for a new visit
+type FieldValidatorWorkload struct {
+	FieldValidatorID uint
+	Region           string
+	OpenAssignments  int64
+	LastAssignedAt   *time.Time
+}
+
+// Pick returns the field validator to assign the loan to, or nil if there is no candidate. The returned workload
+// points into the candidates, so the caller can count the new assignment in it.
+func (s AssignmentStrategy) Pick(loan *Loan, candidates []FieldValidatorWorkload) *FieldValidatorWorkload {
+	inRegion := func(candidate *FieldValidatorWorkload) bool {
+		return candidate.Region != "" && strings.EqualFold(candidate.Region, loan.Borrower.Region)
+	}
+
+	regional := false
+	if s == AssignmentRegion {
+		for i := range candidates {
+			regional = regional || inRegion(&candidates[i])
+		}
+	}
+
+	var picked *FieldValidatorWorkload
+	for i := range candidates {
+		candidate := &candidates[i]
+		if regional && !inRegion(candidate) {
+			continue
+		}
+
+		if picked == nil || s.ranksBefore(candidate, picked) {
+			picked = candidate
+		}
+	}
+
+	return picked
+}
+
+func (s AssignmentStrategy) ranksBefore(a, b *FieldValidatorWorkload) bool {
+	if s == AssignmentRegion && a.OpenAssignments != b.OpenAssignments {
+		return a.OpenAssignments < b.OpenAssignments
+	}
+
+	// Never assigned comes first, then the longest ago
+	switch {
+	case a.LastAssignedAt == nil && b.LastAssignedAt != nil:
+		return true
+	case a.LastAssignedAt != nil && b.LastAssignedAt == nil:
+		return false
+	case a.LastAssignedAt != nil && !a.LastAssignedAt.Equal(*b.LastAssignedAt):
+		return a.LastAssignedAt.Before(*b.LastAssignedAt)
+	}
+
+	return a.FieldValidatorID < b.FieldValidatorID
+}
+
+type FetchLoanAssignmentsOpts struct {
+	LoanID           uint
+	FieldValidatorID uint
+	Status           []LoanAssignmentStatus
+	// Active assignments past their due time
+	Overdue bool
+	// Overdue assignments not reported yet
+	Unreported bool
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"loan-service/services/attachment"
+	"loan-service/services/auth"
+
+	"gorm.io/gorm"
+)
+
+type LoanDocumentType string
+
+const (
+	LoanDocumentProofOfVisit        LoanDocumentType = "proof_of_visit"
+	LoanDocumentIDCard              LoanDocumentType = "id_card"
+	LoanDocumentBusinessPhoto       LoanDocumentType = "business_photo"
+	LoanDocumentSignedAgreement     LoanDocumentType = "signed_agreement"
+	LoanDocumentDisbursementReceipt LoanDocumentType = "disbursement_receipt"
+	// Photo answering a visit checklist question, labelled with the question's key
+	LoanDocumentVisitReportPhoto LoanDocumentType = "visit_report_photo"
+)
+
+// LoanDocumentUploadPermissions lists, for each document type, the permissions allowed to upload it
+var LoanDocumentUploadPermissions = map[LoanDocumentType][]auth.Permission{
+	LoanDocumentProofOfVisit:        {auth.PermissionLoanVisit},
+	LoanDocumentIDCard:              {auth.PermissionLoanVisit, auth.PermissionLoanCreate},
+	LoanDocumentBusinessPhoto:       {auth.PermissionLoanVisit, auth.PermissionLoanCreate},
+	LoanDocumentSignedAgreement:     {auth.PermissionLoanDisburse, auth.PermissionLoanCreate},
+	LoanDocumentDisbursementReceipt: {auth.PermissionLoanDisburse},
+	LoanDocumentVisitReportPhoto:    {auth.PermissionLoanVisit},
+}
+
+// loanDocumentContentTypes lists the content types accepted for each document type
+var loanDocumentContentTypes = map[LoanDocumentType][]string{
+	LoanDocumentProofOfVisit:        {"image/jpeg", "image/png"},
+	LoanDocumentIDCard:              {"image/jpeg", "image/png"},
+	LoanDocumentBusinessPhoto:       {"image/jpeg", "image/png"},
+	LoanDocumentSignedAgreement:     {"image/jpeg", "image/png", "application/pdf"},
+	LoanDocumentDisbursementReceipt: {"image/jpeg", "image/png", "application/pdf"},
+	LoanDocumentVisitReportPhoto:    {"image/jpeg", "image/png"},
+}
+
+// Valid returns true for the known document types
+func (t LoanDocumentType) Valid() bool {
+	_, ok := LoanDocumentUploadPermissions[t]
+	return ok
+}
+
+// ContentTypes returns the content types accepted for the document type
+func (t LoanDocumentType) ContentTypes() []string {
+	return loanDocumentContentTypes[t]
+}
+
+// VisibleTo returns true if a user with the permissions may see documents of this type on a loan they can see.
+// ID cards are personal data, so investors never see them. Proofs of visit show the borrower's premises, so they are
+// only for staff and field validators, and visit report photos are only for those who see the report.
+func (t LoanDocumentType) VisibleTo(permissions []auth.Permission) bool {
+	switch t {
+	case LoanDocumentProofOfVisit:
+		return auth.HasPermission(permissions, auth.PermissionLoanViewAll, auth.PermissionLoanViewProposed)
+	case LoanDocumentIDCard:
+		return auth.HasPermission(
+			permissions,
+			auth.PermissionLoanViewAll,
+			auth.PermissionLoanViewProposed,
+			auth.PermissionLoanViewOwn,
+		)
+	case LoanDocumentVisitReportPhoto:
+		return VisitReportVisibleTo(permissions)
+	default:
+		return true
+	}
+}
+
+type LoanDocumentVariant string
+
+const (
+	LoanDocumentFile      LoanDocumentVariant = "file"
+	LoanDocumentThumbnail LoanDocumentVariant = "thumbnail"
+)
+
+// LoanDocument is a file attached to a loan. Uploading a document of a type the loan already has supersedes it
+// with the next version, and the previous versions are kept.
+type LoanDocument struct {
+	gorm.Model
+	LoanID       uint             `json:"loan_id" gorm:"uniqueIndex:idx_loan_documents_version"`
+	DocumentType LoanDocumentType `json:"document_type" gorm:"uniqueIndex:idx_loan_documents_version"`
+	Version      int              `json:"version" gorm:"uniqueIndex:idx_loan_documents_version"`
+	// Tells apart the documents of a type a loan has several of, e.g. the photo answering each visit checklist
+	// question. Only documents with the same label supersede each other, versions are numbered across labels.
+	Label string `json:"label" gorm:"not null;default:''"`
+	// Unknown for documents migrated from before they were tracked
+	UploaderID     *uint `json:"uploader_id"`
+	Uploader       *User `json:"uploader" gorm:"foreignKey:UploaderID;default:null"`
+	SupersedesID   *uint `json:"supersedes_id"`
+	SupersededByID *uint `json:"superseded_by_id" gorm:"index"`
+	// Storage keys, only exposed through signed download URLs
+	FileKey      string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	// Extracted from the upload before its EXIF was stripped
+	Metadata *attachment.Metadata `json:"metadata" gorm:"type:jsonb"`
+}
+
+func (LoanDocument) TableName() string {
+	return "loan_documents"
+}
+
+// Key returns the storage key of the variant, empty if the document does not have it
+func (d *LoanDocument) Key(variant LoanDocumentVariant) string {
+	switch variant {
+	case LoanDocumentFile:
+		return d.FileKey
+	case LoanDocumentThumbnail:
+		return d.ThumbnailKey
+	default:
+		return ""
+	}
+}
+
+// DownloadPath returns the path of the variant's download endpoint
+func (d *LoanDocument) DownloadPath(variant LoanDocumentVariant) string {
+	return fmt.Sprintf("/app/loans/%d/documents/%d/%s", d.LoanID, d.ID, variant)
+}
+
+type FetchLoanDocumentsOpts struct {
+	// Only the current version of each document type is returned unless set
+	IncludeSuperseded bool
+}
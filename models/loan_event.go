@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type LoanEventType string
+
+const (
+	// Sent when a client subscribes, with the loan as it is at that moment
+	LoanEventSnapshot LoanEventType = "snapshot"
+	// An investment was committed
+	LoanEventFundingProgress LoanEventType = "funding_progress"
+	// The loan moved to another state
+	LoanEventStatusChanged LoanEventType = "status_changed"
+)
+
+// LoanEvent is a committed change to a loan, published to clients following the loan in real time
+type LoanEvent struct {
+	Type            LoanEventType
+	LoanID          uint
+	Status          LoanStatus
+	PrincipalAmount string
+	RemainingAmount string
+	OccurredAt      time.Time
+}
+
+// NewEvent returns an event of the loan's current state
+func (l *Loan) NewEvent(eventType LoanEventType) LoanEvent {
+	return LoanEvent{
+		Type:            eventType,
+		LoanID:          l.ID,
+		Status:          l.Status,
+		PrincipalAmount: l.PrincipalAmount,
+		RemainingAmount: l.RemainingAmount,
+		OccurredAt:      time.Now(),
+	}
+}
+
+// LoanEventBroker fans out loan events to subscribers of each loan
+type LoanEventBroker interface {
+	Publish(loanID uint, event LoanEvent)
+	Subscribe(loanID uint) (<-chan LoanEvent, func())
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"loan-service/services/email"
+	"strconv"
+	"time"
+)
+
+// Borrowers are reminded this long before each installment is due
+const InstallmentReminderLeadTime = time.Hour * 24 * 3
+
+// InstallmentDueAt returns the due date of the nth installment, counted from 1
+func (l *Loan) InstallmentDueAt(n int) time.Time {
+	if l.DisbursedAt == nil {
+		return time.Time{}
+	}
+
+	return l.DisbursedAt.AddDate(0, n, 0)
+}
+
+// InstallmentAmount returns the principal and total interest split evenly over the loan term
+func (l *Loan) InstallmentAmount() string {
+	principal, _ := strconv.ParseFloat(l.PrincipalAmount, 64)
+	totalInterest, _ := strconv.ParseFloat(l.TotalInterest, 64)
+	if l.LoanTerm <= 0 {
+		return fmt.Sprintf("%.2f", principal+totalInterest)
+	}
+
+	return fmt.Sprintf("%.2f", (principal+totalInterest)/float64(l.LoanTerm))
+}
+
+// NextInstallmentReminder returns the upcoming installment the borrower should be reminded of at the time, if any.
+// Installments already past due are skipped, so a late run never sends a burst of stale reminders.
+func (l *Loan) NextInstallmentReminder(now time.Time) (int, bool) {
+	if l.Status != LoanStatusDisbursed || l.DisbursedAt == nil {
+		return 0, false
+	}
+
+	n := l.InstallmentRemindersSent + 1
+	for n <= l.LoanTerm && l.InstallmentDueAt(n).Before(now) {
+		n++
+	}
+
+	if n > l.LoanTerm || now.Before(l.InstallmentDueAt(n).Add(-InstallmentReminderLeadTime)) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// Notification to the borrower that the loan is approved and open to investors
+func (l *Loan) NewApprovedNotification() *Notification {
+	return &Notification{
+		Event:  NotificationEventLoanApproved,
+		LoanID: l.ID,
+		Data: email.LoanApprovedData{
+			Name:            l.Borrower.Name,
+			LoanName:        l.Name,
+			PrincipalAmount: l.PrincipalAmount,
+		},
+	}
+}
+
+// Notification to an investor when the loan is fully invested, with the loan agreement letter attached to the email
+func (l *Loan) NewFundedNotification(investor *User, agreementLetter []byte) *Notification {
+	return &Notification{
+		Event:  NotificationEventLoanFunded,
+		LoanID: l.ID,
+		Data: email.LoanFundedData{
+			Name:            investor.Name,
+			LoanName:        l.Name,
+			BorrowerName:    l.Borrower.Name,
+			PrincipalAmount: l.PrincipalAmount,
+		},
+		AttachmentData:        agreementLetter,
+		AttachmentContentType: email.AttachmentTypePDF,
+		AttachmentFilename: fmt.Sprintf("Loan_Agreement_Letter-%s-%s-%s",
+			l.Name, l.Borrower.Name, l.UpdatedAt.Format(time.RFC3339),
+		),
+	}
+}
+
+// Notification to the borrower that the principal has been disbursed, with the first installment due date
+func (l *Loan) NewDisbursedNotification() *Notification {
+	return &Notification{
+		Event:  NotificationEventLoanDisbursed,
+		LoanID: l.ID,
+		Data: email.LoanDisbursedData{
+			Name:                  l.Borrower.Name,
+			LoanName:              l.Name,
+			PrincipalAmount:       l.PrincipalAmount,
+			DisbursedAt:           *l.DisbursedAt,
+			FirstInstallmentDueAt: l.InstallmentDueAt(1),
+		},
+	}
+}
+
+// Notification to the borrower that the nth installment is due soon
+func (l *Loan) NewInstallmentDueNotification(n int) *Notification {
+	return &Notification{
+		Event:  NotificationEventInstallmentDue,
+		LoanID: l.ID,
+		Data: email.InstallmentDueData{
+			Name:              l.Borrower.Name,
+			LoanName:          l.Name,
+			InstallmentNumber: n,
+			LoanTerm:          l.LoanTerm,
+			Amount:            l.InstallmentAmount(),
+			DueAt:             l.InstallmentDueAt(n),
+		},
+	}
+}
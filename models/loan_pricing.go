@@ -0,0 +1,74 @@
+package models
+
+import (
+	"loan-service/utils/money"
+
+	"gorm.io/gorm"
+)
+
+type PricingTrigger string
+
+const (
+	PricingOnApplication PricingTrigger = "application"
+	// The loan is priced again with its latest risk grade, before it is offered to investors
+	PricingOnApproval PricingTrigger = "approval"
+)
+
+type PricingSource string
+
+const (
+	PricingFromRateGrid PricingSource = "rate_grid"
+	// The product has no rate for the grade, or the loan has no grade
+	PricingFromProductRate PricingSource = "product_rate"
+)
+
+// LoanPricing records how the loan's interest rate was decided, for audit
+type LoanPricing struct {
+	gorm.Model
+	LoanID      uint           `json:"loan_id" gorm:"index"`
+	Trigger     PricingTrigger `json:"trigger"`
+	RiskGrade   RiskGrade      `json:"risk_grade"`
+	CreditScore *int           `json:"credit_score"`
+	Source      PricingSource  `json:"source"`
+	// Interest rate of the product at the time
+	ProductRate float64 `json:"product_rate"`
+	// Interest rate of the loan before it was priced
+	PreviousInterestRate float64 `json:"previous_interest_rate"`
+	InterestRate         float64 `json:"interest_rate"`
+	TotalInterest        string  `json:"total_interest"`
+	ROI                  string  `json:"roi"`
+	// Approver who priced the loan, nil when priced on application
+	ActorID *uint `json:"actor_id"`
+	Actor   *User `json:"actor" gorm:"foreignKey:ActorID"`
+}
+
+func (LoanPricing) TableName() string {
+	return "loan_pricings"
+}
+
+// Price sets the interest rate of the loan's risk grade on the product, and recomputes the interest and ROI. The
+// decision is returned to be recorded.
+func (l *Loan) Price(product *Product, trigger PricingTrigger, actor *User) *LoanPricing {
+	rate, source := product.RateFor(l.RiskGrade)
+	pricing := &LoanPricing{
+		LoanID:               l.ID,
+		Trigger:              trigger,
+		RiskGrade:            l.RiskGrade,
+		CreditScore:          l.CreditScore,
+		Source:               source,
+		ProductRate:          product.InterestRate,
+		PreviousInterestRate: l.InterestRate,
+		InterestRate:         rate,
+	}
+
+	if actor != nil {
+		pricing.ActorID = &actor.ID
+		pricing.Actor = actor
+	}
+
+	l.InterestRate = rate
+	l.ROI, l.TotalInterest = money.CalculateROI(l.PrincipalAmount, rate, l.LoanTerm)
+	pricing.TotalInterest, pricing.ROI = l.TotalInterest, l.ROI
+
+	return pricing
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"loan-service/services/attachment"
+	"loan-service/utils/geo"
+	"time"
+)
+
+type VisitReviewStatus string
+
+const (
+	// Passed every check, no review needed
+	VisitReviewVerified VisitReviewStatus = "verified"
+	// Flagged, the loan cannot be approved until staff accept the visit
+	VisitReviewPending  VisitReviewStatus = "pending_review"
+	VisitReviewAccepted VisitReviewStatus = "accepted"
+	// The borrower must be visited again
+	VisitReviewRejected VisitReviewStatus = "rejected"
+)
+
+type VisitFlag string
+
+const (
+	VisitFlagLocationMissing VisitFlag = "location_missing"
+	// The borrower has no coordinates to compare with
+	VisitFlagAddressUnknown VisitFlag = "address_unknown"
+	VisitFlagTooFar         VisitFlag = "too_far"
+	// The photo was taken somewhere else than the device reported
+	VisitFlagLocationMismatch    VisitFlag = "location_mismatch"
+	VisitFlagCaptureTimeMissing  VisitFlag = "capture_time_missing"
+	VisitFlagStale               VisitFlag = "stale"
+	VisitFlagCaptureTimeInFuture VisitFlag = "capture_time_in_future"
+)
+
+// VisitFlags are the reasons a visit needs review
+type VisitFlags []VisitFlag
+
+// Value implements driver.Valuer, flags are stored as JSON
+func (f VisitFlags) Value() (driver.Value, error) {
+	if f == nil {
+		return json.Marshal([]VisitFlag{})
+	}
+
+	return json.Marshal([]VisitFlag(f))
+}
+
+// Scan implements sql.Scanner.
+func (f *VisitFlags) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, f)
+	case string:
+		return json.Unmarshal([]byte(value), f)
+	default:
+		return fmt.Errorf("cannot scan %T into visit flags", value)
+	}
+}
+
+type VisitLocationSource string
+
+const (
+	VisitLocationFromPhoto   VisitLocationSource = "exif"
+	VisitLocationFromRequest VisitLocationSource = "request"
+)
+
+// VisitLocation is where and when the proof of visit was taken, as reported by the field validator's device
+type VisitLocation struct {
+	Latitude   *float64
+	Longitude  *float64
+	CapturedAt *time.Time
+}
+
+const (
+	DefaultVisitMaxDistanceMeters = 500
+	DefaultVisitMaxAge            = 24 * time.Hour
+
+	// Device clocks drift, so capture times slightly ahead of the server are tolerated
+	visitClockSkew = 5 * time.Minute
+)
+
+// VisitPolicy bounds how far from the borrower's address, and how long ago, a proof of visit may be taken.
+// Zero values fall back to the defaults.
+type VisitPolicy struct {
+	MaxDistanceMeters float64
+	MaxAge            time.Duration
+}
+
+// LoanVisit is the verified location of a loan's proof of visit, and its review by staff
+type LoanVisit struct {
+	Latitude       *float64            `json:"latitude"`
+	Longitude      *float64            `json:"longitude"`
+	CapturedAt     *time.Time          `json:"captured_at"`
+	LocationSource VisitLocationSource `json:"location_source"`
+	// From the borrower's registered address
+	DistanceMeters *float64          `json:"distance_meters"`
+	Flags          VisitFlags        `json:"flags" gorm:"type:jsonb"`
+	ReviewStatus   VisitReviewStatus `json:"review_status"`
+	ReviewerID     *uint             `json:"reviewer_id"`
+	ReviewedAt     *time.Time        `json:"reviewed_at"`
+	ReviewNote     string            `json:"review_note"`
+	// Completed visit checklist, nil for products without one
+	Report *VisitReport `json:"report" gorm:"type:jsonb"`
+}
+
+// Approvable returns false while the visit waits for review or was rejected. Loans visited before visits were
+// verified have no status and stay approvable.
+func (v *LoanVisit) Approvable() bool {
+	return v.ReviewStatus != VisitReviewPending && v.ReviewStatus != VisitReviewRejected
+}
+
+// VerifyVisit records where and when the proof of visit was taken, and flags it for review when it breaks the policy.
+// The photo's EXIF is preferred over the location reported with the request, which is only a fallback.
+func (l *Loan) VerifyVisit(photo *attachment.Metadata, reported *VisitLocation, policy VisitPolicy, now time.Time) {
+	if policy.MaxDistanceMeters <= 0 {
+		policy.MaxDistanceMeters = DefaultVisitMaxDistanceMeters
+	}
+
+	if policy.MaxAge <= 0 {
+		policy.MaxAge = DefaultVisitMaxAge
+	}
+
+	// The report is answered with the visit, not verified with its photo
+	visit := LoanVisit{Report: l.Visit.Report}
+	if reported == nil {
+		reported = &VisitLocation{}
+	}
+
+	photoLocated := photo != nil && photo.Latitude != nil && photo.Longitude != nil
+	reportedLocated := reported.Latitude != nil && reported.Longitude != nil
+
+	switch {
+	case photoLocated:
+		visit.Latitude, visit.Longitude = photo.Latitude, photo.Longitude
+		visit.LocationSource = VisitLocationFromPhoto
+
+		if reportedLocated &&
+			geo.Distance(*photo.Latitude, *photo.Longitude, *reported.Latitude, *reported.Longitude) > policy.MaxDistanceMeters {
+			visit.Flags = append(visit.Flags, VisitFlagLocationMismatch)
+		}
+	case reportedLocated:
+		visit.Latitude, visit.Longitude = reported.Latitude, reported.Longitude
+		visit.LocationSource = VisitLocationFromRequest
+	default:
+		visit.Flags = append(visit.Flags, VisitFlagLocationMissing)
+	}
+
+	if visit.Latitude != nil {
+		if l.Borrower.AddressLatitude == nil || l.Borrower.AddressLongitude == nil {
+			visit.Flags = append(visit.Flags, VisitFlagAddressUnknown)
+		} else {
+			distance := geo.Distance(*visit.Latitude, *visit.Longitude, *l.Borrower.AddressLatitude, *l.Borrower.AddressLongitude)
+			visit.DistanceMeters = &distance
+			if distance > policy.MaxDistanceMeters {
+				visit.Flags = append(visit.Flags, VisitFlagTooFar)
+			}
+		}
+	}
+
+	visit.CapturedAt = reported.CapturedAt
+	if photo != nil && photo.CapturedAt != nil {
+		visit.CapturedAt = photo.CapturedAt
+	}
+
+	switch {
+	case visit.CapturedAt == nil:
+		visit.Flags = append(visit.Flags, VisitFlagCaptureTimeMissing)
+	case visit.CapturedAt.After(now.Add(visitClockSkew)):
+		visit.Flags = append(visit.Flags, VisitFlagCaptureTimeInFuture)
+	case now.Sub(*visit.CapturedAt) > policy.MaxAge:
+		visit.Flags = append(visit.Flags, VisitFlagStale)
+	}
+
+	visit.ReviewStatus = VisitReviewVerified
+	if len(visit.Flags) > 0 {
+		visit.ReviewStatus = VisitReviewPending
+	}
+
+	l.Visit = visit
+	l.VisitedAt = &now
+}
+
+// RetakeVisit verifies a replaced proof of visit against the location reported with the visit, if any. A visit that
+// was flagged or reviewed waits for staff review again whatever the new photo shows, keeping its last review for
+// reference, and votes cast on the previous proof no longer count.
+func (l *Loan) RetakeVisit(photo *attachment.Metadata, policy VisitPolicy, now time.Time) {
+	previous := l.Visit
+
+	var reported *VisitLocation
+	if previous.LocationSource == VisitLocationFromRequest {
+		reported = &VisitLocation{Latitude: previous.Latitude, Longitude: previous.Longitude}
+	}
+
+	l.VerifyVisit(photo, reported, policy, now)
+	l.Visit.ReviewerID = previous.ReviewerID
+	l.Visit.ReviewedAt = previous.ReviewedAt
+	l.Visit.ReviewNote = previous.ReviewNote
+
+	if previous.ReviewStatus != "" && previous.ReviewStatus != VisitReviewVerified {
+		l.Visit.ReviewStatus = VisitReviewPending
+	}
+
+	l.ApprovalRound++
+}
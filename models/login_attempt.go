@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// Failed password logins before the account is temporarily locked
+	MaxFailedLoginAttempts = 5
+	// Failed password logins from a single IP address within LoginAttemptWindow before it is blocked
+	MaxFailedLoginAttemptsPerIP = 20
+
+	LoginAttemptWindow   = time.Minute * 15
+	LoginLockoutDuration = time.Minute * 15
+)
+
+// LoginAttempt records every password login, used for throttling and detecting logins from new devices
+type LoginAttempt struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	Email     string    `json:"email"`
+	IPAddress string    `json:"ip_address" gorm:"index"`
+	UserAgent string    `json:"user_agent"`
+	Succeeded bool      `json:"succeeded"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+type LoginOpts struct {
+	IPAddress string
+	UserAgent string
+}
+
+type CountLoginAttemptsOpts struct {
+	UserID    uint
+	IPAddress string
+	UserAgent string
+	Succeeded *bool
+	Since     time.Time
+}
+
+// LoginDelay returns how long a user must wait after their latest failed login, doubling on every failure
+func LoginDelay(failedAttempts int) time.Duration {
+	if failedAttempts < 2 {
+		return 0
+	}
+
+	return time.Second << (failedAttempts - 2)
+}
@@ -0,0 +1,151 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyRepository is an autogenerated mock type for the APIKeyRepository type
+type APIKeyRepository struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, apiKey
+func (_m *APIKeyRepository) CreateAPIKey(ctx context.Context, apiKey *models.APIKey) error {
+	ret := _m.Called(ctx, apiKey)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.APIKey) error); ok {
+		r0 = rf(ctx, apiKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAPIKeyByID provides a mock function with given fields: ctx, apiKeyID
+func (_m *APIKeyRepository) FetchAPIKeyByID(ctx context.Context, apiKeyID uint) (*models.APIKey, error) {
+	ret := _m.Called(ctx, apiKeyID)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.APIKey, error)); ok {
+		return rf(ctx, apiKeyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.APIKey); ok {
+		r0 = rf(ctx, apiKeyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, apiKeyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchAPIKeyByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *APIKeyRepository) FetchAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.APIKey, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.APIKey); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchAPIKeys provides a mock function with given fields: ctx, serviceAccountID
+func (_m *APIKeyRepository) FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]models.APIKey, error) {
+	ret := _m.Called(ctx, serviceAccountID)
+
+	var r0 []models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]models.APIKey, error)); ok {
+		return rf(ctx, serviceAccountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []models.APIKey); ok {
+		r0 = rf(ctx, serviceAccountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, serviceAccountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, apiKey, revokedAt
+func (_m *APIKeyRepository) RevokeAPIKey(ctx context.Context, apiKey *models.APIKey, revokedAt time.Time) error {
+	ret := _m.Called(ctx, apiKey, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.APIKey, time.Time) error); ok {
+		r0 = rf(ctx, apiKey, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAPIKeyLastUsedAt provides a mock function with given fields: ctx, apiKey, lastUsedAt
+func (_m *APIKeyRepository) UpdateAPIKeyLastUsedAt(ctx context.Context, apiKey *models.APIKey, lastUsedAt time.Time) error {
+	ret := _m.Called(ctx, apiKey, lastUsedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.APIKey, time.Time) error); ok {
+		r0 = rf(ctx, apiKey, lastUsedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyRepository {
+	mock := &APIKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,170 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	auth "loan-service/services/auth"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "loan-service/models"
+
+	time "time"
+)
+
+// APIKeyUsecase is an autogenerated mock type for the APIKeyUsecase type
+type APIKeyUsecase struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, rawKey
+func (_m *APIKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	ret := _m.Called(ctx, rawKey)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.APIKey, error)); ok {
+		return rf(ctx, rawKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.APIKey); ok {
+		r0 = rf(ctx, rawKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, serviceAccountID, actor, name, permissions, expiresAt
+func (_m *APIKeyUsecase) CreateAPIKey(ctx context.Context, serviceAccountID uint, actor *models.User, name string, permissions []auth.Permission, expiresAt *time.Time) (*models.APIKey, string, error) {
+	ret := _m.Called(ctx, serviceAccountID, actor, name, permissions, expiresAt)
+
+	var r0 *models.APIKey
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *models.User, string, []auth.Permission, *time.Time) (*models.APIKey, string, error)); ok {
+		return rf(ctx, serviceAccountID, actor, name, permissions, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *models.User, string, []auth.Permission, *time.Time) *models.APIKey); ok {
+		r0 = rf(ctx, serviceAccountID, actor, name, permissions, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *models.User, string, []auth.Permission, *time.Time) string); ok {
+		r1 = rf(ctx, serviceAccountID, actor, name, permissions, expiresAt)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint, *models.User, string, []auth.Permission, *time.Time) error); ok {
+		r2 = rf(ctx, serviceAccountID, actor, name, permissions, expiresAt)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateServiceAccount provides a mock function with given fields: ctx, actor, name, roleType
+func (_m *APIKeyUsecase) CreateServiceAccount(ctx context.Context, actor *models.User, name string, roleType auth.RoleType) (*models.User, error) {
+	ret := _m.Called(ctx, actor, name, roleType)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, auth.RoleType) (*models.User, error)); ok {
+		return rf(ctx, actor, name, roleType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, auth.RoleType) *models.User); ok {
+		r0 = rf(ctx, actor, name, roleType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, string, auth.RoleType) error); ok {
+		r1 = rf(ctx, actor, name, roleType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchAPIKeys provides a mock function with given fields: ctx, serviceAccountID
+func (_m *APIKeyUsecase) FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]models.APIKey, error) {
+	ret := _m.Called(ctx, serviceAccountID)
+
+	var r0 []models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]models.APIKey, error)); ok {
+		return rf(ctx, serviceAccountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []models.APIKey); ok {
+		r0 = rf(ctx, serviceAccountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, serviceAccountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, apiKeyID
+func (_m *APIKeyUsecase) RevokeAPIKey(ctx context.Context, apiKeyID uint) (*models.APIKey, error) {
+	ret := _m.Called(ctx, apiKeyID)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.APIKey, error)); ok {
+		return rf(ctx, apiKeyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.APIKey); ok {
+		r0 = rf(ctx, apiKeyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, apiKeyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAPIKeyUsecase creates a new instance of APIKeyUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyUsecase {
+	mock := &APIKeyUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KYCRepository is an autogenerated mock type for the KYCRepository type
+type KYCRepository struct {
+	mock.Mock
+}
+
+// CreateKYCDocument provides a mock function with given fields: ctx, document
+func (_m *KYCRepository) CreateKYCDocument(ctx context.Context, document *models.KYCDocument) error {
+	ret := _m.Called(ctx, document)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.KYCDocument) error); ok {
+		r0 = rf(ctx, document)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchKYCDocumentByID provides a mock function with given fields: ctx, documentID
+func (_m *KYCRepository) FetchKYCDocumentByID(ctx context.Context, documentID uint) (*models.KYCDocument, error) {
+	ret := _m.Called(ctx, documentID)
+
+	var r0 *models.KYCDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.KYCDocument, error)); ok {
+		return rf(ctx, documentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.KYCDocument); ok {
+		r0 = rf(ctx, documentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KYCDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, documentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchKYCProfileByUserID provides a mock function with given fields: ctx, userID
+func (_m *KYCRepository) FetchKYCProfileByUserID(ctx context.Context, userID uint) (*models.KYCProfile, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *models.KYCProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.KYCProfile, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.KYCProfile); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KYCProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchKYCProfiles provides a mock function with given fields: ctx, opts
+func (_m *KYCRepository) FetchKYCProfiles(ctx context.Context, opts *models.FetchKYCProfilesOpts) ([]models.KYCProfile, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.KYCProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchKYCProfilesOpts) ([]models.KYCProfile, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchKYCProfilesOpts) []models.KYCProfile); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.KYCProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchKYCProfilesOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveKYCProfile provides a mock function with given fields: ctx, profile
+func (_m *KYCRepository) SaveKYCProfile(ctx context.Context, profile *models.KYCProfile) error {
+	ret := _m.Called(ctx, profile)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.KYCProfile) error); ok {
+		r0 = rf(ctx, profile)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewKYCRepository creates a new instance of KYCRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKYCRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KYCRepository {
+	mock := &KYCRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,162 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	auth "loan-service/services/auth"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "loan-service/models"
+
+	upload "loan-service/services/upload"
+)
+
+// KYCUsecase is an autogenerated mock type for the KYCUsecase type
+type KYCUsecase struct {
+	mock.Mock
+}
+
+// DownloadKYCDocument provides a mock function with given fields: ctx, documentID, userID, permissions, variant
+func (_m *KYCUsecase) DownloadKYCDocument(ctx context.Context, documentID uint, userID uint, permissions []auth.Permission, variant models.LoanDocumentVariant) (io.ReadCloser, *upload.UploadedFile, error) {
+	ret := _m.Called(ctx, documentID, userID, permissions, variant)
+
+	var r0 io.ReadCloser
+	var r1 *upload.UploadedFile
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, []auth.Permission, models.LoanDocumentVariant) (io.ReadCloser, *upload.UploadedFile, error)); ok {
+		return rf(ctx, documentID, userID, permissions, variant)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, []auth.Permission, models.LoanDocumentVariant) io.ReadCloser); ok {
+		r0 = rf(ctx, documentID, userID, permissions, variant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, []auth.Permission, models.LoanDocumentVariant) *upload.UploadedFile); ok {
+		r1 = rf(ctx, documentID, userID, permissions, variant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*upload.UploadedFile)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint, uint, []auth.Permission, models.LoanDocumentVariant) error); ok {
+		r2 = rf(ctx, documentID, userID, permissions, variant)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchKYCProfile provides a mock function with given fields: ctx, userID
+func (_m *KYCUsecase) FetchKYCProfile(ctx context.Context, userID uint) (*models.KYCProfile, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *models.KYCProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.KYCProfile, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.KYCProfile); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KYCProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchKYCReviews provides a mock function with given fields: ctx
+func (_m *KYCUsecase) FetchKYCReviews(ctx context.Context) ([]models.KYCProfile, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.KYCProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.KYCProfile, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.KYCProfile); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.KYCProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReviewKYCProfile provides a mock function with given fields: ctx, profile, reviewer, approve, note
+func (_m *KYCUsecase) ReviewKYCProfile(ctx context.Context, profile *models.KYCProfile, reviewer *models.User, approve bool, note string) error {
+	ret := _m.Called(ctx, profile, reviewer, approve, note)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.KYCProfile, *models.User, bool, string) error); ok {
+		r0 = rf(ctx, profile, reviewer, approve, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmitKYCProfile provides a mock function with given fields: ctx, borrower, submission
+func (_m *KYCUsecase) SubmitKYCProfile(ctx context.Context, borrower *models.User, submission *models.KYCSubmission) (*models.KYCProfile, error) {
+	ret := _m.Called(ctx, borrower, submission)
+
+	var r0 *models.KYCProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.KYCSubmission) (*models.KYCProfile, error)); ok {
+		return rf(ctx, borrower, submission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.KYCSubmission) *models.KYCProfile); ok {
+		r0 = rf(ctx, borrower, submission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KYCProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, *models.KYCSubmission) error); ok {
+		r1 = rf(ctx, borrower, submission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewKYCUsecase creates a new instance of KYCUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKYCUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KYCUsecase {
+	mock := &KYCUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,61 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoanEventBroker is an autogenerated mock type for the LoanEventBroker type
+type LoanEventBroker struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: loanID, event
+func (_m *LoanEventBroker) Publish(loanID uint, event models.LoanEvent) {
+	_m.Called(loanID, event)
+}
+
+// Subscribe provides a mock function with given fields: loanID
+func (_m *LoanEventBroker) Subscribe(loanID uint) (<-chan models.LoanEvent, func()) {
+	ret := _m.Called(loanID)
+
+	var r0 <-chan models.LoanEvent
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(uint) (<-chan models.LoanEvent, func())); ok {
+		return rf(loanID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) <-chan models.LoanEvent); ok {
+		r0 = rf(loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan models.LoanEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) func()); ok {
+		r1 = rf(loanID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// NewLoanEventBroker creates a new instance of LoanEventBroker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoanEventBroker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoanEventBroker {
+	mock := &LoanEventBroker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,34 @@ type LoanRepository struct {
 	mock.Mock
 }
 
+// CreateCreditAssessment provides a mock function with given fields: ctx, assessment
+func (_m *LoanRepository) CreateCreditAssessment(ctx context.Context, assessment *models.CreditAssessment) error {
+	ret := _m.Called(ctx, assessment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CreditAssessment) error); ok {
+		r0 = rf(ctx, assessment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateCreditScorecard provides a mock function with given fields: ctx, scorecard
+func (_m *LoanRepository) CreateCreditScorecard(ctx context.Context, scorecard *models.CreditScorecard) error {
+	ret := _m.Called(ctx, scorecard)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CreditScorecard) error); ok {
+		r0 = rf(ctx, scorecard)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateLoan provides a mock function with given fields: ctx, loan
 func (_m *LoanRepository) CreateLoan(ctx context.Context, loan *models.Loan) error {
 	ret := _m.Called(ctx, loan)
@@ -28,6 +56,232 @@ func (_m *LoanRepository) CreateLoan(ctx context.Context, loan *models.Loan) err
 	return r0
 }
 
+// CreateLoanApproval provides a mock function with given fields: ctx, approval
+func (_m *LoanRepository) CreateLoanApproval(ctx context.Context, approval *models.LoanApproval) error {
+	ret := _m.Called(ctx, approval)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoanApproval) error); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateLoanAssignment provides a mock function with given fields: ctx, assignment
+func (_m *LoanRepository) CreateLoanAssignment(ctx context.Context, assignment *models.LoanAssignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoanAssignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateLoanDocument provides a mock function with given fields: ctx, document
+func (_m *LoanRepository) CreateLoanDocument(ctx context.Context, document *models.LoanDocument) error {
+	ret := _m.Called(ctx, document)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoanDocument) error); ok {
+		r0 = rf(ctx, document)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateLoanPricing provides a mock function with given fields: ctx, pricing
+func (_m *LoanRepository) CreateLoanPricing(ctx context.Context, pricing *models.LoanPricing) error {
+	ret := _m.Called(ctx, pricing)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoanPricing) error); ok {
+		r0 = rf(ctx, pricing)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateVisitSync provides a mock function with given fields: ctx, sync
+func (_m *LoanRepository) CreateVisitSync(ctx context.Context, sync *models.VisitSync) error {
+	ret := _m.Called(ctx, sync)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.VisitSync) error); ok {
+		r0 = rf(ctx, sync)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchApprovalPolicies provides a mock function with given fields: ctx
+func (_m *LoanRepository) FetchApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.ApprovalPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.ApprovalPolicy, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.ApprovalPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ApprovalPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCreditAssessments provides a mock function with given fields: ctx, loanID
+func (_m *LoanRepository) FetchCreditAssessments(ctx context.Context, loanID uint) ([]models.CreditAssessment, error) {
+	ret := _m.Called(ctx, loanID)
+
+	var r0 []models.CreditAssessment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]models.CreditAssessment, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []models.CreditAssessment); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CreditAssessment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCreditScorecard provides a mock function with given fields: ctx
+func (_m *LoanRepository) FetchCreditScorecard(ctx context.Context) (*models.CreditScorecard, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *models.CreditScorecard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.CreditScorecard, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.CreditScorecard); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CreditScorecard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchFieldValidatorWorkloads provides a mock function with given fields: ctx
+func (_m *LoanRepository) FetchFieldValidatorWorkloads(ctx context.Context) ([]models.FieldValidatorWorkload, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.FieldValidatorWorkload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.FieldValidatorWorkload, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.FieldValidatorWorkload); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FieldValidatorWorkload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanAssignmentByID provides a mock function with given fields: ctx, assignmentID
+func (_m *LoanRepository) FetchLoanAssignmentByID(ctx context.Context, assignmentID uint) (*models.LoanAssignment, error) {
+	ret := _m.Called(ctx, assignmentID)
+
+	var r0 *models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.LoanAssignment, error)); ok {
+		return rf(ctx, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.LoanAssignment); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanAssignments provides a mock function with given fields: ctx, opts
+func (_m *LoanRepository) FetchLoanAssignments(ctx context.Context, opts *models.FetchLoanAssignmentsOpts) ([]models.LoanAssignment, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanAssignmentsOpts) ([]models.LoanAssignment, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanAssignmentsOpts) []models.LoanAssignment); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchLoanAssignmentsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchLoanByID provides a mock function with given fields: ctx, loanID, opts
 func (_m *LoanRepository) FetchLoanByID(ctx context.Context, loanID uint, opts *models.FetchLoanOpts) (*models.Loan, error) {
 	ret := _m.Called(ctx, loanID, opts)
@@ -54,6 +308,84 @@ func (_m *LoanRepository) FetchLoanByID(ctx context.Context, loanID uint, opts *
 	return r0, r1
 }
 
+// FetchLoanDocumentByID provides a mock function with given fields: ctx, loanID, documentID
+func (_m *LoanRepository) FetchLoanDocumentByID(ctx context.Context, loanID uint, documentID uint) (*models.LoanDocument, error) {
+	ret := _m.Called(ctx, loanID, documentID)
+
+	var r0 *models.LoanDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*models.LoanDocument, error)); ok {
+		return rf(ctx, loanID, documentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *models.LoanDocument); ok {
+		r0 = rf(ctx, loanID, documentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoanDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, loanID, documentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanDocuments provides a mock function with given fields: ctx, loanID, opts
+func (_m *LoanRepository) FetchLoanDocuments(ctx context.Context, loanID uint, opts *models.FetchLoanDocumentsOpts) ([]models.LoanDocument, error) {
+	ret := _m.Called(ctx, loanID, opts)
+
+	var r0 []models.LoanDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *models.FetchLoanDocumentsOpts) ([]models.LoanDocument, error)); ok {
+		return rf(ctx, loanID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *models.FetchLoanDocumentsOpts) []models.LoanDocument); ok {
+		r0 = rf(ctx, loanID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *models.FetchLoanDocumentsOpts) error); ok {
+		r1 = rf(ctx, loanID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanPricings provides a mock function with given fields: ctx, loanID
+func (_m *LoanRepository) FetchLoanPricings(ctx context.Context, loanID uint) ([]models.LoanPricing, error) {
+	ret := _m.Called(ctx, loanID)
+
+	var r0 []models.LoanPricing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]models.LoanPricing, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []models.LoanPricing); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanPricing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchLoans provides a mock function with given fields: ctx, opts
 func (_m *LoanRepository) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts) ([]models.Loan, error) {
 	ret := _m.Called(ctx, opts)
@@ -80,6 +412,32 @@ func (_m *LoanRepository) FetchLoans(ctx context.Context, opts *models.FetchLoan
 	return r0, r1
 }
 
+// FetchVisitSyncs provides a mock function with given fields: ctx, fieldValidatorID, clientIDs
+func (_m *LoanRepository) FetchVisitSyncs(ctx context.Context, fieldValidatorID uint, clientIDs []string) ([]models.VisitSync, error) {
+	ret := _m.Called(ctx, fieldValidatorID, clientIDs)
+
+	var r0 []models.VisitSync
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) ([]models.VisitSync, error)); ok {
+		return rf(ctx, fieldValidatorID, clientIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) []models.VisitSync); ok {
+		r0 = rf(ctx, fieldValidatorID, clientIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.VisitSync)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []string) error); ok {
+		r1 = rf(ctx, fieldValidatorID, clientIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTotalInvestedAmount provides a mock function with given fields: ctx, investorID
 func (_m *LoanRepository) GetTotalInvestedAmount(ctx context.Context, investorID *uint) (float64, error) {
 	ret := _m.Called(ctx, investorID)
@@ -118,6 +476,34 @@ func (_m *LoanRepository) InvestInLoan(ctx context.Context, loan *models.Loan, i
 	return r0
 }
 
+// LockLoanApprovals provides a mock function with given fields: ctx, loan
+func (_m *LoanRepository) LockLoanApprovals(ctx context.Context, loan *models.Loan) error {
+	ret := _m.Called(ctx, loan)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) error); ok {
+		r0 = rf(ctx, loan)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceApprovalPolicies provides a mock function with given fields: ctx, policies
+func (_m *LoanRepository) ReplaceApprovalPolicies(ctx context.Context, policies []models.ApprovalPolicy) error {
+	ret := _m.Called(ctx, policies)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ApprovalPolicy) error); ok {
+		r0 = rf(ctx, policies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateLoan provides a mock function with given fields: ctx, loan
 func (_m *LoanRepository) UpdateLoan(ctx context.Context, loan *models.Loan) error {
 	ret := _m.Called(ctx, loan)
@@ -132,6 +518,20 @@ func (_m *LoanRepository) UpdateLoan(ctx context.Context, loan *models.Loan) err
 	return r0
 }
 
+// UpdateLoanAssignment provides a mock function with given fields: ctx, assignment
+func (_m *LoanRepository) UpdateLoanAssignment(ctx context.Context, assignment *models.LoanAssignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoanAssignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewLoanRepository creates a new instance of LoanRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLoanRepository(t interface {
@@ -4,11 +4,17 @@ package mocks
 
 import (
 	context "context"
+	auth "loan-service/services/auth"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 
 	models "loan-service/models"
+
+	time "time"
+
+	upload "loan-service/services/upload"
 )
 
 // LoanUsecase is an autogenerated mock type for the LoanUsecase type
@@ -16,18 +22,108 @@ type LoanUsecase struct {
 	mock.Mock
 }
 
-// ApproveLoan provides a mock function with given fields: ctx, loan, approver
-func (_m *LoanUsecase) ApproveLoan(ctx context.Context, loan *models.Loan, approver *models.User) error {
-	ret := _m.Called(ctx, loan, approver)
+// ApproveLoan provides a mock function with given fields: ctx, loan, approver, comment
+func (_m *LoanUsecase) ApproveLoan(ctx context.Context, loan *models.Loan, approver *models.User, comment string) (*models.ApprovalStatus, error) {
+	ret := _m.Called(ctx, loan, approver, comment)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User) error); ok {
-		r0 = rf(ctx, loan, approver)
+	var r0 *models.ApprovalStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, string) (*models.ApprovalStatus, error)); ok {
+		return rf(ctx, loan, approver, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, string) *models.ApprovalStatus); ok {
+		r0 = rf(ctx, loan, approver, comment)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalStatus)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, *models.User, string) error); ok {
+		r1 = rf(ctx, loan, approver, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssessLoanCredit provides a mock function with given fields: ctx, loan, assessor
+func (_m *LoanUsecase) AssessLoanCredit(ctx context.Context, loan *models.Loan, assessor *models.User) (*models.CreditAssessment, error) {
+	ret := _m.Called(ctx, loan, assessor)
+
+	var r0 *models.CreditAssessment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User) (*models.CreditAssessment, error)); ok {
+		return rf(ctx, loan, assessor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User) *models.CreditAssessment); ok {
+		r0 = rf(ctx, loan, assessor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CreditAssessment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, *models.User) error); ok {
+		r1 = rf(ctx, loan, assessor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssignLoan provides a mock function with given fields: ctx, loan, actor, fieldValidatorID, scheduledAt, note
+func (_m *LoanUsecase) AssignLoan(ctx context.Context, loan *models.Loan, actor *models.User, fieldValidatorID uint, scheduledAt *time.Time, note string) (*models.LoanAssignment, error) {
+	ret := _m.Called(ctx, loan, actor, fieldValidatorID, scheduledAt, note)
+
+	var r0 *models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, uint, *time.Time, string) (*models.LoanAssignment, error)); ok {
+		return rf(ctx, loan, actor, fieldValidatorID, scheduledAt, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, uint, *time.Time, string) *models.LoanAssignment); ok {
+		r0 = rf(ctx, loan, actor, fieldValidatorID, scheduledAt, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, *models.User, uint, *time.Time, string) error); ok {
+		r1 = rf(ctx, loan, actor, fieldValidatorID, scheduledAt, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AutoAssignLoans provides a mock function with given fields: ctx
+func (_m *LoanUsecase) AutoAssignLoans(ctx context.Context) ([]models.LoanAssignment, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.LoanAssignment, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.LoanAssignment); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // DisburseLoan provides a mock function with given fields: ctx, loan, disburser
@@ -44,6 +140,171 @@ func (_m *LoanUsecase) DisburseLoan(ctx context.Context, loan *models.Loan, disb
 	return r0
 }
 
+// DownloadLoanDocument provides a mock function with given fields: ctx, loan, permissions, documentID, variant
+func (_m *LoanUsecase) DownloadLoanDocument(ctx context.Context, loan *models.Loan, permissions []auth.Permission, documentID uint, variant models.LoanDocumentVariant) (io.ReadCloser, *upload.UploadedFile, error) {
+	ret := _m.Called(ctx, loan, permissions, documentID, variant)
+
+	var r0 io.ReadCloser
+	var r1 *upload.UploadedFile
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, []auth.Permission, uint, models.LoanDocumentVariant) (io.ReadCloser, *upload.UploadedFile, error)); ok {
+		return rf(ctx, loan, permissions, documentID, variant)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, []auth.Permission, uint, models.LoanDocumentVariant) io.ReadCloser); ok {
+		r0 = rf(ctx, loan, permissions, documentID, variant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, []auth.Permission, uint, models.LoanDocumentVariant) *upload.UploadedFile); ok {
+		r1 = rf(ctx, loan, permissions, documentID, variant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*upload.UploadedFile)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *models.Loan, []auth.Permission, uint, models.LoanDocumentVariant) error); ok {
+		r2 = rf(ctx, loan, permissions, documentID, variant)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchApprovalPolicies provides a mock function with given fields: ctx
+func (_m *LoanUsecase) FetchApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.ApprovalPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.ApprovalPolicy, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.ApprovalPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ApprovalPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCreditAssessments provides a mock function with given fields: ctx, loan
+func (_m *LoanUsecase) FetchCreditAssessments(ctx context.Context, loan *models.Loan) ([]models.CreditAssessment, error) {
+	ret := _m.Called(ctx, loan)
+
+	var r0 []models.CreditAssessment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) ([]models.CreditAssessment, error)); ok {
+		return rf(ctx, loan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) []models.CreditAssessment); ok {
+		r0 = rf(ctx, loan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CreditAssessment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan) error); ok {
+		r1 = rf(ctx, loan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCreditScorecard provides a mock function with given fields: ctx
+func (_m *LoanUsecase) FetchCreditScorecard(ctx context.Context) (*models.CreditScorecard, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *models.CreditScorecard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.CreditScorecard, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.CreditScorecard); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CreditScorecard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanApprovalStatus provides a mock function with given fields: ctx, loan
+func (_m *LoanUsecase) FetchLoanApprovalStatus(ctx context.Context, loan *models.Loan) (*models.ApprovalStatus, error) {
+	ret := _m.Called(ctx, loan)
+
+	var r0 *models.ApprovalStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) (*models.ApprovalStatus, error)); ok {
+		return rf(ctx, loan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) *models.ApprovalStatus); ok {
+		r0 = rf(ctx, loan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan) error); ok {
+		r1 = rf(ctx, loan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanAssignments provides a mock function with given fields: ctx, opts
+func (_m *LoanUsecase) FetchLoanAssignments(ctx context.Context, opts *models.FetchLoanAssignmentsOpts) ([]models.LoanAssignment, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanAssignmentsOpts) ([]models.LoanAssignment, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanAssignmentsOpts) []models.LoanAssignment); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchLoanAssignmentsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchLoanByID provides a mock function with given fields: ctx, loanID, opts
 func (_m *LoanUsecase) FetchLoanByID(ctx context.Context, loanID uint, opts *models.FetchLoanOpts) (*models.Loan, error) {
 	ret := _m.Called(ctx, loanID, opts)
@@ -70,6 +331,58 @@ func (_m *LoanUsecase) FetchLoanByID(ctx context.Context, loanID uint, opts *mod
 	return r0, r1
 }
 
+// FetchLoanDocuments provides a mock function with given fields: ctx, loan, permissions, opts
+func (_m *LoanUsecase) FetchLoanDocuments(ctx context.Context, loan *models.Loan, permissions []auth.Permission, opts *models.FetchLoanDocumentsOpts) ([]models.LoanDocument, error) {
+	ret := _m.Called(ctx, loan, permissions, opts)
+
+	var r0 []models.LoanDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, []auth.Permission, *models.FetchLoanDocumentsOpts) ([]models.LoanDocument, error)); ok {
+		return rf(ctx, loan, permissions, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, []auth.Permission, *models.FetchLoanDocumentsOpts) []models.LoanDocument); ok {
+		r0 = rf(ctx, loan, permissions, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, []auth.Permission, *models.FetchLoanDocumentsOpts) error); ok {
+		r1 = rf(ctx, loan, permissions, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLoanPricings provides a mock function with given fields: ctx, loan
+func (_m *LoanUsecase) FetchLoanPricings(ctx context.Context, loan *models.Loan) ([]models.LoanPricing, error) {
+	ret := _m.Called(ctx, loan)
+
+	var r0 []models.LoanPricing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) ([]models.LoanPricing, error)); ok {
+		return rf(ctx, loan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan) []models.LoanPricing); ok {
+		r0 = rf(ctx, loan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoanPricing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan) error); ok {
+		r1 = rf(ctx, loan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchLoans provides a mock function with given fields: ctx, opts
 func (_m *LoanUsecase) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts) ([]models.Loan, error) {
 	ret := _m.Called(ctx, opts)
@@ -122,6 +435,32 @@ func (_m *LoanUsecase) FetchLoansByUserID(ctx context.Context, userID uint) ([]m
 	return r0, r1
 }
 
+// FetchPendingApprovals provides a mock function with given fields: ctx, opts
+func (_m *LoanUsecase) FetchPendingApprovals(ctx context.Context, opts *models.FetchLoanOpts) ([]models.PendingApproval, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.PendingApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanOpts) ([]models.PendingApproval, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchLoanOpts) []models.PendingApproval); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PendingApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchLoanOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InvestInLoan provides a mock function with given fields: ctx, loan, investor, amount
 func (_m *LoanUsecase) InvestInLoan(ctx context.Context, loan *models.Loan, investor *models.User, amount float64) error {
 	ret := _m.Called(ctx, loan, investor, amount)
@@ -136,13 +475,101 @@ func (_m *LoanUsecase) InvestInLoan(ctx context.Context, loan *models.Loan, inve
 	return r0
 }
 
-// MarkLoanBorrowerVisited provides a mock function with given fields: ctx, loan, visitor, attachment
-func (_m *LoanUsecase) MarkLoanBorrowerVisited(ctx context.Context, loan *models.Loan, visitor *models.User, attachment io.Reader) error {
-	ret := _m.Called(ctx, loan, visitor, attachment)
+// MarkLoanBorrowerVisited provides a mock function with given fields: ctx, loan, visitor, attachment, reported, report
+func (_m *LoanUsecase) MarkLoanBorrowerVisited(ctx context.Context, loan *models.Loan, visitor *models.User, attachment io.Reader, reported *models.VisitLocation, report *models.VisitReportInput) error {
+	ret := _m.Called(ctx, loan, visitor, attachment, reported, report)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, io.Reader, *models.VisitLocation, *models.VisitReportInput) error); ok {
+		r0 = rf(ctx, loan, visitor, attachment, reported, report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RejectLoan provides a mock function with given fields: ctx, loan, approver, comment
+func (_m *LoanUsecase) RejectLoan(ctx context.Context, loan *models.Loan, approver *models.User, comment string) (*models.ApprovalStatus, error) {
+	ret := _m.Called(ctx, loan, approver, comment)
+
+	var r0 *models.ApprovalStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, string) (*models.ApprovalStatus, error)); ok {
+		return rf(ctx, loan, approver, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, string) *models.ApprovalStatus); ok {
+		r0 = rf(ctx, loan, approver, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, *models.User, string) error); ok {
+		r1 = rf(ctx, loan, approver, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemindDueInstallments provides a mock function with given fields: ctx
+func (_m *LoanUsecase) RemindDueInstallments(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReportOverdueVisits provides a mock function with given fields: ctx
+func (_m *LoanUsecase) ReportOverdueVisits(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReviewLoanVisit provides a mock function with given fields: ctx, loan, reviewer, accept, note
+func (_m *LoanUsecase) ReviewLoanVisit(ctx context.Context, loan *models.Loan, reviewer *models.User, accept bool, note string) error {
+	ret := _m.Called(ctx, loan, reviewer, accept, note)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, io.Reader) error); ok {
-		r0 = rf(ctx, loan, visitor, attachment)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, bool, string) error); ok {
+		r0 = rf(ctx, loan, reviewer, accept, note)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -150,6 +577,84 @@ func (_m *LoanUsecase) MarkLoanBorrowerVisited(ctx context.Context, loan *models
 	return r0
 }
 
+// ScheduleLoanVisit provides a mock function with given fields: ctx, fieldValidator, assignmentID, scheduledAt
+func (_m *LoanUsecase) ScheduleLoanVisit(ctx context.Context, fieldValidator *models.User, assignmentID uint, scheduledAt time.Time) (*models.LoanAssignment, error) {
+	ret := _m.Called(ctx, fieldValidator, assignmentID, scheduledAt)
+
+	var r0 *models.LoanAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, time.Time) (*models.LoanAssignment, error)); ok {
+		return rf(ctx, fieldValidator, assignmentID, scheduledAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, time.Time) *models.LoanAssignment); ok {
+		r0 = rf(ctx, fieldValidator, assignmentID, scheduledAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoanAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, uint, time.Time) error); ok {
+		r1 = rf(ctx, fieldValidator, assignmentID, scheduledAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetApprovalPolicies provides a mock function with given fields: ctx, policies
+func (_m *LoanUsecase) SetApprovalPolicies(ctx context.Context, policies []models.ApprovalPolicy) ([]models.ApprovalPolicy, error) {
+	ret := _m.Called(ctx, policies)
+
+	var r0 []models.ApprovalPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ApprovalPolicy) ([]models.ApprovalPolicy, error)); ok {
+		return rf(ctx, policies)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ApprovalPolicy) []models.ApprovalPolicy); ok {
+		r0 = rf(ctx, policies)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ApprovalPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.ApprovalPolicy) error); ok {
+		r1 = rf(ctx, policies)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCreditScorecard provides a mock function with given fields: ctx, scorecard
+func (_m *LoanUsecase) SetCreditScorecard(ctx context.Context, scorecard *models.CreditScorecard) (*models.CreditScorecard, error) {
+	ret := _m.Called(ctx, scorecard)
+
+	var r0 *models.CreditScorecard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CreditScorecard) (*models.CreditScorecard, error)); ok {
+		return rf(ctx, scorecard)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CreditScorecard) *models.CreditScorecard); ok {
+		r0 = rf(ctx, scorecard)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CreditScorecard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.CreditScorecard) error); ok {
+		r1 = rf(ctx, scorecard)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartLoan provides a mock function with given fields: ctx, name, product, borrower
 func (_m *LoanUsecase) StartLoan(ctx context.Context, name string, product *models.Product, borrower *models.User) (*models.Loan, error) {
 	ret := _m.Called(ctx, name, product, borrower)
@@ -176,6 +681,86 @@ func (_m *LoanUsecase) StartLoan(ctx context.Context, name string, product *mode
 	return r0, r1
 }
 
+// SubscribeLoanEvents provides a mock function with given fields: loanID
+func (_m *LoanUsecase) SubscribeLoanEvents(loanID uint) (<-chan models.LoanEvent, func()) {
+	ret := _m.Called(loanID)
+
+	var r0 <-chan models.LoanEvent
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(uint) (<-chan models.LoanEvent, func())); ok {
+		return rf(loanID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) <-chan models.LoanEvent); ok {
+		r0 = rf(loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan models.LoanEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) func()); ok {
+		r1 = rf(loanID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// SyncVisits provides a mock function with given fields: ctx, fieldValidator, items
+func (_m *LoanUsecase) SyncVisits(ctx context.Context, fieldValidator *models.User, items []models.VisitSyncItem) ([]models.VisitSyncResult, error) {
+	ret := _m.Called(ctx, fieldValidator, items)
+
+	var r0 []models.VisitSyncResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, []models.VisitSyncItem) ([]models.VisitSyncResult, error)); ok {
+		return rf(ctx, fieldValidator, items)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, []models.VisitSyncItem) []models.VisitSyncResult); ok {
+		r0 = rf(ctx, fieldValidator, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.VisitSyncResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, []models.VisitSyncItem) error); ok {
+		r1 = rf(ctx, fieldValidator, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadLoanDocument provides a mock function with given fields: ctx, loan, uploader, permissions, documentType, file
+func (_m *LoanUsecase) UploadLoanDocument(ctx context.Context, loan *models.Loan, uploader *models.User, permissions []auth.Permission, documentType models.LoanDocumentType, file io.Reader) (*models.LoanDocument, error) {
+	ret := _m.Called(ctx, loan, uploader, permissions, documentType, file)
+
+	var r0 *models.LoanDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, []auth.Permission, models.LoanDocumentType, io.Reader) (*models.LoanDocument, error)); ok {
+		return rf(ctx, loan, uploader, permissions, documentType, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Loan, *models.User, []auth.Permission, models.LoanDocumentType, io.Reader) *models.LoanDocument); ok {
+		r0 = rf(ctx, loan, uploader, permissions, documentType, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoanDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Loan, *models.User, []auth.Permission, models.LoanDocumentType, io.Reader) error); ok {
+		r1 = rf(ctx, loan, uploader, permissions, documentType, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewLoanUsecase creates a new instance of LoanUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLoanUsecase(t interface {
@@ -0,0 +1,185 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// NotificationRepository is an autogenerated mock type for the NotificationRepository type
+type NotificationRepository struct {
+	mock.Mock
+}
+
+// CountInboxNotifications provides a mock function with given fields: ctx, opts
+func (_m *NotificationRepository) CountInboxNotifications(ctx context.Context, opts *models.FetchInboxNotificationsOpts) (int64, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) (int64, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) int64); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchInboxNotificationsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateInboxNotification provides a mock function with given fields: ctx, notification
+func (_m *NotificationRepository) CreateInboxNotification(ctx context.Context, notification *models.InboxNotification) error {
+	ret := _m.Called(ctx, notification)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.InboxNotification) error); ok {
+		r0 = rf(ctx, notification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchInboxNotificationByID provides a mock function with given fields: ctx, userID, notificationID
+func (_m *NotificationRepository) FetchInboxNotificationByID(ctx context.Context, userID uint, notificationID uint) (*models.InboxNotification, error) {
+	ret := _m.Called(ctx, userID, notificationID)
+
+	var r0 *models.InboxNotification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*models.InboxNotification, error)); ok {
+		return rf(ctx, userID, notificationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *models.InboxNotification); ok {
+		r0 = rf(ctx, userID, notificationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxNotification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, userID, notificationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchInboxNotifications provides a mock function with given fields: ctx, opts
+func (_m *NotificationRepository) FetchInboxNotifications(ctx context.Context, opts *models.FetchInboxNotificationsOpts) ([]models.InboxNotification, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.InboxNotification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) ([]models.InboxNotification, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) []models.InboxNotification); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.InboxNotification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchInboxNotificationsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchNotificationPreferences provides a mock function with given fields: ctx, userID
+func (_m *NotificationRepository) FetchNotificationPreferences(ctx context.Context, userID uint) ([]models.NotificationPreference, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []models.NotificationPreference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]models.NotificationPreference, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []models.NotificationPreference); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NotificationPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkInboxNotificationsRead provides a mock function with given fields: ctx, userID, notificationIDs, readAt
+func (_m *NotificationRepository) MarkInboxNotificationsRead(ctx context.Context, userID uint, notificationIDs []uint, readAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, userID, notificationIDs, readAt)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []uint, time.Time) (int64, error)); ok {
+		return rf(ctx, userID, notificationIDs, readAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []uint, time.Time) int64); ok {
+		r0 = rf(ctx, userID, notificationIDs, readAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []uint, time.Time) error); ok {
+		r1 = rf(ctx, userID, notificationIDs, readAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertNotificationPreferences provides a mock function with given fields: ctx, preferences
+func (_m *NotificationRepository) UpsertNotificationPreferences(ctx context.Context, preferences []models.NotificationPreference) error {
+	ret := _m.Called(ctx, preferences)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.NotificationPreference) error); ok {
+		r0 = rf(ctx, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationRepository {
+	mock := &NotificationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,183 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NotificationUsecase is an autogenerated mock type for the NotificationUsecase type
+type NotificationUsecase struct {
+	mock.Mock
+}
+
+// CountUnreadInboxNotifications provides a mock function with given fields: ctx, userID
+func (_m *NotificationUsecase) CountUnreadInboxNotifications(ctx context.Context, userID uint) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchInbox provides a mock function with given fields: ctx, opts
+func (_m *NotificationUsecase) FetchInbox(ctx context.Context, opts *models.FetchInboxNotificationsOpts) (*models.Inbox, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 *models.Inbox
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) (*models.Inbox, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchInboxNotificationsOpts) *models.Inbox); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Inbox)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchInboxNotificationsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchNotificationPreferences provides a mock function with given fields: ctx, user
+func (_m *NotificationUsecase) FetchNotificationPreferences(ctx context.Context, user *models.User) (map[models.NotificationEvent][]models.NotificationChannel, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 map[models.NotificationEvent][]models.NotificationChannel
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) (map[models.NotificationEvent][]models.NotificationChannel, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) map[models.NotificationEvent][]models.NotificationChannel); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[models.NotificationEvent][]models.NotificationChannel)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkAllInboxNotificationsRead provides a mock function with given fields: ctx, userID
+func (_m *NotificationUsecase) MarkAllInboxNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkInboxNotificationRead provides a mock function with given fields: ctx, userID, notificationID
+func (_m *NotificationUsecase) MarkInboxNotificationRead(ctx context.Context, userID uint, notificationID uint) (*models.InboxNotification, error) {
+	ret := _m.Called(ctx, userID, notificationID)
+
+	var r0 *models.InboxNotification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*models.InboxNotification, error)); ok {
+		return rf(ctx, userID, notificationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *models.InboxNotification); ok {
+		r0 = rf(ctx, userID, notificationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxNotification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, userID, notificationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Notify provides a mock function with given fields: ctx, recipient, notification
+func (_m *NotificationUsecase) Notify(ctx context.Context, recipient *models.User, notification *models.Notification) error {
+	ret := _m.Called(ctx, recipient, notification)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.Notification) error); ok {
+		r0 = rf(ctx, recipient, notification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateNotificationPreferences provides a mock function with given fields: ctx, user, preferences
+func (_m *NotificationUsecase) UpdateNotificationPreferences(ctx context.Context, user *models.User, preferences map[models.NotificationEvent][]models.NotificationChannel) error {
+	ret := _m.Called(ctx, user, preferences)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, map[models.NotificationEvent][]models.NotificationChannel) error); ok {
+		r0 = rf(ctx, user, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotificationUsecase creates a new instance of NotificationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationUsecase {
+	mock := &NotificationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
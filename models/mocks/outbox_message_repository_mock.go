@@ -0,0 +1,151 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxMessageRepository is an autogenerated mock type for the OutboxMessageRepository type
+type OutboxMessageRepository struct {
+	mock.Mock
+}
+
+// ClaimDueOutboxMessages provides a mock function with given fields: ctx, now, leaseUntil, limit
+func (_m *OutboxMessageRepository) ClaimDueOutboxMessages(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	ret := _m.Called(ctx, now, leaseUntil, limit)
+
+	var r0 []models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]models.OutboxMessage, error)); ok {
+		return rf(ctx, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []models.OutboxMessage); ok {
+		r0 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateOutboxMessages provides a mock function with given fields: ctx, messages
+func (_m *OutboxMessageRepository) CreateOutboxMessages(ctx context.Context, messages []*models.OutboxMessage) error {
+	ret := _m.Called(ctx, messages)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.OutboxMessage) error); ok {
+		r0 = rf(ctx, messages)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchOutboxMessageByID provides a mock function with given fields: ctx, messageID
+func (_m *OutboxMessageRepository) FetchOutboxMessageByID(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	ret := _m.Called(ctx, messageID)
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.OutboxMessage, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.OutboxMessage); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchOutboxMessages provides a mock function with given fields: ctx, opts
+func (_m *OutboxMessageRepository) FetchOutboxMessages(ctx context.Context, opts *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchOutboxMessagesOpts) []models.OutboxMessage); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchOutboxMessagesOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendOutboxMessage provides a mock function with given fields: ctx, outboxMessage, readStatus, readNextAttemptAt
+func (_m *OutboxMessageRepository) ResendOutboxMessage(ctx context.Context, outboxMessage *models.OutboxMessage, readStatus models.OutboxMessageStatus, readNextAttemptAt time.Time) error {
+	ret := _m.Called(ctx, outboxMessage, readStatus, readNextAttemptAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.OutboxMessage, models.OutboxMessageStatus, time.Time) error); ok {
+		r0 = rf(ctx, outboxMessage, readStatus, readNextAttemptAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOutboxMessage provides a mock function with given fields: ctx, outboxMessage
+func (_m *OutboxMessageRepository) UpdateOutboxMessage(ctx context.Context, outboxMessage *models.OutboxMessage) error {
+	ret := _m.Called(ctx, outboxMessage)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.OutboxMessage) error); ok {
+		r0 = rf(ctx, outboxMessage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxMessageRepository creates a new instance of OutboxMessageRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxMessageRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxMessageRepository {
+	mock := &OutboxMessageRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
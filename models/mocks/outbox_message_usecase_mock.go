@@ -0,0 +1,152 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OutboxMessageUsecase is an autogenerated mock type for the OutboxMessageUsecase type
+type OutboxMessageUsecase struct {
+	mock.Mock
+}
+
+// DispatchDueMessages provides a mock function with given fields: ctx
+func (_m *OutboxMessageUsecase) DispatchDueMessages(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnqueueMessages provides a mock function with given fields: ctx, messages
+func (_m *OutboxMessageUsecase) EnqueueMessages(ctx context.Context, messages ...*models.OutboxMessage) error {
+	_va := make([]interface{}, len(messages))
+	for _i := range messages {
+		_va[_i] = messages[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...*models.OutboxMessage) error); ok {
+		r0 = rf(ctx, messages...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchOutboxMessageByID provides a mock function with given fields: ctx, messageID
+func (_m *OutboxMessageUsecase) FetchOutboxMessageByID(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	ret := _m.Called(ctx, messageID)
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.OutboxMessage, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.OutboxMessage); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchOutboxMessages provides a mock function with given fields: ctx, opts
+func (_m *OutboxMessageUsecase) FetchOutboxMessages(ctx context.Context, opts *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchOutboxMessagesOpts) []models.OutboxMessage); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchOutboxMessagesOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendOutboxMessage provides a mock function with given fields: ctx, messageID
+func (_m *OutboxMessageUsecase) ResendOutboxMessage(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	ret := _m.Called(ctx, messageID)
+
+	var r0 *models.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.OutboxMessage, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.OutboxMessage); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOutboxMessageUsecase creates a new instance of OutboxMessageUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxMessageUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxMessageUsecase {
+	mock := &OutboxMessageUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -66,6 +66,34 @@ func (_m *ProductRepository) FetchProducts(ctx context.Context) ([]models.Produc
 	return r0, r1
 }
 
+// SaveVisitChecklist provides a mock function with given fields: ctx, checklist
+func (_m *ProductRepository) SaveVisitChecklist(ctx context.Context, checklist *models.VisitChecklist) error {
+	ret := _m.Called(ctx, checklist)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.VisitChecklist) error); ok {
+		r0 = rf(ctx, checklist)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProductRateGrid provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) UpdateProductRateGrid(ctx context.Context, product *models.Product) error {
+	ret := _m.Called(ctx, product)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product) error); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewProductRepository creates a new instance of ProductRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProductRepository(t interface {
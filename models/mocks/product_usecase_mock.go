@@ -66,6 +66,84 @@ func (_m *ProductUsecase) FetchProducts(ctx context.Context) ([]models.Product,
 	return r0, r1
 }
 
+// FetchVisitChecklist provides a mock function with given fields: ctx, productID
+func (_m *ProductUsecase) FetchVisitChecklist(ctx context.Context, productID uint) (*models.VisitChecklist, error) {
+	ret := _m.Called(ctx, productID)
+
+	var r0 *models.VisitChecklist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.VisitChecklist, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.VisitChecklist); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.VisitChecklist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRateGrid provides a mock function with given fields: ctx, product, grid
+func (_m *ProductUsecase) SetRateGrid(ctx context.Context, product *models.Product, grid models.RateGrid) (*models.Product, error) {
+	ret := _m.Called(ctx, product, grid)
+
+	var r0 *models.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product, models.RateGrid) (*models.Product, error)); ok {
+		return rf(ctx, product, grid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product, models.RateGrid) *models.Product); ok {
+		r0 = rf(ctx, product, grid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Product, models.RateGrid) error); ok {
+		r1 = rf(ctx, product, grid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVisitChecklist provides a mock function with given fields: ctx, product, questions
+func (_m *ProductUsecase) SetVisitChecklist(ctx context.Context, product *models.Product, questions []models.VisitQuestion) (*models.VisitChecklist, error) {
+	ret := _m.Called(ctx, product, questions)
+
+	var r0 *models.VisitChecklist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product, []models.VisitQuestion) (*models.VisitChecklist, error)); ok {
+		return rf(ctx, product, questions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product, []models.VisitQuestion) *models.VisitChecklist); ok {
+		r0 = rf(ctx, product, questions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.VisitChecklist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Product, []models.VisitQuestion) error); ok {
+		r1 = rf(ctx, product, questions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewProductUsecase creates a new instance of ProductUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProductUsecase(t interface {
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	auth "loan-service/services/auth"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "loan-service/models"
+)
+
+// RoleRepository is an autogenerated mock type for the RoleRepository type
+type RoleRepository struct {
+	mock.Mock
+}
+
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *RoleRepository) CreateRole(ctx context.Context, role *models.Role) error {
+	ret := _m.Called(ctx, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchPermissions provides a mock function with given fields: ctx
+func (_m *RoleRepository) FetchPermissions(ctx context.Context) ([]models.Permission, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Permission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Permission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPermissionsByCodes provides a mock function with given fields: ctx, codes
+func (_m *RoleRepository) FetchPermissionsByCodes(ctx context.Context, codes []auth.Permission) ([]models.Permission, error) {
+	ret := _m.Called(ctx, codes)
+
+	var r0 []models.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []auth.Permission) ([]models.Permission, error)); ok {
+		return rf(ctx, codes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []auth.Permission) []models.Permission); ok {
+		r0 = rf(ctx, codes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []auth.Permission) error); ok {
+		r1 = rf(ctx, codes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRoleByID provides a mock function with given fields: ctx, roleID
+func (_m *RoleRepository) FetchRoleByID(ctx context.Context, roleID uint) (*models.Role, error) {
+	ret := _m.Called(ctx, roleID)
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.Role, error)); ok {
+		return rf(ctx, roleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.Role); ok {
+		r0 = rf(ctx, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRoles provides a mock function with given fields: ctx
+func (_m *RoleRepository) FetchRoles(ctx context.Context) ([]models.Role, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceRolePermissions provides a mock function with given fields: ctx, role, permissions
+func (_m *RoleRepository) ReplaceRolePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error {
+	ret := _m.Called(ctx, role, permissions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Role, []models.Permission) error); ok {
+		r0 = rf(ctx, role, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRoleRepository creates a new instance of RoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleRepository {
+	mock := &RoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,161 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	auth "loan-service/services/auth"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "loan-service/models"
+)
+
+// RoleUsecase is an autogenerated mock type for the RoleUsecase type
+type RoleUsecase struct {
+	mock.Mock
+}
+
+// CreateRole provides a mock function with given fields: ctx, actor, name, roleType, permissions
+func (_m *RoleUsecase) CreateRole(ctx context.Context, actor *models.User, name string, roleType auth.RoleType, permissions []auth.Permission) (*models.Role, error) {
+	ret := _m.Called(ctx, actor, name, roleType, permissions)
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, auth.RoleType, []auth.Permission) (*models.Role, error)); ok {
+		return rf(ctx, actor, name, roleType, permissions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, auth.RoleType, []auth.Permission) *models.Role); ok {
+		r0 = rf(ctx, actor, name, roleType, permissions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, string, auth.RoleType, []auth.Permission) error); ok {
+		r1 = rf(ctx, actor, name, roleType, permissions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPermissions provides a mock function with given fields: ctx
+func (_m *RoleUsecase) FetchPermissions(ctx context.Context) ([]models.Permission, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Permission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Permission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRoleByID provides a mock function with given fields: ctx, roleID
+func (_m *RoleUsecase) FetchRoleByID(ctx context.Context, roleID uint) (*models.Role, error) {
+	ret := _m.Called(ctx, roleID)
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.Role, error)); ok {
+		return rf(ctx, roleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.Role); ok {
+		r0 = rf(ctx, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRoles provides a mock function with given fields: ctx
+func (_m *RoleUsecase) FetchRoles(ctx context.Context) ([]models.Role, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRolePermissions provides a mock function with given fields: ctx, actor, roleID, permissions
+func (_m *RoleUsecase) SetRolePermissions(ctx context.Context, actor *models.User, roleID uint, permissions []auth.Permission) (*models.Role, error) {
+	ret := _m.Called(ctx, actor, roleID, permissions)
+
+	var r0 *models.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, []auth.Permission) (*models.Role, error)); ok {
+		return rf(ctx, actor, roleID, permissions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, []auth.Permission) *models.Role); ok {
+		r0 = rf(ctx, actor, roleID, permissions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, uint, []auth.Permission) error); ok {
+		r1 = rf(ctx, actor, roleID, permissions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRoleUsecase creates a new instance of RoleUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleUsecase {
+	mock := &RoleUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -16,6 +16,44 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// CountLoginAttempts provides a mock function with given fields: ctx, opts
+func (_m *UserRepository) CountLoginAttempts(ctx context.Context, opts *models.CountLoginAttemptsOpts) (int64, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CountLoginAttemptsOpts) (int64, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CountLoginAttemptsOpts) int64); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.CountLoginAttemptsOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateLoginAttempt provides a mock function with given fields: ctx, attempt
+func (_m *UserRepository) CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	ret := _m.Called(ctx, attempt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoginAttempt) error); ok {
+		r0 = rf(ctx, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
 	ret := _m.Called(ctx, user)
@@ -108,25 +146,77 @@ func (_m *UserRepository) FetchUserByID(ctx context.Context, userID uint, opts *
 	return r0, r1
 }
 
-// FetchUsers provides a mock function with given fields: ctx, allowedRoles, allowedLoanIDs
-func (_m *UserRepository) FetchUsers(ctx context.Context, allowedRoles []auth.RoleType, allowedLoanIDs []uint) ([]models.User, error) {
-	ret := _m.Called(ctx, allowedRoles, allowedLoanIDs)
+// FetchUserByInvitationTokenHash provides a mock function with given fields: ctx, tokenHash
+func (_m *UserRepository) FetchUserByInvitationTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchUserByOIDCSubject provides a mock function with given fields: ctx, subject
+func (_m *UserRepository) FetchUserByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	ret := _m.Called(ctx, subject)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchUsers provides a mock function with given fields: ctx, opts
+func (_m *UserRepository) FetchUsers(ctx context.Context, opts *models.FetchUsersOpts) ([]models.User, error) {
+	ret := _m.Called(ctx, opts)
 
 	var r0 []models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []auth.RoleType, []uint) ([]models.User, error)); ok {
-		return rf(ctx, allowedRoles, allowedLoanIDs)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchUsersOpts) ([]models.User, error)); ok {
+		return rf(ctx, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []auth.RoleType, []uint) []models.User); ok {
-		r0 = rf(ctx, allowedRoles, allowedLoanIDs)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchUsersOpts) []models.User); ok {
+		r0 = rf(ctx, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []auth.RoleType, []uint) error); ok {
-		r1 = rf(ctx, allowedRoles, allowedLoanIDs)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchUsersOpts) error); ok {
+		r1 = rf(ctx, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,13 +224,20 @@ func (_m *UserRepository) FetchUsers(ctx context.Context, allowedRoles []auth.Ro
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: ctx, user
-func (_m *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// UpdateUser provides a mock function with given fields: ctx, user, columns
+func (_m *UserRepository) UpdateUser(ctx context.Context, user *models.User, columns ...string) error {
+	_va := make([]interface{}, len(columns))
+	for _i := range columns {
+		_va[_i] = columns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, user)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, ...string) error); ok {
+		r0 = rf(ctx, user, columns...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,9 +4,13 @@ package mocks
 
 import (
 	context "context"
-	models "loan-service/models"
+	auth "loan-service/services/auth"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "loan-service/models"
+
+	oidc "loan-service/services/oidc"
 )
 
 // UserUsecase is an autogenerated mock type for the UserUsecase type
@@ -14,6 +18,46 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// AcceptInvitation provides a mock function with given fields: ctx, token, password
+func (_m *UserUsecase) AcceptInvitation(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangeUserRole provides a mock function with given fields: ctx, actor, userID, roleType
+func (_m *UserUsecase) ChangeUserRole(ctx context.Context, actor *models.User, userID uint, roleType auth.RoleType) (*models.User, error) {
+	ret := _m.Called(ctx, actor, userID, roleType)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, auth.RoleType) (*models.User, error)); ok {
+		return rf(ctx, actor, userID, roleType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, auth.RoleType) *models.User); ok {
+		r0 = rf(ctx, actor, userID, roleType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, uint, auth.RoleType) error); ok {
+		r1 = rf(ctx, actor, userID, roleType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchUserByID provides a mock function with given fields: ctx, userID, opts
 func (_m *UserUsecase) FetchUserByID(ctx context.Context, userID uint, opts *models.FetchUserByIDOpts) (*models.User, error) {
 	ret := _m.Called(ctx, userID, opts)
@@ -40,37 +84,101 @@ func (_m *UserUsecase) FetchUserByID(ctx context.Context, userID uint, opts *mod
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, email, password
-func (_m *UserUsecase) Login(ctx context.Context, email string, password string) (models.LoginResponse, string, string, error) {
-	ret := _m.Called(ctx, email, password)
+// InviteUser provides a mock function with given fields: ctx, actor, name, email, roleType
+func (_m *UserUsecase) InviteUser(ctx context.Context, actor *models.User, name string, email string, roleType auth.RoleType) (*models.User, error) {
+	ret := _m.Called(ctx, actor, name, email, roleType)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, string, auth.RoleType) (*models.User, error)); ok {
+		return rf(ctx, actor, name, email, roleType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string, string, auth.RoleType) *models.User); ok {
+		r0 = rf(ctx, actor, name, email, roleType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, string, string, auth.RoleType) error); ok {
+		r1 = rf(ctx, actor, name, email, roleType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, email, password, opts
+func (_m *UserUsecase) Login(ctx context.Context, email string, password string, opts *models.LoginOpts) (models.LoginResponse, string, string, error) {
+	ret := _m.Called(ctx, email, password, opts)
+
+	var r0 models.LoginResponse
+	var r1 string
+	var r2 string
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *models.LoginOpts) (models.LoginResponse, string, string, error)); ok {
+		return rf(ctx, email, password, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *models.LoginOpts) models.LoginResponse); ok {
+		r0 = rf(ctx, email, password, opts)
+	} else {
+		r0 = ret.Get(0).(models.LoginResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *models.LoginOpts) string); ok {
+		r1 = rf(ctx, email, password, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, *models.LoginOpts) string); ok {
+		r2 = rf(ctx, email, password, opts)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, *models.LoginOpts) error); ok {
+		r3 = rf(ctx, email, password, opts)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// LoginWithOIDC provides a mock function with given fields: ctx, identity
+func (_m *UserUsecase) LoginWithOIDC(ctx context.Context, identity *oidc.Identity) (models.LoginResponse, string, string, error) {
+	ret := _m.Called(ctx, identity)
 
 	var r0 models.LoginResponse
 	var r1 string
 	var r2 string
 	var r3 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.LoginResponse, string, string, error)); ok {
-		return rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, *oidc.Identity) (models.LoginResponse, string, string, error)); ok {
+		return rf(ctx, identity)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.LoginResponse); ok {
-		r0 = rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, *oidc.Identity) models.LoginResponse); ok {
+		r0 = rf(ctx, identity)
 	} else {
 		r0 = ret.Get(0).(models.LoginResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) string); ok {
-		r1 = rf(ctx, email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, *oidc.Identity) string); ok {
+		r1 = rf(ctx, identity)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string) string); ok {
-		r2 = rf(ctx, email, password)
+	if rf, ok := ret.Get(2).(func(context.Context, *oidc.Identity) string); ok {
+		r2 = rf(ctx, identity)
 	} else {
 		r2 = ret.Get(2).(string)
 	}
 
-	if rf, ok := ret.Get(3).(func(context.Context, string, string) error); ok {
-		r3 = rf(ctx, email, password)
+	if rf, ok := ret.Get(3).(func(context.Context, *oidc.Identity) error); ok {
+		r3 = rf(ctx, identity)
 	} else {
 		r3 = ret.Error(3)
 	}
@@ -92,6 +200,58 @@ func (_m *UserUsecase) RegisterUser(ctx context.Context, user *models.User) erro
 	return r0
 }
 
+// SetUserActive provides a mock function with given fields: ctx, actor, userID, isActive
+func (_m *UserUsecase) SetUserActive(ctx context.Context, actor *models.User, userID uint, isActive bool) (*models.User, error) {
+	ret := _m.Called(ctx, actor, userID, isActive)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, bool) (*models.User, error)); ok {
+		return rf(ctx, actor, userID, isActive)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint, bool) *models.User); ok {
+		r0 = rf(ctx, actor, userID, isActive)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, uint, bool) error); ok {
+		r1 = rf(ctx, actor, userID, isActive)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlockUser provides a mock function with given fields: ctx, actor, userID
+func (_m *UserUsecase) UnlockUser(ctx context.Context, actor *models.User, userID uint) (*models.User, error) {
+	ret := _m.Called(ctx, actor, userID)
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint) (*models.User, error)); ok {
+		return rf(ctx, actor, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, uint) *models.User); ok {
+		r0 = rf(ctx, actor, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, uint) error); ok {
+		r1 = rf(ctx, actor, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateProfile provides a mock function with given fields: ctx, user
 func (_m *UserUsecase) UpdateProfile(ctx context.Context, user *models.User) error {
 	ret := _m.Called(ctx, user)
@@ -0,0 +1,258 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "loan-service/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookUsecase is an autogenerated mock type for the WebhookUsecase type
+type WebhookUsecase struct {
+	mock.Mock
+}
+
+// CreateWebhookSubscription provides a mock function with given fields: ctx, actor, subscription
+func (_m *WebhookUsecase) CreateWebhookSubscription(ctx context.Context, actor *models.User, subscription *models.WebhookSubscription) (*models.WebhookSubscription, string, error) {
+	ret := _m.Called(ctx, actor, subscription)
+
+	var r0 *models.WebhookSubscription
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.WebhookSubscription) (*models.WebhookSubscription, string, error)); ok {
+		return rf(ctx, actor, subscription)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, *models.WebhookSubscription) *models.WebhookSubscription); ok {
+		r0 = rf(ctx, actor, subscription)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, *models.WebhookSubscription) string); ok {
+		r1 = rf(ctx, actor, subscription)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *models.User, *models.WebhookSubscription) error); ok {
+		r2 = rf(ctx, actor, subscription)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, subscriptionID
+func (_m *WebhookUsecase) DeleteWebhookSubscription(ctx context.Context, subscriptionID uint) error {
+	ret := _m.Called(ctx, subscriptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DispatchDueDeliveries provides a mock function with given fields: ctx
+func (_m *WebhookUsecase) DispatchDueDeliveries(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchWebhookDeliveries provides a mock function with given fields: ctx, opts
+func (_m *WebhookUsecase) FetchWebhookDeliveries(ctx context.Context, opts *models.FetchWebhookDeliveriesOpts) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchWebhookDeliveriesOpts) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.FetchWebhookDeliveriesOpts) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.FetchWebhookDeliveriesOpts) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchWebhookDeliveryByID provides a mock function with given fields: ctx, deliveryID
+func (_m *WebhookUsecase) FetchWebhookDeliveryByID(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, deliveryID)
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.WebhookDelivery, error)); ok {
+		return rf(ctx, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.WebhookDelivery); ok {
+		r0 = rf(ctx, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchWebhookSubscriptionByID provides a mock function with given fields: ctx, subscriptionID
+func (_m *WebhookUsecase) FetchWebhookSubscriptionByID(ctx context.Context, subscriptionID uint) (*models.WebhookSubscription, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	var r0 *models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.WebhookSubscription, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.WebhookSubscription); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchWebhookSubscriptions provides a mock function with given fields: ctx
+func (_m *WebhookUsecase) FetchWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, eventType, data
+func (_m *WebhookUsecase) Publish(ctx context.Context, eventType models.WebhookEventType, data interface{}) error {
+	ret := _m.Called(ctx, eventType, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookEventType, interface{}) error); ok {
+		r0 = rf(ctx, eventType, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayWebhookDelivery provides a mock function with given fields: ctx, deliveryID
+func (_m *WebhookUsecase) ReplayWebhookDelivery(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, deliveryID)
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.WebhookDelivery, error)); ok {
+		return rf(ctx, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.WebhookDelivery); ok {
+		r0 = rf(ctx, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhookSubscription provides a mock function with given fields: ctx, subscription
+func (_m *WebhookUsecase) UpdateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	ret := _m.Called(ctx, subscription)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscription) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookUsecase creates a new instance of WebhookUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookUsecase {
+	mock := &WebhookUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"loan-service/services/email"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail    NotificationChannel = "email"
+	NotificationChannelSMS      NotificationChannel = "sms"
+	NotificationChannelWhatsApp NotificationChannel = "whatsapp"
+)
+
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelWhatsApp,
+}
+
+// RequiresPhoneNumber returns true if the channel delivers to the user's phone number instead of their email
+func (c NotificationChannel) RequiresPhoneNumber() bool {
+	return c == NotificationChannelSMS || c == NotificationChannelWhatsApp
+}
+
+type NotificationEvent string
+
+const (
+	NotificationEventLoanApproved   NotificationEvent = "loan_approved"
+	NotificationEventLoanFunded     NotificationEvent = "loan_funded"
+	NotificationEventLoanDisbursed  NotificationEvent = "loan_disbursed"
+	NotificationEventInstallmentDue NotificationEvent = "installment_due"
+	NotificationEventVisitAssigned  NotificationEvent = "visit_assigned"
+	NotificationEventVisitOverdue   NotificationEvent = "visit_overdue"
+)
+
+// NotificationEventTemplates lists the events users can choose channels for, and the template each is rendered from
+var NotificationEventTemplates = map[NotificationEvent]email.TemplateName{
+	NotificationEventLoanApproved:   email.TemplateLoanApproved,
+	NotificationEventLoanFunded:     email.TemplateLoanFunded,
+	NotificationEventLoanDisbursed:  email.TemplateLoanDisbursed,
+	NotificationEventInstallmentDue: email.TemplateInstallmentDue,
+	NotificationEventVisitAssigned:  email.TemplateVisitAssigned,
+	NotificationEventVisitOverdue:   email.TemplateVisitOverdue,
+}
+
+// DefaultNotificationChannels are used for events the user has not set a preference for
+var DefaultNotificationChannels = []NotificationChannel{NotificationChannelEmail}
+
+// NotificationPreference holds the channels a user is notified on for an event
+type NotificationPreference struct {
+	gorm.Model
+	UserID   uint                  `json:"user_id" gorm:"uniqueIndex:idx_notification_preferences_user_event"`
+	Event    NotificationEvent     `json:"event" gorm:"uniqueIndex:idx_notification_preferences_user_event"`
+	Channels []NotificationChannel `json:"channels" gorm:"serializer:json"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// Notification is a key event sent to a user on each of their preferred channels
+type Notification struct {
+	Event NotificationEvent
+	// Template data of the event, e.g. email.LoanFundedData
+	Data any
+	// Loan the event is about, linked from the inbox
+	LoanID uint
+
+	// Only attached to emails
+	AttachmentData        []byte
+	AttachmentContentType email.AttachmentType
+	AttachmentFilename    string
+}
+
+// InboxNotification is a notification kept in the user's in-app inbox, regardless of their channel preferences
+type InboxNotification struct {
+	gorm.Model
+	UserID uint              `json:"user_id" gorm:"index:idx_notification_inbox_user_read"`
+	Event  NotificationEvent `json:"event"`
+	LoanID *uint             `json:"loan_id"`
+	// Rendered in the user's language when the event happened
+	Title  string     `json:"title"`
+	Body   string     `json:"body"`
+	ReadAt *time.Time `json:"read_at" gorm:"index:idx_notification_inbox_user_read"`
+}
+
+func (InboxNotification) TableName() string {
+	return "notification_inbox"
+}
+
+type FetchInboxNotificationsOpts struct {
+	UserID     uint
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// Inbox is a page of the user's inbox, newest first
+type Inbox struct {
+	Notifications []InboxNotification
+	// Total matching the filters, for pagination
+	Total       int64
+	UnreadCount int64
+}
+
+type NotificationRepository interface {
+	FetchNotificationPreferences(ctx context.Context, userID uint) ([]NotificationPreference, error)
+	UpsertNotificationPreferences(ctx context.Context, preferences []NotificationPreference) error
+	CreateInboxNotification(ctx context.Context, notification *InboxNotification) error
+	FetchInboxNotifications(ctx context.Context, opts *FetchInboxNotificationsOpts) ([]InboxNotification, error)
+	FetchInboxNotificationByID(ctx context.Context, userID, notificationID uint) (*InboxNotification, error)
+	CountInboxNotifications(ctx context.Context, opts *FetchInboxNotificationsOpts) (int64, error)
+	// MarkInboxNotificationsRead marks the user's unread notifications as read, or all of them if no IDs are given
+	MarkInboxNotificationsRead(ctx context.Context, userID uint, notificationIDs []uint, readAt time.Time) (int64, error)
+}
+
+type NotificationUsecase interface {
+	Notify(ctx context.Context, recipient *User, notification *Notification) error
+	FetchNotificationPreferences(ctx context.Context, user *User) (map[NotificationEvent][]NotificationChannel, error)
+	UpdateNotificationPreferences(ctx context.Context, user *User, preferences map[NotificationEvent][]NotificationChannel) error
+	FetchInbox(ctx context.Context, opts *FetchInboxNotificationsOpts) (*Inbox, error)
+	CountUnreadInboxNotifications(ctx context.Context, userID uint) (int64, error)
+	MarkInboxNotificationRead(ctx context.Context, userID, notificationID uint) (*InboxNotification, error)
+	MarkAllInboxNotificationsRead(ctx context.Context, userID uint) (int64, error)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"context"
+	"loan-service/services/email"
+	"loan-service/utils/i18n"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxMessageStatus string
+
+const (
+	OutboxMessageStatusPending OutboxMessageStatus = "pending"
+	OutboxMessageStatusSent    OutboxMessageStatus = "sent"
+	// Gave up after too many failed attempts, until resent by an admin
+	OutboxMessageStatusDead OutboxMessageStatus = "dead"
+
+	MaxOutboxMessageAttempts = 8
+
+	outboxMessageBaseBackoff = time.Second * 30
+	outboxMessageMaxBackoff  = time.Hour
+)
+
+// OutboxMessage is a notification waiting to be sent, written in the same transaction as the change it notifies about
+type OutboxMessage struct {
+	gorm.Model
+	Channel     NotificationChannel `json:"channel" gorm:"default:email;index"`
+	RecipientID *uint               `json:"recipient_id" gorm:"index"`
+	// Email address, or phone number for SMS and WhatsApp
+	RecipientAddress string             `json:"recipient_address"`
+	RecipientName    string             `json:"recipient_name"`
+	Locale           i18n.Locale        `json:"locale"`
+	Template         email.TemplateName `json:"template"`
+	Subject          string             `json:"subject"`
+	HTMLBody         string             `json:"-"`
+	// Plain text alternative of an email, or the whole SMS and WhatsApp message
+	TextBody string `json:"-"`
+
+	AttachmentData        []byte               `json:"-"`
+	AttachmentContentType email.AttachmentType `json:"-"`
+	AttachmentFilename    string               `json:"attachment_filename"`
+
+	Status        OutboxMessageStatus `json:"status" gorm:"index"`
+	Attempts      int                 `json:"attempts"`
+	NextAttemptAt time.Time           `json:"next_attempt_at" gorm:"index"`
+	LastError     string              `json:"last_error"`
+	SentAt        *time.Time          `json:"sent_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "notification_outbox"
+}
+
+// MarkSent records a successful delivery
+func (m *OutboxMessage) MarkSent(now time.Time) {
+	m.Attempts++
+	m.Status = OutboxMessageStatusSent
+	m.SentAt = &now
+	m.LastError = ""
+}
+
+// MarkFailed records a failed delivery, and schedules a retry with exponential backoff or dead-letters the message
+func (m *OutboxMessage) MarkFailed(now time.Time, err error) {
+	m.Attempts++
+	m.LastError = err.Error()
+
+	if m.Attempts >= MaxOutboxMessageAttempts {
+		m.Status = OutboxMessageStatusDead
+		return
+	}
+
+	backoff := outboxMessageBaseBackoff << (m.Attempts - 1)
+	if backoff > outboxMessageMaxBackoff {
+		backoff = outboxMessageMaxBackoff
+	}
+
+	m.NextAttemptAt = now.Add(backoff)
+}
+
+// IsResendable returns true if the message is dead, or pending past its next attempt so no dispatcher holds its lease.
+// Messages still leased may be in the middle of being sent, and resending them could deliver them twice.
+func (m *OutboxMessage) IsResendable(now time.Time) bool {
+	return m.Status == OutboxMessageStatusDead || (m.Status == OutboxMessageStatusPending && !m.NextAttemptAt.After(now))
+}
+
+// Resend queues the message again for immediate delivery
+func (m *OutboxMessage) Resend(now time.Time) {
+	m.Status = OutboxMessageStatusPending
+	m.Attempts = 0
+	m.NextAttemptAt = now
+	m.LastError = ""
+}
+
+type FetchOutboxMessagesOpts struct {
+	Status      OutboxMessageStatus
+	Channel     NotificationChannel
+	RecipientID uint
+	Limit       int
+	Offset      int
+}
+
+type OutboxMessageRepository interface {
+	CreateOutboxMessages(ctx context.Context, messages []*OutboxMessage) error
+	FetchOutboxMessages(ctx context.Context, opts *FetchOutboxMessagesOpts) ([]OutboxMessage, error)
+	// ClaimDueOutboxMessages locks the pending messages due at now, skipping those another dispatcher is claiming, and
+	// postpones them to leaseUntil so no other dispatcher sends them meanwhile
+	ClaimDueOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]OutboxMessage, error)
+	FetchOutboxMessageByID(ctx context.Context, messageID uint) (*OutboxMessage, error)
+	UpdateOutboxMessage(ctx context.Context, outboxMessage *OutboxMessage) error
+	// ResendOutboxMessage saves a resent message only if its status and next attempt are still the ones read, so a
+	// dispatcher claiming it in between is not raced. Returns gorm.ErrRecordNotFound otherwise.
+	ResendOutboxMessage(
+		ctx context.Context,
+		outboxMessage *OutboxMessage,
+		readStatus OutboxMessageStatus,
+		readNextAttemptAt time.Time,
+	) error
+}
+
+type OutboxMessageUsecase interface {
+	EnqueueMessages(ctx context.Context, messages ...*OutboxMessage) error
+	DispatchDueMessages(ctx context.Context) (int, error)
+	FetchOutboxMessages(ctx context.Context, opts *FetchOutboxMessagesOpts) ([]OutboxMessage, error)
+	FetchOutboxMessageByID(ctx context.Context, messageID uint) (*OutboxMessage, error)
+	ResendOutboxMessage(ctx context.Context, messageID uint) (*OutboxMessage, error)
+}
@@ -2,6 +2,10 @@ package models
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
 
 	"gorm.io/gorm"
 )
@@ -21,18 +25,107 @@ type Product struct {
 	PrincipalAmount string     `json:"principal_amount"`
 	InterestRate    float64    `json:"interest_rate"`
 	Term            TermLength `json:"term"` // in months
+	// Nil if the product's visits need no report
+	VisitChecklist *VisitChecklist `json:"visit_checklist" gorm:"foreignKey:ProductID"`
+	// Interest rates by risk grade, grades without one get the product's interest rate
+	RateGrid RateGrid `json:"rate_grid" gorm:"type:jsonb"`
 }
 
 func (Product) TableName() string {
 	return "product"
 }
 
+// RateFor returns the interest rate of the risk grade, and where it comes from
+func (p *Product) RateFor(grade RiskGrade) (float64, PricingSource) {
+	for _, rate := range p.RateGrid {
+		if rate.Grade == grade {
+			return rate.InterestRate, PricingFromRateGrid
+		}
+	}
+
+	return p.InterestRate, PricingFromProductRate
+}
+
+// GradeRate is the interest rate per annum of loans of a risk grade
+type GradeRate struct {
+	Grade        RiskGrade `json:"grade"`
+	InterestRate float64   `json:"interest_rate"`
+}
+
+// RateGrid is stored as JSON, from the least to the most risky grade
+type RateGrid []GradeRate
+
+// Value implements driver.Valuer.
+func (g RateGrid) Value() (driver.Value, error) {
+	if g == nil {
+		return json.Marshal([]GradeRate{})
+	}
+
+	return json.Marshal([]GradeRate(g))
+}
+
+// Scan implements sql.Scanner.
+func (g *RateGrid) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*g = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, g)
+	case string:
+		return json.Unmarshal([]byte(value), g)
+	default:
+		return fmt.Errorf("cannot scan %T into rate grid", value)
+	}
+}
+
+// Validate checks each rate is for a known grade, given once, and between 0 and 100%
+func (g RateGrid) Validate() error {
+	grades := map[RiskGrade]bool{}
+	for _, rate := range g {
+		if !slices.Contains(RiskGrades, rate.Grade) {
+			return NewInvalidRateGridError("unknown grade `%s`", rate.Grade)
+		}
+
+		if grades[rate.Grade] {
+			return NewInvalidRateGridError("grade `%s` is given twice", rate.Grade)
+		}
+		grades[rate.Grade] = true
+
+		if rate.InterestRate <= 0 || rate.InterestRate > 1 {
+			return NewInvalidRateGridError("grade `%s`: interest rate must be above 0 and at most 1", rate.Grade)
+		}
+	}
+
+	return nil
+}
+
+// Sorted returns the rates from the least to the most risky grade
+func (g RateGrid) Sorted() RateGrid {
+	sorted := append(RateGrid{}, g...)
+	slices.SortFunc(sorted, func(a, b GradeRate) int {
+		return slices.Index(RiskGrades, a.Grade) - slices.Index(RiskGrades, b.Grade)
+	})
+
+	return sorted
+}
+
 type ProductRepository interface {
 	FetchProducts(ctx context.Context) ([]Product, error)
 	FetchProductByID(ctx context.Context, productID uint) (*Product, error)
+	// SaveVisitChecklist creates the product's checklist, or replaces its questions with the next version
+	SaveVisitChecklist(ctx context.Context, checklist *VisitChecklist) error
+	UpdateProductRateGrid(ctx context.Context, product *Product) error
 }
 
 type ProductUsecase interface {
 	FetchProducts(ctx context.Context) ([]Product, error)
 	FetchProductByID(ctx context.Context, productID uint) (*Product, error)
+	FetchVisitChecklist(ctx context.Context, productID uint) (*VisitChecklist, error)
+	// SetVisitChecklist replaces the questions of the product's visit checklist, loans already visited keep their
+	// report
+	SetVisitChecklist(ctx context.Context, product *Product, questions []VisitQuestion) (*VisitChecklist, error)
+	// SetRateGrid replaces the product's interest rates by risk grade, loans are priced with it when applied for and
+	// again when approved
+	SetRateGrid(ctx context.Context, product *Product, grid RateGrid) (*Product, error)
 }
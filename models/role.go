@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"loan-service/services/auth"
 
 	"gorm.io/gorm"
@@ -8,10 +9,73 @@ import (
 
 type Role struct {
 	gorm.Model
-	Name     string        `json:"name"`
-	RoleType auth.RoleType `json:"role_type" gorm:"column:role_type"`
+	Name        string        `json:"name"`
+	RoleType    auth.RoleType `json:"role_type" gorm:"column:role_type"`
+	Permissions []Permission  `json:"permissions" gorm:"many2many:role_permissions"`
 }
 
 func (Role) TableName() string {
 	return "roles"
 }
+
+// PermissionCodes returns the codes of all permissions granted to the role
+func (r *Role) PermissionCodes() []auth.Permission {
+	codes := make([]auth.Permission, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		codes = append(codes, permission.Code)
+	}
+
+	return codes
+}
+
+// MayGrant returns true if a user of the role may grant every one of the permissions, to a role or to an API key.
+// Superusers may grant any permission, other roles only the ones they hold so nobody hands out more access than they
+// have.
+func (r *Role) MayGrant(permissions []auth.Permission) bool {
+	if r.RoleType == auth.RoleTypeSuperuser {
+		return true
+	}
+
+	held := r.PermissionCodes()
+	for _, permission := range permissions {
+		if !auth.HasPermission(held, permission) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MayAssign returns true if a user of the role may give the other role to a user or service account, i.e. they hold
+// every permission it grants. Superusers may assign any role.
+func (r *Role) MayAssign(role *Role) bool {
+	return r.MayGrant(role.PermissionCodes())
+}
+
+type Permission struct {
+	gorm.Model
+	Code        auth.Permission `json:"code" gorm:"uniqueIndex"`
+	Description string          `json:"description"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+type RoleRepository interface {
+	FetchRoles(ctx context.Context) ([]Role, error)
+	FetchRoleByID(ctx context.Context, roleID uint) (*Role, error)
+	CreateRole(ctx context.Context, role *Role) error
+	FetchPermissions(ctx context.Context) ([]Permission, error)
+	FetchPermissionsByCodes(ctx context.Context, codes []auth.Permission) ([]Permission, error)
+	ReplaceRolePermissions(ctx context.Context, role *Role, permissions []Permission) error
+}
+
+type RoleUsecase interface {
+	FetchRoles(ctx context.Context) ([]Role, error)
+	FetchRoleByID(ctx context.Context, roleID uint) (*Role, error)
+	// CreateRole and SetRolePermissions only grant the permissions the actor may grant
+	CreateRole(ctx context.Context, actor *User, name string, roleType auth.RoleType, permissions []auth.Permission) (*Role, error)
+	FetchPermissions(ctx context.Context) ([]Permission, error)
+	SetRolePermissions(ctx context.Context, actor *User, roleID uint, permissions []auth.Permission) (*Role, error)
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Transactor runs fn in a database transaction, repositories called with the context passed to fn take part in it.
+// Nested calls run in a savepoint of the outer transaction.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error
+}
@@ -2,14 +2,13 @@ package models
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"loan-service/services/auth"
 	"loan-service/services/email"
+	"loan-service/services/oidc"
 	"loan-service/utils/errs"
+	"loan-service/utils/i18n"
 	"time"
 
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -26,6 +25,34 @@ type User struct {
 	InvestedLoans  []Loan       `json:"invested_loans" gorm:"many2many:investments;foreignKey:ID;joinForeignKey:InvestorID;references:ID;joinReferences:LoanID"`
 	Investments    []Investment `json:"investments" gorm:"->;foreignKey:InvestorID"`
 	BorrowedLoans  []Loan       `json:"borrowed_loans" gorm:"foreignKey:BorrowerID"`
+
+	// Pending invitation, the raw token is only ever sent by email
+	InvitationTokenHash string     `json:"-" gorm:"column:invitation_token_hash;index"`
+	InvitationExpiresAt *time.Time `json:"-"`
+
+	// Service accounts cannot log in, and authenticate with API keys instead
+	IsServiceAccount bool `json:"is_service_account"`
+
+	// Subject of the user at the OIDC identity provider, set on first single sign-on
+	OIDCSubject *string `json:"-" gorm:"column:oidc_subject;uniqueIndex"`
+
+	// Failed password logins, reset on a successful login or once they are older than the attempt window
+	FailedLoginAttempts int        `json:"-"`
+	LastFailedLoginAt   *time.Time `json:"-"`
+	LockedUntil         *time.Time `json:"locked_until"`
+
+	// Language of emails and other notifications sent to the user
+	Locale i18n.Locale `json:"locale" gorm:"default:en"`
+
+	// Mobile number in E.164 format, required for SMS and WhatsApp notifications
+	PhoneNumber string `json:"phone_number"`
+
+	// Registered address, visits to the borrower are checked against its coordinates
+	Address          string   `json:"address"`
+	AddressLatitude  *float64 `json:"address_latitude"`
+	AddressLongitude *float64 `json:"address_longitude"`
+	// Where the borrower lives, or the area a field validator covers, e.g. "Jakarta Selatan"
+	Region string `json:"region"`
 }
 
 type LoginResponse struct {
@@ -44,44 +71,165 @@ func (u *User) SetNewPassword(passwordString string) {
 	u.HashedPassword = bcryptPassword
 }
 
-// Notify investor by email when loan is fully invested
-func (u *User) NotifyEmailLoanFunded(ctx context.Context, emailService email.EmailService, loan *Loan, attachment io.Reader) error {
-	subject := "Loan has been fully funded!"
-	body := fmt.Sprintf(`
-			<h1>A loan you financed has been fully funded!</h1>
-			<p>You've invested in "%s", a loan requested by %s.</p>
-			<p>We're proud to let you know that the loan has been fully funded. Thank you for your contribution!.</p>
-			<p>They will now receive the full amount of Rp %s once it has been disbursed by our staff.</p>
-			<p>Attached is the loan agreement letter to sign.</p>
-			<p>Thank you for trusting LoanService.io!</p>
-		`,
-		loan.Name, loan.Borrower.Name, loan.PrincipalAmount,
-	)
-
-	err := emailService.SendMail(
-		ctx,
-		subject,
-		body,
-		mail.Email{Name: emailService.DefaultSenderName(), Address: emailService.DefaultSenderAddress()},
-		mail.Email{Name: u.Name, Address: u.Email},
-		&email.AttachmentOpts{
-			File:        attachment,
-			ContentType: email.AttachmentTypePDF,
-			Filename: fmt.Sprintf("Loan_Agreement_Letter-%s-%s-%s",
-				loan.Name, loan.Borrower.Name, loan.UpdatedAt.Format(time.RFC3339),
-			),
-		},
-	)
+// IsLocked returns true if the account is temporarily locked after too many failed logins
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// LoginAllowedAt returns the earliest time the user may attempt to log in again
+func (u *User) LoginAllowedAt() time.Time {
+	if u.LockedUntil != nil {
+		return *u.LockedUntil
+	}
+
+	if u.LastFailedLoginAt == nil {
+		return time.Time{}
+	}
+
+	return u.LastFailedLoginAt.Add(LoginDelay(u.FailedLoginAttempts))
+}
+
+// RegisterFailedLogin counts a failed login, and locks the account once there are too many
+func (u *User) RegisterFailedLogin(now time.Time) {
+	if u.LastFailedLoginAt != nil && now.Sub(*u.LastFailedLoginAt) > LoginAttemptWindow {
+		u.ResetFailedLogins()
+	}
+
+	u.FailedLoginAttempts++
+	u.LastFailedLoginAt = &now
+
+	if u.FailedLoginAttempts >= MaxFailedLoginAttempts {
+		lockedUntil := now.Add(LoginLockoutDuration)
+		u.LockedUntil = &lockedUntil
+	}
+}
+
+// FailedLoginColumns are the columns changed by RegisterFailedLogin and ResetFailedLogins
+var FailedLoginColumns = []string{"failed_login_attempts", "last_failed_login_at", "locked_until"}
+
+func (u *User) ResetFailedLogins() {
+	u.FailedLoginAttempts = 0
+	u.LastFailedLoginAt = nil
+	u.LockedUntil = nil
+}
+
+// Email to the invited user with a link to set their password
+func (u *User) NewEmailInvitation(invitedBy *User, invitationURL string) (*OutboxMessage, error) {
+	return u.newEmail(email.TemplateInvitation, email.InvitationData{
+		Name:           u.Name,
+		InvitedByName:  invitedBy.Name,
+		RoleName:       u.Role.Name,
+		InvitationURL:  invitationURL,
+		ExpiresInHours: int(InvitationTTL.Hours()),
+	})
+}
+
+// Email to the user that their account has been locked after too many failed logins
+func (u *User) NewEmailAccountLocked(attempt *LoginAttempt) (*OutboxMessage, error) {
+	return u.newEmail(email.TemplateAccountLocked, email.AccountLockedData{
+		Name:           u.Name,
+		FailedAttempts: u.FailedLoginAttempts,
+		IPAddress:      attempt.IPAddress,
+		LockedUntil:    *u.LockedUntil,
+	})
+}
+
+// Email to the user when they log in from a device we haven't seen before
+func (u *User) NewEmailNewDeviceLogin(attempt *LoginAttempt) (*OutboxMessage, error) {
+	return u.newEmail(email.TemplateNewDeviceLogin, email.NewDeviceLoginData{
+		Name:       u.Name,
+		LoggedInAt: attempt.CreatedAt,
+		IPAddress:  attempt.IPAddress,
+		UserAgent:  attempt.UserAgent,
+	})
+}
+
+// newEmail renders the template in the user's preferred locale as an email to be queued for the user
+func (u *User) newEmail(template email.TemplateName, data any) (*OutboxMessage, error) {
+	return u.NewOutboxMessage(NotificationChannelEmail, template, data)
+}
+
+// NewOutboxMessage renders the template in the user's preferred locale as a message to be queued for the user on the channel.
+// SMS and WhatsApp messages are sent to the user's phone number with the short text of the template.
+func (u *User) NewOutboxMessage(channel NotificationChannel, template email.TemplateName, data any) (*OutboxMessage, error) {
+	recipientID := u.ID
+	message := &OutboxMessage{
+		Channel:          channel,
+		RecipientID:      &recipientID,
+		RecipientName:    u.Name,
+		RecipientAddress: u.Email,
+		Locale:           i18n.Resolve(u.Locale),
+		Template:         template,
+	}
+
+	if channel.RequiresPhoneNumber() {
+		text, err := email.RenderShort(template, u.Locale, data)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		message.RecipientAddress = u.PhoneNumber
+		message.TextBody = text
+
+		return message, nil
+	}
+
+	rendered, err := email.Render(template, u.Locale, data)
 	if err != nil {
-		return errs.Wrap(err)
+		return nil, errs.Wrap(err)
 	}
 
-	return nil
+	message.Subject = rendered.Subject
+	message.HTMLBody = rendered.Body.HTML
+	message.TextBody = rendered.Body.Text
+
+	return message, nil
 }
 
+// NewInboxNotification renders the notification for the user's in-app inbox, with the email subject as its title
+func (u *User) NewInboxNotification(template email.TemplateName, notification *Notification) (*InboxNotification, error) {
+	rendered, err := email.Render(template, u.Locale, notification.Data)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	body, err := email.RenderShort(template, u.Locale, notification.Data)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	inboxNotification := &InboxNotification{
+		UserID: u.ID,
+		Event:  notification.Event,
+		Title:  rendered.Subject,
+		Body:   body,
+	}
+
+	if notification.LoanID > 0 {
+		loanID := notification.LoanID
+		inboxNotification.LoanID = &loanID
+	}
+
+	return inboxNotification, nil
+}
+
+const InvitationTTL = time.Hour * 72
+
 type ViewUsersOpt struct {
+	Permissions []auth.Permission
+	UserID      uint
+	// Filters
 	RoleType auth.RoleType
-	UserID   uint
+	IsActive *bool
+	IsLocked bool
+	Search   string
+}
+
+type FetchUsersOpts struct {
+	RoleTypes []auth.RoleType
+	IsActive  *bool
+	IsLocked  bool
+	Search    string // matches name or email
 }
 
 type FetchUserByIDOpts struct {
@@ -91,16 +239,27 @@ type FetchUserByIDOpts struct {
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *User) error
 	FetchUserByID(ctx context.Context, userID uint, opts *FetchUserByIDOpts) (*User, error)
-	FetchUsers(ctx context.Context, allowedRoles []auth.RoleType, allowedLoanIDs []uint) ([]User, error)
-	UpdateUser(ctx context.Context, user *User) error
+	FetchUsers(ctx context.Context, opts *FetchUsersOpts) ([]User, error)
+	// UpdateUser writes only the given columns of the user, so concurrent changes to the others are kept
+	UpdateUser(ctx context.Context, user *User, columns ...string) error
 	FetchRoleByRoleType(ctx context.Context, roleType auth.RoleType) (*Role, error)
 	FetchUserByEmail(ctx context.Context, email string) (*User, error)
+	FetchUserByInvitationTokenHash(ctx context.Context, tokenHash string) (*User, error)
+	FetchUserByOIDCSubject(ctx context.Context, subject string) (*User, error)
+	CreateLoginAttempt(ctx context.Context, attempt *LoginAttempt) error
+	CountLoginAttempts(ctx context.Context, opts *CountLoginAttemptsOpts) (int64, error)
 }
 
 type UserUsecase interface {
-	Login(ctx context.Context, email, password string) (LoginResponse, string, string, error)
+	Login(ctx context.Context, email, password string, opts *LoginOpts) (LoginResponse, string, string, error)
+	LoginWithOIDC(ctx context.Context, identity *oidc.Identity) (LoginResponse, string, string, error)
 	ViewUsers(ctx context.Context, opts ViewUsersOpt) ([]User, error)
 	FetchUserByID(ctx context.Context, userID uint, opts *FetchUserByIDOpts) (*User, error)
 	RegisterUser(ctx context.Context, user *User) error
 	UpdateProfile(ctx context.Context, user *User) error
+	InviteUser(ctx context.Context, actor *User, name, email string, roleType auth.RoleType) (*User, error)
+	AcceptInvitation(ctx context.Context, token, password string) error
+	SetUserActive(ctx context.Context, actor *User, userID uint, isActive bool) (*User, error)
+	ChangeUserRole(ctx context.Context, actor *User, userID uint, roleType auth.RoleType) (*User, error)
+	UnlockUser(ctx context.Context, actor *User, userID uint) (*User, error)
 }
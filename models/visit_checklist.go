@@ -0,0 +1,266 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"loan-service/services/auth"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type VisitQuestionType string
+
+const (
+	VisitQuestionYesNo  VisitQuestionType = "yes_no"
+	VisitQuestionNumber VisitQuestionType = "number"
+	VisitQuestionText   VisitQuestionType = "text"
+	// Answered with a photo uploaded along with the report
+	VisitQuestionPhoto VisitQuestionType = "photo"
+)
+
+var VisitQuestionTypes = []VisitQuestionType{
+	VisitQuestionYesNo,
+	VisitQuestionNumber,
+	VisitQuestionText,
+	VisitQuestionPhoto,
+}
+
+const visitAnswerMaxLength = 2000
+
+var visitQuestionKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// VisitQuestion is a question of a visit checklist, answers refer to it by its key
+type VisitQuestion struct {
+	Key      string            `json:"key"`
+	Prompt   string            `json:"prompt"`
+	Type     VisitQuestionType `json:"type"`
+	Required bool              `json:"required"`
+}
+
+// VisitQuestions are stored as JSON, in the order they are asked
+type VisitQuestions []VisitQuestion
+
+// Value implements driver.Valuer.
+func (q VisitQuestions) Value() (driver.Value, error) {
+	if q == nil {
+		return json.Marshal([]VisitQuestion{})
+	}
+
+	return json.Marshal([]VisitQuestion(q))
+}
+
+// Scan implements sql.Scanner.
+func (q *VisitQuestions) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*q = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, q)
+	case string:
+		return json.Unmarshal([]byte(value), q)
+	default:
+		return fmt.Errorf("cannot scan %T into visit questions", value)
+	}
+}
+
+// VisitChecklist is what the field validator answers when visiting the borrower of a loan of the product. Every change
+// is a new version, reports keep the questions as they were asked.
+type VisitChecklist struct {
+	gorm.Model
+	ProductID uint           `json:"product_id" gorm:"uniqueIndex"`
+	Version   int            `json:"version"`
+	Questions VisitQuestions `json:"questions" gorm:"type:jsonb"`
+}
+
+func (VisitChecklist) TableName() string {
+	return "visit_checklists"
+}
+
+// Validate checks the checklist has questions, each with a unique key, a prompt and a known type
+func (c *VisitChecklist) Validate() error {
+	if len(c.Questions) == 0 {
+		return NewInvalidVisitChecklistError("a visit checklist needs at least one question")
+	}
+
+	keys := map[string]bool{}
+	for i, question := range c.Questions {
+		if !visitQuestionKeyPattern.MatchString(question.Key) {
+			return NewInvalidVisitChecklistError("question %d: key must be up to 50 lowercase letters, digits or underscores", i+1)
+		}
+
+		if keys[question.Key] {
+			return NewInvalidVisitChecklistError("question %d: duplicate key `%s`", i+1, question.Key)
+		}
+		keys[question.Key] = true
+
+		if strings.TrimSpace(question.Prompt) == "" {
+			return NewInvalidVisitChecklistError("question `%s`: prompt is required", question.Key)
+		}
+
+		if !slices.Contains(VisitQuestionTypes, question.Type) {
+			return NewInvalidVisitChecklistError("question `%s`: unknown type `%s`", question.Key, question.Type)
+		}
+	}
+
+	return nil
+}
+
+// VisitReportInput is a completed visit checklist as submitted by the field validator. Photos are keyed by the
+// question they answer.
+type VisitReportInput struct {
+	Answers map[string]json.RawMessage
+	Photos  map[string]io.Reader
+}
+
+// Empty returns true if nothing was answered
+func (in *VisitReportInput) Empty() bool {
+	return in == nil || (len(in.Answers) == 0 && len(in.Photos) == 0)
+}
+
+// VisitAnswer is the answer to a checklist question, along with the question as it was asked. Optional questions left
+// unanswered have no value.
+type VisitAnswer struct {
+	VisitQuestion
+	YesNo  *bool    `json:"yes_no,omitempty"`
+	Number *float64 `json:"number,omitempty"`
+	Text   *string  `json:"text,omitempty"`
+	// Loan document of the photo
+	DocumentID *uint `json:"document_id,omitempty"`
+}
+
+// VisitReport is the visit checklist completed by the field validator
+type VisitReport struct {
+	ChecklistVersion int           `json:"checklist_version"`
+	Answers          []VisitAnswer `json:"answers"`
+}
+
+// Value implements driver.Valuer, the report is stored as JSON
+func (r VisitReport) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner.
+func (r *VisitReport) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, r)
+	case string:
+		return json.Unmarshal([]byte(value), r)
+	default:
+		return fmt.Errorf("cannot scan %T into visit report", value)
+	}
+}
+
+// NewReport validates the answers against the checklist: required questions must be answered, each with a value of
+// the question's type. The documents of the photos are attached to the report once stored.
+func (c *VisitChecklist) NewReport(input *VisitReportInput) (*VisitReport, error) {
+	if input == nil {
+		input = &VisitReportInput{}
+	}
+
+	questions := map[string]VisitQuestion{}
+	for _, question := range c.Questions {
+		questions[question.Key] = question
+	}
+
+	for key := range input.Answers {
+		question, ok := questions[key]
+		if !ok {
+			return nil, NewInvalidVisitReportError("unknown question `%s`", key)
+		}
+
+		if question.Type == VisitQuestionPhoto {
+			return nil, NewInvalidVisitReportError("question `%s` is answered with a photo", key)
+		}
+	}
+
+	for key := range input.Photos {
+		if question, ok := questions[key]; !ok || question.Type != VisitQuestionPhoto {
+			return nil, NewInvalidVisitReportError("unknown photo question `%s`", key)
+		}
+	}
+
+	report := &VisitReport{ChecklistVersion: c.Version}
+	for _, question := range c.Questions {
+		answer, err := newVisitAnswer(question, input)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Answers = append(report.Answers, *answer)
+	}
+
+	return report, nil
+}
+
+func newVisitAnswer(question VisitQuestion, input *VisitReportInput) (*VisitAnswer, error) {
+	answer := &VisitAnswer{VisitQuestion: question}
+
+	if question.Type == VisitQuestionPhoto {
+		if _, ok := input.Photos[question.Key]; !ok && question.Required {
+			return nil, NewInvalidVisitReportError("question `%s` is required", question.Key)
+		}
+
+		return answer, nil
+	}
+
+	raw := bytes.TrimSpace(input.Answers[question.Key])
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		if question.Required {
+			return nil, NewInvalidVisitReportError("question `%s` is required", question.Key)
+		}
+
+		return answer, nil
+	}
+
+	var err error
+	switch question.Type {
+	case VisitQuestionYesNo:
+		err = json.Unmarshal(raw, &answer.YesNo)
+	case VisitQuestionNumber:
+		err = json.Unmarshal(raw, &answer.Number)
+	case VisitQuestionText:
+		err = json.Unmarshal(raw, &answer.Text)
+		if err == nil {
+			text := strings.TrimSpace(*answer.Text)
+			answer.Text = &text
+
+			switch {
+			case text == "" && question.Required:
+				return nil, NewInvalidVisitReportError("question `%s` is required", question.Key)
+			case len(text) > visitAnswerMaxLength:
+				return nil, NewInvalidVisitReportError("question `%s`: answer is longer than %d characters", question.Key, visitAnswerMaxLength)
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, NewInvalidVisitReportError("question `%s` expects a %s answer", question.Key, question.Type)
+	}
+
+	return answer, nil
+}
+
+// VisitReportVisibleTo returns true if a user with the permissions may see visit reports, i.e. those making or
+// approving visits. Reports describe the borrower's home and business, so borrowers and investors do not see them.
+func VisitReportVisibleTo(permissions []auth.Permission) bool {
+	return auth.HasPermission(permissions, auth.PermissionLoanViewAll, auth.PermissionLoanVisit, auth.PermissionLoanApprove)
+}
+
+// AttachPhoto records the document of the photo answering the question
+func (r *VisitReport) AttachPhoto(key string, documentID uint) {
+	for i := range r.Answers {
+		if r.Answers[i].Key == key && r.Answers[i].Type == VisitQuestionPhoto {
+			r.Answers[i].DocumentID = &documentID
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"io"
+	"loan-service/utils/errs"
+
+	"gorm.io/gorm"
+)
+
+type VisitSyncStatus string
+
+const (
+	// The visit was recorded
+	VisitSyncApplied VisitSyncStatus = "applied"
+	// The loan changed while the visit was queued, e.g. it was visited by someone else, so the visit is dropped
+	VisitSyncConflict VisitSyncStatus = "conflict"
+	// The visit is invalid, e.g. a required answer is missing, and can be synced again once fixed
+	VisitSyncRejected VisitSyncStatus = "rejected"
+	// The visit could not be applied, and can be synced again as is
+	VisitSyncFailed VisitSyncStatus = "failed"
+)
+
+// Final returns true for the outcomes that are recorded, a replayed visit gets the same outcome without being applied
+// again
+func (s VisitSyncStatus) Final() bool {
+	return s == VisitSyncApplied || s == VisitSyncConflict
+}
+
+// VisitSync is the outcome of a visit queued offline by a field validator, identified by the ID the client generated
+type VisitSync struct {
+	gorm.Model
+	FieldValidatorID uint            `json:"field_validator_id" gorm:"uniqueIndex:idx_visit_syncs_client"`
+	ClientID         string          `json:"client_id" gorm:"uniqueIndex:idx_visit_syncs_client"`
+	LoanID           uint            `json:"loan_id"`
+	Status           VisitSyncStatus `json:"status"`
+	ErrorCode        string          `json:"error_code"`
+	Message          string          `json:"message"`
+	// When the outcome was decided, empty if the loan does not exist
+	LoanStatus LoanStatus `json:"loan_status"`
+}
+
+func (VisitSync) TableName() string {
+	return "visit_syncs"
+}
+
+// SetOutcome sets the status, along with the code and message of the error that caused it
+func (s *VisitSync) SetOutcome(status VisitSyncStatus, err error) {
+	s.Status = status
+	s.ErrorCode, s.Message = "", ""
+	if err == nil {
+		return
+	}
+
+	var generalErr errs.GeneralError
+	if errors.As(err, &generalErr) {
+		s.ErrorCode = generalErr.ErrorCode
+		s.Message = generalErr.Err.Error()
+		return
+	}
+
+	// Internal errors are not disclosed
+	s.ErrorCode = "InternalError"
+	s.Message = "The visit could not be applied, please sync it again."
+}
+
+// VisitSyncItem is a visit queued offline, with everything MarkLoanBorrowerVisited takes
+type VisitSyncItem struct {
+	ClientID   string
+	LoanID     uint
+	Attachment io.Reader
+	Reported   *VisitLocation
+	Report     *VisitReportInput
+}
+
+type VisitSyncResult struct {
+	VisitSync
+	// The outcome was recorded by an earlier sync of the same visit
+	Replayed bool
+	// The visited loan, only set when the visit was applied by this sync
+	Loan *Loan
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEventLoanApproved  WebhookEventType = "loan.approved"
+	WebhookEventLoanFunded    WebhookEventType = "loan.funded"
+	WebhookEventLoanDisbursed WebhookEventType = "loan.disbursed"
+)
+
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventLoanApproved,
+	WebhookEventLoanFunded,
+	WebhookEventLoanDisbursed,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	// Gave up after too many failed attempts, until replayed by staff
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+
+	MaxWebhookDeliveryAttempts = 8
+
+	webhookDeliveryBaseBackoff = time.Second * 30
+	webhookDeliveryMaxBackoff  = time.Hour * 6
+)
+
+// WebhookSubscription is a partner endpoint receiving the selected loan lifecycle events
+type WebhookSubscription struct {
+	gorm.Model
+	Name       string             `json:"name"`
+	URL        string             `json:"url"`
+	EventTypes []WebhookEventType `json:"event_types" gorm:"serializer:json"`
+	// Shared with the partner to verify payload signatures, only shown upon creation
+	Secret      string `json:"-"`
+	IsActive    bool   `json:"is_active" gorm:"default:true"`
+	CreatedByID uint   `json:"created_by_id"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Deliverable returns false for missing, deleted and inactive subscriptions
+func (w *WebhookSubscription) Deliverable() bool {
+	return w != nil && !w.DeletedAt.Valid && w.IsActive
+}
+
+// Subscribes returns true if the subscription is active and wants the event type
+func (w *WebhookSubscription) Subscribes(eventType WebhookEventType) bool {
+	if !w.IsActive {
+		return false
+	}
+
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDelivery is an event waiting to be delivered to a subscription, written in the same transaction as the change
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint                 `json:"subscription_id" gorm:"index"`
+	Subscription   *WebhookSubscription `json:"subscription,omitempty" gorm:"foreignKey:SubscriptionID"`
+	// Same for every delivery and replay of the event, so partners can drop duplicates
+	EventID   string           `json:"event_id" gorm:"index"`
+	EventType WebhookEventType `json:"event_type"`
+	Payload   string           `json:"payload"`
+	// Delivery this one replays, if any
+	ReplayOfID *uint `json:"replay_of_id"`
+
+	Status         WebhookDeliveryStatus `json:"status" gorm:"index"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" gorm:"index"`
+	LastStatusCode int                   `json:"last_status_code"`
+	LastError      string                `json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+
+	Logs []WebhookDeliveryLog `json:"logs,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// MarkDelivered records a successful attempt
+func (d *WebhookDelivery) MarkDelivered(now time.Time, statusCode int) {
+	d.Attempts++
+	d.Status = WebhookDeliveryStatusDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkFailed records a failed attempt, and schedules a retry with exponential backoff or dead-letters the delivery
+func (d *WebhookDelivery) MarkFailed(now time.Time, statusCode int, err error) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = err.Error()
+
+	if d.Attempts >= MaxWebhookDeliveryAttempts {
+		d.Status = WebhookDeliveryStatusDead
+		return
+	}
+
+	backoff := webhookDeliveryBaseBackoff << (d.Attempts - 1)
+	if backoff > webhookDeliveryMaxBackoff {
+		backoff = webhookDeliveryMaxBackoff
+	}
+
+	d.NextAttemptAt = now.Add(backoff)
+}
+
+// DeadLetter gives up on the delivery without another attempt
+func (d *WebhookDelivery) DeadLetter(err error) {
+	d.Attempts++
+	d.Status = WebhookDeliveryStatusDead
+	d.LastError = err.Error()
+}
+
+// NewReplay returns a pending copy of the delivery with the same event, to be sent again as soon as possible
+func (d *WebhookDelivery) NewReplay(now time.Time) *WebhookDelivery {
+	replayOfID := d.ID
+
+	return &WebhookDelivery{
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		ReplayOfID:     &replayOfID,
+		Status:         WebhookDeliveryStatusPending,
+		NextAttemptAt:  now,
+	}
+}
+
+// WebhookDeliveryLog records a single attempt of a delivery
+type WebhookDeliveryLog struct {
+	ID         uint          `json:"id" gorm:"primarykey"`
+	DeliveryID uint          `json:"delivery_id" gorm:"index"`
+	Attempt    int           `json:"attempt"`
+	StatusCode int           `json:"status_code"`
+	Error      string        `json:"error"`
+	Duration   time.Duration `json:"duration"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+func (WebhookDeliveryLog) TableName() string {
+	return "webhook_delivery_logs"
+}
+
+// WebhookEvent is the signed JSON body posted to subscriptions
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      any              `json:"data"`
+}
+
+// WebhookLoanData is the data of loan lifecycle events
+type WebhookLoanData struct {
+	LoanID          uint       `json:"loan_id"`
+	Name            string     `json:"name"`
+	Status          LoanStatus `json:"status"`
+	BorrowerID      uint       `json:"borrower_id"`
+	PrincipalAmount string     `json:"principal_amount"`
+	RemainingAmount string     `json:"remaining_amount"`
+	InterestRate    float64    `json:"interest_rate"`
+	TotalInterest   string     `json:"total_interest"`
+	ROI             string     `json:"roi"`
+	LoanTerm        int        `json:"loan_term"`
+	DisbursedAt     *time.Time `json:"disbursed_at,omitempty"`
+}
+
+// NewWebhookData returns the loan as sent to partners, without personal data of the borrower or investors
+func (l *Loan) NewWebhookData() WebhookLoanData {
+	return WebhookLoanData{
+		LoanID:          l.ID,
+		Name:            l.Name,
+		Status:          l.Status,
+		BorrowerID:      l.BorrowerID,
+		PrincipalAmount: l.PrincipalAmount,
+		RemainingAmount: l.RemainingAmount,
+		InterestRate:    l.InterestRate,
+		TotalInterest:   l.TotalInterest,
+		ROI:             l.ROI,
+		LoanTerm:        l.LoanTerm,
+		DisbursedAt:     l.DisbursedAt,
+	}
+}
+
+type FetchWebhookDeliveriesOpts struct {
+	SubscriptionID uint
+	Status         WebhookDeliveryStatus
+	Limit          int
+	Offset         int
+}
+
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	FetchWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	FetchWebhookSubscriptionByID(ctx context.Context, subscriptionID uint) (*WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID uint) error
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	FetchWebhookDeliveries(ctx context.Context, opts *FetchWebhookDeliveriesOpts) ([]WebhookDelivery, error)
+	// ClaimDueWebhookDeliveries locks the pending deliveries due at now, skipping those another dispatcher is claiming,
+	// and postpones them to leaseUntil so no other dispatcher sends them meanwhile
+	ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error)
+	FetchWebhookDeliveryByID(ctx context.Context, deliveryID uint) (*WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	CreateWebhookDeliveryLog(ctx context.Context, log *WebhookDeliveryLog) error
+}
+
+type WebhookUsecase interface {
+	// CreateWebhookSubscription returns the subscription and its secret, which is only shown once
+	CreateWebhookSubscription(ctx context.Context, actor *User, subscription *WebhookSubscription) (*WebhookSubscription, string, error)
+	FetchWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	FetchWebhookSubscriptionByID(ctx context.Context, subscriptionID uint) (*WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID uint) error
+	// Publish queues the event for every subscription of its type, in the transaction of the context if any
+	Publish(ctx context.Context, eventType WebhookEventType, data interface{}) error
+	DispatchDueDeliveries(ctx context.Context) (int, error)
+	FetchWebhookDeliveries(ctx context.Context, opts *FetchWebhookDeliveriesOpts) ([]WebhookDelivery, error)
+	FetchWebhookDeliveryByID(ctx context.Context, deliveryID uint) (*WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, deliveryID uint) (*WebhookDelivery, error)
+}
@@ -0,0 +1,94 @@
+package apikeys
+
+import (
+	"context"
+	"loan-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// CreateAPIKey implements models.APIKeyRepository.
+func (r *repository) CreateAPIKey(ctx context.Context, apiKey *models.APIKey) error {
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).Omit("ServiceAccount").Create(apiKey).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchAPIKeys implements models.APIKeyRepository.
+func (r *repository) FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]models.APIKey, error) {
+	var results []models.APIKey
+	query := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Preload("ServiceAccount").
+		Preload("Permissions")
+
+	if serviceAccountID > 0 {
+		query = query.Where("service_account_id = ?", serviceAccountID)
+	}
+
+	err := query.Order("id").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchAPIKeyByID implements models.APIKeyRepository.
+func (r *repository) FetchAPIKeyByID(ctx context.Context, apiKeyID uint) (*models.APIKey, error) {
+	var result *models.APIKey
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Preload("ServiceAccount").
+		Preload("Permissions").
+		Where("id = ?", apiKeyID).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchAPIKeyByPrefix implements models.APIKeyRepository.
+func (r *repository) FetchAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var result *models.APIKey
+	err := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Preload("ServiceAccount.Role.Permissions").
+		Preload("Permissions").
+		Where("prefix = ?", prefix).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateAPIKeyLastUsedAt implements models.APIKeyRepository.
+func (r *repository) UpdateAPIKeyLastUsedAt(ctx context.Context, apiKey *models.APIKey, lastUsedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(apiKey).UpdateColumn("last_used_at", lastUsedAt).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAPIKey implements models.APIKeyRepository.
+func (r *repository) RevokeAPIKey(ctx context.Context, apiKey *models.APIKey, revokedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(apiKey).Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewAPIKeyRepository(db *gorm.DB) models.APIKeyRepository {
+	return &repository{db}
+}
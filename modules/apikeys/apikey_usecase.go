@@ -0,0 +1,251 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/utils/errs"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix = "lsk"
+
+	// Last used timestamp is only written once per interval to avoid a write on every request
+	lastUsedAtResolution = time.Minute
+)
+
+type usecase struct {
+	repo     models.APIKeyRepository
+	userRepo models.UserRepository
+	roleRepo models.RoleRepository
+}
+
+// CreateServiceAccount implements models.APIKeyUsecase.
+func (u *usecase) CreateServiceAccount(ctx context.Context, actor *models.User, name string, roleType auth.RoleType) (*models.User, error) {
+	if actor == nil || name == "" || roleType == "" {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	if roleType.IsPrivileged() && actor.Role.RoleType != auth.RoleTypeSuperuser {
+		return nil, errs.Wrap(ErrPrivilegedRoleForbidden)
+	}
+
+	role, err := u.userRepo.FetchRoleByRoleType(ctx, roleType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrRoleNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if !actor.Role.MayAssign(role) {
+		return nil, errs.Wrap(ErrRoleExceedsPermissions)
+	}
+
+	serviceAccount := &models.User{
+		Name:             name,
+		IsActive:         true,
+		IsServiceAccount: true,
+		RoleID:           role.ID,
+	}
+
+	err = u.userRepo.CreateUser(ctx, serviceAccount)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	serviceAccount.Role = *role
+
+	return serviceAccount, nil
+}
+
+// CreateAPIKey implements models.APIKeyUsecase.
+func (u *usecase) CreateAPIKey(
+	ctx context.Context,
+	serviceAccountID uint,
+	actor *models.User,
+	name string,
+	permissions []auth.Permission,
+	expiresAt *time.Time,
+) (*models.APIKey, string, error) {
+	if actor == nil || name == "" || len(permissions) == 0 {
+		return nil, "", errs.Wrap(ErrInvalidParams)
+	}
+
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", errs.Wrap(ErrExpiryInThePast)
+	}
+
+	serviceAccount, err := u.userRepo.FetchUserByID(ctx, serviceAccountID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", errs.Wrap(ErrServiceAccountNotFound)
+	} else if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	if !serviceAccount.IsServiceAccount {
+		return nil, "", errs.Wrap(ErrServiceAccountNotFound)
+	}
+
+	// Keys act as the service account, so minting one is held to the same rule as creating the account
+	if serviceAccount.Role.RoleType.IsPrivileged() && actor.Role.RoleType != auth.RoleTypeSuperuser {
+		return nil, "", errs.Wrap(ErrPrivilegedRoleForbidden)
+	}
+
+	if !actor.Role.MayAssign(&serviceAccount.Role) {
+		return nil, "", errs.Wrap(ErrRoleExceedsPermissions)
+	}
+
+	// A key can only be scoped down from what the service account's role is granted
+	rolePermissions := serviceAccount.Role.PermissionCodes()
+	for _, permission := range permissions {
+		if !auth.HasPermission(rolePermissions, permission) {
+			return nil, "", errs.Wrap(ErrPermissionNotGranted)
+		}
+	}
+
+	scopes, err := u.roleRepo.FetchPermissionsByCodes(ctx, permissions)
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	prefix, rawKey, err := newAPIKey()
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	apiKey := &models.APIKey{
+		Name:             name,
+		Prefix:           prefix,
+		HashedKey:        hashAPIKey(rawKey),
+		ServiceAccountID: serviceAccount.ID,
+		CreatedByID:      actor.ID,
+		Permissions:      scopes,
+		ExpiresAt:        expiresAt,
+	}
+
+	err = u.repo.CreateAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	apiKey.ServiceAccount = *serviceAccount
+
+	return apiKey, rawKey, nil
+}
+
+// FetchAPIKeys implements models.APIKeyUsecase.
+func (u *usecase) FetchAPIKeys(ctx context.Context, serviceAccountID uint) ([]models.APIKey, error) {
+	apiKeys, err := u.repo.FetchAPIKeys(ctx, serviceAccountID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey implements models.APIKeyUsecase.
+func (u *usecase) RevokeAPIKey(ctx context.Context, apiKeyID uint) (*models.APIKey, error) {
+	apiKey, err := u.repo.FetchAPIKeyByID(ctx, apiKeyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrAPIKeyNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// Revoking is idempotent, keep the original revocation time
+	if apiKey.RevokedAt != nil {
+		return apiKey, nil
+	}
+
+	now := time.Now()
+	err = u.repo.RevokeAPIKey(ctx, apiKey, now)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	apiKey.RevokedAt = &now
+
+	return apiKey, nil
+}
+
+// Authenticate implements models.APIKeyUsecase.
+func (u *usecase) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, errs.Wrap(ErrInvalidAPIKey)
+	}
+
+	apiKey, err := u.repo.FetchAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrInvalidAPIKey)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.HashedKey), []byte(hashAPIKey(rawKey))) != 1 {
+		return nil, errs.Wrap(ErrInvalidAPIKey)
+	}
+
+	now := time.Now()
+	if !apiKey.IsUsable(now) || !apiKey.ServiceAccount.IsActive {
+		return nil, errs.Wrap(ErrInvalidAPIKey)
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedAtResolution {
+		err = u.repo.UpdateAPIKeyLastUsedAt(ctx, apiKey, now)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+// newAPIKey returns the visible key prefix and the full raw key, formatted as `lsk_<prefix>_<secret>`
+func newAPIKey() (string, string, error) {
+	prefixBytes := make([]byte, 4)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	prefix := fmt.Sprintf("%s_%s", apiKeyPrefix, hex.EncodeToString(prefixBytes))
+
+	return prefix, fmt.Sprintf("%s_%s", prefix, hex.EncodeToString(secretBytes)), nil
+}
+
+func parseAPIKeyPrefix(rawKey string) (string, bool) {
+	parts := strings.Split(rawKey, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+
+	return parts[0] + "_" + parts[1], true
+}
+
+func hashAPIKey(rawKey string) string {
+	keyHash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(keyHash[:])
+}
+
+func NewAPIKeyUsecase(
+	repo models.APIKeyRepository,
+	userRepo models.UserRepository,
+	roleRepo models.RoleRepository,
+) models.APIKeyUsecase {
+	return &usecase{repo, userRepo, roleRepo}
+}
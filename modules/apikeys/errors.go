@@ -0,0 +1,63 @@
+package apikeys
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrInvalidAPIKey = errs.GeneralError{
+		StatusCode: http.StatusUnauthorized,
+		ErrorCode:  "InvalidAPIKey",
+		Err:        errors.New("The API key is invalid, expired or revoked."),
+	}
+
+	ErrAPIKeyNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "APIKeyNotFound",
+		Err:        errors.New("Cannot find the requested API key."),
+	}
+
+	ErrServiceAccountNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "ServiceAccountNotFound",
+		Err:        errors.New("Cannot find the requested service account."),
+	}
+
+	ErrRoleNotFound = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "RoleNotFound",
+		Err:        errors.New("Cannot find the requested role."),
+	}
+
+	ErrPermissionNotGranted = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "PermissionNotGranted",
+		Err:        errors.New("API key permissions must be granted to the service account's role."),
+	}
+
+	ErrPrivilegedRoleForbidden = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "PrivilegedRoleForbidden",
+		Err:        errors.New("Only superusers can create staff and superuser service accounts, or their API keys."),
+	}
+
+	ErrRoleExceedsPermissions = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "RoleExceedsPermissions",
+		Err:        errors.New("You cannot create service accounts or API keys for a role with permissions you do not have."),
+	}
+
+	ErrExpiryInThePast = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "ExpiryInThePast",
+		Err:        errors.New("The API key expiry must be in the future."),
+	}
+)
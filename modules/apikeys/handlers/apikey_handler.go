@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/apikeys/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	Usecase     models.APIKeyUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewAPIKeyHandler(
+	g *echo.Group,
+	uc models.APIKeyUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &APIKeyHandler{uc, userUC}
+
+	requireAPIKeyManage := authMiddleware.RequirePermission(auth.PermissionAPIKeyManage)
+
+	g.POST("/service-accounts", handler.CreateServiceAccount, requireAPIKeyManage)
+	g.POST("/service-accounts/:user_id/api-keys", handler.CreateAPIKey, requireAPIKeyManage)
+	g.GET("/api-keys", handler.FetchAPIKeys, requireAPIKeyManage)
+	g.PATCH("/api-keys/:api_key_id/revoke", handler.RevokeAPIKey, requireAPIKeyManage)
+}
+
+func (h *APIKeyHandler) CreateServiceAccount(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.CreateServiceAccountRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	serviceAccount, err := h.Usecase.CreateServiceAccount(reqCtx, actor, body.Name, body.RoleType)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.ServiceAccountModelToDto(serviceAccount))
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.CreateAPIKeyRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	apiKey, rawKey, err := h.Usecase.CreateAPIKey(reqCtx, body.ServiceAccountID, actor, body.Name, body.Permissions, body.ExpiresAt)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.CreateAPIKeyResp{
+		FetchAPIKeyResp: *dto.ModelToDto(apiKey),
+		Key:             rawKey,
+	})
+}
+
+func (h *APIKeyHandler) FetchAPIKeys(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.FetchAPIKeysRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	apiKeys, err := h.Usecase.FetchAPIKeys(reqCtx, body.ServiceAccountID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelsToDto(apiKeys))
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.RevokeAPIKeyRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	apiKey, err := h.Usecase.RevokeAPIKey(reqCtx, body.APIKeyID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(apiKey))
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"loan-service/services/auth"
+	"time"
+)
+
+type CreateServiceAccountRequest struct {
+	Name     string        `json:"name" validate:"required,gt=0"`
+	RoleType auth.RoleType `json:"role_type" validate:"required,gt=0"`
+}
+
+type CreateAPIKeyRequest struct {
+	ServiceAccountID uint              `param:"user_id" validate:"required,gt=0"`
+	Name             string            `json:"name" validate:"required,gt=0"`
+	Permissions      []auth.Permission `json:"permissions" validate:"required,gt=0"`
+	ExpiresAt        *time.Time        `json:"expires_at"`
+}
+
+type FetchAPIKeysRequest struct {
+	ServiceAccountID uint `query:"service_account_id"`
+}
+
+type RevokeAPIKeyRequest struct {
+	APIKeyID uint `param:"api_key_id" validate:"required,gt=0"`
+}
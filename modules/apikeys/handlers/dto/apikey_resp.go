@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"loan-service/models"
+	"loan-service/services/auth"
+	"time"
+)
+
+type FetchServiceAccountResp struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	RoleType string `json:"role_type"`
+	IsActive bool   `json:"is_active"`
+}
+
+type FetchAPIKeyResp struct {
+	ID               uint              `json:"id"`
+	Name             string            `json:"name"`
+	Prefix           string            `json:"prefix"`
+	ServiceAccountID uint              `json:"service_account_id"`
+	ServiceAccount   string            `json:"service_account"`
+	Permissions      []auth.Permission `json:"permissions"`
+	CreatedAt        time.Time         `json:"created_at"`
+	ExpiresAt        *time.Time        `json:"expires_at"`
+	LastUsedAt       *time.Time        `json:"last_used_at"`
+	RevokedAt        *time.Time        `json:"revoked_at"`
+}
+
+// CreateAPIKeyResp is the only response that ever includes the raw key
+type CreateAPIKeyResp struct {
+	FetchAPIKeyResp
+	Key string `json:"key"`
+}
+
+func ServiceAccountModelToDto(u *models.User) *FetchServiceAccountResp {
+	if u == nil {
+		return nil
+	}
+
+	res := FetchServiceAccountResp{
+		ID:       u.ID,
+		Name:     u.Name,
+		RoleType: string(u.Role.RoleType),
+		IsActive: u.IsActive,
+	}
+
+	return &res
+}
+
+func ModelsToDto(apiKeys []models.APIKey) []FetchAPIKeyResp {
+	var result []FetchAPIKeyResp
+	for _, apiKey := range apiKeys {
+		result = append(result, *ModelToDto(&apiKey))
+	}
+
+	return result
+}
+
+func ModelToDto(k *models.APIKey) *FetchAPIKeyResp {
+	if k == nil {
+		return nil
+	}
+
+	permissions := make([]auth.Permission, 0, len(k.Permissions))
+	for _, permission := range k.Permissions {
+		permissions = append(permissions, permission.Code)
+	}
+
+	res := FetchAPIKeyResp{
+		ID:               k.ID,
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		ServiceAccountID: k.ServiceAccountID,
+		ServiceAccount:   k.ServiceAccount.Name,
+		Permissions:      permissions,
+		CreatedAt:        k.CreatedAt,
+		ExpiresAt:        k.ExpiresAt,
+		LastUsedAt:       k.LastUsedAt,
+		RevokedAt:        k.RevokedAt,
+	}
+
+	return &res
+}
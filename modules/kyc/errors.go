@@ -0,0 +1,45 @@
+package kyc
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrKYCProfileNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "KYCProfileNotFound",
+		Err:        errors.New("No identity has been submitted for this user."),
+	}
+
+	ErrKYCAlreadyVerified = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "KYCAlreadyVerified",
+		Err:        errors.New("Your identity is already verified, please contact us to change it."),
+	}
+
+	ErrKYCNIKTaken = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "KYCNIKTaken",
+		Err:        errors.New("This NIK is already registered to another account."),
+	}
+
+	ErrKYCNotPendingReview = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "KYCNotPendingReview",
+		Err:        errors.New("This identity is not waiting for review."),
+	}
+
+	ErrKYCDocumentNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "KYCDocumentNotFound",
+		Err:        errors.New("Cannot find this identity document."),
+	}
+)
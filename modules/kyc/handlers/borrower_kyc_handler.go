@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"io"
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/kyc/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BorrowerKYCHandler struct {
+	Usecase     models.KYCUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewBorrowerKYCHandler(
+	g *echo.Group,
+	uc models.KYCUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &BorrowerKYCHandler{uc, userUC}
+
+	requireKYCSubmit := authMiddleware.RequirePermission(auth.PermissionKYCSubmit)
+
+	g.GET("/kyc", handler.FetchKYCProfile, requireKYCSubmit)
+	g.POST("/kyc", handler.SubmitKYCProfile, requireKYCSubmit)
+}
+
+// FetchKYCProfile shows the borrower their identity and its verification status
+func (h *BorrowerKYCHandler) FetchKYCProfile(c echo.Context) error {
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	profile, err := h.Usecase.FetchKYCProfile(c.Request().Context(), claims.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ProfileToDto(profile, claims.Permissions))
+}
+
+// SubmitKYCProfile submits the borrower's identity with photos of their ID card and a selfie, as the id_card and
+// selfie files
+func (h *BorrowerKYCHandler) SubmitKYCProfile(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.SubmitKYCProfileRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	submission, ok := body.ToSubmission()
+	if !ok {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	for name, file := range map[string]*io.Reader{"id_card": &submission.IDCard, "selfie": &submission.Selfie} {
+		fileHeader, err := c.FormFile(name)
+		if err != nil {
+			return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+		}
+
+		opened, err := fileHeader.Open()
+		if err != nil {
+			return err
+		}
+
+		defer opened.Close()
+		*file = opened
+	}
+
+	borrower, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	profile, err := h.Usecase.SubmitKYCProfile(reqCtx, borrower, submission)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.ProfileToDto(profile, claims.Permissions))
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/kyc/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/services/upload"
+	"loan-service/utils/resp"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DocumentKYCHandler struct {
+	Usecase models.KYCUsecase
+}
+
+// NewDocumentKYCHandler serves identity photos to the borrower they belong to, and to staff reviewing identities
+func NewDocumentKYCHandler(
+	g *echo.Group,
+	uc models.KYCUsecase,
+) {
+	handler := &DocumentKYCHandler{uc}
+
+	g.GET("/kyc/documents/:document_id/:variant", handler.DownloadKYCDocument,
+		authMiddleware.RequirePermission(auth.PermissionKYCSubmit, auth.PermissionKYCReview))
+}
+
+func (h *DocumentKYCHandler) DownloadKYCDocument(c echo.Context) error {
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.DownloadKYCDocumentRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	err := upload.VerifyURL(c.Request().URL.Path, body.Expires, body.Signature, time.Now())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	file, fileInfo, err := h.Usecase.DownloadKYCDocument(
+		c.Request().Context(),
+		body.DocumentID,
+		claims.UserID,
+		claims.Permissions,
+		models.LoanDocumentVariant(body.Variant),
+	)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+	defer file.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", path.Base(fileInfo.Key)))
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	return c.Stream(http.StatusOK, fileInfo.ContentType, file)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"loan-service/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// SubmitKYCProfileRequest is a multipart request, with the id_card and selfie photos
+type SubmitKYCProfileRequest struct {
+	NIK           string  `form:"nik" validate:"required,len=16,numeric"`
+	DateOfBirth   string  `form:"date_of_birth" validate:"required"` // YYYY-MM-DD
+	Address       string  `form:"address" validate:"required,max=500"`
+	PhoneNumber   string  `form:"phone_number" validate:"required,max=20"`
+	Occupation    string  `form:"occupation" validate:"required,max=100"`
+	MonthlyIncome float64 `form:"monthly_income" validate:"required,gt=0"`
+}
+
+// ToSubmission returns false when the date of birth is not a date
+func (r *SubmitKYCProfileRequest) ToSubmission() (*models.KYCSubmission, bool) {
+	dateOfBirth, err := time.Parse(dateLayout, r.DateOfBirth)
+	if err != nil {
+		return nil, false
+	}
+
+	return &models.KYCSubmission{
+		NIK:           r.NIK,
+		DateOfBirth:   dateOfBirth,
+		Address:       strings.TrimSpace(r.Address),
+		PhoneNumber:   strings.TrimSpace(r.PhoneNumber),
+		Occupation:    strings.TrimSpace(r.Occupation),
+		MonthlyIncome: strconv.FormatFloat(r.MonthlyIncome, 'f', 2, 64),
+	}, true
+}
+
+type FetchKYCProfileRequest struct {
+	UserID uint `param:"user_id" validate:"required,gt=0"`
+}
+
+type ReviewKYCProfileRequest struct {
+	UserID   uint   `param:"user_id" validate:"required,gt=0"`
+	Decision string `json:"decision" validate:"required,oneof=approve reject"`
+	Note     string `json:"note" validate:"max=500"`
+}
+
+type DownloadKYCDocumentRequest struct {
+	DocumentID uint   `param:"document_id" validate:"required,gt=0"`
+	Variant    string `param:"variant" validate:"required,oneof=file thumbnail"`
+	Expires    string `query:"expires" validate:"required"`
+	Signature  string `query:"signature" validate:"required"`
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"loan-service/config"
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/services/upload"
+	"loan-service/utils/money"
+	"time"
+)
+
+type KYCProfileResp struct {
+	UserID        uint      `json:"user_id"`
+	Name          string    `json:"name"`
+	NIK           string    `json:"nik"`
+	DateOfBirth   string    `json:"date_of_birth"`
+	Address       string    `json:"address"`
+	PhoneNumber   string    `json:"phone_number"`
+	Occupation    string    `json:"occupation"`
+	MonthlyIncome string    `json:"monthly_income"`
+	Status        string    `json:"status"`
+	SubmittedAt   time.Time `json:"submitted_at"`
+	// Signed download links, expiring after ATTACHMENT_URL_TTL
+	IDCardURL          string `json:"id_card_url,omitempty"`
+	IDCardThumbnailURL string `json:"id_card_thumbnail_url,omitempty"`
+	SelfieURL          string `json:"selfie_url,omitempty"`
+	SelfieThumbnailURL string `json:"selfie_thumbnail_url,omitempty"`
+	// Outcome of the e-KYC provider, for staff reviewing the identity
+	Provider   *KYCProviderResp `json:"provider,omitempty"`
+	ReviewedBy *UserResp        `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewNote string           `json:"review_note,omitempty"`
+}
+
+type KYCProviderResp struct {
+	Name      string `json:"name"`
+	Reference string `json:"reference,omitempty"`
+	Decision  string `json:"decision"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type UserResp struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func ProfilesToDto(profiles []models.KYCProfile, permissions []auth.Permission) []KYCProfileResp {
+	res := []KYCProfileResp{}
+	for i := range profiles {
+		res = append(res, *ProfileToDto(&profiles[i], permissions))
+	}
+
+	return res
+}
+
+func ProfileToDto(p *models.KYCProfile, permissions []auth.Permission) *KYCProfileResp {
+	if p == nil {
+		return nil
+	}
+
+	res := KYCProfileResp{
+		UserID:             p.UserID,
+		NIK:                p.NIK,
+		DateOfBirth:        p.DateOfBirth.Format(dateLayout),
+		Address:            p.Address,
+		PhoneNumber:        p.PhoneNumber,
+		Occupation:         p.Occupation,
+		MonthlyIncome:      money.DisplayMoney(p.MonthlyIncome),
+		Status:             string(p.Status),
+		SubmittedAt:        p.SubmittedAt,
+		IDCardURL:          documentURL(p.IDCard, models.LoanDocumentFile),
+		IDCardThumbnailURL: documentURL(p.IDCard, models.LoanDocumentThumbnail),
+		SelfieURL:          documentURL(p.Selfie, models.LoanDocumentFile),
+		SelfieThumbnailURL: documentURL(p.Selfie, models.LoanDocumentThumbnail),
+		ReviewedAt:         p.ReviewedAt,
+		ReviewNote:         p.ReviewNote,
+	}
+
+	if p.User != nil {
+		res.Name = p.User.Name
+	}
+
+	if p.Reviewer != nil {
+		res.ReviewedBy = &UserResp{Name: p.Reviewer.Name, Email: p.Reviewer.Email}
+	}
+
+	if auth.HasPermission(permissions, auth.PermissionKYCReview) && p.ProviderName != "" {
+		res.Provider = &KYCProviderResp{
+			Name:      p.ProviderName,
+			Reference: p.ProviderReference,
+			Decision:  p.ProviderDecision,
+			Reason:    p.ProviderReason,
+		}
+	}
+
+	return &res
+}
+
+func documentURL(d *models.KYCDocument, variant models.LoanDocumentVariant) string {
+	if d == nil || d.Key(variant) == "" {
+		return ""
+	}
+
+	return upload.SignURL(d.DownloadPath(variant), config.Data.AttachmentURLTTL, time.Now())
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/kyc/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StaffKYCHandler struct {
+	Usecase     models.KYCUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewStaffKYCHandler(
+	g *echo.Group,
+	uc models.KYCUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &StaffKYCHandler{uc, userUC}
+
+	requireKYCReview := authMiddleware.RequirePermission(auth.PermissionKYCReview)
+
+	g.GET("/kyc/reviews", handler.FetchKYCReviews, requireKYCReview)
+	g.GET("/kyc/:user_id", handler.FetchKYCProfile, requireKYCReview)
+	g.PATCH("/kyc/:user_id/review", handler.ReviewKYCProfile, requireKYCReview)
+}
+
+// FetchKYCReviews lists the identities waiting for review, oldest first
+func (h *StaffKYCHandler) FetchKYCReviews(c echo.Context) error {
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	profiles, err := h.Usecase.FetchKYCReviews(c.Request().Context())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ProfilesToDto(profiles, claims.Permissions))
+}
+
+func (h *StaffKYCHandler) FetchKYCProfile(c echo.Context) error {
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchKYCProfileRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	profile, err := h.Usecase.FetchKYCProfile(c.Request().Context(), body.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ProfileToDto(profile, claims.Permissions))
+}
+
+// ReviewKYCProfile verifies or rejects an identity the e-KYC provider could not decide on
+func (h *StaffKYCHandler) ReviewKYCProfile(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.ReviewKYCProfileRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	profile, err := h.Usecase.FetchKYCProfile(reqCtx, body.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	staff, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	err = h.Usecase.ReviewKYCProfile(reqCtx, profile, staff, body.Decision == "approve", body.Note)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ProfileToDto(profile, claims.Permissions))
+}
@@ -0,0 +1,90 @@
+package kyc
+
+import (
+	"context"
+	"loan-service/database"
+	"loan-service/models"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// FetchKYCProfiles implements models.KYCRepository.
+func (r *repository) FetchKYCProfiles(ctx context.Context, opts *models.FetchKYCProfilesOpts) ([]models.KYCProfile, error) {
+	var results []models.KYCProfile
+	query := database.Conn(ctx, r.db).Model(&models.KYCProfile{}).
+		Preload("User").
+		Preload("IDCard").
+		Preload("Selfie").
+		Preload("Reviewer")
+
+	if opts != nil && len(opts.Status) > 0 {
+		query = query.Where("status IN (?)", opts.Status)
+	}
+
+	if opts != nil && opts.NIK != "" {
+		query = query.Where("nik = ?", opts.NIK)
+	}
+
+	err := query.Order("submitted_at").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchKYCProfileByUserID implements models.KYCRepository.
+func (r *repository) FetchKYCProfileByUserID(ctx context.Context, userID uint) (*models.KYCProfile, error) {
+	var result *models.KYCProfile
+	err := database.Conn(ctx, r.db).Model(&models.KYCProfile{}).
+		Preload("User").
+		Preload("IDCard").
+		Preload("Selfie").
+		Preload("Reviewer").
+		Where("user_id = ?", userID).
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SaveKYCProfile implements models.KYCRepository.
+func (r *repository) SaveKYCProfile(ctx context.Context, profile *models.KYCProfile) error {
+	err := database.Conn(ctx, r.db).Omit("User", "IDCard", "Selfie", "Reviewer").Save(profile).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchKYCDocumentByID implements models.KYCRepository.
+func (r *repository) FetchKYCDocumentByID(ctx context.Context, documentID uint) (*models.KYCDocument, error) {
+	var result *models.KYCDocument
+	err := database.Conn(ctx, r.db).Model(&models.KYCDocument{}).Where("id = ?", documentID).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateKYCDocument implements models.KYCRepository.
+func (r *repository) CreateKYCDocument(ctx context.Context, document *models.KYCDocument) error {
+	err := database.Conn(ctx, r.db).Create(document).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewKYCRepository(db *gorm.DB) models.KYCRepository {
+	return &repository{db}
+}
@@ -0,0 +1,293 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"loan-service/models"
+	"loan-service/services/attachment"
+	"loan-service/services/auth"
+	"loan-service/services/ekyc"
+	"loan-service/services/upload"
+	"loan-service/utils/errs"
+	"time"
+
+	"github.com/subosito/gozaru"
+	"gorm.io/gorm"
+)
+
+// Identity photos are taken with the phone camera
+var documentContentTypes = []string{"image/jpeg", "image/png"}
+
+type usecase struct {
+	repo               models.KYCRepository
+	transactor         models.Transactor
+	provider           ekyc.Provider
+	uploadService      upload.UploadService
+	attachmentPipeline attachment.Pipeline
+}
+
+// FetchKYCProfile implements models.KYCUsecase.
+func (u *usecase) FetchKYCProfile(ctx context.Context, userID uint) (*models.KYCProfile, error) {
+	profile, err := u.repo.FetchKYCProfileByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrKYCProfileNotFound)
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return profile, nil
+}
+
+// SubmitKYCProfile implements models.KYCUsecase.
+func (u *usecase) SubmitKYCProfile(
+	ctx context.Context,
+	borrower *models.User,
+	submission *models.KYCSubmission,
+) (*models.KYCProfile, error) {
+	if borrower == nil || submission == nil || submission.IDCard == nil || submission.Selfie == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	profile, err := u.repo.FetchKYCProfileByUserID(ctx, borrower.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		profile = &models.KYCProfile{UserID: borrower.ID}
+	case err != nil:
+		return nil, errs.Wrap(err)
+	case profile.Verified():
+		return nil, errs.Wrap(ErrKYCAlreadyVerified)
+	}
+
+	now := time.Now()
+	profile.NIK = submission.NIK
+	profile.DateOfBirth = submission.DateOfBirth
+	profile.Address = submission.Address
+	profile.PhoneNumber = submission.PhoneNumber
+	profile.Occupation = submission.Occupation
+	profile.MonthlyIncome = submission.MonthlyIncome
+	if err := profile.Validate(now); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// A NIK belongs to a single account, unless its identity was rejected
+	others, err := u.repo.FetchKYCProfiles(ctx, &models.FetchKYCProfilesOpts{
+		NIK:    submission.NIK,
+		Status: []models.KYCStatus{models.KYCStatusPending, models.KYCStatusVerified},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	for _, other := range others {
+		if other.UserID != borrower.ID {
+			return nil, errs.Wrap(ErrKYCNIKTaken)
+		}
+	}
+
+	idCard, idCardData, err := u.processDocument(ctx, borrower, models.KYCDocumentIDCard, submission.IDCard)
+	if err != nil {
+		return nil, err
+	}
+
+	selfie, selfieData, err := u.processDocument(ctx, borrower, models.KYCDocumentSelfie, submission.Selfie)
+	if err != nil {
+		return nil, err
+	}
+
+	result := u.verify(ctx, borrower, profile, idCardData, selfieData)
+
+	profile.SubmittedAt = now
+	profile.ProviderName = u.provider.Name()
+	profile.ProviderReference = result.Reference
+	profile.ProviderDecision = string(result.Decision)
+	profile.ProviderReason = result.Reason
+	profile.ReviewerID, profile.Reviewer, profile.ReviewedAt, profile.ReviewNote = nil, nil, nil, ""
+
+	switch result.Decision {
+	case ekyc.DecisionApproved:
+		profile.Status = models.KYCStatusVerified
+		profile.ReviewedAt = &now
+	case ekyc.DecisionRejected:
+		profile.Status = models.KYCStatusRejected
+		profile.ReviewedAt = &now
+		profile.ReviewNote = result.Reason
+	default:
+		profile.Status = models.KYCStatusPending
+	}
+
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		for _, document := range []*models.KYCDocument{idCard, selfie} {
+			err := u.repo.CreateKYCDocument(txCtx, document)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		profile.IDCardID, profile.SelfieID = &idCard.ID, &selfie.ID
+
+		return u.repo.SaveKYCProfile(txCtx, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	profile.User, profile.IDCard, profile.Selfie = borrower, idCard, selfie
+
+	return profile, nil
+}
+
+// verify has the provider check the identity. Submissions are kept when the provider fails, and wait for staff review
+// instead.
+func (u *usecase) verify(
+	ctx context.Context,
+	borrower *models.User,
+	profile *models.KYCProfile,
+	idCard, selfie []byte,
+) *ekyc.Result {
+	result, err := u.provider.Verify(ctx, ekyc.Request{
+		NIK:         profile.NIK,
+		Name:        borrower.Name,
+		DateOfBirth: profile.DateOfBirth,
+		IDCard:      idCard,
+		Selfie:      selfie,
+	})
+	if err != nil {
+		fmt.Printf("[kyc] %s verification of user %d failed: %v\n", u.provider.Name(), borrower.ID, err)
+		return &ekyc.Result{Decision: ekyc.DecisionReview, Reason: "automated verification unavailable"}
+	}
+
+	return result
+}
+
+// processDocument runs the photo through the attachment pipeline and stores its variants, returning the document to
+// create along with the processed photo
+func (u *usecase) processDocument(
+	ctx context.Context,
+	borrower *models.User,
+	documentType models.KYCDocumentType,
+	file io.Reader,
+) (*models.KYCDocument, []byte, error) {
+	processed, err := u.attachmentPipeline.Process(ctx, file, documentContentTypes...)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	document := &models.KYCDocument{UserID: borrower.ID, DocumentType: documentType}
+	baseName := fmt.Sprintf("kyc_%s_%d_%s", documentType, borrower.ID, time.Now().Format(time.RFC3339))
+	for _, variant := range processed.Variants {
+		filename := baseName
+		if variant.Name != attachment.VariantOriginal {
+			filename += "_" + variant.Name
+		}
+
+		uploadedFile, err := u.uploadService.UploadFile(
+			ctx,
+			bytes.NewReader(variant.Data),
+			gozaru.Sanitize(filename+variant.Extension),
+			variant.ContentType,
+		)
+		if err != nil {
+			return nil, nil, errs.Wrap(err)
+		}
+
+		switch variant.Name {
+		case attachment.VariantOriginal:
+			document.FileKey = uploadedFile.Key
+		case attachment.VariantThumbnail:
+			document.ThumbnailKey = uploadedFile.Key
+		}
+	}
+
+	return document, processed.Variant(attachment.VariantOriginal).Data, nil
+}
+
+// FetchKYCReviews implements models.KYCUsecase.
+func (u *usecase) FetchKYCReviews(ctx context.Context) ([]models.KYCProfile, error) {
+	profiles, err := u.repo.FetchKYCProfiles(ctx, &models.FetchKYCProfilesOpts{
+		Status: []models.KYCStatus{models.KYCStatusPending},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return profiles, nil
+}
+
+// ReviewKYCProfile implements models.KYCUsecase.
+func (u *usecase) ReviewKYCProfile(
+	ctx context.Context,
+	profile *models.KYCProfile,
+	reviewer *models.User,
+	approve bool,
+	note string,
+) error {
+	if profile == nil || reviewer == nil {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	if profile.Status != models.KYCStatusPending {
+		return errs.Wrap(ErrKYCNotPendingReview)
+	}
+
+	now := time.Now()
+	profile.Status = models.KYCStatusRejected
+	if approve {
+		profile.Status = models.KYCStatusVerified
+	}
+
+	profile.ReviewerID = &reviewer.ID
+	profile.Reviewer = reviewer
+	profile.ReviewedAt = &now
+	profile.ReviewNote = note
+
+	err := u.repo.SaveKYCProfile(ctx, profile)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// DownloadKYCDocument implements models.KYCUsecase.
+func (u *usecase) DownloadKYCDocument(
+	ctx context.Context,
+	documentID uint,
+	userID uint,
+	permissions []auth.Permission,
+	variant models.LoanDocumentVariant,
+) (io.ReadCloser, *upload.UploadedFile, error) {
+	document, err := u.repo.FetchKYCDocumentByID(ctx, documentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errs.Wrap(ErrKYCDocumentNotFound)
+	}
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	// Documents of other borrowers are reported as missing, so their existence is not disclosed either
+	key := document.Key(variant)
+	if key == "" || !models.KYCDocumentVisibleTo(document, userID, permissions) {
+		return nil, nil, errs.Wrap(ErrKYCDocumentNotFound)
+	}
+
+	file, fileInfo, err := u.uploadService.DownloadFile(ctx, key)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	return file, fileInfo, nil
+}
+
+func NewKYCUsecase(
+	repo models.KYCRepository,
+	transactor models.Transactor,
+	provider ekyc.Provider,
+	uploadService upload.UploadService,
+	attachmentPipeline attachment.Pipeline,
+) models.KYCUsecase {
+	return &usecase{repo, transactor, provider, uploadService, attachmentPipeline}
+}
@@ -0,0 +1,270 @@
+package loans
+
+import (
+	"context"
+	"loan-service/models"
+	"loan-service/utils/errs"
+)
+
+// ApproveLoan implements models.LoanUsecase.
+func (u *usecase) ApproveLoan(
+	ctx context.Context,
+	loan *models.Loan,
+	approver *models.User,
+	comment string,
+) (*models.ApprovalStatus, error) {
+	if err := checkApprovable(loan, "ApproveLoan"); err != nil {
+		return nil, err
+	}
+
+	policies, err := u.repo.FetchApprovalPolicies(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	var status *models.ApprovalStatus
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		var err error
+		status, err = u.lockApprovalStatus(txCtx, loan, approver, policies, "ApproveLoan")
+		if err != nil {
+			return err
+		}
+
+		if status.HasVoted(approver.ID) {
+			return errs.Wrap(ErrLoanAlreadyVoted)
+		}
+
+		level := status.NextLevel()
+		if !status.MayVoteAt(approver, level) {
+			return errs.Wrap(ErrSuperuserApprovalRequired)
+		}
+
+		err = u.castVote(txCtx, loan, approver, level, models.ApprovalDecisionApprove, comment)
+		if err != nil {
+			return err
+		}
+
+		status = models.NewApprovalStatus(status.Policy, loan.ApprovalRound, loan.Approvals)
+		if !status.Satisfied() {
+			return nil
+		}
+
+		// The last approver completes the policy, and is the one recorded on the loan
+		loan.Approver = approver
+		loan.ApproverID = &approver.ID
+		err = loan.AdvanceState(models.LoanStatusApproved, "ApproveLoan")
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		// Priced again with the latest grade and rate grid, investors are offered the approved rate
+		pricing := loan.Price(&loan.Product, models.PricingOnApproval, approver)
+		err = u.repo.UpdateLoan(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		err = u.repo.CreateLoanPricing(txCtx, pricing)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		err = u.notificationUsecase.Notify(txCtx, &loan.Borrower, loan.NewApprovedNotification())
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.webhookUsecase.Publish(txCtx, models.WebhookEventLoanApproved, loan.NewWebhookData())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.Status == models.LoanStatusApproved {
+		u.events.Publish(loan.ID, loan.NewEvent(models.LoanEventStatusChanged))
+	}
+
+	return status, nil
+}
+
+// RejectLoan implements models.LoanUsecase.
+func (u *usecase) RejectLoan(
+	ctx context.Context,
+	loan *models.Loan,
+	approver *models.User,
+	comment string,
+) (*models.ApprovalStatus, error) {
+	if err := checkApprovable(loan, "RejectLoan"); err != nil {
+		return nil, err
+	}
+
+	policies, err := u.repo.FetchApprovalPolicies(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	var status *models.ApprovalStatus
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		var err error
+		status, err = u.lockApprovalStatus(txCtx, loan, approver, policies, "RejectLoan")
+		if err != nil {
+			return err
+		}
+
+		// Any approver may reject, at whichever level the approval has reached
+		err = u.castVote(txCtx, loan, approver, status.NextLevel(), models.ApprovalDecisionReject, comment)
+		if err != nil {
+			return err
+		}
+
+		loan.ApprovalRound++
+		err = u.repo.UpdateLoan(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		status = models.NewApprovalStatus(status.Policy, loan.ApprovalRound, loan.Approvals)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// lockApprovalStatus locks the loan, and checks the approver may vote on it in its current round
+func (u *usecase) lockApprovalStatus(
+	ctx context.Context,
+	loan *models.Loan,
+	approver *models.User,
+	policies []models.ApprovalPolicy,
+	action string,
+) (*models.ApprovalStatus, error) {
+	err := u.repo.LockLoanApprovals(ctx, loan)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// Another vote may have approved the loan meanwhile
+	if err := checkApprovable(loan, action); err != nil {
+		return nil, err
+	}
+
+	status := models.NewApprovalStatus(models.ApprovalPolicyFor(policies, loan), loan.ApprovalRound, loan.Approvals)
+	if status.IsVisitorBarred(loan, approver) {
+		return nil, errs.Wrap(ErrApproverIsVisitor)
+	}
+
+	return status, nil
+}
+
+func (u *usecase) castVote(
+	ctx context.Context,
+	loan *models.Loan,
+	approver *models.User,
+	level models.ApprovalLevel,
+	decision models.ApprovalDecision,
+	comment string,
+) error {
+	vote := models.LoanApproval{
+		LoanID:     loan.ID,
+		Round:      loan.ApprovalRound,
+		ApproverID: approver.ID,
+		Level:      level,
+		Decision:   decision,
+		Comment:    comment,
+	}
+
+	err := u.repo.CreateLoanApproval(ctx, &vote)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	vote.Approver = approver
+	loan.Approvals = append(loan.Approvals, vote)
+
+	return nil
+}
+
+// checkApprovable returns the error approving the loan would fail with, so no vote is cast on a loan that cannot be
+// approved yet
+func checkApprovable(loan *models.Loan, action string) error {
+	if loan.Status == models.LoanStatusProposed && !loan.Visit.Approvable() {
+		return errs.Wrap(ErrLoanVisitNotApprovable)
+	}
+
+	next := *loan
+	if err := next.AdvanceState(models.LoanStatusApproved, action); err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// FetchLoanApprovalStatus implements models.LoanUsecase.
+func (u *usecase) FetchLoanApprovalStatus(ctx context.Context, loan *models.Loan) (*models.ApprovalStatus, error) {
+	if loan == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	policies, err := u.repo.FetchApprovalPolicies(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return models.NewApprovalStatus(models.ApprovalPolicyFor(policies, loan), loan.ApprovalRound, loan.Approvals), nil
+}
+
+// FetchPendingApprovals implements models.LoanUsecase.
+func (u *usecase) FetchPendingApprovals(ctx context.Context, opts *models.FetchLoanOpts) ([]models.PendingApproval, error) {
+	if opts == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	opts.Status = []models.LoanStatus{models.LoanStatusProposed}
+	opts.AwaitingApproval = true
+	loans, err := u.FetchLoans(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := u.repo.FetchApprovalPolicies(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	results := []models.PendingApproval{}
+	for i := range loans {
+		status := models.NewApprovalStatus(models.ApprovalPolicyFor(policies, &loans[i]), loans[i].ApprovalRound, loans[i].Approvals)
+		results = append(results, models.PendingApproval{Loan: loans[i], Status: *status})
+	}
+
+	return results, nil
+}
+
+// FetchApprovalPolicies implements models.LoanUsecase.
+func (u *usecase) FetchApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	policies, err := u.repo.FetchApprovalPolicies(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return policies, nil
+}
+
+// SetApprovalPolicies implements models.LoanUsecase.
+// Loans waiting for approval follow the new tiers from their next vote, votes already cast keep counting.
+func (u *usecase) SetApprovalPolicies(ctx context.Context, policies []models.ApprovalPolicy) ([]models.ApprovalPolicy, error) {
+	if err := models.ValidateApprovalPolicies(policies); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err := u.repo.ReplaceApprovalPolicies(ctx, policies)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return u.FetchApprovalPolicies(ctx)
+}
@@ -0,0 +1,295 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"loan-service/config"
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/utils/errs"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssignLoan implements models.LoanUsecase.
+func (u *usecase) AssignLoan(
+	ctx context.Context,
+	loan *models.Loan,
+	actor *models.User,
+	fieldValidatorID uint,
+	scheduledAt *time.Time,
+	note string,
+) (*models.LoanAssignment, error) {
+	if loan == nil || actor == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	strategy := models.AssignmentManual
+	if fieldValidatorID == 0 {
+		workloads, err := u.repo.FetchFieldValidatorWorkloads(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		strategy = assignmentStrategy()
+		picked := strategy.Pick(loan, workloads)
+		if picked == nil {
+			return nil, errs.Wrap(ErrNoFieldValidatorAvailable)
+		}
+
+		fieldValidatorID = picked.FieldValidatorID
+	}
+
+	assignment, err := u.assign(ctx, loan, fieldValidatorID, strategy, &actor.ID, scheduledAt, note)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	assignment.AssignedBy = actor
+
+	return assignment, nil
+}
+
+// AutoAssignLoans implements models.LoanUsecase.
+func (u *usecase) AutoAssignLoans(ctx context.Context) ([]models.LoanAssignment, error) {
+	loans, err := u.repo.FetchLoans(ctx, &models.FetchLoanOpts{
+		Status: []models.LoanStatus{models.LoanStatusProposed},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	workloads, err := u.repo.FetchFieldValidatorWorkloads(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	strategy := assignmentStrategy()
+	assigned := []models.LoanAssignment{}
+	for i := range loans {
+		loan := &loans[i]
+		if loan.VisitorID != nil || loan.ActiveAssignment() != nil {
+			continue
+		}
+
+		picked := strategy.Pick(loan, workloads)
+		if picked == nil {
+			return assigned, errs.Wrap(ErrNoFieldValidatorAvailable)
+		}
+
+		assignment, err := u.assign(ctx, loan, picked.FieldValidatorID, strategy, nil, nil, "")
+		if err != nil {
+			return assigned, errs.Wrap(err)
+		}
+
+		// Later loans of the run see the new workload, so they are spread among field validators
+		picked.OpenAssignments++
+		picked.LastAssignedAt = &assignment.CreatedAt
+
+		assigned = append(assigned, *assignment)
+	}
+
+	return assigned, nil
+}
+
+// assign makes the field validator the one to visit the borrower, and notifies them
+func (u *usecase) assign(
+	ctx context.Context,
+	loan *models.Loan,
+	fieldValidatorID uint,
+	strategy models.AssignmentStrategy,
+	assignedByID *uint,
+	scheduledAt *time.Time,
+	note string,
+) (*models.LoanAssignment, error) {
+	if loan.Status != models.LoanStatusProposed || loan.VisitorID != nil {
+		return nil, errs.Wrap(ErrLoanNotAssignable)
+	}
+
+	fieldValidator, err := u.userUsecase.FetchUserByID(ctx, fieldValidatorID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrInvalidFieldValidator)
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if !fieldValidator.IsActive || fieldValidator.Role.RoleType != auth.RoleTypeFieldValidator {
+		return nil, errs.Wrap(ErrInvalidFieldValidator)
+	}
+
+	assignment := &models.LoanAssignment{
+		LoanID:           loan.ID,
+		Loan:             loan,
+		FieldValidatorID: fieldValidator.ID,
+		FieldValidator:   fieldValidator,
+		Status:           models.LoanAssignmentActive,
+		Strategy:         strategy,
+		AssignedByID:     assignedByID,
+		Note:             note,
+		DueAt:            time.Now().Add(visitSLA()),
+	}
+
+	if scheduledAt != nil {
+		assignment.Schedule(*scheduledAt, visitSlotDuration())
+		if err := u.checkVisitSlot(ctx, assignment); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.CreateLoanAssignment(txCtx, assignment)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.notificationUsecase.Notify(txCtx, fieldValidator, assignment.NewAssignedNotification(fieldValidator))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loan.Assignments = []models.LoanAssignment{*assignment}
+
+	return assignment, nil
+}
+
+// FetchLoanAssignments implements models.LoanUsecase.
+func (u *usecase) FetchLoanAssignments(ctx context.Context, opts *models.FetchLoanAssignmentsOpts) ([]models.LoanAssignment, error) {
+	assignments, err := u.repo.FetchLoanAssignments(ctx, opts)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return assignments, nil
+}
+
+// ScheduleLoanVisit implements models.LoanUsecase.
+func (u *usecase) ScheduleLoanVisit(
+	ctx context.Context,
+	fieldValidator *models.User,
+	assignmentID uint,
+	scheduledAt time.Time,
+) (*models.LoanAssignment, error) {
+	if fieldValidator == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	assignment, err := u.repo.FetchLoanAssignmentByID(ctx, assignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrLoanAssignmentNotFound)
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// Assignments of other field validators are reported as missing
+	if assignment.FieldValidatorID != fieldValidator.ID {
+		return nil, errs.Wrap(ErrLoanAssignmentNotFound)
+	}
+
+	if assignment.Status != models.LoanAssignmentActive {
+		return nil, errs.Wrap(ErrLoanAssignmentNotActive)
+	}
+
+	assignment.Schedule(scheduledAt, visitSlotDuration())
+	if err := u.checkVisitSlot(ctx, assignment); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err = u.repo.UpdateLoanAssignment(ctx, assignment)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return assignment, nil
+}
+
+// checkVisitSlot rejects visit slots in the past, and slots overlapping another visit of the field validator
+func (u *usecase) checkVisitSlot(ctx context.Context, assignment *models.LoanAssignment) error {
+	if assignment.ScheduledAt.Before(time.Now()) {
+		return errs.Wrap(ErrVisitSlotInvalid)
+	}
+
+	scheduled, err := u.repo.FetchLoanAssignments(ctx, &models.FetchLoanAssignmentsOpts{
+		FieldValidatorID: assignment.FieldValidatorID,
+		Status:           []models.LoanAssignmentStatus{models.LoanAssignmentActive},
+	})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	for i := range scheduled {
+		// A loan being reassigned to the same field validator keeps its slot free
+		if scheduled[i].LoanID == assignment.LoanID {
+			continue
+		}
+
+		if scheduled[i].SlotOverlaps(assignment) {
+			return errs.Wrap(ErrVisitSlotTaken)
+		}
+	}
+
+	return nil
+}
+
+// ReportOverdueVisits implements models.LoanUsecase.
+func (u *usecase) ReportOverdueVisits(ctx context.Context) (int, error) {
+	assignments, err := u.repo.FetchLoanAssignments(ctx, &models.FetchLoanAssignmentsOpts{
+		Overdue:    true,
+		Unreported: true,
+	})
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	reported := 0
+	for i := range assignments {
+		assignment := &assignments[i]
+
+		err := u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+			overdueAt := time.Now()
+			assignment.OverdueAt = &overdueAt
+
+			err := u.repo.UpdateLoanAssignment(txCtx, assignment)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+
+			return u.notificationUsecase.Notify(txCtx, assignment.FieldValidator, assignment.NewOverdueNotification())
+		})
+		if err != nil {
+			return reported, errs.Wrap(err)
+		}
+
+		reported++
+	}
+
+	return reported, nil
+}
+
+// assignmentStrategy reads the automatic assignment strategy from the config, region based by default
+func assignmentStrategy() models.AssignmentStrategy {
+	if strategy := models.AssignmentStrategy(config.Data.VisitAssignmentStrategy); strategy == models.AssignmentRoundRobin {
+		return strategy
+	}
+
+	return models.AssignmentRegion
+}
+
+func visitSLA() time.Duration {
+	if config.Data.VisitSLA <= 0 {
+		return models.DefaultVisitSLA
+	}
+
+	return config.Data.VisitSLA
+}
+
+func visitSlotDuration() time.Duration {
+	if config.Data.VisitSlotDuration <= 0 {
+		return models.DefaultVisitSlotDuration
+	}
+
+	return config.Data.VisitSlotDuration
+}
@@ -0,0 +1,142 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/utils/errs"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssessLoanCredit implements models.LoanUsecase.
+func (u *usecase) AssessLoanCredit(ctx context.Context, loan *models.Loan, assessor *models.User) (*models.CreditAssessment, error) {
+	if loan == nil || assessor == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	// Approved loans keep the assessment they were approved with
+	if loan.Status != models.LoanStatusProposed {
+		return nil, errs.Wrap(ErrLoanNotProposed)
+	}
+
+	assessment, err := u.assessCredit(ctx, loan, &loan.Product)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment.AssessorID = &assessor.ID
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.CreateCreditAssessment(txCtx, assessment)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.repo.UpdateLoan(txCtx, loan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assessment.Assessor = assessor
+
+	return assessment, nil
+}
+
+// assessCredit scores the loan of the product with the latest scorecard, and sets its score and risk grade. The
+// assessment is stored by the caller, once the loan is.
+func (u *usecase) assessCredit(ctx context.Context, loan *models.Loan, product *models.Product) (*models.CreditAssessment, error) {
+	scorecard, err := u.FetchCreditScorecard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loans applied for before identities were verified are scored without one
+	profile, err := u.kycUsecase.FetchKYCProfile(ctx, loan.BorrowerID)
+	var generalErr errs.GeneralError
+	if errors.As(err, &generalErr) && generalErr.StatusCode == http.StatusNotFound {
+		profile, err = nil, nil
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	history, err := u.repo.FetchLoans(ctx, &models.FetchLoanOpts{
+		UserID:      loan.BorrowerID,
+		Permissions: []auth.Permission{auth.PermissionLoanViewOwn},
+		Status:      []models.LoanStatus{models.LoanStatusDisbursed},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	assessment := scorecard.Assess(models.NewCreditFactorValues(loan, product, profile, history, time.Now()))
+	assessment.LoanID = loan.ID
+	loan.CreditScore = &assessment.Score
+	loan.RiskGrade = assessment.Grade
+
+	return assessment, nil
+}
+
+// FetchCreditAssessments implements models.LoanUsecase.
+func (u *usecase) FetchCreditAssessments(ctx context.Context, loan *models.Loan) ([]models.CreditAssessment, error) {
+	if loan == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	assessments, err := u.repo.FetchCreditAssessments(ctx, loan.ID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return assessments, nil
+}
+
+// FetchCreditScorecard implements models.LoanUsecase.
+func (u *usecase) FetchCreditScorecard(ctx context.Context) (*models.CreditScorecard, error) {
+	scorecard, err := u.repo.FetchCreditScorecard(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaultScorecard := models.DefaultCreditScorecard
+		return &defaultScorecard, nil
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return scorecard, nil
+}
+
+// SetCreditScorecard implements models.LoanUsecase.
+func (u *usecase) SetCreditScorecard(ctx context.Context, scorecard *models.CreditScorecard) (*models.CreditScorecard, error) {
+	if scorecard == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	if err := scorecard.Validate(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err := u.repo.CreateCreditScorecard(ctx, scorecard)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return scorecard, nil
+}
+
+// FetchLoanPricings implements models.LoanUsecase.
+func (u *usecase) FetchLoanPricings(ctx context.Context, loan *models.Loan) ([]models.LoanPricing, error) {
+	if loan == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	pricings, err := u.repo.FetchLoanPricings(ctx, loan.ID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return pricings, nil
+}
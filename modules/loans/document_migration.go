@@ -0,0 +1,80 @@
+package loans
+
+import (
+	"context"
+	"fmt"
+	"loan-service/models"
+	"loan-service/utils/errs"
+
+	"gorm.io/gorm"
+)
+
+// legacyAttachmentColumns are the loan columns that held attachments before loan documents, in drop order
+var legacyAttachmentColumns = []string{
+	"proof_of_visit_attachment_file",
+	"proof_of_visit_thumbnail_file",
+	"proof_of_visit_metadata",
+	"agreement_attachment_file",
+}
+
+// MigrateLoanDocuments moves the attachments stored on loans into loan documents as their first version, then drops
+// the old columns. It runs after the loan_documents table is migrated, and does nothing once the columns are gone.
+func MigrateLoanDocuments(ctx context.Context, db *gorm.DB) error {
+	migrator := db.Migrator()
+	hasColumn := map[string]bool{}
+	for _, column := range legacyAttachmentColumns {
+		hasColumn[column] = migrator.HasColumn(&models.Loan{}, column)
+	}
+
+	// Columns added later may be missing from databases migrated before them
+	optional := func(column, fallback string) string {
+		if hasColumn[column] {
+			return column
+		}
+
+		return fallback
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if hasColumn["proof_of_visit_attachment_file"] {
+			// The visitor is the only one who could have uploaded the proof of visit
+			err := tx.Exec(fmt.Sprintf(`
+				INSERT INTO loan_documents
+					(created_at, updated_at, loan_id, document_type, label, version, uploader_id, file_key, thumbnail_key, metadata)
+				SELECT updated_at, updated_at, id, ?, '', 1, visitor_id, proof_of_visit_attachment_file, %s, %s
+				FROM loans
+				WHERE proof_of_visit_attachment_file <> '' AND deleted_at IS NULL`,
+				optional("proof_of_visit_thumbnail_file", "''"),
+				optional("proof_of_visit_metadata", "NULL"),
+			), models.LoanDocumentProofOfVisit).Error
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		if hasColumn["agreement_attachment_file"] {
+			err := tx.Exec(`
+				INSERT INTO loan_documents (created_at, updated_at, loan_id, document_type, label, version, file_key)
+				SELECT updated_at, updated_at, id, ?, '', 1, agreement_attachment_file
+				FROM loans
+				WHERE agreement_attachment_file <> '' AND deleted_at IS NULL`,
+				models.LoanDocumentSignedAgreement,
+			).Error
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		for _, column := range legacyAttachmentColumns {
+			if !hasColumn[column] {
+				continue
+			}
+
+			if err := tx.Migrator().DropColumn(&models.Loan{}, column); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -40,7 +40,7 @@ var (
 	ErrLoanAlreadyExists = errs.GeneralError{
 		StatusCode: http.StatusBadRequest,
 		ErrorCode:  "LoanAlreadyExists",
-		Err:        errors.New("You already have an existing loan, please finalize the loan before you create a new one."),
+		Err:        errors.New("You already have an open loan, please wait until its term has ended before you create a new one."),
 	}
 
 	ErrInvestmentAmountExceedsPrincipal = errs.GeneralError{
@@ -48,4 +48,124 @@ var (
 		ErrorCode:  "InvestmentAmountExceedsPrincipal",
 		Err:        errors.New("The amount you are trying to invest exceeds total amount already invested in this loan. Please invest a lower amount."),
 	}
+
+	ErrLoanDocumentNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "LoanDocumentNotFound",
+		Err:        errors.New("This loan has no such document."),
+	}
+
+	ErrLoanDocumentNotAllowed = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "LoanDocumentNotAllowed",
+		Err:        errors.New("You are not allowed to upload this type of document."),
+	}
+
+	ErrLoanDocumentLocked = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "LoanDocumentLocked",
+		Err:        errors.New("This document cannot be replaced at the current stage of the loan."),
+	}
+
+	ErrLoanVisitNotApprovable = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "LoanVisitNotApprovable",
+		Err:        errors.New("The borrower visit must be accepted by staff, or redone, before this loan can be approved."),
+	}
+
+	ErrLoanVisitNotPendingReview = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "LoanVisitNotPendingReview",
+		Err:        errors.New("This loan has no visit waiting for review."),
+	}
+
+	ErrLoanNotAssigned = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "LoanNotAssigned",
+		Err:        errors.New("You are not assigned to visit the borrower of this loan."),
+	}
+
+	ErrLoanNotAssignable = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "LoanNotAssignable",
+		Err:        errors.New("Only proposed loans waiting for a visit can be assigned."),
+	}
+
+	ErrInvalidFieldValidator = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidFieldValidator",
+		Err:        errors.New("Visits can only be assigned to active field validators."),
+	}
+
+	ErrNoFieldValidatorAvailable = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "NoFieldValidatorAvailable",
+		Err:        errors.New("There is no active field validator to assign the visit to."),
+	}
+
+	ErrLoanAssignmentNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "LoanAssignmentNotFound",
+		Err:        errors.New("Cannot find this visit assignment."),
+	}
+
+	ErrLoanAssignmentNotActive = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "LoanAssignmentNotActive",
+		Err:        errors.New("This visit was already done or reassigned."),
+	}
+
+	ErrVisitSlotInvalid = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "VisitSlotInvalid",
+		Err:        errors.New("Visits cannot be scheduled in the past."),
+	}
+
+	ErrVisitSlotTaken = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "VisitSlotTaken",
+		Err:        errors.New("Another visit is already scheduled at this time."),
+	}
+
+	ErrVisitChecklistMissing = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "VisitChecklistMissing",
+		Err:        errors.New("The loan's product has no visit checklist to report on."),
+	}
+
+	ErrLoanNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "LoanNotFound",
+		Err:        errors.New("Cannot find the loan."),
+	}
+
+	ErrLoanNotProposed = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "LoanNotProposed",
+		Err:        errors.New("This loan is no longer waiting for a visit."),
+	}
+
+	ErrLoanAlreadyVoted = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "LoanAlreadyVoted",
+		Err:        errors.New("You have already approved this loan, another approver must vote next."),
+	}
+
+	ErrApproverIsVisitor = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "ApproverIsVisitor",
+		Err:        errors.New("You visited the borrower of this loan, someone else must decide on its approval."),
+	}
+
+	ErrSuperuserApprovalRequired = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "SuperuserApprovalRequired",
+		Err:        errors.New("This loan is waiting for a superuser to give the final approval."),
+	}
+
+	ErrKYCNotVerified = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "KYCNotVerified",
+		Err:        errors.New("Your identity must be verified before you can apply for a loan."),
+	}
 )
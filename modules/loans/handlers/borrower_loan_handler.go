@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/models"
 	"loan-service/modules/loans/handlers/dto"
 	"loan-service/services/auth"
@@ -25,9 +26,11 @@ func NewBorrowerHandler(
 ) {
 	handler := &BorrowerLoanHandler{uc, userUC, productUC, commonHandler}
 
-	g.GET("/loans", commonHandler.FetchLoans)
-	g.GET("/loan/:loan_id", commonHandler.FetchLoan)
-	g.POST("/loans", handler.StartLoan)
+	requireLoanView := authMiddleware.RequirePermission(auth.PermissionLoanViewAll, auth.PermissionLoanViewOwn)
+
+	g.GET("/loans", commonHandler.FetchLoans, requireLoanView)
+	g.GET("/loan/:loan_id", commonHandler.FetchLoan, requireLoanView)
+	g.POST("/loans", handler.StartLoan, authMiddleware.RequirePermission(auth.PermissionLoanCreate))
 }
 
 func (h *BorrowerLoanHandler) StartLoan(c echo.Context) error {
@@ -26,8 +26,8 @@ func (h *CommonLoanHandler) FetchLoans(c echo.Context) error {
 	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
 
 	loans, err := h.Usecase.FetchLoans(reqCtx, &models.FetchLoanOpts{
-		UserID:   claims.UserID,
-		RoleType: claims.RoleType,
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
@@ -50,12 +50,12 @@ func (h *CommonLoanHandler) FetchLoan(c echo.Context) error {
 	}
 
 	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
-		UserID:   claims.UserID,
-		RoleType: claims.RoleType,
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
 	}
 
-	return resp.HTTPOk(c, dto.ModelToDto(loan))
+	return resp.HTTPOk(c, dto.ModelToDetailDto(loan, claims.Permissions))
 }
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/loans/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/services/upload"
+	"loan-service/utils/resp"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DocumentLoanHandler struct {
+	Usecase     models.LoanUsecase
+	UserUsecase models.UserUsecase
+}
+
+// NewDocumentHandler serves loan documents to anyone who can see the loan, and takes uploads of the document types
+// allowed by the uploader's permissions
+func NewDocumentHandler(
+	g *echo.Group,
+	uc models.LoanUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &DocumentLoanHandler{uc, userUC}
+
+	requireLoanView := authMiddleware.RequirePermission(
+		auth.PermissionLoanViewAll,
+		auth.PermissionLoanViewProposed,
+		auth.PermissionLoanViewInvestable,
+		auth.PermissionLoanViewOwn,
+	)
+
+	// Each document type is further restricted to its own upload permissions
+	requireDocumentUpload := authMiddleware.RequirePermission(
+		auth.PermissionLoanCreate,
+		auth.PermissionLoanVisit,
+		auth.PermissionLoanDisburse,
+	)
+
+	g.GET("/loans/:loan_id/documents", handler.FetchLoanDocuments, requireLoanView)
+	g.POST("/loans/:loan_id/documents", handler.UploadLoanDocument, requireLoanView, requireDocumentUpload)
+	g.GET("/loans/:loan_id/documents/:document_id/:variant", handler.DownloadLoanDocument, requireLoanView)
+}
+
+func (h *DocumentLoanHandler) FetchLoanDocuments(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchLoanDocumentsRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	documents, err := h.Usecase.FetchLoanDocuments(reqCtx, loan, claims.Permissions, &models.FetchLoanDocumentsOpts{
+		IncludeSuperseded: body.IncludeSuperseded,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.DocumentsToDto(documents))
+}
+
+func (h *DocumentLoanHandler) UploadLoanDocument(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.UploadLoanDocumentRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	uploader, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	document, err := h.Usecase.UploadLoanDocument(
+		reqCtx,
+		loan,
+		uploader,
+		claims.Permissions,
+		models.LoanDocumentType(body.DocumentType),
+		file,
+	)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.DocumentToDto(document))
+}
+
+func (h *DocumentLoanHandler) DownloadLoanDocument(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.DownloadLoanDocumentRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	err := upload.VerifyURL(c.Request().URL.Path, body.Expires, body.Signature, time.Now())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	// Signed links are not enough on their own, the requester must be able to see the loan
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	file, fileInfo, err := h.Usecase.DownloadLoanDocument(
+		reqCtx,
+		loan,
+		claims.Permissions,
+		body.DocumentID,
+		models.LoanDocumentVariant(body.Variant),
+	)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+	defer file.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", path.Base(fileInfo.Key)))
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	return c.Stream(http.StatusOK, fileInfo.ContentType, file)
+}
@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"encoding/json"
+	"loan-service/models"
+	"strings"
+	"time"
+)
+
 type StartLoanRequest struct {
 	Name      string `json:"name" validate:"required,gt=0"`
 	ProductID uint   `json:"product_id" validate:"required,gt=0"`
@@ -12,12 +19,224 @@ type InvestInLoanRequest struct {
 
 type MarkLoanBorrowerVisitedRequest struct {
 	LoanID uint `param:"loan_id" validate:"required,gt=0"`
+	// Reported by the device, used when the photo has no location of its own
+	Latitude   *float64   `form:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64   `form:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	CapturedAt *time.Time `form:"captured_at"`
+	// Answers to the visit checklist, a JSON object keyed by question. Photo questions are answered with files named
+	// after the question, see VisitReportPhotoPrefix.
+	Report string `form:"report" validate:"max=50000"`
+}
+
+// VisitReportPhotoPrefix prefixes the form files answering photo questions, e.g. report_photo_shop_front
+const VisitReportPhotoPrefix = "report_photo_"
+
+// VisitLocation returns false when only one of the coordinates is given
+func (r *MarkLoanBorrowerVisitedRequest) VisitLocation() (*models.VisitLocation, bool) {
+	return visitLocation(r.Latitude, r.Longitude, r.CapturedAt)
+}
+
+func visitLocation(latitude, longitude *float64, capturedAt *time.Time) (*models.VisitLocation, bool) {
+	if (latitude == nil) != (longitude == nil) {
+		return nil, false
+	}
+
+	return &models.VisitLocation{Latitude: latitude, Longitude: longitude, CapturedAt: capturedAt}, true
+}
+
+// VisitReportAnswers returns false when the report is not a JSON object
+func (r *MarkLoanBorrowerVisitedRequest) VisitReportAnswers() (map[string]json.RawMessage, bool) {
+	if strings.TrimSpace(r.Report) == "" {
+		return nil, true
+	}
+
+	var answers map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(r.Report), &answers); err != nil || answers == nil {
+		return nil, false
+	}
+
+	return answers, true
+}
+
+// SyncVisitsRequest is a multipart request, the manifest lists the visits queued offline and refers to the files sent
+// along by their form name
+type SyncVisitsRequest struct {
+	Manifest string `form:"manifest" validate:"required"`
+}
+
+type VisitSyncManifest struct {
+	Visits []VisitSyncItemReq `json:"visits" validate:"required,min=1,max=20,dive"`
+}
+
+type VisitSyncItemReq struct {
+	// Generated by the app when the visit was queued, syncing it again does not apply it twice
+	ClientID   string                     `json:"client_id" validate:"required,max=64"`
+	LoanID     uint                       `json:"loan_id" validate:"required,gt=0"`
+	Latitude   *float64                   `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64                   `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	CapturedAt *time.Time                 `json:"captured_at"`
+	Report     map[string]json.RawMessage `json:"report"`
+	// Form names of the proof of visit, and of the photos answering the checklist keyed by question
+	Attachment   string            `json:"attachment" validate:"required"`
+	ReportPhotos map[string]string `json:"report_photos"`
+}
+
+// VisitSyncManifest returns false when the manifest is not valid JSON
+func (r *SyncVisitsRequest) VisitSyncManifest() (*VisitSyncManifest, bool) {
+	manifest := &VisitSyncManifest{}
+	if err := json.Unmarshal([]byte(r.Manifest), manifest); err != nil {
+		return nil, false
+	}
+
+	return manifest, true
+}
+
+// VisitLocation returns false when only one of the coordinates is given
+func (r *VisitSyncItemReq) VisitLocation() (*models.VisitLocation, bool) {
+	return visitLocation(r.Latitude, r.Longitude, r.CapturedAt)
+}
+
+type ReviewLoanVisitRequest struct {
+	LoanID   uint   `param:"loan_id" validate:"required,gt=0"`
+	Decision string `json:"decision" validate:"required,oneof=accept reject"`
+	Note     string `json:"note" validate:"max=500"`
 }
 
 type DisburseLoanRequest struct {
 	LoanID uint `param:"loan_id" validate:"required,gt=0"`
 }
 
+type ApproveLoanRequest struct {
+	LoanID  uint   `param:"loan_id" validate:"required,gt=0"`
+	Comment string `json:"comment" validate:"max=1000"`
+}
+
+// RejectLoanRequest needs a comment, so the next round of approvers know what to look at
+type RejectLoanRequest struct {
+	LoanID  uint   `param:"loan_id" validate:"required,gt=0"`
+	Comment string `json:"comment" validate:"required,max=1000"`
+}
+
+// SetApprovalPoliciesRequest replaces every tier, no tiers falls back to the default policy
+type SetApprovalPoliciesRequest struct {
+	Tiers []ApprovalPolicyReq `json:"tiers" validate:"max=10,dive"`
+}
+
+type ApprovalPolicyReq struct {
+	Name               string  `json:"name" validate:"required,max=100"`
+	MinPrincipalAmount float64 `json:"min_principal_amount" validate:"gte=0"`
+	RequiredApprovals  int     `json:"required_approvals" validate:"required,min=1,max=5"`
+	VisitorMayApprove  bool    `json:"visitor_may_approve"`
+	RequireSuperuser   bool    `json:"require_superuser"`
+}
+
+func (r *SetApprovalPoliciesRequest) ToModels() []models.ApprovalPolicy {
+	policies := []models.ApprovalPolicy{}
+	for _, tier := range r.Tiers {
+		policies = append(policies, models.ApprovalPolicy{
+			Name:               strings.TrimSpace(tier.Name),
+			MinPrincipalAmount: tier.MinPrincipalAmount,
+			RequiredApprovals:  tier.RequiredApprovals,
+			VisitorMayApprove:  tier.VisitorMayApprove,
+			RequireSuperuser:   tier.RequireSuperuser,
+		})
+	}
+
+	return policies
+}
+
+// SetCreditScorecardRequest stores the scorecard as its next version
+type SetCreditScorecardRequest struct {
+	BaseScore int                  `json:"base_score" validate:"gte=0,lte=1000"`
+	Factors   []ScorecardFactorReq `json:"factors" validate:"required,min=1,max=10,dive"`
+	Grades    []GradeThresholdReq  `json:"grades" validate:"required,min=1,max=5,dive"`
+}
+
+type ScorecardFactorReq struct {
+	Factor string         `json:"factor" validate:"required"`
+	Bands  []ScoreBandReq `json:"bands" validate:"required,min=1,max=20,dive"`
+}
+
+// ScoreBandReq gives points to values from min, up to but excluding max, either may be left out
+type ScoreBandReq struct {
+	Min    *float64 `json:"min"`
+	Max    *float64 `json:"max"`
+	Points int      `json:"points" validate:"gte=-1000,lte=1000"`
+	Label  string   `json:"label" validate:"max=100"`
+}
+
+type GradeThresholdReq struct {
+	Grade    string `json:"grade" validate:"required,oneof=A B C D E"`
+	MinScore int    `json:"min_score"`
+}
+
+func (r *SetCreditScorecardRequest) ToModel() *models.CreditScorecard {
+	scorecard := &models.CreditScorecard{BaseScore: r.BaseScore}
+	for _, factor := range r.Factors {
+		bands := []models.ScoreBand{}
+		for _, band := range factor.Bands {
+			bands = append(bands, models.ScoreBand{
+				Min:    band.Min,
+				Max:    band.Max,
+				Points: band.Points,
+				Label:  strings.TrimSpace(band.Label),
+			})
+		}
+
+		scorecard.Factors = append(scorecard.Factors, models.ScorecardFactor{
+			Factor: models.CreditFactor(factor.Factor),
+			Bands:  bands,
+		})
+	}
+
+	for _, grade := range r.Grades {
+		scorecard.Grades = append(scorecard.Grades, models.GradeThreshold{
+			Grade:    models.RiskGrade(grade.Grade),
+			MinScore: grade.MinScore,
+		})
+	}
+
+	return scorecard
+}
+
 type FetchLoanRequest struct {
 	LoanID uint `param:"loan_id" validate:"required,gt=0"`
 }
+
+type FetchLoanDocumentsRequest struct {
+	LoanID            uint `param:"loan_id" validate:"required,gt=0"`
+	IncludeSuperseded bool `query:"include_superseded"`
+}
+
+type UploadLoanDocumentRequest struct {
+	LoanID       uint   `param:"loan_id" validate:"required,gt=0"`
+	DocumentType string `form:"document_type" validate:"required,oneof=proof_of_visit id_card business_photo signed_agreement disbursement_receipt"` //nolint:lll
+}
+
+type DownloadLoanDocumentRequest struct {
+	LoanID     uint   `param:"loan_id" validate:"required,gt=0"`
+	DocumentID uint   `param:"document_id" validate:"required,gt=0"`
+	Variant    string `param:"variant" validate:"required,oneof=file thumbnail"`
+	Expires    string `query:"expires" validate:"required"`
+	Signature  string `query:"signature" validate:"required"`
+}
+
+type AssignLoanRequest struct {
+	LoanID uint `param:"loan_id" validate:"required,gt=0"`
+	// Picked by the configured strategy when omitted
+	FieldValidatorID uint       `json:"field_validator_id"`
+	ScheduledAt      *time.Time `json:"scheduled_at"`
+	Note             string     `json:"note" validate:"max=500"`
+}
+
+type FetchLoanAssignmentsRequest struct {
+	LoanID           uint   `query:"loan_id"`
+	FieldValidatorID uint   `query:"field_validator_id"`
+	Status           string `query:"status" validate:"omitempty,oneof=active completed reassigned"`
+	Overdue          bool   `query:"overdue"`
+}
+
+type ScheduleLoanVisitRequest struct {
+	AssignmentID uint       `param:"assignment_id" validate:"required,gt=0"`
+	ScheduledAt  *time.Time `json:"scheduled_at" validate:"required"`
+}
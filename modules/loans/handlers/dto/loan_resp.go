@@ -2,22 +2,44 @@ package dto
 
 import (
 	"fmt"
+	"loan-service/config"
 	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/services/upload"
 	"loan-service/utils/money"
+	"strconv"
+	"time"
 )
 
 type FetchMyLoansResp struct {
-	ID              uint      `json:"id"`
-	Name            string    `json:"name"`
-	Status          string    `json:"status"`
-	PrincipalAmount string    `json:"principal_amount"`
-	RemainingAmount string    `json:"remaining_amount"`
-	InterestRate    string    `json:"interest_rate"`
-	TotalInterest   string    `json:"total_interest"`
-	LoanTerm        string    `json:"loan_term"`
-	VisitedBy       *UserResp `json:"visited_by,omitempty"`
-	ApprovedBy      *UserResp `json:"approved_by,omitempty"`
-	DisbursedBy     *UserResp `json:"disbursed_by,omitempty"`
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	Status          string     `json:"status"`
+	PrincipalAmount string     `json:"principal_amount"`
+	RemainingAmount string     `json:"remaining_amount"`
+	InterestRate    string     `json:"interest_rate"`
+	TotalInterest   string     `json:"total_interest"`
+	LoanTerm        string     `json:"loan_term"`
+	RiskGrade       string     `json:"risk_grade,omitempty"`
+	AssignedTo      *UserResp  `json:"assigned_to,omitempty"`
+	VisitedBy       *UserResp  `json:"visited_by,omitempty"`
+	Visit           *VisitResp `json:"visit,omitempty"`
+	ApprovedBy      *UserResp  `json:"approved_by,omitempty"`
+	DisbursedBy     *UserResp  `json:"disbursed_by,omitempty"`
+	// Signed download links, expiring after ATTACHMENT_URL_TTL
+	ProofOfVisitURL          string `json:"proof_of_visit_url,omitempty"`
+	ProofOfVisitThumbnailURL string `json:"proof_of_visit_thumbnail_url,omitempty"`
+	AgreementURL             string `json:"agreement_url,omitempty"`
+}
+
+// VisitResp summarizes the verification of the visit, without disclosing where the borrower lives
+type VisitResp struct {
+	VisitedAt      *time.Time `json:"visited_at,omitempty"`
+	CapturedAt     *time.Time `json:"captured_at,omitempty"`
+	DistanceMeters *float64   `json:"distance_meters,omitempty"`
+	Flags          []string   `json:"flags"`
+	ReviewStatus   string     `json:"review_status"`
+	ReviewNote     string     `json:"review_note,omitempty"`
 }
 
 type UserResp struct {
@@ -48,12 +70,32 @@ func ModelToDto(l *models.Loan) *FetchMyLoansResp {
 		InterestRate:    money.DisplayAsPercentage(l.InterestRate),
 		TotalInterest:   money.DisplayMoney(l.TotalInterest),
 		LoanTerm:        fmt.Sprintf("%d months", l.LoanTerm),
+		RiskGrade:       string(l.RiskGrade),
+	}
+
+	if assignment := l.ActiveAssignment(); assignment != nil && assignment.FieldValidator != nil {
+		res.AssignedTo = &UserResp{Name: assignment.FieldValidator.Name, Email: assignment.FieldValidator.Email}
 	}
 
 	if l.Visitor != nil {
 		res.VisitedBy = &UserResp{Name: l.Visitor.Name, Email: l.Visitor.Email}
 	}
 
+	if l.Visit.ReviewStatus != "" {
+		res.Visit = &VisitResp{
+			VisitedAt:      l.VisitedAt,
+			CapturedAt:     l.Visit.CapturedAt,
+			DistanceMeters: l.Visit.DistanceMeters,
+			Flags:          []string{},
+			ReviewStatus:   string(l.Visit.ReviewStatus),
+			ReviewNote:     l.Visit.ReviewNote,
+		}
+
+		for _, flag := range l.Visit.Flags {
+			res.Visit.Flags = append(res.Visit.Flags, string(flag))
+		}
+	}
+
 	if l.Approver != nil {
 		res.ApprovedBy = &UserResp{Name: l.Approver.Name, Email: l.Approver.Email}
 	}
@@ -62,5 +104,545 @@ func ModelToDto(l *models.Loan) *FetchMyLoansResp {
 		res.DisbursedBy = &UserResp{Name: l.Disburser.Name, Email: l.Disburser.Email}
 	}
 
+	proofOfVisit := l.Document(models.LoanDocumentProofOfVisit)
+	res.ProofOfVisitURL = documentURL(proofOfVisit, models.LoanDocumentFile)
+	res.ProofOfVisitThumbnailURL = documentURL(proofOfVisit, models.LoanDocumentThumbnail)
+	res.AgreementURL = documentURL(l.Document(models.LoanDocumentSignedAgreement), models.LoanDocumentFile)
+
+	return &res
+}
+
+// FetchLoanDetailResp is a loan with its visit report, for those making or approving visits
+type FetchLoanDetailResp struct {
+	FetchMyLoansResp
+	// Questions to answer when visiting the borrower, until the borrower is visited
+	VisitChecklist []VisitQuestionResp `json:"visit_checklist,omitempty"`
+	VisitReport    *VisitReportResp    `json:"visit_report,omitempty"`
+}
+
+type VisitQuestionResp struct {
+	Key      string `json:"key"`
+	Prompt   string `json:"prompt"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type VisitReportResp struct {
+	ChecklistVersion int               `json:"checklist_version"`
+	Answers          []VisitAnswerResp `json:"answers"`
+}
+
+type VisitAnswerResp struct {
+	VisitQuestionResp
+	YesNo  *bool    `json:"yes_no,omitempty"`
+	Number *float64 `json:"number,omitempty"`
+	Text   *string  `json:"text,omitempty"`
+	// Signed download links of the photo, expiring after ATTACHMENT_URL_TTL
+	PhotoURL          string `json:"photo_url,omitempty"`
+	PhotoThumbnailURL string `json:"photo_thumbnail_url,omitempty"`
+}
+
+func ModelToDetailDto(l *models.Loan, permissions []auth.Permission) *FetchLoanDetailResp {
+	if l == nil {
+		return nil
+	}
+
+	res := FetchLoanDetailResp{FetchMyLoansResp: *ModelToDto(l)}
+	if !models.VisitReportVisibleTo(permissions) {
+		return &res
+	}
+
+	if checklist := l.Product.VisitChecklist; checklist != nil && l.Status == models.LoanStatusProposed && l.VisitorID == nil {
+		for _, question := range checklist.Questions {
+			res.VisitChecklist = append(res.VisitChecklist, questionToDto(question))
+		}
+	}
+
+	if report := l.Visit.Report; report != nil {
+		res.VisitReport = &VisitReportResp{ChecklistVersion: report.ChecklistVersion, Answers: []VisitAnswerResp{}}
+		for _, answer := range report.Answers {
+			answerResp := VisitAnswerResp{
+				VisitQuestionResp: questionToDto(answer.VisitQuestion),
+				YesNo:             answer.YesNo,
+				Number:            answer.Number,
+				Text:              answer.Text,
+			}
+
+			if answer.DocumentID != nil {
+				photo := l.DocumentByID(*answer.DocumentID)
+				answerResp.PhotoURL = documentURL(photo, models.LoanDocumentFile)
+				answerResp.PhotoThumbnailURL = documentURL(photo, models.LoanDocumentThumbnail)
+			}
+
+			res.VisitReport.Answers = append(res.VisitReport.Answers, answerResp)
+		}
+	}
+
+	return &res
+}
+
+func questionToDto(q models.VisitQuestion) VisitQuestionResp {
+	return VisitQuestionResp{Key: q.Key, Prompt: q.Prompt, Type: string(q.Type), Required: q.Required}
+}
+
+type VisitSyncResultResp struct {
+	ClientID string `json:"client_id"`
+	LoanID   uint   `json:"loan_id"`
+	Status   string `json:"status"`
+	// The outcome was recorded by an earlier sync, the visit was not applied again
+	Replayed   bool   `json:"replayed"`
+	ErrorCode  string `json:"error_code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	LoanStatus string `json:"loan_status,omitempty"`
+	// The visited loan, when the visit was applied by this sync
+	Loan *FetchLoanDetailResp `json:"loan,omitempty"`
+}
+
+func VisitSyncResultsToDto(results []models.VisitSyncResult, permissions []auth.Permission) []VisitSyncResultResp {
+	res := []VisitSyncResultResp{}
+	for i := range results {
+		result := &results[i]
+		res = append(res, VisitSyncResultResp{
+			ClientID:   result.ClientID,
+			LoanID:     result.LoanID,
+			Status:     string(result.Status),
+			Replayed:   result.Replayed,
+			ErrorCode:  result.ErrorCode,
+			Message:    result.Message,
+			LoanStatus: string(result.LoanStatus),
+			Loan:       ModelToDetailDto(result.Loan, permissions),
+		})
+	}
+
+	return res
+}
+
+type LoanDocumentResp struct {
+	ID             uint      `json:"id"`
+	DocumentType   string    `json:"document_type"`
+	Label          string    `json:"label,omitempty"`
+	Version        int       `json:"version"`
+	SupersedesID   *uint     `json:"supersedes_id,omitempty"`
+	SupersededByID *uint     `json:"superseded_by_id,omitempty"`
+	UploadedBy     *UserResp `json:"uploaded_by,omitempty"`
+	UploadedAt     time.Time `json:"uploaded_at"`
+	ContentType    string    `json:"content_type,omitempty"`
+	Size           int64     `json:"size,omitempty"`
+	// Signed download links, expiring after ATTACHMENT_URL_TTL
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+func DocumentsToDto(documents []models.LoanDocument) []LoanDocumentResp {
+	result := []LoanDocumentResp{}
+	for i := range documents {
+		result = append(result, *DocumentToDto(&documents[i]))
+	}
+
+	return result
+}
+
+func DocumentToDto(d *models.LoanDocument) *LoanDocumentResp {
+	if d == nil {
+		return nil
+	}
+
+	res := LoanDocumentResp{
+		ID:             d.ID,
+		DocumentType:   string(d.DocumentType),
+		Label:          d.Label,
+		Version:        d.Version,
+		SupersedesID:   d.SupersedesID,
+		SupersededByID: d.SupersededByID,
+		UploadedAt:     d.CreatedAt,
+		URL:            documentURL(d, models.LoanDocumentFile),
+		ThumbnailURL:   documentURL(d, models.LoanDocumentThumbnail),
+	}
+
+	if d.Uploader != nil {
+		res.UploadedBy = &UserResp{Name: d.Uploader.Name, Email: d.Uploader.Email}
+	}
+
+	if d.Metadata != nil {
+		res.ContentType = d.Metadata.ContentType
+		res.Size = d.Metadata.Size
+	}
+
+	return &res
+}
+
+func documentURL(d *models.LoanDocument, variant models.LoanDocumentVariant) string {
+	if d == nil || d.Key(variant) == "" {
+		return ""
+	}
+
+	return upload.SignURL(d.DownloadPath(variant), config.Data.AttachmentURLTTL, time.Now())
+}
+
+type LoanAssignmentResp struct {
+	ID             uint               `json:"id"`
+	LoanID         uint               `json:"loan_id"`
+	LoanName       string             `json:"loan_name"`
+	Borrower       *VisitBorrowerResp `json:"borrower,omitempty"`
+	FieldValidator *UserResp          `json:"field_validator,omitempty"`
+	AssignedBy     *UserResp          `json:"assigned_by,omitempty"`
+	AssignedAt     time.Time          `json:"assigned_at"`
+	Strategy       string             `json:"strategy"`
+	Status         string             `json:"status"`
+	Note           string             `json:"note,omitempty"`
+	ScheduledAt    *time.Time         `json:"scheduled_at"`
+	ScheduledUntil *time.Time         `json:"scheduled_until"`
+	DueAt          time.Time          `json:"due_at"`
+	Overdue        bool               `json:"overdue"`
+	CompletedAt    *time.Time         `json:"completed_at,omitempty"`
+}
+
+// VisitBorrowerResp is what the field validator needs to find and contact the borrower
+type VisitBorrowerResp struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Address     string `json:"address"`
+	Region      string `json:"region"`
+}
+
+func AssignmentsToDto(assignments []models.LoanAssignment) []LoanAssignmentResp {
+	result := []LoanAssignmentResp{}
+	for i := range assignments {
+		result = append(result, *AssignmentToDto(&assignments[i]))
+	}
+
+	return result
+}
+
+func AssignmentToDto(a *models.LoanAssignment) *LoanAssignmentResp {
+	if a == nil {
+		return nil
+	}
+
+	res := LoanAssignmentResp{
+		ID:             a.ID,
+		LoanID:         a.LoanID,
+		AssignedAt:     a.CreatedAt,
+		Strategy:       string(a.Strategy),
+		Status:         string(a.Status),
+		Note:           a.Note,
+		ScheduledAt:    a.ScheduledAt,
+		ScheduledUntil: a.ScheduledUntil,
+		DueAt:          a.DueAt,
+		Overdue:        a.Overdue(time.Now()),
+		CompletedAt:    a.CompletedAt,
+	}
+
+	if a.Loan != nil {
+		res.LoanName = a.Loan.Name
+		res.Borrower = &VisitBorrowerResp{
+			Name:        a.Loan.Borrower.Name,
+			PhoneNumber: a.Loan.Borrower.PhoneNumber,
+			Address:     a.Loan.Borrower.Address,
+			Region:      a.Loan.Borrower.Region,
+		}
+	}
+
+	if a.FieldValidator != nil {
+		res.FieldValidator = &UserResp{Name: a.FieldValidator.Name, Email: a.FieldValidator.Email}
+	}
+
+	if a.AssignedBy != nil {
+		res.AssignedBy = &UserResp{Name: a.AssignedBy.Name, Email: a.AssignedBy.Email}
+	}
+
+	return &res
+}
+
+type LoanEventResp struct {
+	Type             string    `json:"type"`
+	LoanID           uint      `json:"loan_id"`
+	Status           string    `json:"status"`
+	PrincipalAmount  string    `json:"principal_amount"`
+	RemainingAmount  string    `json:"remaining_amount"`
+	FundedPercentage string    `json:"funded_percentage"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+func LoanEventToDto(e models.LoanEvent) LoanEventResp {
+	principal, _ := strconv.ParseFloat(e.PrincipalAmount, 64)
+	remaining, _ := strconv.ParseFloat(e.RemainingAmount, 64)
+
+	var funded float64
+	if principal > 0 {
+		funded = (principal - remaining) / principal
+	}
+
+	return LoanEventResp{
+		Type:             string(e.Type),
+		LoanID:           e.LoanID,
+		Status:           string(e.Status),
+		PrincipalAmount:  money.DisplayMoney(e.PrincipalAmount),
+		RemainingAmount:  money.DisplayMoney(e.RemainingAmount),
+		FundedPercentage: money.DisplayAsPercentage(funded),
+		OccurredAt:       e.OccurredAt,
+	}
+}
+
+// LoanApprovalResp is a loan with how far it is from satisfying its approval policy
+type LoanApprovalResp struct {
+	FetchMyLoansResp
+	Approval *ApprovalStatusResp `json:"approval"`
+}
+
+type ApprovalStatusResp struct {
+	Policy            string `json:"policy"`
+	RequiredApprovals int    `json:"required_approvals"`
+	RequireSuperuser  bool   `json:"require_superuser"`
+	Round             int    `json:"round"`
+	Approvals         int    `json:"approvals"`
+	SuperuserApproved bool   `json:"superuser_approved"`
+	Satisfied         bool   `json:"satisfied"`
+	// Level the next approval counts for, until the policy is satisfied
+	NextLevel int `json:"next_level,omitempty"`
+	// Votes of every round, rejections included
+	Votes []ApprovalVoteResp `json:"votes"`
+}
+
+type ApprovalVoteResp struct {
+	Round    int       `json:"round"`
+	Level    int       `json:"level"`
+	Decision string    `json:"decision"`
+	Comment  string    `json:"comment,omitempty"`
+	Approver *UserResp `json:"approver,omitempty"`
+	VotedAt  time.Time `json:"voted_at"`
+}
+
+func ApprovalToDto(l *models.Loan, status *models.ApprovalStatus) *LoanApprovalResp {
+	if l == nil || status == nil {
+		return nil
+	}
+
+	res := LoanApprovalResp{
+		FetchMyLoansResp: *ModelToDto(l),
+		Approval: &ApprovalStatusResp{
+			Policy:            status.Policy.Name,
+			RequiredApprovals: status.Policy.RequiredApprovals,
+			RequireSuperuser:  status.Policy.RequireSuperuser,
+			Round:             status.Round,
+			Approvals:         status.Approvals,
+			SuperuserApproved: status.SuperuserApproved,
+			Satisfied:         status.Satisfied(),
+			Votes:             []ApprovalVoteResp{},
+		},
+	}
+
+	if !res.Approval.Satisfied && l.Status == models.LoanStatusProposed {
+		res.Approval.NextLevel = int(status.NextLevel())
+	}
+
+	for _, vote := range l.Approvals {
+		voteResp := ApprovalVoteResp{
+			Round:    vote.Round,
+			Level:    int(vote.Level),
+			Decision: string(vote.Decision),
+			Comment:  vote.Comment,
+			VotedAt:  vote.CreatedAt,
+		}
+
+		if vote.Approver != nil {
+			voteResp.Approver = &UserResp{Name: vote.Approver.Name, Email: vote.Approver.Email}
+		}
+
+		res.Approval.Votes = append(res.Approval.Votes, voteResp)
+	}
+
+	return &res
+}
+
+func PendingApprovalsToDto(pending []models.PendingApproval) []LoanApprovalResp {
+	res := []LoanApprovalResp{}
+	for i := range pending {
+		res = append(res, *ApprovalToDto(&pending[i].Loan, &pending[i].Status))
+	}
+
+	return res
+}
+
+type ApprovalPolicyResp struct {
+	ID                 uint    `json:"id"`
+	Name               string  `json:"name"`
+	MinPrincipalAmount float64 `json:"min_principal_amount"`
+	RequiredApprovals  int     `json:"required_approvals"`
+	VisitorMayApprove  bool    `json:"visitor_may_approve"`
+	RequireSuperuser   bool    `json:"require_superuser"`
+}
+
+func ApprovalPoliciesToDto(policies []models.ApprovalPolicy) []ApprovalPolicyResp {
+	res := []ApprovalPolicyResp{}
+	for _, policy := range policies {
+		res = append(res, ApprovalPolicyResp{
+			ID:                 policy.ID,
+			Name:               policy.Name,
+			MinPrincipalAmount: policy.MinPrincipalAmount,
+			RequiredApprovals:  policy.RequiredApprovals,
+			VisitorMayApprove:  policy.VisitorMayApprove,
+			RequireSuperuser:   policy.RequireSuperuser,
+		})
+	}
+
+	return res
+}
+
+// CreditAssessmentResp is a credit score of the loan, explained factor by factor
+type CreditAssessmentResp struct {
+	ID               uint                    `json:"id"`
+	ScorecardVersion int                     `json:"scorecard_version"`
+	Score            int                     `json:"score"`
+	Grade            string                  `json:"grade"`
+	Factors          []CreditFactorScoreResp `json:"factors"`
+	// Staff who asked for the loan to be assessed again, empty when assessed on application
+	AssessedBy *UserResp `json:"assessed_by,omitempty"`
+	AssessedAt time.Time `json:"assessed_at"`
+}
+
+type CreditFactorScoreResp struct {
+	Factor      string   `json:"factor"`
+	Value       *float64 `json:"value"`
+	Points      int      `json:"points"`
+	Explanation string   `json:"explanation"`
+}
+
+func CreditAssessmentsToDto(assessments []models.CreditAssessment) []CreditAssessmentResp {
+	res := []CreditAssessmentResp{}
+	for i := range assessments {
+		res = append(res, *CreditAssessmentToDto(&assessments[i]))
+	}
+
+	return res
+}
+
+func CreditAssessmentToDto(a *models.CreditAssessment) *CreditAssessmentResp {
+	if a == nil {
+		return nil
+	}
+
+	res := CreditAssessmentResp{
+		ID:               a.ID,
+		ScorecardVersion: a.ScorecardVersion,
+		Score:            a.Score,
+		Grade:            string(a.Grade),
+		Factors:          []CreditFactorScoreResp{},
+		AssessedAt:       a.CreatedAt,
+	}
+
+	for _, factor := range a.Factors {
+		res.Factors = append(res.Factors, CreditFactorScoreResp{
+			Factor:      string(factor.Factor),
+			Value:       factor.Value,
+			Points:      factor.Points,
+			Explanation: factor.Explanation,
+		})
+	}
+
+	if a.Assessor != nil {
+		res.AssessedBy = &UserResp{Name: a.Assessor.Name, Email: a.Assessor.Email}
+	}
+
+	return &res
+}
+
+type LoanPricingResp struct {
+	ID          uint   `json:"id"`
+	Trigger     string `json:"trigger"`
+	RiskGrade   string `json:"risk_grade,omitempty"`
+	CreditScore *int   `json:"credit_score,omitempty"`
+	// Either the product's rate grid, or its interest rate when the grid has no rate for the grade
+	Source               string `json:"source"`
+	ProductRate          string `json:"product_rate"`
+	PreviousInterestRate string `json:"previous_interest_rate"`
+	InterestRate         string `json:"interest_rate"`
+	TotalInterest        string `json:"total_interest"`
+	ROI                  string `json:"roi"`
+	// Approver who priced the loan, empty when priced on application
+	PricedBy *UserResp `json:"priced_by,omitempty"`
+	PricedAt time.Time `json:"priced_at"`
+}
+
+func LoanPricingsToDto(pricings []models.LoanPricing) []LoanPricingResp {
+	res := []LoanPricingResp{}
+	for _, p := range pricings {
+		res = append(res, LoanPricingResp{
+			ID:                   p.ID,
+			Trigger:              string(p.Trigger),
+			RiskGrade:            string(p.RiskGrade),
+			CreditScore:          p.CreditScore,
+			Source:               string(p.Source),
+			ProductRate:          money.DisplayAsPercentage(p.ProductRate),
+			PreviousInterestRate: money.DisplayAsPercentage(p.PreviousInterestRate),
+			InterestRate:         money.DisplayAsPercentage(p.InterestRate),
+			TotalInterest:        money.DisplayMoney(p.TotalInterest),
+			ROI:                  p.ROI + "%",
+			PricedAt:             p.CreatedAt,
+		})
+
+		if p.Actor != nil {
+			res[len(res)-1].PricedBy = &UserResp{Name: p.Actor.Name, Email: p.Actor.Email}
+		}
+	}
+
+	return res
+}
+
+type CreditScorecardResp struct {
+	// 0 for the default scorecard, which applies until one is stored
+	Version   int                   `json:"version"`
+	BaseScore int                   `json:"base_score"`
+	Factors   []ScorecardFactorResp `json:"factors"`
+	Grades    []GradeThresholdResp  `json:"grades"`
+}
+
+type ScorecardFactorResp struct {
+	Factor string          `json:"factor"`
+	Bands  []ScoreBandResp `json:"bands"`
+}
+
+type ScoreBandResp struct {
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Points int      `json:"points"`
+	Label  string   `json:"label,omitempty"`
+}
+
+type GradeThresholdResp struct {
+	Grade    string `json:"grade"`
+	MinScore int    `json:"min_score"`
+}
+
+func CreditScorecardToDto(s *models.CreditScorecard) *CreditScorecardResp {
+	if s == nil {
+		return nil
+	}
+
+	res := CreditScorecardResp{
+		Version:   s.Version,
+		BaseScore: s.BaseScore,
+		Factors:   []ScorecardFactorResp{},
+		Grades:    []GradeThresholdResp{},
+	}
+
+	for _, factor := range s.Factors {
+		factorResp := ScorecardFactorResp{Factor: string(factor.Factor), Bands: []ScoreBandResp{}}
+		for _, band := range factor.Bands {
+			factorResp.Bands = append(factorResp.Bands, ScoreBandResp{
+				Min:    band.Min,
+				Max:    band.Max,
+				Points: band.Points,
+				Label:  band.Label,
+			})
+		}
+
+		res.Factors = append(res.Factors, factorResp)
+	}
+
+	for _, grade := range s.Grades {
+		res.Grades = append(res.Grades, GradeThresholdResp{Grade: string(grade.Grade), MinScore: grade.MinScore})
+	}
+
 	return &res
 }
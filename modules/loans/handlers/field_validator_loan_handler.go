@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"io"
+	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/models"
 	"loan-service/modules/loans/handlers/dto"
 	"loan-service/services/auth"
 	"loan-service/utils/resp"
+	"mime/multipart"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -23,10 +27,15 @@ func NewFieldValidatorHandler(
 ) {
 	handler := &FieldValidatorLoanHandler{uc, userUC, commonHandler}
 
-	g.GET("/loans", commonHandler.FetchLoans)
-	g.GET("/loan/:loan_id", commonHandler.FetchLoan)
-	g.PATCH("/loan/:loan_id/visit", handler.MarkLoanBorrowerVisited)
-	g.PATCH("/loan/:loan_id/disburse", handler.DisburseLoan)
+	requireLoanView := authMiddleware.RequirePermission(auth.PermissionLoanViewAll, auth.PermissionLoanViewProposed)
+
+	g.GET("/loans", commonHandler.FetchLoans, requireLoanView)
+	g.GET("/loan/:loan_id", commonHandler.FetchLoan, requireLoanView)
+	g.PATCH("/loan/:loan_id/visit", handler.MarkLoanBorrowerVisited, authMiddleware.RequirePermission(auth.PermissionLoanVisit))
+	g.PATCH("/loan/:loan_id/disburse", handler.DisburseLoan, authMiddleware.RequirePermission(auth.PermissionLoanDisburse))
+	g.POST("/visits/sync", handler.SyncVisits, authMiddleware.RequirePermission(auth.PermissionLoanVisit))
+	g.GET("/assignments", handler.FetchAssignments, authMiddleware.RequirePermission(auth.PermissionLoanVisit))
+	g.PATCH("/assignments/:assignment_id/schedule", handler.ScheduleLoanVisit, authMiddleware.RequirePermission(auth.PermissionLoanVisit))
 }
 
 func (h *FieldValidatorLoanHandler) MarkLoanBorrowerVisited(c echo.Context) error {
@@ -42,6 +51,16 @@ func (h *FieldValidatorLoanHandler) MarkLoanBorrowerVisited(c echo.Context) erro
 		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
 	}
 
+	reported, ok := body.VisitLocation()
+	if !ok {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	answers, ok := body.VisitReportAnswers()
+	if !ok {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
 	fileHeader, err := c.FormFile("attachment")
 	if err != nil {
 		return err
@@ -54,9 +73,31 @@ func (h *FieldValidatorLoanHandler) MarkLoanBorrowerVisited(c echo.Context) erro
 
 	defer attachedFile.Close()
 
+	report := &models.VisitReportInput{Answers: answers, Photos: map[string]io.Reader{}}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return err
+	}
+
+	for field, fileHeaders := range form.File {
+		key, ok := strings.CutPrefix(field, dto.VisitReportPhotoPrefix)
+		if !ok || len(fileHeaders) == 0 {
+			continue
+		}
+
+		photo, err := fileHeaders[0].Open()
+		if err != nil {
+			return err
+		}
+
+		defer photo.Close()
+
+		report.Photos[key] = photo
+	}
+
 	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
-		UserID:   claims.UserID,
-		RoleType: claims.RoleType,
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
@@ -67,12 +108,117 @@ func (h *FieldValidatorLoanHandler) MarkLoanBorrowerVisited(c echo.Context) erro
 		return resp.HTTPRespFromError(c, err)
 	}
 
-	err = h.Usecase.MarkLoanBorrowerVisited(reqCtx, loan, fieldValidator, attachedFile)
+	err = h.Usecase.MarkLoanBorrowerVisited(reqCtx, loan, fieldValidator, attachedFile, reported, report)
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
 	}
 
-	return resp.HTTPOk(c, dto.ModelToDto(loan))
+	return resp.HTTPOk(c, dto.ModelToDetailDto(loan, claims.Permissions))
+}
+
+// SyncVisits applies the visits the app queued while offline. Each visit gets its own result, so the app can drop the
+// applied and conflicting ones from its queue, and sync the others again.
+func (h *FieldValidatorLoanHandler) SyncVisits(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.SyncVisitsRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	manifest, ok := body.VisitSyncManifest()
+	if !ok {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(manifest); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return err
+	}
+
+	var files []multipart.File
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	// openFile opens a file the manifest refers to, or returns nil if it was not sent along
+	openFile := func(name string) (io.Reader, error) {
+		fileHeaders := form.File[name]
+		if len(fileHeaders) == 0 {
+			return nil, nil
+		}
+
+		file, err := fileHeaders[0].Open()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+
+		return file, nil
+	}
+
+	items := []models.VisitSyncItem{}
+	for _, visit := range manifest.Visits {
+		reported, ok := visit.VisitLocation()
+		if !ok {
+			return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+		}
+
+		attachment, err := openFile(visit.Attachment)
+		if err != nil {
+			return err
+		}
+
+		if attachment == nil {
+			return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+		}
+
+		report := &models.VisitReportInput{Answers: visit.Report, Photos: map[string]io.Reader{}}
+		for key, name := range visit.ReportPhotos {
+			photo, err := openFile(name)
+			if err != nil {
+				return err
+			}
+
+			if photo == nil {
+				return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+			}
+
+			report.Photos[key] = photo
+		}
+
+		items = append(items, models.VisitSyncItem{
+			ClientID:   visit.ClientID,
+			LoanID:     visit.LoanID,
+			Attachment: attachment,
+			Reported:   reported,
+			Report:     report,
+		})
+	}
+
+	fieldValidator, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	results, err := h.Usecase.SyncVisits(reqCtx, fieldValidator, items)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.VisitSyncResultsToDto(results, claims.Permissions))
 }
 
 func (h *FieldValidatorLoanHandler) DisburseLoan(c echo.Context) error {
@@ -89,8 +235,8 @@ func (h *FieldValidatorLoanHandler) DisburseLoan(c echo.Context) error {
 	}
 
 	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
-		UserID:   claims.UserID,
-		RoleType: claims.RoleType,
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
@@ -108,3 +254,45 @@ func (h *FieldValidatorLoanHandler) DisburseLoan(c echo.Context) error {
 
 	return resp.HTTPOk(c, dto.ModelToDto(loan))
 }
+
+// FetchAssignments is the field validator's task list, the visits to make in schedule order
+func (h *FieldValidatorLoanHandler) FetchAssignments(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	assignments, err := h.Usecase.FetchLoanAssignments(reqCtx, &models.FetchLoanAssignmentsOpts{
+		FieldValidatorID: claims.UserID,
+		Status:           []models.LoanAssignmentStatus{models.LoanAssignmentActive},
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.AssignmentsToDto(assignments))
+}
+
+func (h *FieldValidatorLoanHandler) ScheduleLoanVisit(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.ScheduleLoanVisitRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	fieldValidator, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	assignment, err := h.Usecase.ScheduleLoanVisit(reqCtx, fieldValidator, body.AssignmentID, *body.ScheduledAt)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.AssignmentToDto(assignment))
+}
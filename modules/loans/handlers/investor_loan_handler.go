@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/models"
 	"loan-service/modules/loans/handlers/dto"
 	"loan-service/services/auth"
 	"loan-service/utils/resp"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// Keeps idle streams open through proxies that close quiet connections
+const loanStreamHeartbeatInterval = time.Second * 15
+
 type InvestorLoanHandler struct {
 	Usecase       models.LoanUsecase
 	UserUsecase   models.UserUsecase
@@ -23,9 +28,12 @@ func NewInvestorHandler(
 ) {
 	handler := &InvestorLoanHandler{uc, userUC, commonHandler}
 
-	g.POST("/loans/:loan_id/invest", handler.InvestInLoan)
-	g.GET("/loans", commonHandler.FetchLoans)
-	g.GET("/loans/:loan_id", commonHandler.FetchLoan)
+	requireLoanView := authMiddleware.RequirePermission(auth.PermissionLoanViewAll, auth.PermissionLoanViewInvestable)
+
+	g.POST("/loans/:loan_id/invest", handler.InvestInLoan, authMiddleware.RequirePermission(auth.PermissionLoanInvest))
+	g.GET("/loans", commonHandler.FetchLoans, requireLoanView)
+	g.GET("/loans/:loan_id", commonHandler.FetchLoan, requireLoanView)
+	g.GET("/loans/:loan_id/stream", handler.StreamLoan, requireLoanView)
 }
 
 func (h *InvestorLoanHandler) InvestInLoan(c echo.Context) error {
@@ -42,7 +50,7 @@ func (h *InvestorLoanHandler) InvestInLoan(c echo.Context) error {
 	}
 
 	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
-		UserID: claims.UserID, RoleType: claims.RoleType,
+		UserID: claims.UserID, Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
@@ -60,3 +68,68 @@ func (h *InvestorLoanHandler) InvestInLoan(c echo.Context) error {
 
 	return resp.HTTPCreated(c, nil)
 }
+
+// StreamLoan pushes the loan's funding progress and state changes as Server-Sent Events, starting with its current state.
+// The stream ends once the loan is disbursed, as nothing changes afterwards.
+func (h *InvestorLoanHandler) StreamLoan(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	// Subscribe before reading the loan, so changes committed in between are not missed
+	events, unsubscribe := h.Usecase.SubscribeLoanEvents(body.LoanID)
+	defer unsubscribe()
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	resp.SSEStart(c)
+
+	snapshot := loan.NewEvent(models.LoanEventSnapshot)
+	if err := resp.SSEEvent(c, string(snapshot.Type), dto.LoanEventToDto(snapshot)); err != nil {
+		return nil
+	}
+
+	if loan.Status == models.LoanStatusDisbursed {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(loanStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Errors past this point mean the client is gone, the response has already been sent
+	for {
+		select {
+		case <-reqCtx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := resp.SSEComment(c, "ping"); err != nil {
+				return nil
+			}
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := resp.SSEEvent(c, string(event.Type), dto.LoanEventToDto(event)); err != nil {
+				return nil
+			}
+
+			if event.Status == models.LoanStatusDisbursed {
+				return nil
+			}
+		}
+	}
+}
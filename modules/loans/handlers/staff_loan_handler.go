@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
+	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/models"
 	"loan-service/modules/loans/handlers/dto"
 	"loan-service/services/auth"
 	"loan-service/utils/resp"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -23,12 +26,87 @@ func NewStaffHandler(
 ) {
 	handler := &StaffLoanHandler{uc, userUC, commonHandler}
 
-	g.PATCH("/loans/:loan_id/approve", handler.ApproveLoan)
-	g.GET("/loans", commonHandler.FetchLoans)
-	g.GET("/loans/:loan_id", commonHandler.FetchLoan)
+	requireLoanView := authMiddleware.RequirePermission(auth.PermissionLoanViewAll)
+
+	requireLoanApprove := authMiddleware.RequirePermission(auth.PermissionLoanApprove)
+	g.PATCH("/loans/:loan_id/approve", handler.ApproveLoan, requireLoanApprove)
+	g.PATCH("/loans/:loan_id/reject", handler.RejectLoan, requireLoanApprove)
+	g.GET("/loans/:loan_id/approval", handler.FetchLoanApproval, requireLoanView)
+	g.GET("/loans/approvals", handler.FetchPendingApprovals, requireLoanApprove)
+	g.GET("/approval-policies", handler.FetchApprovalPolicies, requireLoanApprove)
+	g.PUT("/approval-policies", handler.SetApprovalPolicies, authMiddleware.RequirePermission(auth.PermissionApprovalPolicyManage))
+	g.GET("/loans/:loan_id/credit-assessments", handler.FetchCreditAssessments, requireLoanView)
+	g.POST("/loans/:loan_id/credit-assessments", handler.AssessLoanCredit, requireLoanApprove)
+	g.GET("/loans/:loan_id/pricing", handler.FetchLoanPricings, requireLoanView)
+	g.GET("/credit-scorecard", handler.FetchCreditScorecard, requireLoanView)
+	g.PUT("/credit-scorecard", handler.SetCreditScorecard, authMiddleware.RequirePermission(auth.PermissionCreditScorecardManage))
+	g.PATCH("/loans/:loan_id/visit/review", handler.ReviewLoanVisit, requireLoanApprove)
+	g.GET("/loans/visit-reviews", handler.FetchVisitReviews, requireLoanView)
+
+	requireLoanAssign := authMiddleware.RequirePermission(auth.PermissionLoanAssign)
+	g.POST("/loans/:loan_id/assignment", handler.AssignLoan, requireLoanAssign)
+	g.POST("/loans/assignments/auto", handler.AutoAssignLoans, requireLoanAssign)
+	g.GET("/loans/assignments", handler.FetchLoanAssignments, requireLoanView)
+	g.GET("/loans", commonHandler.FetchLoans, requireLoanView)
+	g.GET("/loans/:loan_id", commonHandler.FetchLoan, requireLoanView)
 }
 
+// ApproveLoan votes to approve the loan, it is approved once the votes satisfy its approval policy
 func (h *StaffLoanHandler) ApproveLoan(c echo.Context) error {
+	body := dto.ApproveLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	return h.voteOnLoan(c, body.LoanID, body.Comment, h.Usecase.ApproveLoan)
+}
+
+// RejectLoan votes against the approval of the loan, approvers start over
+func (h *StaffLoanHandler) RejectLoan(c echo.Context) error {
+	body := dto.RejectLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	return h.voteOnLoan(c, body.LoanID, body.Comment, h.Usecase.RejectLoan)
+}
+
+type voteFunc func(ctx context.Context, loan *models.Loan, approver *models.User, comment string) (*models.ApprovalStatus, error)
+
+func (h *StaffLoanHandler) voteOnLoan(c echo.Context, loanID uint, comment string, vote voteFunc) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, loanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	staff, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	status, err := vote(reqCtx, loan, staff, strings.TrimSpace(comment))
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ApprovalToDto(loan, status))
+}
+
+// FetchLoanApproval shows the votes on the loan, and what its approval policy still needs
+func (h *StaffLoanHandler) FetchLoanApproval(c echo.Context) error {
 	reqCtx := c.Request().Context()
 	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
 
@@ -42,7 +120,216 @@ func (h *StaffLoanHandler) ApproveLoan(c echo.Context) error {
 	}
 
 	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
-		UserID: claims.UserID, RoleType: claims.RoleType,
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	status, err := h.Usecase.FetchLoanApprovalStatus(reqCtx, loan)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ApprovalToDto(loan, status))
+}
+
+// FetchPendingApprovals lists the visited loans waiting for approval votes
+func (h *StaffLoanHandler) FetchPendingApprovals(c echo.Context) error {
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	pending, err := h.Usecase.FetchPendingApprovals(c.Request().Context(), &models.FetchLoanOpts{
+		UserID:      claims.UserID,
+		Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.PendingApprovalsToDto(pending))
+}
+
+func (h *StaffLoanHandler) FetchApprovalPolicies(c echo.Context) error {
+	policies, err := h.Usecase.FetchApprovalPolicies(c.Request().Context())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ApprovalPoliciesToDto(policies))
+}
+
+// SetApprovalPolicies replaces the tiers of the approval policy
+func (h *StaffLoanHandler) SetApprovalPolicies(c echo.Context) error {
+	body := dto.SetApprovalPoliciesRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	policies, err := h.Usecase.SetApprovalPolicies(c.Request().Context(), body.ToModels())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ApprovalPoliciesToDto(policies))
+}
+
+// FetchCreditAssessments shows how the loan was credit scored, latest assessment first
+func (h *StaffLoanHandler) FetchCreditAssessments(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	assessments, err := h.Usecase.FetchCreditAssessments(reqCtx, loan)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.CreditAssessmentsToDto(assessments))
+}
+
+// FetchLoanPricings lists how the loan's interest rate was decided, latest first
+func (h *StaffLoanHandler) FetchLoanPricings(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	pricings, err := h.Usecase.FetchLoanPricings(reqCtx, loan)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.LoanPricingsToDto(pricings))
+}
+
+// AssessLoanCredit scores the proposed loan again with the latest scorecard
+func (h *StaffLoanHandler) AssessLoanCredit(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	staff, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	assessment, err := h.Usecase.AssessLoanCredit(reqCtx, loan, staff)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.CreditAssessmentToDto(assessment))
+}
+
+func (h *StaffLoanHandler) FetchCreditScorecard(c echo.Context) error {
+	scorecard, err := h.Usecase.FetchCreditScorecard(c.Request().Context())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.CreditScorecardToDto(scorecard))
+}
+
+// SetCreditScorecard stores a new version of the credit scorecard
+func (h *StaffLoanHandler) SetCreditScorecard(c echo.Context) error {
+	body := dto.SetCreditScorecardRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	scorecard, err := h.Usecase.SetCreditScorecard(c.Request().Context(), body.ToModel())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.CreditScorecardToDto(scorecard))
+}
+
+// FetchVisitReviews lists the loans whose visit waits for review
+func (h *StaffLoanHandler) FetchVisitReviews(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	loans, err := h.Usecase.FetchLoans(reqCtx, &models.FetchLoanOpts{
+		UserID:            claims.UserID,
+		Permissions:       claims.Permissions,
+		Status:            []models.LoanStatus{models.LoanStatusProposed},
+		VisitReviewStatus: []models.VisitReviewStatus{models.VisitReviewPending},
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelsToDto(loans))
+}
+
+func (h *StaffLoanHandler) ReviewLoanVisit(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.ReviewLoanVisitRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
 	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
@@ -53,10 +340,83 @@ func (h *StaffLoanHandler) ApproveLoan(c echo.Context) error {
 		return resp.HTTPRespFromError(c, err)
 	}
 
-	err = h.Usecase.ApproveLoan(reqCtx, loan, staff)
+	err = h.Usecase.ReviewLoanVisit(reqCtx, loan, staff, body.Decision == "accept", body.Note)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(loan))
+}
+
+// AssignLoan assigns or reassigns the visit of the loan's borrower
+func (h *StaffLoanHandler) AssignLoan(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.AssignLoanRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	loan, err := h.Usecase.FetchLoanByID(reqCtx, body.LoanID, &models.FetchLoanOpts{
+		UserID: claims.UserID, Permissions: claims.Permissions,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	staff, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	assignment, err := h.Usecase.AssignLoan(reqCtx, loan, staff, body.FieldValidatorID, body.ScheduledAt, body.Note)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.AssignmentToDto(assignment))
+}
+
+// AutoAssignLoans assigns every proposed loan waiting for a visit with the configured strategy
+func (h *StaffLoanHandler) AutoAssignLoans(c echo.Context) error {
+	assignments, err := h.Usecase.AutoAssignLoans(c.Request().Context())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.AssignmentsToDto(assignments))
+}
+
+// FetchLoanAssignments lists assignments with their history, e.g. overdue visits to follow up on
+func (h *StaffLoanHandler) FetchLoanAssignments(c echo.Context) error {
+	body := dto.FetchLoanAssignmentsRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	opts := &models.FetchLoanAssignmentsOpts{
+		LoanID:           body.LoanID,
+		FieldValidatorID: body.FieldValidatorID,
+		Overdue:          body.Overdue,
+	}
+
+	if body.Status != "" {
+		opts.Status = []models.LoanAssignmentStatus{models.LoanAssignmentStatus(body.Status)}
+	}
+
+	assignments, err := h.Usecase.FetchLoanAssignments(c.Request().Context(), opts)
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
 	}
 
-	return resp.HTTPOk(c, loan)
+	return resp.HTTPOk(c, dto.AssignmentsToDto(assignments))
 }
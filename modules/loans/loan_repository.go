@@ -4,13 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"loan-service/database"
 	"loan-service/models"
 	"loan-service/services/auth"
 	"loan-service/utils/errs"
 	"loan-service/utils/money"
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -21,16 +24,30 @@ type repository struct {
 // FetchLoanOpts is for the system to fetch loans associated a certain user according to their role type
 func (r *repository) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts) ([]models.Loan, error) {
 	var results []models.Loan
-	query := r.db.WithContext(ctx).Model(&models.Loan{}).
+	query := database.Conn(ctx, r.db).Model(&models.Loan{}).
 		Preload("Borrower").
-		Preload("Product")
+		Preload("Product.VisitChecklist").
+		Preload("Documents", "superseded_by_id IS NULL").
+		Preload("Assignments", "status = ?", models.LoanAssignmentActive).
+		Preload("Assignments.FieldValidator").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("loan_approvals.id") }).
+		Preload("Approvals.Approver")
 
 	if opts != nil && len(opts.Status) > 0 {
 		query = query.Where("status IN (?)", opts.Status)
 	}
 
-	if opts != nil && opts.UserID > 0 && opts.RoleType != "" {
-		query = scopeLoanQuery(query, opts.UserID, opts.RoleType)
+	if opts != nil && len(opts.VisitReviewStatus) > 0 {
+		query = query.Where("visit_review_status IN (?)", opts.VisitReviewStatus)
+	}
+
+	if opts != nil && opts.AwaitingApproval {
+		query = query.Where("loans.visitor_id IS NOT NULL AND (visit_review_status IS NULL OR visit_review_status NOT IN (?))",
+			[]models.VisitReviewStatus{models.VisitReviewPending, models.VisitReviewRejected})
+	}
+
+	if opts != nil && opts.UserID > 0 {
+		query = scopeLoanQuery(query, opts.UserID, opts.Permissions)
 	}
 
 	err := query.Find(&results).Error
@@ -44,16 +61,30 @@ func (r *repository) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts)
 // FetchLoanByID implements models.LoanRepository.
 func (r *repository) FetchLoanByID(ctx context.Context, loanID uint, opts *models.FetchLoanOpts) (*models.Loan, error) {
 	var result *models.Loan
-	query := r.db.WithContext(ctx).Model(&models.Loan{}).
+	query := database.Conn(ctx, r.db).Model(&models.Loan{}).
 		Preload("Borrower").
-		Preload("Product")
+		Preload("Product.VisitChecklist").
+		Preload("Documents", "superseded_by_id IS NULL").
+		Preload("Assignments", "status = ?", models.LoanAssignmentActive).
+		Preload("Assignments.FieldValidator").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("loan_approvals.id") }).
+		Preload("Approvals.Approver")
 
 	if opts != nil && len(opts.Status) > 0 {
 		query = query.Where("status IN (?)", opts.Status)
 	}
 
-	if opts != nil && opts.UserID > 0 && opts.RoleType != "" {
-		query = scopeLoanQuery(query, opts.UserID, opts.RoleType)
+	if opts != nil && len(opts.VisitReviewStatus) > 0 {
+		query = query.Where("visit_review_status IN (?)", opts.VisitReviewStatus)
+	}
+
+	if opts != nil && opts.AwaitingApproval {
+		query = query.Where("loans.visitor_id IS NOT NULL AND (visit_review_status IS NULL OR visit_review_status NOT IN (?))",
+			[]models.VisitReviewStatus{models.VisitReviewPending, models.VisitReviewRejected})
+	}
+
+	if opts != nil && opts.UserID > 0 {
+		query = scopeLoanQuery(query, opts.UserID, opts.Permissions)
 	}
 
 	err := query.Where("loans.id = ?", loanID).First(&result).Error
@@ -66,7 +97,7 @@ func (r *repository) FetchLoanByID(ctx context.Context, loanID uint, opts *model
 
 // CreateLoan implements models.LoanRepository.
 func (r *repository) CreateLoan(ctx context.Context, loan *models.Loan) error {
-	err := r.db.WithContext(ctx).Model(&models.Loan{}).Create(loan).Error
+	err := database.Conn(ctx, r.db).Model(&models.Loan{}).Create(loan).Error
 	if err != nil {
 		return err
 	}
@@ -76,7 +107,7 @@ func (r *repository) CreateLoan(ctx context.Context, loan *models.Loan) error {
 
 // InvestInLoan implements models.LoanRepository.
 func (r *repository) InvestInLoan(ctx context.Context, loan *models.Loan, investor *models.User, amount float64) error {
-	txErr := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	txErr := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		// Check for existing investments
 		var existingInvestments []models.Investment
 		err := tx.Model(&models.Investment{}).Where("loan_id = ?", loan.ID).Find(&existingInvestments).Error
@@ -149,7 +180,7 @@ func (r *repository) InvestInLoan(ctx context.Context, loan *models.Loan, invest
 // GetTotalInvestedAmount implements models.LoanRepository.
 func (r *repository) GetTotalInvestedAmount(ctx context.Context, investorID *uint) (float64, error) {
 	var results []models.Investment
-	err := r.db.WithContext(ctx).Model(&models.Investment{}).Where("investor_id = ?", investorID).Find(&results).Error
+	err := database.Conn(ctx, r.db).Model(&models.Investment{}).Where("investor_id = ?", investorID).Find(&results).Error
 	if err != nil {
 		return 0, err
 	}
@@ -169,14 +200,33 @@ func (r *repository) GetTotalInvestedAmount(ctx context.Context, investorID *uin
 
 // UpdateLoan implements models.LoanRepository.
 func (r *repository) UpdateLoan(ctx context.Context, loan *models.Loan) error {
-	err := r.db.WithContext(ctx).Model(loan).Updates(map[string]any{
-		"name":                           loan.Name,
-		"status":                         loan.Status,
-		"remaining_amount":               loan.RemainingAmount,
-		"visitor_id":                     loan.VisitorID,
-		"approver_id":                    loan.ApproverID,
-		"disburser_id":                   loan.DisburserID,
-		"proof_of_visit_attachment_file": loan.ProofOfVisitAttachmentFile,
+	err := database.Conn(ctx, r.db).Model(loan).Updates(map[string]any{
+		"name":                       loan.Name,
+		"status":                     loan.Status,
+		"remaining_amount":           loan.RemainingAmount,
+		"visitor_id":                 loan.VisitorID,
+		"approver_id":                loan.ApproverID,
+		"disburser_id":               loan.DisburserID,
+		"visited_at":                 loan.VisitedAt,
+		"visit_latitude":             loan.Visit.Latitude,
+		"visit_longitude":            loan.Visit.Longitude,
+		"visit_captured_at":          loan.Visit.CapturedAt,
+		"visit_location_source":      loan.Visit.LocationSource,
+		"visit_distance_meters":      loan.Visit.DistanceMeters,
+		"visit_flags":                loan.Visit.Flags,
+		"visit_review_status":        loan.Visit.ReviewStatus,
+		"visit_reviewer_id":          loan.Visit.ReviewerID,
+		"visit_reviewed_at":          loan.Visit.ReviewedAt,
+		"visit_review_note":          loan.Visit.ReviewNote,
+		"visit_report":               loan.Visit.Report,
+		"approval_round":             loan.ApprovalRound,
+		"interest_rate":              loan.InterestRate,
+		"total_interest":             loan.TotalInterest,
+		"roi":                        loan.ROI,
+		"credit_score":               loan.CreditScore,
+		"risk_grade":                 loan.RiskGrade,
+		"disbursed_at":               loan.DisbursedAt,
+		"installment_reminders_sent": loan.InstallmentRemindersSent,
 	}).Error
 	if err != nil {
 		return err
@@ -185,38 +235,471 @@ func (r *repository) UpdateLoan(ctx context.Context, loan *models.Loan) error {
 	return nil
 }
 
+// FetchLoanDocuments implements models.LoanRepository.
+func (r *repository) FetchLoanDocuments(
+	ctx context.Context,
+	loanID uint,
+	opts *models.FetchLoanDocumentsOpts,
+) ([]models.LoanDocument, error) {
+	var results []models.LoanDocument
+	query := database.Conn(ctx, r.db).Model(&models.LoanDocument{}).
+		Preload("Uploader").
+		Where("loan_id = ?", loanID)
+
+	if opts == nil || !opts.IncludeSuperseded {
+		query = query.Where("superseded_by_id IS NULL")
+	}
+
+	err := query.Order("document_type, version DESC").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchLoanDocumentByID implements models.LoanRepository.
+func (r *repository) FetchLoanDocumentByID(ctx context.Context, loanID, documentID uint) (*models.LoanDocument, error) {
+	var result *models.LoanDocument
+	err := database.Conn(ctx, r.db).Model(&models.LoanDocument{}).
+		Preload("Uploader").
+		Where("loan_id = ? AND id = ?", loanID, documentID).
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateLoanDocument implements models.LoanRepository.
+// Concurrent uploads of the same type race for the same version, and all but one fail on its unique index.
+func (r *repository) CreateLoanDocument(ctx context.Context, document *models.LoanDocument) error {
+	txErr := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current models.LoanDocument
+		err := tx.Model(&models.LoanDocument{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(
+				"loan_id = ? AND document_type = ? AND label = ? AND superseded_by_id IS NULL",
+				document.LoanID, document.DocumentType, document.Label,
+			).
+			Order("version DESC").
+			Limit(1).
+			Find(&current).Error
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		var version int
+		err = tx.Model(&models.LoanDocument{}).
+			Where("loan_id = ? AND document_type = ?", document.LoanID, document.DocumentType).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&version).Error
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		document.Version = version + 1
+		if current.ID > 0 {
+			document.SupersedesID = &current.ID
+		}
+
+		if err := tx.Create(document).Error; err != nil {
+			return errs.Wrap(err)
+		}
+
+		if current.ID > 0 {
+			if err := tx.Model(&current).Update("superseded_by_id", document.ID).Error; err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		return nil
+	})
+
+	if txErr != nil {
+		return errs.Wrap(txErr)
+	}
+
+	return nil
+}
+
+// FetchLoanAssignments implements models.LoanRepository.
+// Assignments are ordered as a task list, scheduled visits first and the most urgent first.
+func (r *repository) FetchLoanAssignments(
+	ctx context.Context,
+	opts *models.FetchLoanAssignmentsOpts,
+) ([]models.LoanAssignment, error) {
+	var results []models.LoanAssignment
+	query := database.Conn(ctx, r.db).Model(&models.LoanAssignment{}).
+		Preload("Loan.Borrower").
+		Preload("FieldValidator").
+		Preload("AssignedBy")
+
+	if opts != nil && opts.LoanID > 0 {
+		query = query.Where("loan_id = ?", opts.LoanID)
+	}
+
+	if opts != nil && opts.FieldValidatorID > 0 {
+		query = query.Where("field_validator_id = ?", opts.FieldValidatorID)
+	}
+
+	if opts != nil && len(opts.Status) > 0 {
+		query = query.Where("status IN (?)", opts.Status)
+	}
+
+	if opts != nil && opts.Overdue {
+		query = query.Where("status = ? AND due_at < ?", models.LoanAssignmentActive, time.Now())
+	}
+
+	if opts != nil && opts.Unreported {
+		query = query.Where("overdue_at IS NULL")
+	}
+
+	err := query.Order("scheduled_at ASC NULLS LAST, due_at ASC, id ASC").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchLoanAssignmentByID implements models.LoanRepository.
+func (r *repository) FetchLoanAssignmentByID(ctx context.Context, assignmentID uint) (*models.LoanAssignment, error) {
+	var result *models.LoanAssignment
+	err := database.Conn(ctx, r.db).Model(&models.LoanAssignment{}).
+		Preload("Loan.Borrower").
+		Preload("FieldValidator").
+		Preload("AssignedBy").
+		Where("id = ?", assignmentID).
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateLoanAssignment implements models.LoanRepository.
+// The loan is locked, so concurrent assignments of the same loan are applied one after the other.
+func (r *repository) CreateLoanAssignment(ctx context.Context, assignment *models.LoanAssignment) error {
+	txErr := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var loan models.Loan
+		err := tx.Model(&models.Loan{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", assignment.LoanID).
+			First(&loan).Error
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		err = tx.Model(&models.LoanAssignment{}).
+			Where("loan_id = ? AND status = ?", assignment.LoanID, models.LoanAssignmentActive).
+			Update("status", models.LoanAssignmentReassigned).Error
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return tx.Omit(clause.Associations).Create(assignment).Error
+	})
+
+	if txErr != nil {
+		return errs.Wrap(txErr)
+	}
+
+	return nil
+}
+
+// UpdateLoanAssignment implements models.LoanRepository.
+func (r *repository) UpdateLoanAssignment(ctx context.Context, assignment *models.LoanAssignment) error {
+	err := database.Conn(ctx, r.db).Model(assignment).Updates(map[string]any{
+		"status":          assignment.Status,
+		"scheduled_at":    assignment.ScheduledAt,
+		"scheduled_until": assignment.ScheduledUntil,
+		"due_at":          assignment.DueAt,
+		"overdue_at":      assignment.OverdueAt,
+		"completed_at":    assignment.CompletedAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchFieldValidatorWorkloads implements models.LoanRepository.
+func (r *repository) FetchFieldValidatorWorkloads(ctx context.Context) ([]models.FieldValidatorWorkload, error) {
+	var results []models.FieldValidatorWorkload
+	err := database.Conn(ctx, r.db).Table("users").
+		Select(`users.id AS field_validator_id, users.region,
+			COUNT(loan_assignments.id) FILTER (WHERE loan_assignments.status = ?) AS open_assignments,
+			MAX(loan_assignments.created_at) AS last_assigned_at`, models.LoanAssignmentActive).
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Joins("LEFT JOIN loan_assignments ON loan_assignments.field_validator_id = users.id AND loan_assignments.deleted_at IS NULL").
+		Where("roles.role_type = ? AND users.is_active AND users.deleted_at IS NULL", auth.RoleTypeFieldValidator).
+		Group("users.id, users.region").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func NewLoanRepository(db *gorm.DB) models.LoanRepository {
 	return &repository{db}
 }
 
-func scopeLoanQuery(query *gorm.DB, userID uint, roleType auth.RoleType) *gorm.DB {
-	switch roleType {
-	// Fetch loans that an investor has funded
-	case auth.RoleTypeInvestor:
+// FetchVisitSyncs implements models.LoanRepository.
+func (r *repository) FetchVisitSyncs(ctx context.Context, fieldValidatorID uint, clientIDs []string) ([]models.VisitSync, error) {
+	var results []models.VisitSync
+	err := database.Conn(ctx, r.db).Model(&models.VisitSync{}).
+		Where("field_validator_id = ? AND client_id IN (?)", fieldValidatorID, clientIDs).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CreateVisitSync implements models.LoanRepository.
+func (r *repository) CreateVisitSync(ctx context.Context, sync *models.VisitSync) error {
+	err := database.Conn(ctx, r.db).Create(sync).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchApprovalPolicies implements models.LoanRepository.
+func (r *repository) FetchApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	var results []models.ApprovalPolicy
+	err := database.Conn(ctx, r.db).Model(&models.ApprovalPolicy{}).
+		Order("min_principal_amount").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ReplaceApprovalPolicies implements models.LoanRepository.
+func (r *repository) ReplaceApprovalPolicies(ctx context.Context, policies []models.ApprovalPolicy) error {
+	txErr := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("1 = 1").Delete(&models.ApprovalPolicy{}).Error
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		if len(policies) == 0 {
+			return nil
+		}
+
+		return tx.Create(&policies).Error
+	})
+
+	if txErr != nil {
+		return errs.Wrap(txErr)
+	}
+
+	return nil
+}
+
+// FetchCreditScorecard implements models.LoanRepository.
+func (r *repository) FetchCreditScorecard(ctx context.Context) (*models.CreditScorecard, error) {
+	var result models.CreditScorecard
+	err := database.Conn(ctx, r.db).Model(&models.CreditScorecard{}).
+		Order("version DESC").
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateCreditScorecard implements models.LoanRepository.
+func (r *repository) CreateCreditScorecard(ctx context.Context, scorecard *models.CreditScorecard) error {
+	txErr := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		err := tx.Model(&models.CreditScorecard{}).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		// Concurrent changes get the same version, and all but one are refused by its unique index
+		scorecard.Version = latest + 1
+
+		return tx.Create(scorecard).Error
+	})
+
+	if txErr != nil {
+		return errs.Wrap(txErr)
+	}
+
+	return nil
+}
+
+// FetchCreditAssessments implements models.LoanRepository.
+func (r *repository) FetchCreditAssessments(ctx context.Context, loanID uint) ([]models.CreditAssessment, error) {
+	var results []models.CreditAssessment
+	err := database.Conn(ctx, r.db).Model(&models.CreditAssessment{}).
+		Preload("Assessor").
+		Where("loan_id = ?", loanID).
+		Order("id DESC").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CreateCreditAssessment implements models.LoanRepository.
+func (r *repository) CreateCreditAssessment(ctx context.Context, assessment *models.CreditAssessment) error {
+	err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(assessment).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchLoanPricings implements models.LoanRepository.
+func (r *repository) FetchLoanPricings(ctx context.Context, loanID uint) ([]models.LoanPricing, error) {
+	var results []models.LoanPricing
+	err := database.Conn(ctx, r.db).Model(&models.LoanPricing{}).
+		Preload("Actor").
+		Where("loan_id = ?", loanID).
+		Order("id DESC").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CreateLoanPricing implements models.LoanRepository.
+func (r *repository) CreateLoanPricing(ctx context.Context, pricing *models.LoanPricing) error {
+	err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(pricing).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockLoanApprovals implements models.LoanRepository.
+func (r *repository) LockLoanApprovals(ctx context.Context, loan *models.Loan) error {
+	var locked models.Loan
+	err := database.Conn(ctx, r.db).Model(&models.Loan{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select(
+			"id", "status", "approval_round", "product_id", "credit_score", "risk_grade", "visitor_id", "visited_at",
+			"visit_latitude", "visit_longitude", "visit_captured_at", "visit_location_source", "visit_distance_meters",
+			"visit_flags", "visit_review_status", "visit_reviewer_id", "visit_reviewed_at", "visit_review_note",
+			"visit_report",
+		).
+		Where("id = ?", loan.ID).
+		First(&locked).Error
+	if err != nil {
+		return err
+	}
+
+	// Products archived after the loan was requested still price it
+	var product models.Product
+	err = database.Conn(ctx, r.db).Unscoped().Model(&models.Product{}).
+		Preload("VisitChecklist").
+		Where("id = ?", locked.ProductID).
+		First(&product).Error
+	if err != nil {
+		return err
+	}
+
+	var approvals []models.LoanApproval
+	err = database.Conn(ctx, r.db).Model(&models.LoanApproval{}).
+		Preload("Approver").
+		Where("loan_id = ?", loan.ID).
+		Order("id").
+		Find(&approvals).Error
+	if err != nil {
+		return err
+	}
+
+	loan.Status = locked.Status
+	loan.ApprovalRound = locked.ApprovalRound
+	loan.Approvals = approvals
+
+	// Approving prices the loan, so it must be with the grade of the latest credit assessment and the current rates
+	loan.CreditScore = locked.CreditScore
+	loan.RiskGrade = locked.RiskGrade
+	loan.ProductID = locked.ProductID
+	loan.Product = product
+
+	// The visit decides whether the loan may be approved, and is written back with every update of the loan
+	if loan.VisitorID == nil || locked.VisitorID == nil || *loan.VisitorID != *locked.VisitorID {
+		loan.Visitor = nil
+	}
+	loan.VisitorID = locked.VisitorID
+	loan.VisitedAt = locked.VisitedAt
+	loan.Visit = locked.Visit
+
+	return nil
+}
+
+// CreateLoanApproval implements models.LoanRepository.
+func (r *repository) CreateLoanApproval(ctx context.Context, approval *models.LoanApproval) error {
+	err := database.Conn(ctx, r.db).Omit(clause.Associations).Create(approval).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// scopeLoanQuery limits the loans visible to a user by the broadest loan view permission they hold
+func scopeLoanQuery(query *gorm.DB, userID uint, permissions []auth.Permission) *gorm.DB {
+	switch {
+	// Fetch all loans
+	case auth.HasPermission(permissions, auth.PermissionLoanViewAll):
+		query = query.Preload("Visitor").
+			Preload("Approver").
+			Preload("Investors").
+			Preload("Disburser")
+	// Fetch loans that a field validator is assigned to or has worked on
+	case auth.HasPermission(permissions, auth.PermissionLoanViewProposed):
 		query = query.Preload("Visitor").
 			Preload("Approver").
 			Preload("Investors").
 			Preload("Disburser").
-			Joins("LEFT JOIN investments ON investments.investor_id = ?", userID).
-			Where("status != ?", models.LoanStatusProposed)
-	// Fetch loans that a field validator has worked on
-	case auth.RoleTypeFieldValidator:
+			Where(`loans.visitor_id = ? OR loans.disburser_id = ? OR EXISTS (
+				SELECT 1 FROM loan_assignments
+				WHERE loan_assignments.loan_id = loans.id AND loan_assignments.field_validator_id = ?
+					AND loan_assignments.status = ? AND loan_assignments.deleted_at IS NULL
+			)`, userID, userID, userID, models.LoanAssignmentActive)
+	// Fetch loans that an investor has funded
+	case auth.HasPermission(permissions, auth.PermissionLoanViewInvestable):
 		query = query.Preload("Visitor").
 			Preload("Approver").
 			Preload("Investors").
 			Preload("Disburser").
-			Where("visitor_id = ? OR disburser_id = ?", userID, userID).
-			Or("status = ?", models.LoanStatusProposed)
+			Joins("LEFT JOIN investments ON investments.loan_id = loans.id AND investments.investor_id = ?", userID).
+			Where("status != ?", models.LoanStatusProposed)
 	// Fetch loans that a borrower has requested
-	case auth.RoleTypeBorrower:
+	case auth.HasPermission(permissions, auth.PermissionLoanViewOwn):
 		query = query.Preload("Visitor").
 			Preload("Disburser").
 			Where("borrower_id = ?", userID)
+	// No loan view permission, nothing is visible
 	default:
-		query = query.Preload("Visitor").
-			Preload("Approver").
-			Preload("Investors").
-			Preload("Disburser")
+		query = query.Where("1 = 0")
 	}
 
 	return query
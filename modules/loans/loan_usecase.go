@@ -1,45 +1,61 @@
 package loans
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
+	"loan-service/config"
 	"loan-service/models"
+	"loan-service/services/attachment"
 	"loan-service/services/auth"
-	"loan-service/services/email"
 	"loan-service/services/upload"
 	"loan-service/utils/errs"
+	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
-	"github.com/gabriel-vasile/mimetype"
 	"github.com/subosito/gozaru"
-	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
 
 type usecase struct {
-	repo          models.LoanRepository
-	userUsecase   models.UserUsecase
-	emailService  email.EmailService
-	uploadService upload.UploadService
+	repo                models.LoanRepository
+	userUsecase         models.UserUsecase
+	kycUsecase          models.KYCUsecase
+	transactor          models.Transactor
+	notificationUsecase models.NotificationUsecase
+	webhookUsecase      models.WebhookUsecase
+	events              models.LoanEventBroker
+	uploadService       upload.UploadService
+	attachmentPipeline  attachment.Pipeline
 }
 
 // FetchLoanByID implements models.LoanUsecase.
 func (u *usecase) FetchLoanByID(ctx context.Context, loanID uint, opts *models.FetchLoanOpts) (*models.Loan, error) {
+	// Loans outside of the user's scope are not found either
 	loan, err := u.repo.FetchLoanByID(ctx, loanID, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrLoanNotFound)
+	}
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
 
+	if opts != nil {
+		loan.HideDocuments(opts.Permissions)
+	}
+
 	return loan, nil
 }
 
 // FetchLoans implements models.LoanUsecase.
 func (u *usecase) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts) ([]models.Loan, error) {
-	if opts != nil && !(opts.UserID > 0 && opts.RoleType != "") {
+	if opts != nil && !(opts.UserID > 0 && len(opts.Permissions) > 0) {
 		return nil, errs.Wrap(ErrInvalidParams)
 	}
 
@@ -48,6 +64,12 @@ func (u *usecase) FetchLoans(ctx context.Context, opts *models.FetchLoanOpts) ([
 		return nil, errs.Wrap(err)
 	}
 
+	if opts != nil {
+		for i := range loans {
+			loans[i].HideDocuments(opts.Permissions)
+		}
+	}
+
 	return loans, nil
 }
 
@@ -87,20 +109,60 @@ func (u *usecase) StartLoan(ctx context.Context, name string, product *models.Pr
 		return nil, errs.Wrap(ErrInvalidParams)
 	}
 
+	// Only borrowers whose identity is verified may borrow
+	profile, err := u.kycUsecase.FetchKYCProfile(ctx, borrower.ID)
+	var generalErr errs.GeneralError
+	if errors.As(err, &generalErr) && generalErr.StatusCode == http.StatusNotFound {
+		return nil, errs.Wrap(ErrKYCNotVerified)
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if !profile.Verified() {
+		return nil, errs.Wrap(ErrKYCNotVerified)
+	}
+
 	existingLoans, err := u.repo.FetchLoans(ctx, &models.FetchLoanOpts{
-		UserID:   borrower.ID,
-		RoleType: borrower.Role.RoleType,
+		UserID:      borrower.ID,
+		Permissions: []auth.Permission{auth.PermissionLoanViewOwn},
 	})
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
 
-	if len(existingLoans) > 0 {
-		return nil, errs.Wrap(ErrLoanAlreadyExists)
+	// Borrowers with a loan that ran its full term may borrow again, and are scored on that history
+	now := time.Now()
+	for i := range existingLoans {
+		if existingLoans[i].IsOpen(now) {
+			return nil, errs.Wrap(ErrLoanAlreadyExists)
+		}
 	}
 
+	// Affordability is scored at the product's rate, the grade then sets the rate the loan is offered at
 	loan := models.NewLoan(name, product, borrower)
-	err = u.repo.CreateLoan(ctx, loan)
+	assessment, err := u.assessCredit(ctx, loan, product)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := loan.Price(product, models.PricingOnApplication, nil)
+
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.CreateLoan(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		assessment.LoanID = loan.ID
+		err = u.repo.CreateCreditAssessment(txCtx, assessment)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		pricing.LoanID = loan.ID
+		return u.repo.CreateLoanPricing(txCtx, pricing)
+	})
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
@@ -109,67 +171,408 @@ func (u *usecase) StartLoan(ctx context.Context, name string, product *models.Pr
 }
 
 // MarkLoanBorrowerVisited implements models.LoanUsecase.
-func (u *usecase) MarkLoanBorrowerVisited(ctx context.Context, loan *models.Loan, visitor *models.User, attachment io.Reader) error {
-	if loan == nil || visitor == nil || attachment == nil {
-		return errs.Wrap(ErrInvalidParams)
+func (u *usecase) MarkLoanBorrowerVisited(
+	ctx context.Context,
+	loan *models.Loan,
+	visitor *models.User,
+	attachmentFile io.Reader,
+	reported *models.VisitLocation,
+	reportInput *models.VisitReportInput,
+) error {
+	visit, err := u.prepareVisit(ctx, loan, visitor, attachmentFile, reported, reportInput)
+	if err != nil {
+		return err
+	}
+
+	return u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		return u.recordVisit(txCtx, visit)
+	})
+}
+
+// preparedVisit is a visit whose files are processed and stored, waiting to be recorded on the loan
+type preparedVisit struct {
+	loan       *models.Loan
+	visitor    *models.User
+	assignment *models.LoanAssignment
+	reported   *models.VisitLocation
+	report     *models.VisitReport
+	document   *models.LoanDocument
+	photos     []models.LoanDocument
+}
+
+// prepareVisit checks the visitor may visit the loan, and stores the proof of visit and report photos. Nothing is
+// written to the database, so it runs outside of transactions.
+func (u *usecase) prepareVisit(
+	ctx context.Context,
+	loan *models.Loan,
+	visitor *models.User,
+	attachmentFile io.Reader,
+	reported *models.VisitLocation,
+	reportInput *models.VisitReportInput,
+) (*preparedVisit, error) {
+	if loan == nil || visitor == nil || attachmentFile == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
 	}
 
 	if loan.VisitorID != nil {
-		return errs.Wrap(ErrLoanAlreadyVisited)
+		return nil, errs.Wrap(ErrLoanAlreadyVisited)
 	}
 
-	mimeType, err := mimetype.DetectReader(attachment)
+	assignment := loan.ActiveAssignment()
+	if assignment == nil || assignment.FieldValidatorID != visitor.ID {
+		return nil, errs.Wrap(ErrLoanNotAssigned)
+	}
+
+	// The report is checked before anything is uploaded
+	report, err := newVisitReport(loan, reportInput)
 	if err != nil {
-		return errs.Wrap(err)
+		return nil, errs.Wrap(err)
 	}
 
-	var extension string
-	switch mimeType.String() {
-	case "image/png":
-		extension = "png"
-	case "image/jpeg":
-		extension = "jpg"
-	default:
-		// wrong mimetype
-		return errs.Wrap(ErrInvalidParams)
+	document, err := u.processLoanDocument(ctx, loan, visitor, models.LoanDocumentProofOfVisit, attachmentFile)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	photos, err := u.processVisitReportPhotos(ctx, loan, visitor, reportInput)
+	if err != nil {
+		return nil, errs.Wrap(err)
 	}
 
-	safePath := gozaru.Sanitize(fmt.Sprintf("ProofOfVisit_%s.%s", time.Now().Format(time.RFC3339), extension))
+	return &preparedVisit{
+		loan:       loan,
+		visitor:    visitor,
+		assignment: assignment,
+		reported:   reported,
+		report:     report,
+		document:   document,
+		photos:     photos,
+	}, nil
+}
+
+// recordVisit verifies the prepared visit, and records it on the loan along with its documents in the transaction of
+// the context
+func (u *usecase) recordVisit(ctx context.Context, visit *preparedVisit) error {
+	loan, assignment := visit.loan, visit.assignment
+
+	loan.Visitor = visit.visitor
+	loan.VisitorID = &visit.visitor.ID
+	loan.VerifyVisit(visit.document.Metadata, visit.reported, visitPolicy(), time.Now())
+
+	completedAt := time.Now()
+	assignment.Status = models.LoanAssignmentCompleted
+	assignment.CompletedAt = &completedAt
 
-	attachmentPath, err := u.uploadService.UploadFile(
-		attachment,
-		safePath,
-		mimeType.String(),
-	)
+	err := u.repo.CreateLoanDocument(ctx, visit.document)
 	if err != nil {
 		return errs.Wrap(err)
 	}
 
-	loan.Visitor = visitor
-	loan.ProofOfVisitAttachmentFile = attachmentPath
+	for i := range visit.photos {
+		err := u.repo.CreateLoanDocument(ctx, &visit.photos[i])
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		visit.report.AttachPhoto(visit.photos[i].Label, visit.photos[i].ID)
+	}
+
+	loan.Visit.Report = visit.report
 
 	err = u.repo.UpdateLoan(ctx, loan)
 	if err != nil {
 		return errs.Wrap(err)
 	}
 
+	err = u.repo.UpdateLoanAssignment(ctx, assignment)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	loan.Documents = append(loan.Documents, *visit.document)
+	loan.Documents = append(loan.Documents, visit.photos...)
+
 	return nil
 }
 
-// ApproveLoan implements models.LoanUsecase.
-func (u *usecase) ApproveLoan(ctx context.Context, loan *models.Loan, approver *models.User) error {
-	loan.Approver = approver
-	err := loan.AdvanceState(models.LoanStatusApproved, "ApproveLoan")
+// newVisitReport validates the report against the visit checklist of the loan's product. Products without a
+// checklist take no report.
+func newVisitReport(loan *models.Loan, input *models.VisitReportInput) (*models.VisitReport, error) {
+	checklist := loan.Product.VisitChecklist
+	if checklist == nil {
+		if !input.Empty() {
+			return nil, errs.Wrap(ErrVisitChecklistMissing)
+		}
+
+		return nil, nil
+	}
+
+	report, err := checklist.NewReport(input)
 	if err != nil {
-		return errs.Wrap(err)
+		return nil, errs.Wrap(err)
 	}
 
-	err = u.repo.UpdateLoan(ctx, loan)
+	return report, nil
+}
+
+// processVisitReportPhotos stores the photos answering the checklist, as documents labelled with their question
+func (u *usecase) processVisitReportPhotos(
+	ctx context.Context,
+	loan *models.Loan,
+	visitor *models.User,
+	input *models.VisitReportInput,
+) ([]models.LoanDocument, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(input.Photos))
+	for key := range input.Photos {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var photos []models.LoanDocument
+	for _, key := range keys {
+		photo, err := u.processLoanDocument(ctx, loan, visitor, models.LoanDocumentVisitReportPhoto, input.Photos[key])
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		photo.Label = key
+		photos = append(photos, *photo)
+	}
+
+	return photos, nil
+}
+
+// ReviewLoanVisit implements models.LoanUsecase.
+func (u *usecase) ReviewLoanVisit(ctx context.Context, loan *models.Loan, reviewer *models.User, accept bool, note string) error {
+	if loan == nil || reviewer == nil {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	// Locked like votes, so an approval never goes through on a visit being rejected, nor a review on a retaken visit
+	return u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.LockLoanApprovals(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		if loan.Status != models.LoanStatusProposed || loan.Visit.ReviewStatus != models.VisitReviewPending {
+			return errs.Wrap(ErrLoanVisitNotPendingReview)
+		}
+
+		reviewedAt := time.Now()
+		loan.Visit.ReviewerID = &reviewer.ID
+		loan.Visit.ReviewedAt = &reviewedAt
+		loan.Visit.ReviewNote = note
+		loan.Visit.ReviewStatus = models.VisitReviewAccepted
+
+		if !accept {
+			// The borrower is visited again once the loan is assigned again, the next proof of visit becomes a new
+			// version of the document
+			loan.Visit.ReviewStatus = models.VisitReviewRejected
+			loan.Visitor = nil
+			loan.VisitorID = nil
+		}
+
+		err = u.repo.UpdateLoan(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return nil
+	})
+}
+
+// visitPolicy reads the visit policy from the config, unset values fall back to the defaults
+func visitPolicy() models.VisitPolicy {
+	return models.VisitPolicy{
+		MaxDistanceMeters: config.Data.VisitMaxDistance,
+		MaxAge:            config.Data.VisitMaxAge,
+	}
+}
+
+// FetchLoanDocuments implements models.LoanUsecase.
+func (u *usecase) FetchLoanDocuments(
+	ctx context.Context,
+	loan *models.Loan,
+	permissions []auth.Permission,
+	opts *models.FetchLoanDocumentsOpts,
+) ([]models.LoanDocument, error) {
+	if loan == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	documents, err := u.repo.FetchLoanDocuments(ctx, loan.ID, opts)
 	if err != nil {
-		return errs.Wrap(err)
+		return nil, errs.Wrap(err)
 	}
 
-	return nil
+	visible := []models.LoanDocument{}
+	for _, document := range documents {
+		if document.DocumentType.VisibleTo(permissions) {
+			visible = append(visible, document)
+		}
+	}
+
+	return visible, nil
+}
+
+// UploadLoanDocument implements models.LoanUsecase.
+// The proof of visit is first uploaded by visiting the borrower, it can then be replaced until the loan is approved.
+func (u *usecase) UploadLoanDocument(
+	ctx context.Context,
+	loan *models.Loan,
+	uploader *models.User,
+	permissions []auth.Permission,
+	documentType models.LoanDocumentType,
+	file io.Reader,
+) (*models.LoanDocument, error) {
+	if loan == nil || uploader == nil || file == nil || !documentType.Valid() {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	if !auth.HasPermission(permissions, models.LoanDocumentUploadPermissions[documentType]...) {
+		return nil, errs.Wrap(ErrLoanDocumentNotAllowed)
+	}
+
+	if documentType == models.LoanDocumentProofOfVisit && (loan.VisitorID == nil || loan.Status != models.LoanStatusProposed) {
+		return nil, errs.Wrap(ErrLoanDocumentLocked)
+	}
+
+	// Whoever holds the permission, only the field validator who visited the borrower can retake their proof
+	if documentType == models.LoanDocumentProofOfVisit && *loan.VisitorID != uploader.ID {
+		return nil, errs.Wrap(ErrLoanDocumentNotAllowed)
+	}
+
+	document, err := u.processLoanDocument(ctx, loan, uploader, documentType, file)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		// A retaken proof of visit is verified again and starts a new approval round, on the loan as it is now
+		if documentType == models.LoanDocumentProofOfVisit {
+			err := u.repo.LockLoanApprovals(txCtx, loan)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+
+			if loan.Status != models.LoanStatusProposed || loan.VisitorID == nil {
+				return errs.Wrap(ErrLoanDocumentLocked)
+			}
+
+			if *loan.VisitorID != uploader.ID {
+				return errs.Wrap(ErrLoanDocumentNotAllowed)
+			}
+
+			loan.RetakeVisit(document.Metadata, visitPolicy(), time.Now())
+			err = u.repo.UpdateLoan(txCtx, loan)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		return u.repo.CreateLoanDocument(txCtx, document)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	document.Uploader = uploader
+
+	return document, nil
+}
+
+// processLoanDocument runs the file through the attachment pipeline and stores its variants, returning the document
+// to create
+func (u *usecase) processLoanDocument(
+	ctx context.Context,
+	loan *models.Loan,
+	uploader *models.User,
+	documentType models.LoanDocumentType,
+	file io.Reader,
+) (*models.LoanDocument, error) {
+	processed, err := u.attachmentPipeline.Process(ctx, file, documentType.ContentTypes()...)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	baseName := fmt.Sprintf("%s_%d_%s", documentType, loan.ID, time.Now().Format(time.RFC3339))
+	uploadedFiles, err := u.uploadVariants(ctx, processed, baseName)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &models.LoanDocument{
+		LoanID:       loan.ID,
+		DocumentType: documentType,
+		UploaderID:   &uploader.ID,
+		FileKey:      uploadedFiles[attachment.VariantOriginal],
+		ThumbnailKey: uploadedFiles[attachment.VariantThumbnail],
+		Metadata:     &processed.Metadata,
+	}, nil
+}
+
+// uploadVariants stores every variant of a processed attachment, and returns their keys by variant name
+func (u *usecase) uploadVariants(ctx context.Context, processed *attachment.Result, baseName string) (map[string]string, error) {
+	keys := map[string]string{}
+	for _, variant := range processed.Variants {
+		filename := baseName
+		if variant.Name != attachment.VariantOriginal {
+			filename += "_" + variant.Name
+		}
+
+		uploadedFile, err := u.uploadService.UploadFile(
+			ctx,
+			bytes.NewReader(variant.Data),
+			gozaru.Sanitize(filename+variant.Extension),
+			variant.ContentType,
+		)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		keys[variant.Name] = uploadedFile.Key
+	}
+
+	return keys, nil
+}
+
+// DownloadLoanDocument implements models.LoanUsecase.
+func (u *usecase) DownloadLoanDocument(
+	ctx context.Context,
+	loan *models.Loan,
+	permissions []auth.Permission,
+	documentID uint,
+	variant models.LoanDocumentVariant,
+) (io.ReadCloser, *upload.UploadedFile, error) {
+	if loan == nil {
+		return nil, nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	document, err := u.repo.FetchLoanDocumentByID(ctx, loan.ID, documentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errs.Wrap(ErrLoanDocumentNotFound)
+	}
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	// Hidden documents are reported as missing, so their existence is not disclosed either
+	key := document.Key(variant)
+	if key == "" || !document.DocumentType.VisibleTo(permissions) {
+		return nil, nil, errs.Wrap(ErrLoanDocumentNotFound)
+	}
+
+	file, fileInfo, err := u.uploadService.DownloadFile(ctx, key)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	return file, fileInfo, nil
 }
 
 // InvestInLoan implements models.LoanUsecase.
@@ -178,38 +581,62 @@ func (u *usecase) InvestInLoan(ctx context.Context, loan *models.Loan, investor
 		return ErrLoanNotInvestable
 	}
 
-	err := u.repo.InvestInLoan(ctx, loan, investor, amount)
+	previousStatus := loan.Status
+
+	// Funded notifications are queued in the same transaction, so they are sent only if the investment is committed
+	err := u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.InvestInLoan(txCtx, loan, investor, amount)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		remainingAmountFloat, _ := strconv.ParseFloat(loan.RemainingAmount, 64)
+		if remainingAmountFloat > 0 {
+			return nil
+		}
+
+		err = u.notifyLoanFunded(txCtx, loan, investor)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.webhookUsecase.Publish(txCtx, models.WebhookEventLoanFunded, loan.NewWebhookData())
+	}, &sql.TxOptions{Isolation: sql.LevelSerializable})
 	if err != nil {
-		return errs.Wrap(err)
+		return err
 	}
 
-	remainingAmountFloat, _ := strconv.ParseFloat(loan.RemainingAmount, 64)
-	if remainingAmountFloat > 0 {
-		return nil
+	u.events.Publish(loan.ID, loan.NewEvent(models.LoanEventFundingProgress))
+	if loan.Status != previousStatus {
+		u.events.Publish(loan.ID, loan.NewEvent(models.LoanEventStatusChanged))
 	}
 
-	// observer and fan-out pattern
-	eg, egCtx := errgroup.WithContext(ctx)
-	for _, investor := range loan.Investors {
-		func(loan *models.Loan, investor *models.User) {
-			eg.Go(func() error {
-				// TODO: Generate an actual loan agreement PDF letter for attachment
-				file, err := os.Open("public/loan-agreement-letter.pdf")
-				if err != nil {
-					return errs.Wrap(err)
-				}
+	return nil
+}
 
-				err = investor.NotifyEmailLoanFunded(egCtx, u.emailService, loan, file)
-				if err != nil {
-					return errs.Wrap(err)
-				}
+// notifyLoanFunded notifies every investor of the loan, including the one who completed it
+func (u *usecase) notifyLoanFunded(ctx context.Context, loan *models.Loan, lastInvestor *models.User) error {
+	// TODO: Generate an actual loan agreement PDF letter for attachment
+	agreementLetter, err := os.ReadFile("public/loan-agreement-letter.pdf")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	investors := append([]models.User{*lastInvestor}, loan.Investors...)
+	notified := map[uint]bool{}
+	for i := range investors {
+		if notified[investors[i].ID] {
+			continue
+		}
+		notified[investors[i].ID] = true
 
-				return nil
-			})
-		}(loan, &investor)
+		err := u.notificationUsecase.Notify(ctx, &investors[i], loan.NewFundedNotification(&investors[i], agreementLetter))
+		if err != nil {
+			return errs.Wrap(err)
+		}
 	}
 
-	return eg.Wait()
+	return nil
 }
 
 // DisburseLoan implements models.LoanUsecase.
@@ -224,19 +651,87 @@ func (u *usecase) DisburseLoan(ctx context.Context, loan *models.Loan, disburser
 		return errs.Wrap(err)
 	}
 
-	err = u.repo.UpdateLoan(ctx, loan)
+	disbursedAt := time.Now()
+	loan.DisbursedAt = &disbursedAt
+
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.UpdateLoan(txCtx, loan)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		err = u.notificationUsecase.Notify(txCtx, &loan.Borrower, loan.NewDisbursedNotification())
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.webhookUsecase.Publish(txCtx, models.WebhookEventLoanDisbursed, loan.NewWebhookData())
+	})
 	if err != nil {
-		return errs.Wrap(err)
+		return err
 	}
 
+	u.events.Publish(loan.ID, loan.NewEvent(models.LoanEventStatusChanged))
+
 	return nil
 }
 
+// SubscribeLoanEvents implements models.LoanUsecase.
+// Events are published only after their transaction commits, so subscribers never see a rolled back change.
+func (u *usecase) SubscribeLoanEvents(loanID uint) (<-chan models.LoanEvent, func()) {
+	return u.events.Subscribe(loanID)
+}
+
+// RemindDueInstallments implements models.LoanUsecase.
+// Borrowers are reminded once for each installment, a few days before it is due.
+func (u *usecase) RemindDueInstallments(ctx context.Context) (int, error) {
+	loans, err := u.repo.FetchLoans(ctx, &models.FetchLoanOpts{
+		Status: []models.LoanStatus{models.LoanStatusDisbursed},
+	})
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	now := time.Now()
+	reminded := 0
+	for i := range loans {
+		loan := &loans[i]
+
+		installment, ok := loan.NextInstallmentReminder(now)
+		if !ok {
+			continue
+		}
+
+		err := u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+			loan.InstallmentRemindersSent = installment
+
+			err := u.repo.UpdateLoan(txCtx, loan)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+
+			return u.notificationUsecase.Notify(txCtx, &loan.Borrower, loan.NewInstallmentDueNotification(installment))
+		})
+		if err != nil {
+			return reminded, errs.Wrap(err)
+		}
+
+		reminded++
+	}
+
+	return reminded, nil
+}
+
 func NewLoanUsecase(
 	repo models.LoanRepository,
 	userUC models.UserUsecase,
-	emailService email.EmailService,
+	kycUC models.KYCUsecase,
+	transactor models.Transactor,
+	notificationUC models.NotificationUsecase,
+	webhookUC models.WebhookUsecase,
+	events models.LoanEventBroker,
 	uploadService upload.UploadService,
+	attachmentPipeline attachment.Pipeline,
 ) models.LoanUsecase {
-	return &usecase{repo, userUC, emailService, uploadService}
+	return &usecase{repo, userUC, kycUC, transactor, notificationUC, webhookUC, events, uploadService, attachmentPipeline}
 }
@@ -0,0 +1,54 @@
+package loans
+
+import (
+	"context"
+	"fmt"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"time"
+)
+
+// RunInstallmentReminders reminds borrowers of upcoming installments on every tick until the context is cancelled
+func RunInstallmentReminders(ctx context.Context, uc models.LoanUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reminded, err := uc.RemindDueInstallments(ctx)
+			if err != nil {
+				fmt.Println(errs.Wrap(err))
+			}
+
+			if reminded > 0 {
+				fmt.Printf("[installments] reminded %d borrowers\n", reminded)
+			}
+		}
+	}
+}
+
+// RunOverdueVisitReports notifies field validators of visits past their due time on every tick until the context is
+// cancelled
+func RunOverdueVisitReports(ctx context.Context, uc models.LoanUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reported, err := uc.ReportOverdueVisits(ctx)
+			if err != nil {
+				fmt.Println(errs.Wrap(err))
+			}
+
+			if reported > 0 {
+				fmt.Printf("[visits] reported %d overdue visits\n", reported)
+			}
+		}
+	}
+}
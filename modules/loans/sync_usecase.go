@@ -0,0 +1,135 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// SyncVisits implements models.LoanUsecase.
+func (u *usecase) SyncVisits(
+	ctx context.Context,
+	fieldValidator *models.User,
+	items []models.VisitSyncItem,
+) ([]models.VisitSyncResult, error) {
+	if fieldValidator == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	clientIDs := []string{}
+	for _, item := range items {
+		clientIDs = append(clientIDs, item.ClientID)
+	}
+
+	syncs, err := u.repo.FetchVisitSyncs(ctx, fieldValidator.ID, clientIDs)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	recorded := map[string]models.VisitSync{}
+	for _, sync := range syncs {
+		recorded[sync.ClientID] = sync
+	}
+
+	results := []models.VisitSyncResult{}
+	for i := range items {
+		if sync, ok := recorded[items[i].ClientID]; ok {
+			results = append(results, models.VisitSyncResult{VisitSync: sync, Replayed: true})
+			continue
+		}
+
+		result := u.syncVisit(ctx, fieldValidator, &items[i])
+		if result.Status.Final() {
+			recorded[result.ClientID] = result.VisitSync
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// syncVisit applies the visit, and records its outcome along with it. The server's state wins conflicts: a loan
+// visited by someone else or no longer proposed keeps its visit, and the queued one is dropped.
+func (u *usecase) syncVisit(ctx context.Context, fieldValidator *models.User, item *models.VisitSyncItem) models.VisitSyncResult {
+	result := models.VisitSyncResult{
+		VisitSync: models.VisitSync{
+			FieldValidatorID: fieldValidator.ID,
+			ClientID:         item.ClientID,
+			LoanID:           item.LoanID,
+		},
+	}
+
+	loan, err := u.repo.FetchLoanByID(ctx, item.LoanID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		result.SetOutcome(models.VisitSyncRejected, ErrLoanNotFound)
+		return result
+	}
+	if err != nil {
+		fmt.Printf("[sync] visit %s of field validator %d failed: %v\n", item.ClientID, fieldValidator.ID, err)
+		result.SetOutcome(models.VisitSyncFailed, err)
+		return result
+	}
+
+	result.LoanStatus = loan.Status
+
+	err = ErrLoanNotProposed
+	if loan.Status == models.LoanStatusProposed {
+		err = u.applySyncedVisit(ctx, loan, fieldValidator, item, &result)
+	}
+
+	var generalErr errs.GeneralError
+	switch {
+	case err == nil:
+		result.Loan = loan
+		return result
+	case errors.Is(err, ErrLoanNotProposed), errors.Is(err, ErrLoanAlreadyVisited), errors.Is(err, ErrLoanNotAssigned):
+		result.SetOutcome(models.VisitSyncConflict, err)
+	case errors.As(err, &generalErr) && generalErr.StatusCode < http.StatusInternalServerError:
+		result.SetOutcome(models.VisitSyncRejected, err)
+		return result
+	default:
+		fmt.Printf("[sync] visit %s of field validator %d failed: %v\n", item.ClientID, fieldValidator.ID, err)
+		result.SetOutcome(models.VisitSyncFailed, err)
+		return result
+	}
+
+	// The conflict is recorded so replays of the visit are dropped too, even once the loan changes again
+	if err := u.repo.CreateVisitSync(ctx, &result.VisitSync); err != nil {
+		fmt.Printf("[sync] visit %s of field validator %d failed: %v\n", item.ClientID, fieldValidator.ID, err)
+		result.SetOutcome(models.VisitSyncFailed, err)
+	}
+
+	return result
+}
+
+// applySyncedVisit stores the files of the visit first, then records the visit and its outcome in a transaction only
+// as long as the database writes. The visit is verified against the time it is synced rather than when it was queued,
+// which only the device reports, so a visit queued for longer than the visit policy allows is flagged for review.
+func (u *usecase) applySyncedVisit(
+	ctx context.Context,
+	loan *models.Loan,
+	fieldValidator *models.User,
+	item *models.VisitSyncItem,
+	result *models.VisitSyncResult,
+) error {
+	visit, err := u.prepareVisit(ctx, loan, fieldValidator, item.Attachment, item.Reported, item.Report)
+	if err != nil {
+		return err
+	}
+
+	return u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.recordVisit(txCtx, visit)
+		if err != nil {
+			return err
+		}
+
+		result.SetOutcome(models.VisitSyncApplied, nil)
+		return u.repo.CreateVisitSync(txCtx, &result.VisitSync)
+	})
+}
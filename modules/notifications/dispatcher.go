@@ -0,0 +1,31 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"time"
+)
+
+// RunDispatcher delivers due outbox messages on every tick until the context is cancelled
+func RunDispatcher(ctx context.Context, uc models.OutboxMessageUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := uc.DispatchDueMessages(ctx)
+			if err != nil {
+				fmt.Println(errs.Wrap(err))
+			}
+
+			if sent > 0 {
+				fmt.Printf("[outbox] sent %d messages\n", sent)
+			}
+		}
+	}
+}
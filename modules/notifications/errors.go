@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrOutboxMessageNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "OutboxMessageNotFound",
+		Err:        errors.New("Cannot find the requested message."),
+	}
+
+	ErrOutboxMessageAlreadySent = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "OutboxMessageAlreadySent",
+		Err:        errors.New("This message has already been sent."),
+	}
+
+	ErrOutboxMessageNotResendable = errs.GeneralError{
+		StatusCode: http.StatusConflict,
+		ErrorCode:  "OutboxMessageNotResendable",
+		Err:        errors.New("This message may be in the middle of being sent, please try again later."),
+	}
+
+	ErrInboxNotificationNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "NotificationNotFound",
+		Err:        errors.New("Cannot find the requested notification."),
+	}
+
+	ErrUnknownNotificationEvent = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "UnknownNotificationEvent",
+		Err:        errors.New("Notification event is not supported."),
+	}
+
+	ErrUnknownNotificationChannel = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "UnknownNotificationChannel",
+		Err:        errors.New("Notification channel is not supported."),
+	}
+
+	ErrChannelNotConfigured = errs.GeneralError{
+		StatusCode: http.StatusServiceUnavailable,
+		ErrorCode:  "ChannelNotConfigured",
+		Err:        errors.New("No provider is configured for this notification channel."),
+	}
+
+	ErrPhoneNumberRequired = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "PhoneNumberRequired",
+		Err:        errors.New("Please add a phone number to your profile to receive SMS or WhatsApp notifications."),
+	}
+)
@@ -0,0 +1,12 @@
+package dto
+
+import (
+	"loan-service/services/email"
+	"loan-service/utils/i18n"
+)
+
+type PreviewEmailTemplateRequest struct {
+	Template email.TemplateName `param:"template" validate:"required,gt=0"`
+	Locale   i18n.Locale        `query:"locale" validate:"omitempty,oneof=en id"`
+	Format   string             `query:"format" validate:"omitempty,oneof=json html"`
+}
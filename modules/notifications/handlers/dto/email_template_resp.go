@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"loan-service/services/email"
+	"loan-service/utils/i18n"
+)
+
+type FetchEmailTemplateResp struct {
+	Name    email.TemplateName `json:"name"`
+	Locales []i18n.Locale      `json:"locales"`
+}
+
+type PreviewEmailTemplateResp struct {
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"html_body"`
+	TextBody string `json:"text_body"`
+}
+
+func TemplatesToDto(templates []email.TemplateName) []FetchEmailTemplateResp {
+	var result []FetchEmailTemplateResp
+	for _, template := range templates {
+		result = append(result, FetchEmailTemplateResp{
+			Name:    template,
+			Locales: i18n.SupportedLocales,
+		})
+	}
+
+	return result
+}
+
+func RenderedEmailToDto(rendered *email.RenderedEmail) *PreviewEmailTemplateResp {
+	if rendered == nil {
+		return nil
+	}
+
+	return &PreviewEmailTemplateResp{
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.Body.HTML,
+		TextBody: rendered.Body.Text,
+	}
+}
@@ -0,0 +1,11 @@
+package dto
+
+type FetchInboxRequest struct {
+	UnreadOnly bool `query:"unread"`
+	Limit      int  `query:"limit" validate:"omitempty,gt=0,lte=100"`
+	Offset     int  `query:"offset" validate:"omitempty,gte=0"`
+}
+
+type InboxNotificationIDRequest struct {
+	NotificationID uint `param:"notification_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"loan-service/models"
+	"time"
+)
+
+type FetchInboxNotificationResp struct {
+	ID        uint                     `json:"id"`
+	Event     models.NotificationEvent `json:"event"`
+	LoanID    *uint                    `json:"loan_id"`
+	Title     string                   `json:"title"`
+	Body      string                   `json:"body"`
+	IsRead    bool                     `json:"is_read"`
+	ReadAt    *time.Time               `json:"read_at"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+type FetchInboxResp struct {
+	Notifications []FetchInboxNotificationResp `json:"notifications"`
+	Total         int64                        `json:"total"`
+	UnreadCount   int64                        `json:"unread_count"`
+	Limit         int                          `json:"limit"`
+	Offset        int                          `json:"offset"`
+}
+
+type UnreadCountResp struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkAllReadResp struct {
+	Marked int64 `json:"marked"`
+}
+
+func InboxToDto(inbox *models.Inbox, limit, offset int) *FetchInboxResp {
+	if inbox == nil {
+		return nil
+	}
+
+	result := &FetchInboxResp{
+		Notifications: []FetchInboxNotificationResp{},
+		Total:         inbox.Total,
+		UnreadCount:   inbox.UnreadCount,
+		Limit:         limit,
+		Offset:        offset,
+	}
+
+	for _, notification := range inbox.Notifications {
+		result.Notifications = append(result.Notifications, *InboxNotificationToDto(&notification))
+	}
+
+	return result
+}
+
+func InboxNotificationToDto(n *models.InboxNotification) *FetchInboxNotificationResp {
+	if n == nil {
+		return nil
+	}
+
+	return &FetchInboxNotificationResp{
+		ID:        n.ID,
+		Event:     n.Event,
+		LoanID:    n.LoanID,
+		Title:     n.Title,
+		Body:      n.Body,
+		IsRead:    n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "loan-service/models"
+
+type UpdateNotificationPreferencesRequest struct {
+	// Channels per event, events left out keep their current channels
+	Preferences map[models.NotificationEvent][]models.NotificationChannel `json:"preferences" validate:"required,gt=0"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "loan-service/models"
+
+type FetchNotificationPreferencesResp struct {
+	PhoneNumber string                                                    `json:"phone_number"`
+	Preferences map[models.NotificationEvent][]models.NotificationChannel `json:"preferences"`
+}
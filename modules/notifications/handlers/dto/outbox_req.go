@@ -0,0 +1,15 @@
+package dto
+
+import "loan-service/models"
+
+type FetchOutboxMessagesRequest struct {
+	Status      models.OutboxMessageStatus `query:"status" validate:"omitempty,oneof=pending sent dead"`
+	Channel     models.NotificationChannel `query:"channel" validate:"omitempty,oneof=email sms whatsapp"`
+	RecipientID uint                       `query:"recipient_id"`
+	Limit       int                        `query:"limit" validate:"omitempty,gt=0,lte=100"`
+	Offset      int                        `query:"offset" validate:"omitempty,gte=0"`
+}
+
+type OutboxMessageIDRequest struct {
+	MessageID uint `param:"message_id" validate:"required,gt=0"`
+}
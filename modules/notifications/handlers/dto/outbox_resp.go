@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"loan-service/models"
+	"loan-service/services/email"
+	"time"
+)
+
+// FetchOutboxMessageResp never includes the message body, it may hold secrets such as invitation links
+type FetchOutboxMessageResp struct {
+	ID                 uint                       `json:"id"`
+	Channel            models.NotificationChannel `json:"channel"`
+	RecipientID        *uint                      `json:"recipient_id"`
+	RecipientName      string                     `json:"recipient_name"`
+	RecipientAddress   string                     `json:"recipient_address"`
+	Template           email.TemplateName         `json:"template"`
+	Subject            string                     `json:"subject"`
+	AttachmentFilename string                     `json:"attachment_filename,omitempty"`
+	Status             models.OutboxMessageStatus `json:"status"`
+	Attempts           int                        `json:"attempts"`
+	NextAttemptAt      time.Time                  `json:"next_attempt_at"`
+	LastError          string                     `json:"last_error,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
+	SentAt             *time.Time                 `json:"sent_at"`
+}
+
+func OutboxMessagesToDto(outboxMessages []models.OutboxMessage) []FetchOutboxMessageResp {
+	result := []FetchOutboxMessageResp{}
+	for _, outboxMessage := range outboxMessages {
+		result = append(result, *OutboxMessageToDto(&outboxMessage))
+	}
+
+	return result
+}
+
+func OutboxMessageToDto(m *models.OutboxMessage) *FetchOutboxMessageResp {
+	if m == nil {
+		return nil
+	}
+
+	return &FetchOutboxMessageResp{
+		ID:                 m.ID,
+		Channel:            m.Channel,
+		RecipientID:        m.RecipientID,
+		RecipientName:      m.RecipientName,
+		RecipientAddress:   m.RecipientAddress,
+		Template:           m.Template,
+		Subject:            m.Subject,
+		AttachmentFilename: m.AttachmentFilename,
+		Status:             m.Status,
+		Attempts:           m.Attempts,
+		NextAttemptAt:      m.NextAttemptAt,
+		LastError:          m.LastError,
+		CreatedAt:          m.CreatedAt,
+		SentAt:             m.SentAt,
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/modules/notifications/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/services/email"
+	"loan-service/utils/resp"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EmailTemplateHandler struct{}
+
+func NewEmailTemplateHandler(
+	g *echo.Group,
+) {
+	handler := &EmailTemplateHandler{}
+
+	requireNotificationView := authMiddleware.RequirePermission(auth.PermissionNotificationView)
+
+	g.GET("/email-templates", handler.FetchEmailTemplates, requireNotificationView)
+	g.GET("/email-templates/:template/preview", handler.PreviewEmailTemplate, requireNotificationView)
+}
+
+func (h *EmailTemplateHandler) FetchEmailTemplates(c echo.Context) error {
+	return resp.HTTPOk(c, dto.TemplatesToDto(email.Templates))
+}
+
+// PreviewEmailTemplate renders the template with sample data, as raw HTML for viewing in a browser if format=html
+func (h *EmailTemplateHandler) PreviewEmailTemplate(c echo.Context) error {
+	body := dto.PreviewEmailTemplateRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	rendered, err := email.Render(body.Template, body.Locale, email.TemplateSamples[body.Template])
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if body.Format == "html" {
+		return c.HTML(http.StatusOK, rendered.Body.HTML)
+	}
+
+	return resp.HTTPOk(c, dto.RenderedEmailToDto(rendered))
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"loan-service/models"
+	"loan-service/modules/notifications/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultInboxPageSize = 20
+
+// InboxHandler serves the logged in user's own notifications, so no permission is required
+type InboxHandler struct {
+	Usecase models.NotificationUsecase
+}
+
+func NewInboxHandler(
+	g *echo.Group,
+	uc models.NotificationUsecase,
+) {
+	handler := &InboxHandler{uc}
+
+	g.GET("/notifications", handler.FetchInbox)
+	g.GET("/notifications/unread-count", handler.CountUnreadNotifications)
+	g.PATCH("/notifications/read", handler.MarkAllNotificationsRead)
+	g.PATCH("/notifications/:notification_id/read", handler.MarkNotificationRead)
+}
+
+func (h *InboxHandler) FetchInbox(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	body := dto.FetchInboxRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if body.Limit == 0 {
+		body.Limit = defaultInboxPageSize
+	}
+
+	inbox, err := h.Usecase.FetchInbox(reqCtx, &models.FetchInboxNotificationsOpts{
+		UserID:     claims.UserID,
+		UnreadOnly: body.UnreadOnly,
+		Limit:      body.Limit,
+		Offset:     body.Offset,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.InboxToDto(inbox, body.Limit, body.Offset))
+}
+
+func (h *InboxHandler) CountUnreadNotifications(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	unreadCount, err := h.Usecase.CountUnreadInboxNotifications(reqCtx, claims.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.UnreadCountResp{UnreadCount: unreadCount})
+}
+
+func (h *InboxHandler) MarkNotificationRead(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	body := dto.InboxNotificationIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	notification, err := h.Usecase.MarkInboxNotificationRead(reqCtx, claims.UserID, body.NotificationID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.InboxNotificationToDto(notification))
+}
+
+func (h *InboxHandler) MarkAllNotificationsRead(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	marked, err := h.Usecase.MarkAllInboxNotificationsRead(reqCtx, claims.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.MarkAllReadResp{Marked: marked})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"loan-service/models"
+	"loan-service/modules/notifications/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationPreferenceHandler struct {
+	Usecase     models.NotificationUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewNotificationPreferenceHandler(
+	g *echo.Group,
+	uc models.NotificationUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &NotificationPreferenceHandler{uc, userUC}
+
+	g.GET("/notification-preferences", handler.FetchNotificationPreferences)
+	g.PUT("/notification-preferences", handler.UpdateNotificationPreferences)
+}
+
+func (h *NotificationPreferenceHandler) FetchNotificationPreferences(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	user, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if user == nil {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	preferences, err := h.Usecase.FetchNotificationPreferences(reqCtx, user)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.FetchNotificationPreferencesResp{
+		PhoneNumber: user.PhoneNumber,
+		Preferences: preferences,
+	})
+}
+
+func (h *NotificationPreferenceHandler) UpdateNotificationPreferences(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	body := dto.UpdateNotificationPreferencesRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	user, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if user == nil {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	err = h.Usecase.UpdateNotificationPreferences(reqCtx, user, body.Preferences)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	preferences, err := h.Usecase.FetchNotificationPreferences(reqCtx, user)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.FetchNotificationPreferencesResp{
+		PhoneNumber: user.PhoneNumber,
+		Preferences: preferences,
+	})
+}
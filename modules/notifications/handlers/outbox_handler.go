@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/notifications/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultOutboxPageSize = 50
+
+type OutboxHandler struct {
+	Usecase models.OutboxMessageUsecase
+}
+
+func NewOutboxHandler(
+	g *echo.Group,
+	uc models.OutboxMessageUsecase,
+) {
+	handler := &OutboxHandler{uc}
+
+	requireNotificationView := authMiddleware.RequirePermission(auth.PermissionNotificationView)
+	requireNotificationManage := authMiddleware.RequirePermission(auth.PermissionNotificationManage)
+
+	g.GET("/outbox", handler.FetchOutboxMessages, requireNotificationView)
+	g.GET("/outbox/:message_id", handler.FetchOutboxMessageByID, requireNotificationView)
+	g.POST("/outbox/:message_id/resend", handler.ResendOutboxMessage, requireNotificationManage)
+}
+
+func (h *OutboxHandler) FetchOutboxMessages(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.FetchOutboxMessagesRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if body.Limit == 0 {
+		body.Limit = defaultOutboxPageSize
+	}
+
+	outboxMessages, err := h.Usecase.FetchOutboxMessages(reqCtx, &models.FetchOutboxMessagesOpts{
+		Status:      body.Status,
+		Channel:     body.Channel,
+		RecipientID: body.RecipientID,
+		Limit:       body.Limit,
+		Offset:      body.Offset,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.OutboxMessagesToDto(outboxMessages))
+}
+
+func (h *OutboxHandler) FetchOutboxMessageByID(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.OutboxMessageIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	outboxMessage, err := h.Usecase.FetchOutboxMessageByID(reqCtx, body.MessageID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.OutboxMessageToDto(outboxMessage))
+}
+
+// ResendOutboxMessage queues a dead-lettered message, or a pending one whose dispatcher lease expired, for immediate
+// delivery
+func (h *OutboxHandler) ResendOutboxMessage(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.OutboxMessageIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	outboxMessage, err := h.Usecase.ResendOutboxMessage(reqCtx, body.MessageID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.OutboxMessageToDto(outboxMessage))
+}
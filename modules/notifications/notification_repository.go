@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"loan-service/database"
+	"loan-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// FetchNotificationPreferences implements models.NotificationRepository.
+func (r *notificationRepository) FetchNotificationPreferences(ctx context.Context, userID uint) ([]models.NotificationPreference, error) {
+	var results []models.NotificationPreference
+	err := database.Conn(ctx, r.db).Model(&models.NotificationPreference{}).Where("user_id = ?", userID).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// UpsertNotificationPreferences implements models.NotificationRepository.
+func (r *notificationRepository) UpsertNotificationPreferences(ctx context.Context, preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	err := database.Conn(ctx, r.db).Model(&models.NotificationPreference{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channels", "updated_at"}),
+	}).Create(&preferences).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateInboxNotification implements models.NotificationRepository.
+func (r *notificationRepository) CreateInboxNotification(ctx context.Context, notification *models.InboxNotification) error {
+	return database.Conn(ctx, r.db).Create(notification).Error
+}
+
+func (r *notificationRepository) inboxQuery(ctx context.Context, opts *models.FetchInboxNotificationsOpts) *gorm.DB {
+	query := database.Conn(ctx, r.db).Model(&models.InboxNotification{})
+	if opts == nil {
+		return query
+	}
+
+	if opts.UserID > 0 {
+		query = query.Where("user_id = ?", opts.UserID)
+	}
+
+	if opts.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	return query
+}
+
+// FetchInboxNotifications implements models.NotificationRepository.
+func (r *notificationRepository) FetchInboxNotifications(
+	ctx context.Context,
+	opts *models.FetchInboxNotificationsOpts,
+) ([]models.InboxNotification, error) {
+	query := r.inboxQuery(ctx, opts).Order("created_at DESC, id DESC")
+
+	if opts != nil && opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	if opts != nil && opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	var results []models.InboxNotification
+	err := query.Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchInboxNotificationByID implements models.NotificationRepository.
+func (r *notificationRepository) FetchInboxNotificationByID(
+	ctx context.Context,
+	userID, notificationID uint,
+) (*models.InboxNotification, error) {
+	var result *models.InboxNotification
+	err := database.Conn(ctx, r.db).Model(&models.InboxNotification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CountInboxNotifications implements models.NotificationRepository.
+func (r *notificationRepository) CountInboxNotifications(ctx context.Context, opts *models.FetchInboxNotificationsOpts) (int64, error) {
+	var count int64
+	err := r.inboxQuery(ctx, opts).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkInboxNotificationsRead implements models.NotificationRepository.
+func (r *notificationRepository) MarkInboxNotificationsRead(
+	ctx context.Context,
+	userID uint,
+	notificationIDs []uint,
+	readAt time.Time,
+) (int64, error) {
+	query := database.Conn(ctx, r.db).Model(&models.InboxNotification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(notificationIDs) > 0 {
+		query = query.Where("id IN ?", notificationIDs)
+	}
+
+	result := query.Update("read_at", readAt)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func NewNotificationRepository(db *gorm.DB) models.NotificationRepository {
+	return &notificationRepository{db}
+}
@@ -0,0 +1,243 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type notificationUsecase struct {
+	repo          models.NotificationRepository
+	outboxUsecase models.OutboxMessageUsecase
+}
+
+// Notify implements models.NotificationUsecase.
+func (u *notificationUsecase) Notify(ctx context.Context, recipient *models.User, notification *models.Notification) error {
+	if recipient == nil || notification == nil {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	template, ok := models.NotificationEventTemplates[notification.Event]
+	if !ok {
+		return errs.Wrap(ErrUnknownNotificationEvent)
+	}
+
+	preferences, err := u.FetchNotificationPreferences(ctx, recipient)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	var messages []*models.OutboxMessage
+	for _, channel := range preferences[notification.Event] {
+		// Phone number may have been removed since the preference was set
+		if channel.RequiresPhoneNumber() && recipient.PhoneNumber == "" {
+			continue
+		}
+
+		message, err := recipient.NewOutboxMessage(channel, template, notification.Data)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		if channel == models.NotificationChannelEmail {
+			message.AttachmentData = notification.AttachmentData
+			message.AttachmentContentType = notification.AttachmentContentType
+			message.AttachmentFilename = notification.AttachmentFilename
+		}
+
+		messages = append(messages, message)
+	}
+
+	err = u.outboxUsecase.EnqueueMessages(ctx, messages...)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	inboxNotification, err := recipient.NewInboxNotification(template, notification)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = u.repo.CreateInboxNotification(ctx, inboxNotification)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// FetchNotificationPreferences implements models.NotificationUsecase.
+func (u *notificationUsecase) FetchNotificationPreferences(
+	ctx context.Context,
+	user *models.User,
+) (map[models.NotificationEvent][]models.NotificationChannel, error) {
+	if user == nil || user.ID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	stored, err := u.repo.FetchNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	preferences := map[models.NotificationEvent][]models.NotificationChannel{}
+	for event := range models.NotificationEventTemplates {
+		preferences[event] = models.DefaultNotificationChannels
+	}
+
+	for _, preference := range stored {
+		if _, ok := preferences[preference.Event]; ok {
+			preferences[preference.Event] = preference.Channels
+		}
+	}
+
+	return preferences, nil
+}
+
+// UpdateNotificationPreferences implements models.NotificationUsecase.
+// Events left out keep their current channels.
+func (u *notificationUsecase) UpdateNotificationPreferences(
+	ctx context.Context,
+	user *models.User,
+	preferences map[models.NotificationEvent][]models.NotificationChannel,
+) error {
+	if user == nil || user.ID == 0 {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	updated := make([]models.NotificationPreference, 0, len(preferences))
+	for event, channels := range preferences {
+		if _, ok := models.NotificationEventTemplates[event]; !ok {
+			return errs.Wrap(ErrUnknownNotificationEvent)
+		}
+
+		deduped := []models.NotificationChannel{}
+		for _, channel := range channels {
+			if !slices.Contains(models.NotificationChannels, channel) {
+				return errs.Wrap(ErrUnknownNotificationChannel)
+			}
+
+			if channel.RequiresPhoneNumber() && user.PhoneNumber == "" {
+				return errs.Wrap(ErrPhoneNumberRequired)
+			}
+
+			if !slices.Contains(deduped, channel) {
+				deduped = append(deduped, channel)
+			}
+		}
+
+		updated = append(updated, models.NotificationPreference{
+			UserID:   user.ID,
+			Event:    event,
+			Channels: deduped,
+		})
+	}
+
+	err := u.repo.UpsertNotificationPreferences(ctx, updated)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// FetchInbox implements models.NotificationUsecase.
+func (u *notificationUsecase) FetchInbox(ctx context.Context, opts *models.FetchInboxNotificationsOpts) (*models.Inbox, error) {
+	if opts == nil || opts.UserID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	notifications, err := u.repo.FetchInboxNotifications(ctx, opts)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	total, err := u.repo.CountInboxNotifications(ctx, &models.FetchInboxNotificationsOpts{
+		UserID:     opts.UserID,
+		UnreadOnly: opts.UnreadOnly,
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	unreadCount, err := u.CountUnreadInboxNotifications(ctx, opts.UserID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &models.Inbox{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unreadCount,
+	}, nil
+}
+
+// CountUnreadInboxNotifications implements models.NotificationUsecase.
+func (u *notificationUsecase) CountUnreadInboxNotifications(ctx context.Context, userID uint) (int64, error) {
+	if userID == 0 {
+		return 0, errs.Wrap(ErrInvalidParams)
+	}
+
+	count, err := u.repo.CountInboxNotifications(ctx, &models.FetchInboxNotificationsOpts{
+		UserID:     userID,
+		UnreadOnly: true,
+	})
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	return count, nil
+}
+
+// MarkInboxNotificationRead implements models.NotificationUsecase.
+// Marking an already read notification keeps its original read time.
+func (u *notificationUsecase) MarkInboxNotificationRead(ctx context.Context, userID, notificationID uint) (*models.InboxNotification, error) {
+	if userID == 0 || notificationID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	// Scoped to the user, so other users' notifications are reported as missing
+	notification, err := u.repo.FetchInboxNotificationByID(ctx, userID, notificationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrInboxNotificationNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	readAt := time.Now()
+	_, err = u.repo.MarkInboxNotificationsRead(ctx, userID, []uint{notificationID}, readAt)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	notification.ReadAt = &readAt
+
+	return notification, nil
+}
+
+// MarkAllInboxNotificationsRead implements models.NotificationUsecase.
+func (u *notificationUsecase) MarkAllInboxNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	if userID == 0 {
+		return 0, errs.Wrap(ErrInvalidParams)
+	}
+
+	marked, err := u.repo.MarkInboxNotificationsRead(ctx, userID, nil, time.Now())
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	return marked, nil
+}
+
+func NewNotificationUsecase(repo models.NotificationRepository, outboxUC models.OutboxMessageUsecase) models.NotificationUsecase {
+	return &notificationUsecase{repo, outboxUC}
+}
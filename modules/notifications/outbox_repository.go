@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	"context"
+	"loan-service/database"
+	"loan-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// CreateOutboxMessages implements models.OutboxMessageRepository.
+func (r *outboxRepository) CreateOutboxMessages(ctx context.Context, messages []*models.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	err := database.Conn(ctx, r.db).Model(&models.OutboxMessage{}).Create(messages).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchOutboxMessages implements models.OutboxMessageRepository.
+func (r *outboxRepository) FetchOutboxMessages(ctx context.Context, opts *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error) {
+	var results []models.OutboxMessage
+	query := database.Conn(ctx, r.db).Model(&models.OutboxMessage{})
+
+	if opts != nil && opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+
+	if opts != nil && opts.Channel != "" {
+		query = query.Where("channel = ?", opts.Channel)
+	}
+
+	if opts != nil && opts.RecipientID > 0 {
+		query = query.Where("recipient_id = ?", opts.RecipientID)
+	}
+
+	query = query.Order("id DESC")
+
+	if opts != nil && opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	if opts != nil && opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	err := query.Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ClaimDueOutboxMessages implements models.OutboxMessageRepository.
+func (r *outboxRepository) ClaimDueOutboxMessages(
+	ctx context.Context,
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) ([]models.OutboxMessage, error) {
+	var results []models.OutboxMessage
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.OutboxMessage{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxMessageStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&results).Error
+		if err != nil || len(results) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(results))
+		for i := range results {
+			ids = append(ids, results[i].ID)
+			results[i].NextAttemptAt = leaseUntil
+		}
+
+		return tx.Model(&models.OutboxMessage{}).Where("id IN (?)", ids).Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchOutboxMessageByID implements models.OutboxMessageRepository.
+func (r *outboxRepository) FetchOutboxMessageByID(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	var result *models.OutboxMessage
+	err := database.Conn(ctx, r.db).Model(&models.OutboxMessage{}).Where("id = ?", messageID).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateOutboxMessage implements models.OutboxMessageRepository.
+func (r *outboxRepository) UpdateOutboxMessage(ctx context.Context, outboxMessage *models.OutboxMessage) error {
+	err := database.Conn(ctx, r.db).Model(outboxMessage).Updates(map[string]any{
+		"status":          outboxMessage.Status,
+		"attempts":        outboxMessage.Attempts,
+		"next_attempt_at": outboxMessage.NextAttemptAt,
+		"last_error":      outboxMessage.LastError,
+		"sent_at":         outboxMessage.SentAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResendOutboxMessage implements models.OutboxMessageRepository.
+func (r *outboxRepository) ResendOutboxMessage(
+	ctx context.Context,
+	outboxMessage *models.OutboxMessage,
+	readStatus models.OutboxMessageStatus,
+	readNextAttemptAt time.Time,
+) error {
+	result := database.Conn(ctx, r.db).Model(outboxMessage).
+		Where("status = ? AND next_attempt_at = ?", readStatus, readNextAttemptAt).
+		Updates(map[string]any{
+			"status":          outboxMessage.Status,
+			"attempts":        outboxMessage.Attempts,
+			"next_attempt_at": outboxMessage.NextAttemptAt,
+			"last_error":      outboxMessage.LastError,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func NewOutboxMessageRepository(db *gorm.DB) models.OutboxMessageRepository {
+	return &outboxRepository{db}
+}
@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"loan-service/models"
+	"loan-service/services/email"
+	"loan-service/services/sms"
+	"loan-service/services/whatsapp"
+	"loan-service/utils/errs"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"gorm.io/gorm"
+)
+
+const (
+	// Messages sent per dispatch, the rest wait for the next tick
+	dispatchBatchSize = 50
+	sendTimeout       = time.Second * 30
+	// Claimed messages are left to their dispatcher for as long as the batch may take to send, and are picked up by
+	// another one afterwards if it stopped before updating them
+	claimLease = dispatchBatchSize * sendTimeout
+)
+
+type outboxUsecase struct {
+	repo            models.OutboxMessageRepository
+	emailService    email.EmailService
+	smsService      sms.SMSService
+	whatsAppService whatsapp.WhatsAppService
+}
+
+// EnqueueMessages implements models.OutboxMessageUsecase.
+func (u *outboxUsecase) EnqueueMessages(ctx context.Context, messages ...*models.OutboxMessage) error {
+	now := time.Now()
+	for _, outboxMessage := range messages {
+		outboxMessage.Status = models.OutboxMessageStatusPending
+		outboxMessage.NextAttemptAt = now
+	}
+
+	err := u.repo.CreateOutboxMessages(ctx, messages)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// DispatchDueMessages implements models.OutboxMessageUsecase.
+func (u *outboxUsecase) DispatchDueMessages(ctx context.Context) (int, error) {
+	// Every instance runs a dispatcher, claiming the messages keeps them from being sent once per instance
+	now := time.Now()
+	dueMessages, err := u.repo.ClaimDueOutboxMessages(ctx, now, now.Add(claimLease), dispatchBatchSize)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	sent := 0
+	for i := range dueMessages {
+		outboxMessage := &dueMessages[i]
+
+		sendErr := u.send(ctx, outboxMessage)
+		if sendErr != nil {
+			outboxMessage.MarkFailed(time.Now(), sendErr)
+			fmt.Printf("[outbox] %s message %d failed (attempt %d, %s): %v\n",
+				outboxMessage.Channel, outboxMessage.ID, outboxMessage.Attempts, outboxMessage.Status, sendErr)
+		} else {
+			outboxMessage.MarkSent(time.Now())
+			sent++
+		}
+
+		err := u.repo.UpdateOutboxMessage(ctx, outboxMessage)
+		if err != nil {
+			return sent, errs.Wrap(err)
+		}
+	}
+
+	return sent, nil
+}
+
+// send delivers the message with the service of its channel
+func (u *outboxUsecase) send(ctx context.Context, outboxMessage *models.OutboxMessage) error {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	switch outboxMessage.Channel {
+	case models.NotificationChannelEmail, "":
+		return u.sendEmail(sendCtx, outboxMessage)
+	case models.NotificationChannelSMS:
+		if u.smsService == nil {
+			return errs.Wrap(ErrChannelNotConfigured)
+		}
+
+		return u.smsService.SendSMS(sendCtx, outboxMessage.RecipientAddress, outboxMessage.TextBody)
+	case models.NotificationChannelWhatsApp:
+		if u.whatsAppService == nil {
+			return errs.Wrap(ErrChannelNotConfigured)
+		}
+
+		return u.whatsAppService.SendTemplateMessage(
+			sendCtx,
+			outboxMessage.RecipientAddress,
+			string(outboxMessage.Template),
+			outboxMessage.Locale,
+			outboxMessage.TextBody,
+		)
+	default:
+		return errs.Wrap(ErrUnknownNotificationChannel)
+	}
+}
+
+func (u *outboxUsecase) sendEmail(ctx context.Context, outboxMessage *models.OutboxMessage) error {
+	if u.emailService == nil {
+		return errs.Wrap(ErrChannelNotConfigured)
+	}
+
+	var attachment *email.AttachmentOpts
+	if len(outboxMessage.AttachmentData) > 0 {
+		attachment = &email.AttachmentOpts{
+			File:        bytes.NewReader(outboxMessage.AttachmentData),
+			ContentType: outboxMessage.AttachmentContentType,
+			Filename:    outboxMessage.AttachmentFilename,
+		}
+	}
+
+	return u.emailService.SendMail(
+		ctx,
+		outboxMessage.Subject,
+		email.Body{HTML: outboxMessage.HTMLBody, Text: outboxMessage.TextBody},
+		mail.Email{Name: u.emailService.DefaultSenderName(), Address: u.emailService.DefaultSenderAddress()},
+		mail.Email{Name: outboxMessage.RecipientName, Address: outboxMessage.RecipientAddress},
+		attachment,
+	)
+}
+
+// FetchOutboxMessages implements models.OutboxMessageUsecase.
+func (u *outboxUsecase) FetchOutboxMessages(ctx context.Context, opts *models.FetchOutboxMessagesOpts) ([]models.OutboxMessage, error) {
+	results, err := u.repo.FetchOutboxMessages(ctx, opts)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return results, nil
+}
+
+// FetchOutboxMessageByID implements models.OutboxMessageUsecase.
+func (u *outboxUsecase) FetchOutboxMessageByID(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	if messageID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	outboxMessage, err := u.repo.FetchOutboxMessageByID(ctx, messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrOutboxMessageNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return outboxMessage, nil
+}
+
+// ResendOutboxMessage implements models.OutboxMessageUsecase.
+func (u *outboxUsecase) ResendOutboxMessage(ctx context.Context, messageID uint) (*models.OutboxMessage, error) {
+	outboxMessage, err := u.FetchOutboxMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if outboxMessage.Status == models.OutboxMessageStatusSent {
+		return nil, errs.Wrap(ErrOutboxMessageAlreadySent)
+	}
+
+	now := time.Now()
+	if !outboxMessage.IsResendable(now) {
+		return nil, errs.Wrap(ErrOutboxMessageNotResendable)
+	}
+
+	readStatus, readNextAttemptAt := outboxMessage.Status, outboxMessage.NextAttemptAt
+	outboxMessage.Resend(now)
+
+	err = u.repo.ResendOutboxMessage(ctx, outboxMessage, readStatus, readNextAttemptAt)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrOutboxMessageNotResendable)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return outboxMessage, nil
+}
+
+func NewOutboxMessageUsecase(
+	repo models.OutboxMessageRepository,
+	emailService email.EmailService,
+	smsService sms.SMSService,
+	whatsAppService whatsapp.WhatsAppService,
+) models.OutboxMessageUsecase {
+	return &outboxUsecase{repo, emailService, smsService, whatsAppService}
+}
@@ -0,0 +1,21 @@
+package loans
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrProductNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "ProductNotFound",
+		Err:        errors.New("Cannot find the product."),
+	}
+
+	ErrVisitChecklistNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "VisitChecklistNotFound",
+		Err:        errors.New("This product has no visit checklist."),
+	}
+)
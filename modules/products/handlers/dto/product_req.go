@@ -0,0 +1,60 @@
+package dto
+
+import "loan-service/models"
+
+type FetchVisitChecklistRequest struct {
+	ProductID uint `param:"product_id" validate:"required,gt=0"`
+}
+
+type SetVisitChecklistRequest struct {
+	ProductID uint               `param:"product_id" validate:"required,gt=0"`
+	Questions []VisitQuestionReq `json:"questions" validate:"required,min=1,max=50,dive"`
+}
+
+type VisitQuestionReq struct {
+	Key      string `json:"key" validate:"required,max=50"`
+	Prompt   string `json:"prompt" validate:"required,max=500"`
+	Type     string `json:"type" validate:"required,oneof=yes_no number text photo"`
+	Required bool   `json:"required"`
+}
+
+func (r *SetVisitChecklistRequest) VisitQuestions() []models.VisitQuestion {
+	questions := []models.VisitQuestion{}
+	for _, question := range r.Questions {
+		questions = append(questions, models.VisitQuestion{
+			Key:      question.Key,
+			Prompt:   question.Prompt,
+			Type:     models.VisitQuestionType(question.Type),
+			Required: question.Required,
+		})
+	}
+
+	return questions
+}
+
+type FetchRateGridRequest struct {
+	ProductID uint `param:"product_id" validate:"required,gt=0"`
+}
+
+// SetRateGridRequest replaces the product's rate grid, an empty list prices every grade at the product's interest rate
+type SetRateGridRequest struct {
+	ProductID uint           `param:"product_id" validate:"required,gt=0"`
+	Rates     []GradeRateReq `json:"rates" validate:"max=5,dive"`
+}
+
+type GradeRateReq struct {
+	Grade        string  `json:"grade" validate:"required,oneof=A B C D E"`
+	InterestRate float64 `json:"interest_rate" validate:"gt=0,lte=1"`
+}
+
+func (r *SetRateGridRequest) RateGrid() models.RateGrid {
+	grid := models.RateGrid{}
+	for _, rate := range r.Rates {
+		grid = append(grid, models.GradeRate{
+			Grade:        models.RiskGrade(rate.Grade),
+			InterestRate: rate.InterestRate,
+		})
+	}
+
+	return grid
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"loan-service/models"
 	"loan-service/utils/money"
+	"time"
 )
 
 type FetchProductResp struct {
@@ -12,6 +13,8 @@ type FetchProductResp struct {
 	PrincipalAmount string `json:"principal_amount"`
 	InterestRate    string `json:"interest_rate"`
 	LoanTerm        string `json:"loan_term"`
+	// Interest rates by risk grade, if the product prices loans by risk
+	RateGrid []GradeRateResp `json:"rate_grid,omitempty"`
 }
 
 func ModelsToDto(products []models.Product) []FetchProductResp {
@@ -34,6 +37,85 @@ func ModelToDto(p *models.Product) *FetchProductResp {
 		PrincipalAmount: money.DisplayMoney(p.PrincipalAmount),
 		InterestRate:    money.DisplayAsPercentage(p.InterestRate),
 		LoanTerm:        fmt.Sprintf("%d months", p.Term),
+		RateGrid:        gradeRatesToDto(p.RateGrid),
+	}
+
+	return &res
+}
+
+type GradeRateResp struct {
+	Grade        string `json:"grade"`
+	InterestRate string `json:"interest_rate"`
+}
+
+func gradeRatesToDto(grid models.RateGrid) []GradeRateResp {
+	var result []GradeRateResp
+	for _, rate := range grid {
+		result = append(result, GradeRateResp{
+			Grade:        string(rate.Grade),
+			InterestRate: money.DisplayAsPercentage(rate.InterestRate),
+		})
+	}
+
+	return result
+}
+
+type RateGridResp struct {
+	ProductID uint `json:"product_id"`
+	// Rate of grades missing from the grid
+	InterestRate string          `json:"interest_rate"`
+	Rates        []GradeRateResp `json:"rates"`
+}
+
+func RateGridToDto(p *models.Product) *RateGridResp {
+	if p == nil {
+		return nil
+	}
+
+	res := RateGridResp{
+		ProductID:    p.ID,
+		InterestRate: money.DisplayAsPercentage(p.InterestRate),
+		Rates:        []GradeRateResp{},
+	}
+
+	res.Rates = append(res.Rates, gradeRatesToDto(p.RateGrid)...)
+
+	return &res
+}
+
+type VisitChecklistResp struct {
+	ProductID uint                `json:"product_id"`
+	Version   int                 `json:"version"`
+	Questions []VisitQuestionResp `json:"questions"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+type VisitQuestionResp struct {
+	Key      string `json:"key"`
+	Prompt   string `json:"prompt"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+func ChecklistToDto(c *models.VisitChecklist) *VisitChecklistResp {
+	if c == nil {
+		return nil
+	}
+
+	res := VisitChecklistResp{
+		ProductID: c.ProductID,
+		Version:   c.Version,
+		Questions: []VisitQuestionResp{},
+		UpdatedAt: c.UpdatedAt,
+	}
+
+	for _, question := range c.Questions {
+		res.Questions = append(res.Questions, VisitQuestionResp{
+			Key:      question.Key,
+			Prompt:   question.Prompt,
+			Type:     string(question.Type),
+			Required: question.Required,
+		})
 	}
 
 	return &res
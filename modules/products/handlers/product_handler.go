@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	authMiddleware "loan-service/app/web/middleware"
 	"loan-service/models"
 	"loan-service/modules/products/handlers/dto"
+	"loan-service/services/auth"
 	"loan-service/utils/resp"
 
 	"github.com/labstack/echo/v4"
@@ -18,7 +20,7 @@ func NewProductHandler(
 ) {
 	handler := &ProductHandler{uc}
 
-	g.GET("/products", handler.FetchProducts)
+	g.GET("/products", handler.FetchProducts, authMiddleware.RequirePermission(auth.PermissionProductView))
 }
 
 func (h *ProductHandler) FetchProducts(c echo.Context) error {
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/products/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StaffProductHandler struct {
+	Usecase models.ProductUsecase
+}
+
+func NewStaffProductHandler(
+	g *echo.Group,
+	uc models.ProductUsecase,
+) {
+	handler := &StaffProductHandler{uc}
+
+	requireProductManage := authMiddleware.RequirePermission(auth.PermissionProductManage)
+
+	g.GET("/products/:product_id/visit-checklist", handler.FetchVisitChecklist, requireProductManage)
+	g.PUT("/products/:product_id/visit-checklist", handler.SetVisitChecklist, requireProductManage)
+	g.GET("/products/:product_id/rate-grid", handler.FetchRateGrid, requireProductManage)
+	g.PUT("/products/:product_id/rate-grid", handler.SetRateGrid, requireProductManage)
+}
+
+func (h *StaffProductHandler) FetchVisitChecklist(c echo.Context) error {
+	body := dto.FetchVisitChecklistRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	checklist, err := h.Usecase.FetchVisitChecklist(c.Request().Context(), body.ProductID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ChecklistToDto(checklist))
+}
+
+// SetVisitChecklist replaces the questions field validators answer when visiting borrowers of the product
+func (h *StaffProductHandler) SetVisitChecklist(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.SetVisitChecklistRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	product, err := h.Usecase.FetchProductByID(reqCtx, body.ProductID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	checklist, err := h.Usecase.SetVisitChecklist(reqCtx, product, body.VisitQuestions())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ChecklistToDto(checklist))
+}
+
+func (h *StaffProductHandler) FetchRateGrid(c echo.Context) error {
+	body := dto.FetchRateGridRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	product, err := h.Usecase.FetchProductByID(c.Request().Context(), body.ProductID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if product.ID == 0 {
+		return resp.HTTPNotFound(c, "ProductNotFound", "Cannot find the product.")
+	}
+
+	return resp.HTTPOk(c, dto.RateGridToDto(product))
+}
+
+// SetRateGrid replaces the interest rates loans of the product are priced at by risk grade. Loans already approved
+// keep their rate.
+func (h *StaffProductHandler) SetRateGrid(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.SetRateGridRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	product, err := h.Usecase.FetchProductByID(reqCtx, body.ProductID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	product, err = h.Usecase.SetRateGrid(reqCtx, product, body.RateGrid())
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.RateGridToDto(product))
+}
@@ -5,6 +5,7 @@ import (
 	"loan-service/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -14,7 +15,7 @@ type repository struct {
 // FetchProducts implements models.ProductRepository.
 func (r *repository) FetchProducts(ctx context.Context) ([]models.Product, error) {
 	var results []models.Product
-	err := r.db.WithContext(ctx).Model(&models.Product{}).Find(&results).Error
+	err := r.db.WithContext(ctx).Model(&models.Product{}).Preload("VisitChecklist").Find(&results).Error
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +26,7 @@ func (r *repository) FetchProducts(ctx context.Context) ([]models.Product, error
 // FetchProductByID implements models.ProductRepository.
 func (r *repository) FetchProductByID(ctx context.Context, productID uint) (*models.Product, error) {
 	var result models.Product
-	err := r.db.WithContext(ctx).Model(&models.Product{}).Where("id = ?", productID).Find(&result).Error
+	err := r.db.WithContext(ctx).Model(&models.Product{}).Preload("VisitChecklist").Where("id = ?", productID).Find(&result).Error
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +34,39 @@ func (r *repository) FetchProductByID(ctx context.Context, productID uint) (*mod
 	return &result, nil
 }
 
+// SaveVisitChecklist implements models.ProductRepository.
+func (r *repository) SaveVisitChecklist(ctx context.Context, checklist *models.VisitChecklist) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.VisitChecklist
+		err := tx.Model(&models.VisitChecklist{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", checklist.ProductID).
+			Limit(1).
+			Find(&current).Error
+		if err != nil {
+			return err
+		}
+
+		checklist.Version = current.Version + 1
+		if current.ID == 0 {
+			return tx.Create(checklist).Error
+		}
+
+		checklist.ID = current.ID
+		checklist.CreatedAt = current.CreatedAt
+
+		return tx.Model(checklist).Updates(map[string]any{
+			"version":   checklist.Version,
+			"questions": checklist.Questions,
+		}).Error
+	})
+}
+
+// UpdateProductRateGrid implements models.ProductRepository.
+func (r *repository) UpdateProductRateGrid(ctx context.Context, product *models.Product) error {
+	return r.db.WithContext(ctx).Model(product).Update("rate_grid", product.RateGrid).Error
+}
+
 func NewProductRepository(db *gorm.DB) models.ProductRepository {
 	return &repository{db}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"loan-service/models"
+	"loan-service/utils/errs"
 
 	"gorm.io/gorm"
 )
@@ -32,6 +33,68 @@ func (u *usecase) FetchProductByID(ctx context.Context, productID uint) (*models
 	return product, nil
 }
 
+// FetchVisitChecklist implements models.ProductUsecase.
+func (u *usecase) FetchVisitChecklist(ctx context.Context, productID uint) (*models.VisitChecklist, error) {
+	product, err := u.repo.FetchProductByID(ctx, productID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if product.ID == 0 {
+		return nil, errs.Wrap(ErrProductNotFound)
+	}
+
+	if product.VisitChecklist == nil {
+		return nil, errs.Wrap(ErrVisitChecklistNotFound)
+	}
+
+	return product.VisitChecklist, nil
+}
+
+// SetVisitChecklist implements models.ProductUsecase.
+func (u *usecase) SetVisitChecklist(
+	ctx context.Context,
+	product *models.Product,
+	questions []models.VisitQuestion,
+) (*models.VisitChecklist, error) {
+	if product == nil || product.ID == 0 {
+		return nil, errs.Wrap(ErrProductNotFound)
+	}
+
+	checklist := &models.VisitChecklist{ProductID: product.ID, Questions: questions}
+	if err := checklist.Validate(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err := u.repo.SaveVisitChecklist(ctx, checklist)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	product.VisitChecklist = checklist
+
+	return checklist, nil
+}
+
+// SetRateGrid implements models.ProductUsecase.
+func (u *usecase) SetRateGrid(ctx context.Context, product *models.Product, grid models.RateGrid) (*models.Product, error) {
+	if product == nil || product.ID == 0 {
+		return nil, errs.Wrap(ErrProductNotFound)
+	}
+
+	if err := grid.Validate(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	product.RateGrid = grid.Sorted()
+	err := u.repo.UpdateProductRateGrid(ctx, product)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return product, nil
+}
+
 func NewProductUsecase(repo models.ProductRepository) models.ProductUsecase {
 	return &usecase{repo}
 }
@@ -0,0 +1,45 @@
+package roles
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrRoleNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "RoleNotFound",
+		Err:        errors.New("Cannot find the requested role."),
+	}
+
+	ErrRoleAlreadyExists = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "RoleAlreadyExists",
+		Err:        errors.New("A role with the same role type already exists."),
+	}
+
+	ErrUnknownPermission = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "UnknownPermission",
+		Err:        errors.New("One or more of the requested permissions does not exist."),
+	}
+
+	ErrPermissionNotGrantable = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "PermissionNotGrantable",
+		Err:        errors.New("You cannot grant permissions you do not have."),
+	}
+
+	ErrSuperuserRoleImmutable = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "SuperuserRoleImmutable",
+		Err:        errors.New("Permissions of the superuser role cannot be modified."),
+	}
+)
@@ -0,0 +1,18 @@
+package dto
+
+import "loan-service/services/auth"
+
+type CreateRoleRequest struct {
+	Name        string            `json:"name" validate:"required,gt=0"`
+	RoleType    auth.RoleType     `json:"role_type" validate:"required,gt=0"`
+	Permissions []auth.Permission `json:"permissions"`
+}
+
+type FetchRoleRequest struct {
+	RoleID uint `param:"role_id" validate:"required,gt=0"`
+}
+
+type SetRolePermissionsRequest struct {
+	RoleID      uint              `param:"role_id" validate:"required,gt=0"`
+	Permissions []auth.Permission `json:"permissions"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"loan-service/models"
+	"loan-service/services/auth"
+)
+
+type FetchRoleResp struct {
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	RoleType    string            `json:"role_type"`
+	Permissions []auth.Permission `json:"permissions"`
+}
+
+type FetchPermissionResp struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+func ModelsToDto(roles []models.Role) []FetchRoleResp {
+	var result []FetchRoleResp
+	for _, role := range roles {
+		result = append(result, *ModelToDto(&role))
+	}
+
+	return result
+}
+
+func ModelToDto(r *models.Role) *FetchRoleResp {
+	if r == nil {
+		return nil
+	}
+
+	res := FetchRoleResp{
+		ID:          r.ID,
+		Name:        r.Name,
+		RoleType:    string(r.RoleType),
+		Permissions: r.PermissionCodes(),
+	}
+
+	return &res
+}
+
+func PermissionModelsToDto(permissions []models.Permission) []FetchPermissionResp {
+	var result []FetchPermissionResp
+	for _, permission := range permissions {
+		result = append(result, FetchPermissionResp{
+			Code:        string(permission.Code),
+			Description: permission.Description,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/roles/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	Usecase     models.RoleUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewRoleHandler(
+	g *echo.Group,
+	uc models.RoleUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &RoleHandler{uc, userUC}
+
+	requireRoleManage := authMiddleware.RequirePermission(auth.PermissionRoleManage)
+
+	g.GET("/permissions", handler.FetchPermissions, requireRoleManage)
+	g.GET("/roles", handler.FetchRoles, requireRoleManage)
+	g.GET("/roles/:role_id", handler.FetchRole, requireRoleManage)
+	g.POST("/roles", handler.CreateRole, requireRoleManage)
+	g.PUT("/roles/:role_id/permissions", handler.SetRolePermissions, requireRoleManage)
+}
+
+func (h *RoleHandler) FetchPermissions(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	permissions, err := h.Usecase.FetchPermissions(reqCtx)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.PermissionModelsToDto(permissions))
+}
+
+func (h *RoleHandler) FetchRoles(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	roles, err := h.Usecase.FetchRoles(reqCtx)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelsToDto(roles))
+}
+
+func (h *RoleHandler) FetchRole(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.FetchRoleRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	role, err := h.Usecase.FetchRoleByID(reqCtx, body.RoleID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(role))
+}
+
+func (h *RoleHandler) CreateRole(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.CreateRoleRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	role, err := h.Usecase.CreateRole(reqCtx, actor, body.Name, body.RoleType, body.Permissions)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.ModelToDto(role))
+}
+
+func (h *RoleHandler) SetRolePermissions(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.SetRolePermissionsRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	role, err := h.Usecase.SetRolePermissions(reqCtx, actor, body.RoleID, body.Permissions)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(role))
+}
@@ -0,0 +1,85 @@
+package roles
+
+import (
+	"context"
+	"loan-service/models"
+	"loan-service/services/auth"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// FetchRoles implements models.RoleRepository.
+func (r *repository) FetchRoles(ctx context.Context) ([]models.Role, error) {
+	var results []models.Role
+	err := r.db.WithContext(ctx).Model(&models.Role{}).Preload("Permissions").Order("id").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchRoleByID implements models.RoleRepository.
+func (r *repository) FetchRoleByID(ctx context.Context, roleID uint) (*models.Role, error) {
+	var result *models.Role
+	err := r.db.WithContext(ctx).Model(&models.Role{}).Preload("Permissions").Where("id = ?", roleID).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateRole implements models.RoleRepository.
+func (r *repository) CreateRole(ctx context.Context, role *models.Role) error {
+	err := r.db.WithContext(ctx).Model(&models.Role{}).Create(role).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchPermissions implements models.RoleRepository.
+func (r *repository) FetchPermissions(ctx context.Context) ([]models.Permission, error) {
+	var results []models.Permission
+	err := r.db.WithContext(ctx).Model(&models.Permission{}).Order("id").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchPermissionsByCodes implements models.RoleRepository.
+func (r *repository) FetchPermissionsByCodes(ctx context.Context, codes []auth.Permission) ([]models.Permission, error) {
+	var results []models.Permission
+	if len(codes) == 0 {
+		return results, nil
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.Permission{}).Where("code IN (?)", codes).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ReplaceRolePermissions implements models.RoleRepository.
+func (r *repository) ReplaceRolePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error {
+	err := r.db.WithContext(ctx).Model(role).Association("Permissions").Replace(permissions)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewRoleRepository(db *gorm.DB) models.RoleRepository {
+	return &repository{db}
+}
@@ -0,0 +1,156 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/utils/errs"
+
+	"gorm.io/gorm"
+)
+
+type usecase struct {
+	repo     models.RoleRepository
+	userRepo models.UserRepository
+}
+
+// FetchRoles implements models.RoleUsecase.
+func (u *usecase) FetchRoles(ctx context.Context) ([]models.Role, error) {
+	roles, err := u.repo.FetchRoles(ctx)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	return roles, nil
+}
+
+// FetchRoleByID implements models.RoleUsecase.
+func (u *usecase) FetchRoleByID(ctx context.Context, roleID uint) (*models.Role, error) {
+	role, err := u.repo.FetchRoleByID(ctx, roleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrRoleNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return role, nil
+}
+
+// CreateRole implements models.RoleUsecase.
+func (u *usecase) CreateRole(
+	ctx context.Context,
+	actor *models.User,
+	name string,
+	roleType auth.RoleType,
+	permissions []auth.Permission,
+) (*models.Role, error) {
+	if actor == nil || name == "" || roleType == "" {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	// Else the actor could assign the role to themselves, and gain permissions they were not given
+	if !actor.Role.MayGrant(permissions) {
+		return nil, errs.Wrap(ErrPermissionNotGrantable)
+	}
+
+	existingRole, err := u.userRepo.FetchRoleByRoleType(ctx, roleType)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	if existingRole != nil {
+		return nil, errs.Wrap(ErrRoleAlreadyExists)
+	}
+
+	grantedPermissions, err := u.fetchPermissionsByCodes(ctx, permissions)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	role := &models.Role{
+		Name:        name,
+		RoleType:    roleType,
+		Permissions: grantedPermissions,
+	}
+
+	err = u.repo.CreateRole(ctx, role)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return role, nil
+}
+
+// FetchPermissions implements models.RoleUsecase.
+func (u *usecase) FetchPermissions(ctx context.Context) ([]models.Permission, error) {
+	permissions, err := u.repo.FetchPermissions(ctx)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	return permissions, nil
+}
+
+// SetRolePermissions implements models.RoleUsecase.
+func (u *usecase) SetRolePermissions(
+	ctx context.Context,
+	actor *models.User,
+	roleID uint,
+	permissions []auth.Permission,
+) (*models.Role, error) {
+	if actor == nil {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	role, err := u.FetchRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// Superuser must always be able to manage roles, else nobody can
+	if role.RoleType == auth.RoleTypeSuperuser {
+		return nil, errs.Wrap(ErrSuperuserRoleImmutable)
+	}
+
+	if !actor.Role.MayGrant(permissions) {
+		return nil, errs.Wrap(ErrPermissionNotGrantable)
+	}
+
+	grantedPermissions, err := u.fetchPermissionsByCodes(ctx, permissions)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	err = u.repo.ReplaceRolePermissions(ctx, role, grantedPermissions)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	role.Permissions = grantedPermissions
+
+	return role, nil
+}
+
+// fetchPermissionsByCodes ensures every requested permission code exists
+func (u *usecase) fetchPermissionsByCodes(ctx context.Context, codes []auth.Permission) ([]models.Permission, error) {
+	permissions, err := u.repo.FetchPermissionsByCodes(ctx, codes)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	uniqueCodes := map[auth.Permission]bool{}
+	for _, code := range codes {
+		uniqueCodes[code] = true
+	}
+
+	if len(permissions) != len(uniqueCodes) {
+		return nil, errs.Wrap(ErrUnknownPermission)
+	}
+
+	return permissions, nil
+}
+
+func NewRoleUsecase(repo models.RoleRepository, userRepo models.UserRepository) models.RoleUsecase {
+	return &usecase{repo, userRepo}
+}
@@ -12,4 +12,64 @@ var (
 		ErrorCode:  "NotFound",
 		Err:        errors.New("Resource not found."),
 	}
+
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrUserNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "UserNotFound",
+		Err:        errors.New("Cannot find the requested user."),
+	}
+
+	ErrRoleNotFound = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "RoleNotFound",
+		Err:        errors.New("Cannot find the requested role."),
+	}
+
+	ErrEmailAlreadyRegistered = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "EmailAlreadyRegistered",
+		Err:        errors.New("A user with this email address is already registered."),
+	}
+
+	ErrPrivilegedRoleForbidden = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "PrivilegedRoleForbidden",
+		Err:        errors.New("Only superusers can manage staff and superuser accounts."),
+	}
+
+	ErrRoleExceedsPermissions = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "RoleExceedsPermissions",
+		Err:        errors.New("You cannot assign a role with permissions you do not have."),
+	}
+
+	ErrCannotModifySelf = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "CannotModifySelf",
+		Err:        errors.New("You cannot deactivate or change the role of your own account."),
+	}
+
+	ErrInvalidInvitation = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidInvitation",
+		Err:        errors.New("This invitation is invalid or has expired."),
+	}
+
+	ErrSSORoleNotMapped = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "SSORoleNotMapped",
+		Err:        errors.New("Your identity provider account is not assigned to any group allowed to use this service."),
+	}
+
+	ErrTooManyLoginAttempts = errs.GeneralError{
+		StatusCode: http.StatusTooManyRequests,
+		ErrorCode:  "TooManyLoginAttempts",
+		Err:        errors.New("Too many failed login attempts, please try again later."),
+	}
 )
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/users/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminUserHandler struct {
+	Usecase models.UserUsecase
+}
+
+func NewAdminUserHandler(
+	g *echo.Group,
+	uc models.UserUsecase,
+) {
+	handler := &AdminUserHandler{uc}
+
+	requireUserManage := authMiddleware.RequirePermission(auth.PermissionUserManage)
+
+	g.GET("/users", handler.FetchUsers, authMiddleware.RequirePermission(auth.PermissionUserView, auth.PermissionUserManage))
+	g.GET("/users/locked", handler.FetchLockedUsers, requireUserManage)
+	g.POST("/users", handler.InviteUser, requireUserManage)
+	g.PATCH("/users/:user_id/deactivate", handler.DeactivateUser, requireUserManage)
+	g.PATCH("/users/:user_id/reactivate", handler.ReactivateUser, requireUserManage)
+	g.PATCH("/users/:user_id/role", handler.ChangeUserRole, requireUserManage)
+	g.PATCH("/users/:user_id/unlock", handler.UnlockUser, requireUserManage)
+}
+
+func (h *AdminUserHandler) FetchUsers(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchUsersRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	users, err := h.Usecase.ViewUsers(reqCtx, models.ViewUsersOpt{
+		Permissions: claims.Permissions,
+		UserID:      claims.UserID,
+		RoleType:    body.RoleType,
+		IsActive:    body.IsActive,
+		Search:      body.Search,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelsToDto(users))
+}
+
+func (h *AdminUserHandler) FetchLockedUsers(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	users, err := h.Usecase.ViewUsers(reqCtx, models.ViewUsersOpt{
+		Permissions: claims.Permissions,
+		UserID:      claims.UserID,
+		IsLocked:    true,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelsToDto(users))
+}
+
+func (h *AdminUserHandler) InviteUser(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.InviteUserRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.Usecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	user, err := h.Usecase.InviteUser(reqCtx, actor, body.Name, body.Email, body.RoleType)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.ModelToDto(user))
+}
+
+func (h *AdminUserHandler) DeactivateUser(c echo.Context) error {
+	return h.setUserActive(c, false)
+}
+
+func (h *AdminUserHandler) ReactivateUser(c echo.Context) error {
+	return h.setUserActive(c, true)
+}
+
+func (h *AdminUserHandler) setUserActive(c echo.Context, isActive bool) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchUserRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.Usecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	user, err := h.Usecase.SetUserActive(reqCtx, actor, body.UserID, isActive)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(user))
+}
+
+func (h *AdminUserHandler) ChangeUserRole(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.ChangeUserRoleRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.Usecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	user, err := h.Usecase.ChangeUserRole(reqCtx, actor, body.UserID, body.RoleType)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(user))
+}
+
+func (h *AdminUserHandler) UnlockUser(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.FetchUserRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.Usecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	user, err := h.Usecase.UnlockUser(reqCtx, actor, body.UserID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToDto(user))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"loan-service/models"
+	"loan-service/modules/users/handlers/dto"
 	"loan-service/services/auth"
 	"loan-service/utils/resp"
 
@@ -21,6 +22,8 @@ func NewCommonUserHandler(
 
 	e.POST("/login", handler.Login)
 	e.POST("/logout", handler.Logout, jwtAuthMiddleware)
+	e.POST("/invitations/accept", handler.AcceptInvitation)
+	e.PATCH("/profile", handler.UpdateProfile, jwtAuthMiddleware)
 }
 
 func (h *CommonUserHandler) Login(c echo.Context) error {
@@ -31,7 +34,10 @@ func (h *CommonUserHandler) Login(c echo.Context) error {
 		return resp.HTTPUnauthorized(c)
 	}
 
-	res, accessToken, refreshToken, err := h.Usecase.Login(reqCtx, email, password)
+	res, accessToken, refreshToken, err := h.Usecase.Login(reqCtx, email, password, &models.LoginOpts{
+		IPAddress: c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
 	if err != nil {
 		return resp.HTTPRespFromError(c, err)
 	}
@@ -52,3 +58,81 @@ func (h *CommonUserHandler) Logout(c echo.Context) error {
 
 	return resp.HTTPNoContent(c)
 }
+
+func (h *CommonUserHandler) AcceptInvitation(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.AcceptInvitationRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	err := h.Usecase.AcceptInvitation(reqCtx, body.Token, body.Password)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPNoContent(c)
+}
+
+func (h *CommonUserHandler) UpdateProfile(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims, ok := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+	if !ok {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	body := dto.UpdateProfileRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	user, err := h.Usecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if user == nil {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	if body.Name != "" {
+		user.Name = body.Name
+	}
+
+	if body.Locale != "" {
+		user.Locale = body.Locale
+	}
+
+	if body.PhoneNumber != nil {
+		user.PhoneNumber = *body.PhoneNumber
+	}
+
+	if body.Address != nil {
+		user.Address = *body.Address
+	}
+
+	if body.Region != nil {
+		user.Region = *body.Region
+	}
+
+	if body.AddressLatitude != nil || body.AddressLongitude != nil {
+		user.AddressLatitude = body.AddressLatitude
+		user.AddressLongitude = body.AddressLongitude
+	}
+
+	err = h.Usecase.UpdateProfile(reqCtx, user)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.ModelToProfileDto(user))
+}
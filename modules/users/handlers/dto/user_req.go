@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"loan-service/services/auth"
+	"loan-service/utils/i18n"
+)
+
+type FetchUsersRequest struct {
+	RoleType auth.RoleType `query:"role_type"`
+	IsActive *bool         `query:"is_active"`
+	Search   string        `query:"search"`
+}
+
+type InviteUserRequest struct {
+	Name     string        `json:"name" validate:"required,gt=0"`
+	Email    string        `json:"email" validate:"required,email"`
+	RoleType auth.RoleType `json:"role_type" validate:"required,gt=0"`
+}
+
+type FetchUserRequest struct {
+	UserID uint `param:"user_id" validate:"required,gt=0"`
+}
+
+type ChangeUserRoleRequest struct {
+	UserID   uint          `param:"user_id" validate:"required,gt=0"`
+	RoleType auth.RoleType `json:"role_type" validate:"required,gt=0"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required,gt=0"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type UpdateProfileRequest struct {
+	Name   string      `json:"name"`
+	Locale i18n.Locale `json:"locale" validate:"omitempty,oneof=en id"`
+	// E.164 format, an empty string removes the phone number
+	PhoneNumber *string `json:"phone_number" validate:"omitempty,e164"`
+	Address     *string `json:"address" validate:"omitempty,max=500"`
+	// Both coordinates are given together, they replace the previous ones
+	AddressLatitude  *float64 `json:"address_latitude" validate:"omitempty,gte=-90,lte=90"`
+	AddressLongitude *float64 `json:"address_longitude" validate:"omitempty,gte=-180,lte=180"`
+	Region           *string  `json:"region" validate:"omitempty,max=100"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"loan-service/models"
+	"loan-service/utils/i18n"
+	"time"
+)
+
+type FetchUserResp struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	IsActive bool   `json:"is_active"`
+	RoleType string `json:"role_type"`
+	Role     string `json:"role"`
+
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+func ModelsToDto(users []models.User) []FetchUserResp {
+	var result []FetchUserResp
+	for _, user := range users {
+		result = append(result, *ModelToDto(&user))
+	}
+
+	return result
+}
+
+func ModelToDto(u *models.User) *FetchUserResp {
+	if u == nil {
+		return nil
+	}
+
+	res := FetchUserResp{
+		ID:       u.ID,
+		Name:     u.Name,
+		Email:    u.Email,
+		IsActive: u.IsActive,
+		RoleType: string(u.Role.RoleType),
+		Role:     u.Role.Name,
+	}
+
+	if u.IsLocked(time.Now()) {
+		res.LockedUntil = u.LockedUntil
+	}
+
+	return &res
+}
+
+type FetchProfileResp struct {
+	FetchUserResp
+	Locale           i18n.Locale `json:"locale"`
+	PhoneNumber      string      `json:"phone_number"`
+	Address          string      `json:"address"`
+	AddressLatitude  *float64    `json:"address_latitude"`
+	AddressLongitude *float64    `json:"address_longitude"`
+	Region           string      `json:"region"`
+}
+
+func ModelToProfileDto(u *models.User) *FetchProfileResp {
+	if u == nil {
+		return nil
+	}
+
+	return &FetchProfileResp{
+		FetchUserResp:    *ModelToDto(u),
+		Locale:           i18n.Resolve(u.Locale),
+		PhoneNumber:      u.PhoneNumber,
+		Address:          u.Address,
+		AddressLatitude:  u.AddressLatitude,
+		AddressLongitude: u.AddressLongitude,
+		Region:           u.Region,
+	}
+}
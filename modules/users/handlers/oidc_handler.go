@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"loan-service/models"
+	"loan-service/services/auth"
+	"loan-service/services/oidc"
+	"loan-service/utils/resp"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OIDCHandler struct {
+	Usecase  models.UserUsecase
+	Provider oidc.Provider
+}
+
+func NewOIDCHandler(
+	e *echo.Echo,
+	uc models.UserUsecase,
+	provider oidc.Provider,
+) {
+	handler := &OIDCHandler{uc, provider}
+
+	e.GET("/oidc/login", handler.Login)
+	e.GET("/oidc/callback", handler.Callback)
+}
+
+// Login redirects the user to the identity provider
+func (h *OIDCHandler) Login(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	authRequest, err := h.Provider.NewAuthRequest()
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	authURL, err := h.Provider.AuthCodeURL(reqCtx, authRequest)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	stateCookie, err := oidc.StateCookie(authRequest)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	c.SetCookie(stateCookie)
+
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the login after the identity provider redirects the user back
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	// Always discard the login session, it can only be used once
+	c.SetCookie(oidc.StateRemovalCookie())
+
+	if c.QueryParam("error") != "" {
+		return resp.HTTPUnauthorized(c)
+	}
+
+	stateCookie, err := c.Cookie(oidc.StateCookieKey)
+	if err != nil {
+		return resp.HTTPRespFromError(c, oidc.ErrOIDCInvalidState)
+	}
+
+	authRequest, err := oidc.ParseStateCookie(stateCookie.Value, c.QueryParam("state"))
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	identity, err := h.Provider.Exchange(reqCtx, c.QueryParam("code"), authRequest)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	res, accessToken, refreshToken, err := h.Usecase.LoginWithOIDC(reqCtx, identity)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	c.Response().Header().Set("Authorization", accessToken)
+	c.SetCookie(auth.RefreshTokenCookie(refreshToken))
+
+	return resp.HTTPOk(c, res)
+}
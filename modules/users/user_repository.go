@@ -2,25 +2,27 @@ package users
 
 import (
 	"context"
+	"errors"
+	"loan-service/database"
 	"loan-service/models"
 	"loan-service/services/auth"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// likeEscaper escapes the wildcards of LIKE patterns, and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type repository struct {
 	db *gorm.DB
 }
 
 // CreateUser implements models.UserRepository.
 func (r *repository) CreateUser(ctx context.Context, user *models.User) error {
-	err := r.db.WithContext(ctx).Model(&models.User{}).Save(models.User{
-		Name:           user.Name,
-		Email:          user.Email,
-		HashedPassword: user.HashedPassword,
-		IsActive:       user.IsActive,
-		RoleID:         user.RoleID,
-	}).Error
+	err := database.Conn(ctx, r.db).Model(&models.User{}).Omit(clause.Associations).Create(user).Error
 
 	if err != nil {
 		return err
@@ -32,7 +34,7 @@ func (r *repository) CreateUser(ctx context.Context, user *models.User) error {
 // FetchUser implements models.UserRepository.
 func (r *repository) FetchUserByID(ctx context.Context, userID uint, opts *models.FetchUserByIDOpts) (*models.User, error) {
 	var result *models.User
-	query := r.db.WithContext(ctx).Model(&models.User{}).Preload("Role")
+	query := database.Conn(ctx, r.db).Model(&models.User{}).Preload("Role.Permissions")
 
 	if opts != nil && opts.IncludeBorrowedLoans {
 		query = query.Preload("BorrowedLoans")
@@ -47,11 +49,34 @@ func (r *repository) FetchUserByID(ctx context.Context, userID uint, opts *model
 }
 
 // FetchAllUsers implements models.UserRepository.
-func (r *repository) FetchUsers(ctx context.Context, allowedRoles []auth.RoleType, allowedLoanIDs []uint) ([]models.User, error) {
+func (r *repository) FetchUsers(ctx context.Context, opts *models.FetchUsersOpts) ([]models.User, error) {
 	var results []models.User
-	var userIDs []uint
+	query := database.Conn(ctx, r.db).Model(&models.User{}).
+		Preload("Role").
+		Joins("JOIN roles ON roles.id = users.role_id")
+
+	if opts != nil && len(opts.RoleTypes) > 0 {
+		query = query.Where("roles.role_type IN (?)", opts.RoleTypes)
+	}
+
+	if opts != nil && opts.IsActive != nil {
+		query = query.Where("users.is_active = ?", *opts.IsActive)
+	}
+
+	if opts != nil && opts.IsLocked {
+		query = query.Where("users.locked_until > ?", time.Now())
+	}
 
-	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role_type IN (?) AND id IN (?)", allowedRoles, userIDs).Find(&results).Error
+	if opts != nil && opts.Search != "" {
+		// Wildcards typed by the user are matched literally
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(opts.Search)) + "%"
+		query = query.Where(
+			`(LOWER(users.name) LIKE ? ESCAPE '\' OR LOWER(users.email) LIKE ? ESCAPE '\')`,
+			pattern, pattern,
+		)
+	}
+
+	err := query.Order("users.id").Find(&results).Error
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +85,13 @@ func (r *repository) FetchUsers(ctx context.Context, allowedRoles []auth.RoleTyp
 }
 
 // UpdateUser implements models.UserRepository.
-func (r *repository) UpdateUser(ctx context.Context, user *models.User) error {
-	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).Save(user).Error
+func (r *repository) UpdateUser(ctx context.Context, user *models.User, columns ...string) error {
+	// Without columns gorm would write every field, and overwrite concurrent changes to the others
+	if len(columns) == 0 {
+		return errors.New("no user columns to update")
+	}
+
+	err := database.Conn(ctx, r.db).Model(user).Select(columns).Omit(clause.Associations).Updates(user).Error
 	if err != nil {
 		return err
 	}
@@ -72,7 +102,10 @@ func (r *repository) UpdateUser(ctx context.Context, user *models.User) error {
 // FetchRoleByRoleType implements models.UserRepository.
 func (r *repository) FetchRoleByRoleType(ctx context.Context, roleType auth.RoleType) (*models.Role, error) {
 	var result *models.Role
-	err := r.db.WithContext(ctx).Model(&models.Role{}).Where("role_type = ?", string(roleType)).First(&result).Error
+	err := database.Conn(ctx, r.db).Model(&models.Role{}).
+		Preload("Permissions").
+		Where("role_type = ?", string(roleType)).
+		First(&result).Error
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +116,19 @@ func (r *repository) FetchRoleByRoleType(ctx context.Context, roleType auth.Role
 // FetchUserByEmail implements models.UserRepository.
 func (r *repository) FetchUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var result *models.User
-	err := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).
+	err := database.Conn(ctx, r.db).Model(&models.User{}).Where("email = ?", email).
+		Preload("Role.Permissions").First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchUserByInvitationTokenHash implements models.UserRepository.
+func (r *repository) FetchUserByInvitationTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	var result *models.User
+	err := database.Conn(ctx, r.db).Model(&models.User{}).Where("invitation_token_hash = ?", tokenHash).
 		Preload("Role").First(&result).Error
 	if err != nil {
 		return nil, err
@@ -92,6 +137,61 @@ func (r *repository) FetchUserByEmail(ctx context.Context, email string) (*model
 	return result, nil
 }
 
+// FetchUserByOIDCSubject implements models.UserRepository.
+func (r *repository) FetchUserByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	var result *models.User
+	err := database.Conn(ctx, r.db).Model(&models.User{}).Where("oidc_subject = ?", subject).
+		Preload("Role.Permissions").First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateLoginAttempt implements models.UserRepository.
+func (r *repository) CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	err := database.Conn(ctx, r.db).Create(attempt).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CountLoginAttempts implements models.UserRepository.
+func (r *repository) CountLoginAttempts(ctx context.Context, opts *models.CountLoginAttemptsOpts) (int64, error) {
+	var count int64
+	query := database.Conn(ctx, r.db).Model(&models.LoginAttempt{})
+
+	if opts != nil && opts.UserID > 0 {
+		query = query.Where("user_id = ?", opts.UserID)
+	}
+
+	if opts != nil && opts.IPAddress != "" {
+		query = query.Where("ip_address = ?", opts.IPAddress)
+	}
+
+	if opts != nil && opts.UserAgent != "" {
+		query = query.Where("user_agent = ?", opts.UserAgent)
+	}
+
+	if opts != nil && opts.Succeeded != nil {
+		query = query.Where("succeeded = ?", *opts.Succeeded)
+	}
+
+	if opts != nil && !opts.Since.IsZero() {
+		query = query.Where("created_at >= ?", opts.Since)
+	}
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func NewUserRepository(db *gorm.DB) models.UserRepository {
 	return &repository{db}
 }
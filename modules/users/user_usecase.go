@@ -2,10 +2,18 @@ package users
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"loan-service/config"
 	"loan-service/models"
 	"loan-service/services/auth"
+	"loan-service/services/oidc"
 	"loan-service/utils/errs"
+	"loan-service/utils/i18n"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -14,7 +22,9 @@ import (
 )
 
 type usecase struct {
-	repo models.UserRepository
+	repo          models.UserRepository
+	transactor    models.Transactor
+	outboxUsecase models.OutboxMessageUsecase
 }
 
 // FetchUserByID implements models.UserUsecase.
@@ -29,27 +39,29 @@ func (u *usecase) FetchUserByID(ctx context.Context, userID uint, opts *models.F
 
 // ViewUsers implements models.UserUsecase.
 func (u *usecase) ViewUsers(ctx context.Context, opts models.ViewUsersOpt) ([]models.User, error) {
-	var allowedRoles []auth.RoleType
-
-	role, err := u.repo.FetchRoleByRoleType(ctx, opts.RoleType)
-	if err != nil {
-		return nil, errs.Wrap(err)
+	if !auth.HasPermission(opts.Permissions, auth.PermissionUserView, auth.PermissionUserManage) {
+		return nil, ErrUnauthorized
 	}
 
-	switch role.RoleType {
-	case auth.RoleTypeSuperuser:
-		allowedRoles = []auth.RoleType{auth.RoleTypeInvestor, auth.RoleTypeFieldValidator, auth.RoleTypeBorrower}
-	case auth.RoleTypeStaff:
+	// Users without manage permission can only see non-privileged users
+	var allowedRoles []auth.RoleType
+	if !auth.HasPermission(opts.Permissions, auth.PermissionUserManage) {
 		allowedRoles = []auth.RoleType{auth.RoleTypeInvestor, auth.RoleTypeFieldValidator, auth.RoleTypeBorrower}
-	case auth.RoleTypeFieldValidator:
-		allowedRoles = []auth.RoleType{auth.RoleTypeStaff, auth.RoleTypeBorrower}
-	case auth.RoleTypeInvestor:
-		allowedRoles = []auth.RoleType{auth.RoleTypeBorrower}
-	default:
-		return nil, ErrUnauthorized
 	}
 
-	results, err := u.repo.FetchUsers(ctx, allowedRoles, nil)
+	if opts.RoleType != "" {
+		if allowedRoles != nil && !slices.Contains(allowedRoles, opts.RoleType) {
+			return []models.User{}, nil
+		}
+		allowedRoles = []auth.RoleType{opts.RoleType}
+	}
+
+	results, err := u.repo.FetchUsers(ctx, &models.FetchUsersOpts{
+		RoleTypes: allowedRoles,
+		IsActive:  opts.IsActive,
+		IsLocked:  opts.IsLocked,
+		Search:    opts.Search,
+	})
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
@@ -58,34 +70,247 @@ func (u *usecase) ViewUsers(ctx context.Context, opts models.ViewUsersOpt) ([]mo
 }
 
 // Login implements models.UserUsecase.
-func (u *usecase) Login(ctx context.Context, email string, password string) (models.LoginResponse, string, string, error) {
+func (u *usecase) Login(ctx context.Context, email string, password string, opts *models.LoginOpts) (models.LoginResponse, string, string, error) {
 	var response models.LoginResponse
-	var hashedPassword []byte
 
 	if email == "" || password == "" {
 		return response, "", "", errs.Wrap(ErrUnauthorized)
 	}
 
+	if opts == nil {
+		opts = &models.LoginOpts{}
+	}
+
+	now := time.Now()
+	attempt := &models.LoginAttempt{
+		CreatedAt: now,
+		Email:     email,
+		IPAddress: opts.IPAddress,
+		UserAgent: opts.UserAgent,
+	}
+
+	// Block IP addresses guessing passwords across many accounts
+	if opts.IPAddress != "" {
+		failed := false
+		failedAttempts, err := u.repo.CountLoginAttempts(ctx, &models.CountLoginAttemptsOpts{
+			IPAddress: opts.IPAddress,
+			Succeeded: &failed,
+			Since:     now.Add(-models.LoginAttemptWindow),
+		})
+		if err != nil {
+			return response, "", "", errs.Wrap(err)
+		}
+
+		if failedAttempts >= models.MaxFailedLoginAttemptsPerIP {
+			return response, "", "", errs.Wrap(ErrTooManyLoginAttempts)
+		}
+	}
+
 	authenticatedUser, err := u.repo.FetchUserByEmail(ctx, email)
 	if err != nil {
+		u.recordLoginAttempt(ctx, attempt)
+		return response, "", "", errs.Wrap(ErrUnauthorized)
+	}
+
+	attempt.UserID = &authenticatedUser.ID
+
+	// Reject before checking the password, so a locked account cannot be brute-forced
+	if now.Before(authenticatedUser.LoginAllowedAt()) {
+		u.recordLoginAttempt(ctx, attempt)
+		return response, "", "", errs.Wrap(ErrTooManyLoginAttempts)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(authenticatedUser.HashedPassword, []byte(password)); err != nil {
+		u.recordLoginAttempt(ctx, attempt)
+		u.registerFailedLogin(ctx, authenticatedUser, attempt)
+		return response, "", "", errs.Wrap(ErrUnauthorized)
+	}
+
+	if !authenticatedUser.IsActive {
+		u.recordLoginAttempt(ctx, attempt)
 		return response, "", "", errs.Wrap(ErrUnauthorized)
 	}
 
-	if authenticatedUser != nil {
-		hashedPassword = authenticatedUser.HashedPassword
+	if authenticatedUser.FailedLoginAttempts > 0 || authenticatedUser.LockedUntil != nil {
+		authenticatedUser.ResetFailedLogins()
+
+		err = u.repo.UpdateUser(ctx, authenticatedUser, models.FailedLoginColumns...)
+		if err != nil {
+			return response, "", "", errs.Wrap(err)
+		}
+	}
+
+	attempt.Succeeded = true
+	u.notifyIfNewDevice(ctx, authenticatedUser, attempt)
+	u.recordLoginAttempt(ctx, attempt)
+
+	return issueTokens(authenticatedUser)
+}
+
+// registerFailedLogin counts a failed login against the user, and notifies them once their account is locked
+func (u *usecase) registerFailedLogin(ctx context.Context, user *models.User, attempt *models.LoginAttempt) {
+	wasLocked := user.IsLocked(attempt.CreatedAt)
+	user.RegisterFailedLogin(attempt.CreatedAt)
+
+	// The lockout and its email are committed together
+	err := u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.UpdateUser(txCtx, user, models.FailedLoginColumns...)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		if wasLocked || user.LockedUntil == nil {
+			return nil
+		}
+
+		outboxMessage, err := user.NewEmailAccountLocked(attempt)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.outboxUsecase.EnqueueMessages(txCtx, outboxMessage)
+	})
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+	}
+}
+
+// notifyIfNewDevice emails the user when they log in from a device not used in any of their previous logins
+func (u *usecase) notifyIfNewDevice(ctx context.Context, user *models.User, attempt *models.LoginAttempt) {
+	succeeded := true
+	previousLogins, err := u.repo.CountLoginAttempts(ctx, &models.CountLoginAttemptsOpts{
+		UserID:    user.ID,
+		Succeeded: &succeeded,
+	})
+	if err != nil || previousLogins == 0 {
+		return
+	}
+
+	previousDeviceLogins, err := u.repo.CountLoginAttempts(ctx, &models.CountLoginAttemptsOpts{
+		UserID:    user.ID,
+		UserAgent: attempt.UserAgent,
+		Succeeded: &succeeded,
+	})
+	if err != nil || previousDeviceLogins > 0 {
+		return
+	}
+
+	outboxMessage, err := user.NewEmailNewDeviceLogin(attempt)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return
+	}
+
+	err = u.outboxUsecase.EnqueueMessages(ctx, outboxMessage)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
 	}
+}
 
-	if err := bcrypt.CompareHashAndPassword(hashedPassword, []byte(password)); err != nil {
+// recordLoginAttempt stores the attempt, failing to do so must not prevent the user from logging in
+func (u *usecase) recordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) {
+	err := u.repo.CreateLoginAttempt(ctx, attempt)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+	}
+}
+
+// LoginWithOIDC implements models.UserUsecase.
+func (u *usecase) LoginWithOIDC(ctx context.Context, identity *oidc.Identity) (models.LoginResponse, string, string, error) {
+	var response models.LoginResponse
+
+	if identity == nil || identity.Subject == "" {
 		return response, "", "", errs.Wrap(ErrUnauthorized)
 	}
 
+	if identity.RoleType == "" {
+		return response, "", "", errs.Wrap(ErrSSORoleNotMapped)
+	}
+
+	role, err := u.repo.FetchRoleByRoleType(ctx, identity.RoleType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response, "", "", errs.Wrap(ErrSSORoleNotMapped)
+	} else if err != nil {
+		return response, "", "", errs.Wrap(err)
+	}
+
+	user, err := u.findOIDCUser(ctx, identity)
+	if err != nil {
+		return response, "", "", errs.Wrap(err)
+	}
+
+	// Provision the user on first login, the identity provider is the source of truth for their role
+	if user == nil {
+		user = &models.User{
+			Name:        identity.Name,
+			Email:       identity.Email,
+			IsActive:    true,
+			RoleID:      role.ID,
+			OIDCSubject: &identity.Subject,
+		}
+
+		err = u.repo.CreateUser(ctx, user)
+		if err != nil {
+			return response, "", "", errs.Wrap(err)
+		}
+	} else {
+		if !user.IsActive || user.IsServiceAccount {
+			return response, "", "", errs.Wrap(ErrUnauthorized)
+		}
+
+		user.OIDCSubject = &identity.Subject
+		user.RoleID = role.ID
+
+		err = u.repo.UpdateUser(ctx, user, "oidc_subject", "role_id")
+		if err != nil {
+			return response, "", "", errs.Wrap(err)
+		}
+	}
+
+	user.Role = *role
+
+	return issueTokens(user)
+}
+
+// findOIDCUser finds the user linked to the identity, or an existing user with the same verified email
+func (u *usecase) findOIDCUser(ctx context.Context, identity *oidc.Identity) (*models.User, error) {
+	user, err := u.repo.FetchUserByOIDCSubject(ctx, identity.Subject)
+	if err == nil {
+		return user, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, nil
+	}
+
+	user, err = u.repo.FetchUserByEmail(ctx, identity.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// Never take over an account already linked to another identity
+	if user.OIDCSubject != nil {
+		return nil, errs.Wrap(ErrUnauthorized)
+	}
+
+	return user, nil
+}
+
+// issueTokens issues a new access and refresh token pair for an authenticated user
+func issueTokens(user *models.User) (models.LoginResponse, string, string, error) {
+	var response models.LoginResponse
+
 	now := time.Now()
 
 	accessToken, err := auth.NewAccessToken(auth.AuthClaims{
-		UserID:   authenticatedUser.ID,
-		Email:    authenticatedUser.Email,
-		Name:     authenticatedUser.Name,
-		RoleType: authenticatedUser.Role.RoleType,
+		UserID:   user.ID,
+		Email:    user.Email,
+		Name:     user.Name,
+		RoleType: user.Role.RoleType,
 		StandardClaims: jwt.StandardClaims{
 			IssuedAt:  now.Unix(),
 			ExpiresAt: now.Add(auth.DefaultAccessTokenTTL).Unix(),
@@ -104,9 +329,9 @@ func (u *usecase) Login(ctx context.Context, email string, password string) (mod
 	}
 
 	response = models.LoginResponse{
-		UserID: authenticatedUser.ID,
-		Email:  authenticatedUser.Email,
-		Name:   authenticatedUser.Name,
+		UserID: user.ID,
+		Email:  user.Email,
+		Name:   user.Name,
 	}
 
 	return response, accessToken, refreshToken, nil
@@ -119,9 +344,240 @@ func (u *usecase) RegisterUser(ctx context.Context, user *models.User) error {
 
 // UpdateProfile implements models.UserUsecase.
 func (u *usecase) UpdateProfile(ctx context.Context, user *models.User) error {
-	panic("unimplemented")
+	if user != nil && user.Locale == "" {
+		user.Locale = i18n.DefaultLocale
+	}
+
+	if user == nil || user.ID == 0 || user.Name == "" || !i18n.IsSupported(user.Locale) {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	// Half an address location cannot be checked against
+	if (user.AddressLatitude == nil) != (user.AddressLongitude == nil) {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	err := u.repo.UpdateUser(ctx, user,
+		"name", "locale", "phone_number", "address", "address_latitude", "address_longitude", "region")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// InviteUser implements models.UserUsecase.
+func (u *usecase) InviteUser(ctx context.Context, actor *models.User, name, email string, roleType auth.RoleType) (*models.User, error) {
+	if actor == nil || name == "" || email == "" || roleType == "" {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	if roleType.IsPrivileged() && actor.Role.RoleType != auth.RoleTypeSuperuser {
+		return nil, errs.Wrap(ErrPrivilegedRoleForbidden)
+	}
+
+	existingUser, err := u.repo.FetchUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(err)
+	}
+
+	if existingUser != nil {
+		return nil, errs.Wrap(ErrEmailAlreadyRegistered)
+	}
+
+	role, err := u.repo.FetchRoleByRoleType(ctx, roleType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrRoleNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if !actor.Role.MayAssign(role) {
+		return nil, errs.Wrap(ErrRoleExceedsPermissions)
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// User stays inactive until the invitation is accepted
+	expiresAt := time.Now().Add(models.InvitationTTL)
+	user := &models.User{
+		Name:                name,
+		Email:               email,
+		IsActive:            false,
+		RoleID:              role.ID,
+		InvitationTokenHash: tokenHash,
+		InvitationExpiresAt: &expiresAt,
+	}
+
+	// The invitation is queued with the user, so an invited user always has an invitation on its way
+	err = u.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		err := u.repo.CreateUser(txCtx, user)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		user.Role = *role
+
+		invitationURL := fmt.Sprintf("%s/invitations/accept?token=%s", config.Data.AppBaseURL, token)
+		outboxMessage, err := user.NewEmailInvitation(actor, invitationURL)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		return u.outboxUsecase.EnqueueMessages(txCtx, outboxMessage)
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// AcceptInvitation implements models.UserUsecase.
+func (u *usecase) AcceptInvitation(ctx context.Context, token, password string) error {
+	if token == "" || password == "" {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	user, err := u.repo.FetchUserByInvitationTokenHash(ctx, hashInvitationToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.Wrap(ErrInvalidInvitation)
+	} else if err != nil {
+		return errs.Wrap(err)
+	}
+
+	if user.InvitationExpiresAt == nil || time.Now().After(*user.InvitationExpiresAt) {
+		return errs.Wrap(ErrInvalidInvitation)
+	}
+
+	user.SetNewPassword(password)
+	user.IsActive = true
+	user.InvitationTokenHash = ""
+	user.InvitationExpiresAt = nil
+
+	err = u.repo.UpdateUser(ctx, user, "hashed_password", "is_active", "invitation_token_hash", "invitation_expires_at")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// SetUserActive implements models.UserUsecase.
+func (u *usecase) SetUserActive(ctx context.Context, actor *models.User, userID uint, isActive bool) (*models.User, error) {
+	user, err := u.fetchManageableUser(ctx, actor, userID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	user.IsActive = isActive
+
+	err = u.repo.UpdateUser(ctx, user, "is_active")
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// ChangeUserRole implements models.UserUsecase.
+func (u *usecase) ChangeUserRole(ctx context.Context, actor *models.User, userID uint, roleType auth.RoleType) (*models.User, error) {
+	if roleType == "" {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	user, err := u.fetchManageableUser(ctx, actor, userID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if roleType.IsPrivileged() && actor.Role.RoleType != auth.RoleTypeSuperuser {
+		return nil, errs.Wrap(ErrPrivilegedRoleForbidden)
+	}
+
+	role, err := u.repo.FetchRoleByRoleType(ctx, roleType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrRoleNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if !actor.Role.MayAssign(role) {
+		return nil, errs.Wrap(ErrRoleExceedsPermissions)
+	}
+
+	user.RoleID = role.ID
+	user.Role = *role
+
+	err = u.repo.UpdateUser(ctx, user, "role_id")
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// UnlockUser implements models.UserUsecase.
+func (u *usecase) UnlockUser(ctx context.Context, actor *models.User, userID uint) (*models.User, error) {
+	user, err := u.fetchManageableUser(ctx, actor, userID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	user.ResetFailedLogins()
+
+	err = u.repo.UpdateUser(ctx, user, models.FailedLoginColumns...)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// fetchManageableUser fetches a user that the actor is allowed to modify
+func (u *usecase) fetchManageableUser(ctx context.Context, actor *models.User, userID uint) (*models.User, error) {
+	if actor == nil || userID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	if actor.ID == userID {
+		return nil, errs.Wrap(ErrCannotModifySelf)
+	}
+
+	user, err := u.repo.FetchUserByID(ctx, userID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrUserNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if user.Role.RoleType.IsPrivileged() && actor.Role.RoleType != auth.RoleTypeSuperuser {
+		return nil, errs.Wrap(ErrPrivilegedRoleForbidden)
+	}
+
+	return user, nil
+}
+
+// newInvitationToken returns a random token to be sent to the user, and its hash to be stored
+func newInvitationToken() (string, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(tokenBytes)
+
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(tokenHash[:])
 }
 
-func NewUserUsecase(repo models.UserRepository) models.UserUsecase {
-	return &usecase{repo}
+func NewUserUsecase(repo models.UserRepository, transactor models.Transactor, outboxUC models.OutboxMessageUsecase) models.UserUsecase {
+	return &usecase{repo, transactor, outboxUC}
 }
@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"time"
+)
+
+// RunDispatcher delivers due webhooks on every tick until the context is cancelled
+func RunDispatcher(ctx context.Context, uc models.WebhookUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, err := uc.DispatchDueDeliveries(ctx)
+			if err != nil {
+				fmt.Println(errs.Wrap(err))
+			}
+
+			if delivered > 0 {
+				fmt.Printf("[webhooks] delivered %d events\n", delivered)
+			}
+		}
+	}
+}
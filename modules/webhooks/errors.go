@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrInvalidParams = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidParams",
+		Err:        errors.New("Invalid request, please check your input."),
+	}
+
+	ErrWebhookSubscriptionNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "WebhookSubscriptionNotFound",
+		Err:        errors.New("Cannot find the requested webhook subscription."),
+	}
+
+	ErrWebhookDeliveryNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "WebhookDeliveryNotFound",
+		Err:        errors.New("Cannot find the requested webhook delivery."),
+	}
+
+	ErrUnknownWebhookEventType = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "UnknownWebhookEventType",
+		Err:        errors.New("Webhook event type is not supported."),
+	}
+
+	ErrWebhookDeliveryPending = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "WebhookDeliveryPending",
+		Err:        errors.New("This delivery is still being attempted."),
+	}
+
+	ErrWebhookSubscriptionInactive = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "WebhookSubscriptionInactive",
+		Err:        errors.New("The webhook subscription is inactive or deleted."),
+	}
+
+	ErrInvalidWebhookURL = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidWebhookURL",
+		Err:        errors.New("Webhook URL must be an absolute https URL."),
+	}
+
+	errWebhookRejected       = errors.New("webhook receiver responded with a non-2xx status")
+	errWebhookAddressBlocked = errors.New("webhook receiver address is not publicly routable")
+)
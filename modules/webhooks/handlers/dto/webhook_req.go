@@ -0,0 +1,33 @@
+package dto
+
+import "loan-service/models"
+
+type CreateWebhookSubscriptionRequest struct {
+	Name       string                    `json:"name" validate:"required,gt=0"`
+	URL        string                    `json:"url" validate:"required,http_url"`
+	EventTypes []models.WebhookEventType `json:"event_types" validate:"required,gt=0"`
+}
+
+// UpdateWebhookSubscriptionRequest only changes the fields that are set
+type UpdateWebhookSubscriptionRequest struct {
+	SubscriptionID uint                      `param:"webhook_id" validate:"required,gt=0"`
+	Name           *string                   `json:"name" validate:"omitempty,gt=0"`
+	URL            *string                   `json:"url" validate:"omitempty,http_url"`
+	EventTypes     []models.WebhookEventType `json:"event_types" validate:"omitempty,gt=0"`
+	IsActive       *bool                     `json:"is_active"`
+}
+
+type WebhookSubscriptionIDRequest struct {
+	SubscriptionID uint `param:"webhook_id" validate:"required,gt=0"`
+}
+
+type FetchWebhookDeliveriesRequest struct {
+	SubscriptionID uint                         `param:"webhook_id" validate:"required,gt=0"`
+	Status         models.WebhookDeliveryStatus `query:"status" validate:"omitempty,oneof=pending delivered dead"`
+	Limit          int                          `query:"limit" validate:"omitempty,gt=0,lte=100"`
+	Offset         int                          `query:"offset" validate:"omitempty,gte=0"`
+}
+
+type WebhookDeliveryIDRequest struct {
+	DeliveryID uint `param:"delivery_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"encoding/json"
+	"loan-service/models"
+	"time"
+)
+
+type FetchWebhookSubscriptionResp struct {
+	ID         uint                      `json:"id"`
+	Name       string                    `json:"name"`
+	URL        string                    `json:"url"`
+	EventTypes []models.WebhookEventType `json:"event_types"`
+	IsActive   bool                      `json:"is_active"`
+	CreatedAt  time.Time                 `json:"created_at"`
+}
+
+// CreateWebhookSubscriptionResp is the only response that ever includes the signing secret
+type CreateWebhookSubscriptionResp struct {
+	FetchWebhookSubscriptionResp
+	Secret string `json:"secret"`
+}
+
+type FetchWebhookDeliveryLogResp struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type FetchWebhookDeliveryResp struct {
+	ID             uint                          `json:"id"`
+	SubscriptionID uint                          `json:"subscription_id"`
+	URL            string                        `json:"url"`
+	EventID        string                        `json:"event_id"`
+	EventType      models.WebhookEventType       `json:"event_type"`
+	ReplayOfID     *uint                         `json:"replay_of_id"`
+	Status         models.WebhookDeliveryStatus  `json:"status"`
+	Attempts       int                           `json:"attempts"`
+	NextAttemptAt  time.Time                     `json:"next_attempt_at"`
+	LastStatusCode int                           `json:"last_status_code,omitempty"`
+	LastError      string                        `json:"last_error,omitempty"`
+	CreatedAt      time.Time                     `json:"created_at"`
+	DeliveredAt    *time.Time                    `json:"delivered_at"`
+	Payload        json.RawMessage               `json:"payload,omitempty"`
+	Logs           []FetchWebhookDeliveryLogResp `json:"logs,omitempty"`
+}
+
+func SubscriptionsToDto(subscriptions []models.WebhookSubscription) []FetchWebhookSubscriptionResp {
+	result := []FetchWebhookSubscriptionResp{}
+	for _, subscription := range subscriptions {
+		result = append(result, *SubscriptionToDto(&subscription))
+	}
+
+	return result
+}
+
+func SubscriptionToDto(w *models.WebhookSubscription) *FetchWebhookSubscriptionResp {
+	if w == nil {
+		return nil
+	}
+
+	return &FetchWebhookSubscriptionResp{
+		ID:         w.ID,
+		Name:       w.Name,
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		IsActive:   w.IsActive,
+		CreatedAt:  w.CreatedAt,
+	}
+}
+
+// DeliveriesToDto leaves out payloads and logs, which are included when fetching a single delivery
+func DeliveriesToDto(deliveries []models.WebhookDelivery) []FetchWebhookDeliveryResp {
+	result := []FetchWebhookDeliveryResp{}
+	for _, delivery := range deliveries {
+		res := DeliveryToDto(&delivery)
+		res.Payload = nil
+		res.Logs = nil
+		result = append(result, *res)
+	}
+
+	return result
+}
+
+func DeliveryToDto(d *models.WebhookDelivery) *FetchWebhookDeliveryResp {
+	if d == nil {
+		return nil
+	}
+
+	res := &FetchWebhookDeliveryResp{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		ReplayOfID:     d.ReplayOfID,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+		Payload:        json.RawMessage(d.Payload),
+	}
+
+	if d.Subscription != nil {
+		res.URL = d.Subscription.URL
+	}
+
+	for _, log := range d.Logs {
+		res.Logs = append(res.Logs, FetchWebhookDeliveryLogResp{
+			Attempt:    log.Attempt,
+			StatusCode: log.StatusCode,
+			Error:      log.Error,
+			DurationMs: log.Duration.Milliseconds(),
+			CreatedAt:  log.CreatedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	"loan-service/modules/webhooks/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/utils/resp"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultDeliveryPageSize = 50
+
+type WebhookHandler struct {
+	Usecase     models.WebhookUsecase
+	UserUsecase models.UserUsecase
+}
+
+func NewWebhookHandler(
+	g *echo.Group,
+	uc models.WebhookUsecase,
+	userUC models.UserUsecase,
+) {
+	handler := &WebhookHandler{uc, userUC}
+
+	requireWebhookManage := authMiddleware.RequirePermission(auth.PermissionWebhookManage)
+
+	g.POST("/webhooks", handler.CreateWebhookSubscription, requireWebhookManage)
+	g.GET("/webhooks", handler.FetchWebhookSubscriptions, requireWebhookManage)
+	g.GET("/webhooks/:webhook_id", handler.FetchWebhookSubscription, requireWebhookManage)
+	g.PATCH("/webhooks/:webhook_id", handler.UpdateWebhookSubscription, requireWebhookManage)
+	g.DELETE("/webhooks/:webhook_id", handler.DeleteWebhookSubscription, requireWebhookManage)
+	g.GET("/webhooks/:webhook_id/deliveries", handler.FetchWebhookDeliveries, requireWebhookManage)
+	g.GET("/webhook-deliveries/:delivery_id", handler.FetchWebhookDelivery, requireWebhookManage)
+	g.POST("/webhook-deliveries/:delivery_id/replay", handler.ReplayWebhookDelivery, requireWebhookManage)
+}
+
+func (h *WebhookHandler) CreateWebhookSubscription(c echo.Context) error {
+	reqCtx := c.Request().Context()
+	claims := c.Get(auth.AuthClaimsCtxKey).(auth.AuthClaims)
+
+	body := dto.CreateWebhookSubscriptionRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	actor, err := h.UserUsecase.FetchUserByID(reqCtx, claims.UserID, nil)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	subscription, secret, err := h.Usecase.CreateWebhookSubscription(reqCtx, actor, &models.WebhookSubscription{
+		Name:       body.Name,
+		URL:        body.URL,
+		EventTypes: body.EventTypes,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.CreateWebhookSubscriptionResp{
+		FetchWebhookSubscriptionResp: *dto.SubscriptionToDto(subscription),
+		Secret:                       secret,
+	})
+}
+
+func (h *WebhookHandler) FetchWebhookSubscriptions(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	subscriptions, err := h.Usecase.FetchWebhookSubscriptions(reqCtx)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.SubscriptionsToDto(subscriptions))
+}
+
+func (h *WebhookHandler) FetchWebhookSubscription(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.WebhookSubscriptionIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	subscription, err := h.Usecase.FetchWebhookSubscriptionByID(reqCtx, body.SubscriptionID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.SubscriptionToDto(subscription))
+}
+
+func (h *WebhookHandler) UpdateWebhookSubscription(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.UpdateWebhookSubscriptionRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	subscription, err := h.Usecase.FetchWebhookSubscriptionByID(reqCtx, body.SubscriptionID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	if body.Name != nil {
+		subscription.Name = *body.Name
+	}
+
+	if body.URL != nil {
+		subscription.URL = *body.URL
+	}
+
+	if len(body.EventTypes) > 0 {
+		subscription.EventTypes = body.EventTypes
+	}
+
+	if body.IsActive != nil {
+		subscription.IsActive = *body.IsActive
+	}
+
+	err = h.Usecase.UpdateWebhookSubscription(reqCtx, subscription)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.SubscriptionToDto(subscription))
+}
+
+func (h *WebhookHandler) DeleteWebhookSubscription(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.WebhookSubscriptionIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	err := h.Usecase.DeleteWebhookSubscription(reqCtx, body.SubscriptionID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPNoContent(c)
+}
+
+func (h *WebhookHandler) FetchWebhookDeliveries(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.FetchWebhookDeliveriesRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if body.Limit == 0 {
+		body.Limit = defaultDeliveryPageSize
+	}
+
+	deliveries, err := h.Usecase.FetchWebhookDeliveries(reqCtx, &models.FetchWebhookDeliveriesOpts{
+		SubscriptionID: body.SubscriptionID,
+		Status:         body.Status,
+		Limit:          body.Limit,
+		Offset:         body.Offset,
+	})
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.DeliveriesToDto(deliveries))
+}
+
+// FetchWebhookDelivery includes the payload and the log of every attempt
+func (h *WebhookHandler) FetchWebhookDelivery(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.WebhookDeliveryIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	delivery, err := h.Usecase.FetchWebhookDeliveryByID(reqCtx, body.DeliveryID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPOk(c, dto.DeliveryToDto(delivery))
+}
+
+// ReplayWebhookDelivery sends a delivered or dead-lettered event again as a new delivery
+func (h *WebhookHandler) ReplayWebhookDelivery(c echo.Context) error {
+	reqCtx := c.Request().Context()
+
+	body := dto.WebhookDeliveryIDRequest{}
+	if err := c.Bind(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	if err := c.Validate(&body); err != nil {
+		return resp.HTTPBadRequest(c, "InvalidBody", "invalid request parameters")
+	}
+
+	replay, err := h.Usecase.ReplayWebhookDelivery(reqCtx, body.DeliveryID)
+	if err != nil {
+		return resp.HTTPRespFromError(c, err)
+	}
+
+	return resp.HTTPCreated(c, dto.DeliveryToDto(replay))
+}
@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers sent with every webhook request
+const (
+	HeaderEventID   = "X-Webhook-ID"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signatureScheme = "sha256="
+)
+
+// Sign returns the signature of a payload sent at the timestamp (in unix seconds).
+// It is the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret, so a captured request
+// cannot be replayed with another timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp)
+	_, _ = mac.Write(body)
+
+	return signatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature and timestamp headers of a received webhook, rejecting requests older than the tolerance.
+// Partners written in Go can use it as is, others can follow Sign.
+func VerifySignature(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) bool {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signatureHeader))
+}
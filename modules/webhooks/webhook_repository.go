@@ -0,0 +1,204 @@
+package webhooks
+
+import (
+	"context"
+	"loan-service/database"
+	"loan-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// CreateWebhookSubscription implements models.WebhookRepository.
+func (r *repository) CreateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	err := database.Conn(ctx, r.db).Model(&models.WebhookSubscription{}).Create(subscription).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchWebhookSubscriptions implements models.WebhookRepository.
+func (r *repository) FetchWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var results []models.WebhookSubscription
+	err := database.Conn(ctx, r.db).Model(&models.WebhookSubscription{}).Order("id").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchWebhookSubscriptionByID implements models.WebhookRepository.
+func (r *repository) FetchWebhookSubscriptionByID(ctx context.Context, subscriptionID uint) (*models.WebhookSubscription, error) {
+	var result *models.WebhookSubscription
+	err := database.Conn(ctx, r.db).Model(&models.WebhookSubscription{}).Where("id = ?", subscriptionID).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateWebhookSubscription implements models.WebhookRepository.
+func (r *repository) UpdateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	err := database.Conn(ctx, r.db).Model(subscription).Updates(map[string]any{
+		"name":        subscription.Name,
+		"url":         subscription.URL,
+		"event_types": subscription.EventTypes,
+		"is_active":   subscription.IsActive,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription implements models.WebhookRepository.
+func (r *repository) DeleteWebhookSubscription(ctx context.Context, subscriptionID uint) error {
+	result := database.Conn(ctx, r.db).Delete(&models.WebhookSubscription{}, subscriptionID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CreateWebhookDeliveries implements models.WebhookRepository.
+func (r *repository) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	err := database.Conn(ctx, r.db).Model(&models.WebhookDelivery{}).Omit("Subscription", "Logs").Create(deliveries).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchWebhookDeliveries implements models.WebhookRepository.
+// Subscriptions are preloaded including deleted ones, so their deliveries can still be listed.
+func (r *repository) FetchWebhookDeliveries(ctx context.Context, opts *models.FetchWebhookDeliveriesOpts) ([]models.WebhookDelivery, error) {
+	var results []models.WebhookDelivery
+	query := database.Conn(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Preload("Subscription", func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+
+	if opts != nil && opts.SubscriptionID > 0 {
+		query = query.Where("subscription_id = ?", opts.SubscriptionID)
+	}
+
+	if opts != nil && opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+
+	query = query.Order("id DESC")
+
+	if opts != nil && opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	if opts != nil && opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	err := query.Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ClaimDueWebhookDeliveries implements models.WebhookRepository.
+func (r *repository) ClaimDueWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	var results []models.WebhookDelivery
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.WebhookDelivery{}).
+			Preload("Subscription", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&results).Error
+		if err != nil || len(results) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(results))
+		for i := range results {
+			ids = append(ids, results[i].ID)
+			results[i].NextAttemptAt = leaseUntil
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN (?)", ids).Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FetchWebhookDeliveryByID implements models.WebhookRepository.
+func (r *repository) FetchWebhookDeliveryByID(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	var result *models.WebhookDelivery
+	err := database.Conn(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Preload("Subscription", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Logs", func(db *gorm.DB) *gorm.DB { return db.Order("attempt") }).
+		Where("id = ?", deliveryID).
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateWebhookDelivery implements models.WebhookRepository.
+func (r *repository) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	err := database.Conn(ctx, r.db).Model(delivery).Updates(map[string]any{
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"delivered_at":     delivery.DeliveredAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateWebhookDeliveryLog implements models.WebhookRepository.
+func (r *repository) CreateWebhookDeliveryLog(ctx context.Context, log *models.WebhookDeliveryLog) error {
+	err := database.Conn(ctx, r.db).Create(log).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewWebhookRepository(db *gorm.DB) models.WebhookRepository {
+	return &repository{db}
+}
@@ -0,0 +1,400 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"loan-service/config"
+	"loan-service/models"
+	"loan-service/utils/errs"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	secretPrefix  = "whsec"
+	eventIDPrefix = "evt"
+
+	// Deliveries attempted per dispatch, the rest wait for the next tick
+	dispatchBatchSize = 50
+	deliveryTimeout   = time.Second * 10
+	// Claimed deliveries are left to their dispatcher for as long as the batch may take to send, and are picked up by
+	// another one afterwards if it stopped before updating them
+	claimLease = dispatchBatchSize * deliveryTimeout
+)
+
+type usecase struct {
+	repo       models.WebhookRepository
+	httpClient *http.Client
+}
+
+// CreateWebhookSubscription implements models.WebhookUsecase.
+func (u *usecase) CreateWebhookSubscription(
+	ctx context.Context,
+	actor *models.User,
+	subscription *models.WebhookSubscription,
+) (*models.WebhookSubscription, string, error) {
+	if actor == nil || subscription == nil {
+		return nil, "", errs.Wrap(ErrInvalidParams)
+	}
+
+	err := validateSubscription(subscription)
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	secret, err := randomToken(secretPrefix, 32)
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	subscription.Secret = secret
+	subscription.IsActive = true
+	subscription.CreatedByID = actor.ID
+
+	err = u.repo.CreateWebhookSubscription(ctx, subscription)
+	if err != nil {
+		return nil, "", errs.Wrap(err)
+	}
+
+	return subscription, secret, nil
+}
+
+// FetchWebhookSubscriptions implements models.WebhookUsecase.
+func (u *usecase) FetchWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subscriptions, err := u.repo.FetchWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return subscriptions, nil
+}
+
+// FetchWebhookSubscriptionByID implements models.WebhookUsecase.
+func (u *usecase) FetchWebhookSubscriptionByID(ctx context.Context, subscriptionID uint) (*models.WebhookSubscription, error) {
+	if subscriptionID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	subscription, err := u.repo.FetchWebhookSubscriptionByID(ctx, subscriptionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrWebhookSubscriptionNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return subscription, nil
+}
+
+// UpdateWebhookSubscription implements models.WebhookUsecase.
+func (u *usecase) UpdateWebhookSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if subscription == nil || subscription.ID == 0 {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	err := validateSubscription(subscription)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = u.repo.UpdateWebhookSubscription(ctx, subscription)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription implements models.WebhookUsecase.
+// Pending deliveries of the subscription are dead-lettered when they are next due.
+func (u *usecase) DeleteWebhookSubscription(ctx context.Context, subscriptionID uint) error {
+	if subscriptionID == 0 {
+		return errs.Wrap(ErrInvalidParams)
+	}
+
+	err := u.repo.DeleteWebhookSubscription(ctx, subscriptionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.Wrap(ErrWebhookSubscriptionNotFound)
+	} else if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// Publish implements models.WebhookUsecase.
+func (u *usecase) Publish(ctx context.Context, eventType models.WebhookEventType, data interface{}) error {
+	if !slices.Contains(models.WebhookEventTypes, eventType) {
+		return errs.Wrap(ErrUnknownWebhookEventType)
+	}
+
+	subscriptions, err := u.repo.FetchWebhookSubscriptions(ctx)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	eventID, err := randomToken(eventIDPrefix, 16)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for i := range subscriptions {
+		if !subscriptions[i].Subscribes(eventType) {
+			continue
+		}
+
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			SubscriptionID: subscriptions[i].ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryStatusPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	err = u.repo.CreateWebhookDeliveries(ctx, deliveries)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+// DispatchDueDeliveries implements models.WebhookUsecase.
+func (u *usecase) DispatchDueDeliveries(ctx context.Context) (int, error) {
+	// Every instance runs a dispatcher, claiming the deliveries keeps them from being sent once per instance
+	now := time.Now()
+	dueDeliveries, err := u.repo.ClaimDueWebhookDeliveries(ctx, now, now.Add(claimLease), dispatchBatchSize)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	delivered := 0
+	for i := range dueDeliveries {
+		delivery := &dueDeliveries[i]
+
+		var log *models.WebhookDeliveryLog
+		if !delivery.Subscription.Deliverable() {
+			// Nothing to retry until the subscription is reactivated, the delivery can be replayed afterwards
+			log = &models.WebhookDeliveryLog{Error: ErrWebhookSubscriptionInactive.Error()}
+			delivery.DeadLetter(ErrWebhookSubscriptionInactive)
+		} else if log = u.deliver(ctx, delivery); log.Error != "" {
+			delivery.MarkFailed(time.Now(), log.StatusCode, errors.New(log.Error))
+			fmt.Printf("[webhooks] delivery %d of %s failed (attempt %d, %s): %s\n",
+				delivery.ID, delivery.EventID, delivery.Attempts, delivery.Status, log.Error)
+		} else {
+			delivery.MarkDelivered(time.Now(), log.StatusCode)
+			delivered++
+		}
+
+		log.DeliveryID = delivery.ID
+		log.Attempt = delivery.Attempts
+		err := u.repo.CreateWebhookDeliveryLog(ctx, log)
+		if err != nil {
+			return delivered, errs.Wrap(err)
+		}
+
+		err = u.repo.UpdateWebhookDelivery(ctx, delivery)
+		if err != nil {
+			return delivered, errs.Wrap(err)
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver posts the signed payload to the subscription, and returns the log of the attempt
+func (u *usecase) deliver(ctx context.Context, delivery *models.WebhookDelivery) *models.WebhookDeliveryLog {
+	log := &models.WebhookDeliveryLog{}
+	subscription := delivery.Subscription
+
+	sendCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		log.Error = err.Error()
+		return log
+	}
+
+	// Signed at send time, so retries and replays carry a fresh timestamp
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LoanService-Webhooks/1.0")
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEventType, string(delivery.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	start := time.Now()
+	res, err := u.httpClient.Do(req)
+	log.Duration = time.Since(start)
+	if err != nil {
+		log.Error = err.Error()
+		return log
+	}
+	defer res.Body.Close()
+
+	// Only the status is kept, receivers are not trusted with what ends up in the delivery log
+	log.StatusCode = res.StatusCode
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		log.Error = fmt.Sprintf("%v: %d", errWebhookRejected, res.StatusCode)
+	}
+
+	return log
+}
+
+// FetchWebhookDeliveries implements models.WebhookUsecase.
+func (u *usecase) FetchWebhookDeliveries(ctx context.Context, opts *models.FetchWebhookDeliveriesOpts) ([]models.WebhookDelivery, error) {
+	deliveries, err := u.repo.FetchWebhookDeliveries(ctx, opts)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return deliveries, nil
+}
+
+// FetchWebhookDeliveryByID implements models.WebhookUsecase.
+func (u *usecase) FetchWebhookDeliveryByID(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	if deliveryID == 0 {
+		return nil, errs.Wrap(ErrInvalidParams)
+	}
+
+	delivery, err := u.repo.FetchWebhookDeliveryByID(ctx, deliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.Wrap(ErrWebhookDeliveryNotFound)
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return delivery, nil
+}
+
+// ReplayWebhookDelivery implements models.WebhookUsecase.
+// The event is queued again as a new delivery with the same event ID, keeping the original and its logs intact.
+func (u *usecase) ReplayWebhookDelivery(ctx context.Context, deliveryID uint) (*models.WebhookDelivery, error) {
+	delivery, err := u.FetchWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if delivery.Status == models.WebhookDeliveryStatusPending {
+		return nil, errs.Wrap(ErrWebhookDeliveryPending)
+	}
+
+	if !delivery.Subscription.Deliverable() {
+		return nil, errs.Wrap(ErrWebhookSubscriptionInactive)
+	}
+
+	replay := delivery.NewReplay(time.Now())
+	err = u.repo.CreateWebhookDeliveries(ctx, []*models.WebhookDelivery{replay})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	replay.Subscription = delivery.Subscription
+
+	return replay, nil
+}
+
+func validateSubscription(subscription *models.WebhookSubscription) error {
+	if subscription.Name == "" || subscription.URL == "" || len(subscription.EventTypes) == 0 {
+		return ErrInvalidParams
+	}
+
+	parsedURL, err := url.Parse(subscription.URL)
+	if err != nil || !parsedURL.IsAbs() || parsedURL.Hostname() == "" || parsedURL.User != nil {
+		return ErrInvalidWebhookURL
+	}
+
+	if parsedURL.Scheme != "https" && (parsedURL.Scheme != "http" || !config.Data.WebhookAllowInsecureURLs) {
+		return ErrInvalidWebhookURL
+	}
+
+	for _, eventType := range subscription.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, eventType) {
+			return ErrUnknownWebhookEventType
+		}
+	}
+
+	return nil
+}
+
+func randomToken(prefix string, size int) (string, error) {
+	randomBytes := make([]byte, size)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s_%s", prefix, hex.EncodeToString(randomBytes)), nil
+}
+
+// newDeliveryClient returns a client that only connects to public addresses, checked after DNS resolution so a
+// receiver cannot be pointed at internal services by changing its records, and does not follow redirects
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if config.Data.WebhookAllowInsecureURLs {
+				return nil
+			}
+
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			addr := addrPort.Addr().Unmap()
+			if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() ||
+				addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+				return fmt.Errorf("%w: %s", errWebhookAddressBlocked, addr)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the receiver, bypassing the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func NewWebhookUsecase(repo models.WebhookRepository) models.WebhookUsecase {
+	return &usecase{repo, newDeliveryClient()}
+}
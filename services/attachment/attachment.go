@@ -0,0 +1,145 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"loan-service/utils/errs"
+	"slices"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+const (
+	DefaultMaxSize = 10 << 20 // 10 MiB
+
+	VariantOriginal  = "original"
+	VariantThumbnail = "thumbnail"
+)
+
+// Pipeline validates and processes uploaded files before they are stored
+type Pipeline interface {
+	// Process reads the file, detects its type and runs it through the matching processor.
+	// When content types are given, any other type is rejected.
+	Process(ctx context.Context, file io.Reader, allowedContentTypes ...string) (*Result, error)
+}
+
+// Processor turns the raw bytes of the content types it accepts into the variants to store
+type Processor interface {
+	Accepts(contentType string) bool
+	Process(ctx context.Context, data []byte, contentType string) (*Result, error)
+}
+
+// Result holds every variant to store, and the metadata extracted before any of it was stripped
+type Result struct {
+	Variants []Variant
+	Metadata Metadata
+}
+
+// Variant returns the variant with the name, or nil if the processor did not produce it
+func (r *Result) Variant(name string) *Variant {
+	for i := range r.Variants {
+		if r.Variants[i].Name == name {
+			return &r.Variants[i]
+		}
+	}
+
+	return nil
+}
+
+type Variant struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	// Including the dot, e.g. ".jpg"
+	Extension string
+}
+
+// Metadata of the uploaded file, capture time and location are only known for photos that carried them
+type Metadata struct {
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	Width       int        `json:"width,omitempty"`
+	Height      int        `json:"height,omitempty"`
+	CapturedAt  *time.Time `json:"captured_at,omitempty"`
+	Latitude    *float64   `json:"latitude,omitempty"`
+	Longitude   *float64   `json:"longitude,omitempty"`
+}
+
+// Value implements driver.Valuer, metadata is stored as JSON
+func (m Metadata) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner.
+func (m *Metadata) Scan(value any) error {
+	switch value := value.(type) {
+	case []byte:
+		return json.Unmarshal(value, m)
+	case string:
+		return json.Unmarshal([]byte(value), m)
+	default:
+		return fmt.Errorf("cannot scan %T into attachment metadata", value)
+	}
+}
+
+// NewPipeline rejects files above maxSize bytes, or DefaultMaxSize if zero, and hands the rest to the first
+// processor accepting their content type
+func NewPipeline(maxSize int64, processors ...Processor) Pipeline {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	return &pipeline{maxSize, processors}
+}
+
+type pipeline struct {
+	maxSize    int64
+	processors []Processor
+}
+
+// Process implements Pipeline.
+func (p *pipeline) Process(ctx context.Context, file io.Reader, allowedContentTypes ...string) (*Result, error) {
+	var buf bytes.Buffer
+	// One byte over the limit is enough to tell the file is too large
+	size, err := io.Copy(&buf, io.LimitReader(file, p.maxSize+1))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if size > p.maxSize {
+		return nil, errs.Wrap(ErrFileTooLarge)
+	}
+
+	if size == 0 {
+		return nil, errs.Wrap(ErrUnsupportedFileType)
+	}
+
+	data := buf.Bytes()
+	contentType := mimetype.Detect(data).String()
+	if len(allowedContentTypes) > 0 && !slices.Contains(allowedContentTypes, contentType) {
+		return nil, errs.Wrap(ErrUnsupportedFileType)
+	}
+
+	for _, processor := range p.processors {
+		if !processor.Accepts(contentType) {
+			continue
+		}
+
+		result, err := processor.Process(ctx, data, contentType)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		result.Metadata.ContentType = contentType
+		result.Metadata.Size = size
+
+		return result, nil
+	}
+
+	return nil, errs.Wrap(ErrUnsupportedFileType)
+}
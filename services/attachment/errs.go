@@ -0,0 +1,33 @@
+package attachment
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrFileTooLarge = errs.GeneralError{
+		StatusCode: http.StatusRequestEntityTooLarge,
+		ErrorCode:  "FileTooLarge",
+		Err:        errors.New("file is too large"),
+	}
+
+	ErrImageTooLarge = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "ImageTooLarge",
+		Err:        errors.New("image dimensions are too large"),
+	}
+
+	ErrUnsupportedFileType = errs.GeneralError{
+		StatusCode: http.StatusUnsupportedMediaType,
+		ErrorCode:  "UnsupportedFileType",
+		Err:        errors.New("file type is not supported"),
+	}
+
+	ErrInvalidImage = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidImage",
+		Err:        errors.New("image is corrupted or cannot be read"),
+	}
+)
@@ -0,0 +1,334 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+const (
+	exifTagOrientation        = 0x0112
+	exifTagDateTime           = 0x0132
+	exifTagExifIFD            = 0x8769
+	exifTagGPSIFD             = 0x8825
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+
+	gpsTagLatitudeRef  = 0x01
+	gpsTagLatitude     = 0x02
+	gpsTagLongitudeRef = 0x03
+	gpsTagLongitude    = 0x04
+	gpsTagTimeStamp    = 0x07
+	gpsTagDateStamp    = 0x1d
+
+	exifDateTimeFormat = "2006:01:02 15:04:05"
+)
+
+// exifData is the subset of EXIF used by the pipeline
+type exifData struct {
+	// 1 to 8, how the image must be rotated and flipped to be displayed upright
+	Orientation int
+	CapturedAt  *time.Time
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// readExif extracts EXIF from JPEG APP1 segments or PNG eXIf chunks, returning nil when there is none.
+// Malformed EXIF is ignored, as the image itself may still be fine.
+func readExif(data []byte, contentType string) *exifData {
+	var tiff []byte
+	switch contentType {
+	case "image/jpeg":
+		tiff = jpegExif(data)
+	case "image/png":
+		tiff = pngExif(data)
+	}
+
+	if tiff == nil {
+		return nil
+	}
+
+	return parseTIFF(tiff)
+}
+
+func jpegExif(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+
+		marker := data[i+1]
+		// Fill bytes before a marker
+		if marker == 0xFF {
+			i++
+			continue
+		}
+
+		// Start of scan or end of image, metadata segments all come before
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return nil
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+
+		i += 2 + length
+	}
+
+	return nil
+}
+
+func pngExif(data []byte) []byte {
+	if len(data) < 8 || !bytes.Equal(data[:8], []byte("\x89PNG\r\n\x1a\n")) {
+		return nil
+	}
+
+	for i := 8; i+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		if length < 0 || i+12+length > len(data) {
+			return nil
+		}
+
+		switch chunkType {
+		case "eXIf":
+			return data[i+8 : i+8+length]
+		case "IEND":
+			return nil
+		}
+
+		i += 12 + length
+	}
+
+	return nil
+}
+
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	fieldType uint16
+	count     uint32
+	value     []byte
+}
+
+func parseTIFF(data []byte) *exifData {
+	if len(data) < 8 {
+		return nil
+	}
+
+	r := &tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	if r.order.Uint16(data[2:4]) != 42 {
+		return nil
+	}
+
+	ifd0 := r.readIFD(r.order.Uint32(data[4:8]))
+	if ifd0 == nil {
+		return nil
+	}
+
+	result := &exifData{Orientation: 1}
+	if orientation, ok := r.uint(ifd0[exifTagOrientation]); ok && orientation >= 1 && orientation <= 8 {
+		result.Orientation = int(orientation)
+	}
+
+	dateTime, offset := r.ascii(ifd0[exifTagDateTime]), ""
+	if exifOffset, ok := r.uint(ifd0[exifTagExifIFD]); ok {
+		if exifIFD := r.readIFD(exifOffset); exifIFD != nil {
+			if original := r.ascii(exifIFD[exifTagDateTimeOriginal]); original != "" {
+				dateTime = original
+			}
+
+			offset = r.ascii(exifIFD[exifTagOffsetTimeOriginal])
+		}
+	}
+
+	var gpsTime *time.Time
+	if gpsOffset, ok := r.uint(ifd0[exifTagGPSIFD]); ok {
+		if gpsIFD := r.readIFD(gpsOffset); gpsIFD != nil {
+			result.Latitude = r.coordinate(gpsIFD[gpsTagLatitude], r.ascii(gpsIFD[gpsTagLatitudeRef]), "S")
+			result.Longitude = r.coordinate(gpsIFD[gpsTagLongitude], r.ascii(gpsIFD[gpsTagLongitudeRef]), "W")
+			gpsTime = r.gpsTime(gpsIFD[gpsTagDateStamp], gpsIFD[gpsTagTimeStamp])
+		}
+	}
+
+	if result.Latitude == nil || result.Longitude == nil {
+		result.Latitude, result.Longitude = nil, nil
+	}
+
+	result.CapturedAt = captureTime(dateTime, offset, gpsTime)
+
+	return result
+}
+
+// captureTime prefers the camera time with its offset, then the GPS time which is always UTC.
+// Without either the camera's zone is unknown, so the time is taken as UTC.
+func captureTime(dateTime, offset string, gpsTime *time.Time) *time.Time {
+	if dateTime != "" && offset != "" {
+		if t, err := time.Parse(exifDateTimeFormat+"-07:00", dateTime+offset); err == nil {
+			return &t
+		}
+	}
+
+	if gpsTime != nil {
+		return gpsTime
+	}
+
+	if dateTime != "" {
+		if t, err := time.Parse(exifDateTimeFormat, dateTime); err == nil {
+			return &t
+		}
+	}
+
+	return nil
+}
+
+func (r *tiffReader) readIFD(offset uint32) map[uint16]ifdEntry {
+	start := int(offset)
+	if start < 8 || start+2 > len(r.data) {
+		return nil
+	}
+
+	count := int(r.order.Uint16(r.data[start : start+2]))
+	if start+2+count*12 > len(r.data) {
+		return nil
+	}
+
+	entries := make(map[uint16]ifdEntry, count)
+	for i := 0; i < count; i++ {
+		raw := r.data[start+2+i*12 : start+2+(i+1)*12]
+		entry := ifdEntry{
+			fieldType: r.order.Uint16(raw[2:4]),
+			count:     r.order.Uint32(raw[4:8]),
+		}
+
+		size := int(typeSize(entry.fieldType)) * int(entry.count)
+		if size <= 0 {
+			continue
+		}
+
+		// Values up to 4 bytes are stored in the entry itself, larger ones at an offset
+		if size <= 4 {
+			entry.value = raw[8 : 8+size]
+		} else {
+			valueOffset := int(r.order.Uint32(raw[8:12]))
+			if valueOffset+size > len(r.data) || valueOffset < 0 {
+				continue
+			}
+
+			entry.value = r.data[valueOffset : valueOffset+size]
+		}
+
+		entries[r.order.Uint16(raw[0:2])] = entry
+	}
+
+	return entries
+}
+
+func typeSize(fieldType uint16) uint32 {
+	switch fieldType {
+	case 1, 2, 7: // byte, ascii, undefined
+		return 1
+	case 3: // short
+		return 2
+	case 4, 9: // long, signed long
+		return 4
+	case 5, 10: // rational, signed rational
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (r *tiffReader) uint(entry ifdEntry) (uint32, bool) {
+	switch {
+	case entry.fieldType == 3 && len(entry.value) >= 2:
+		return uint32(r.order.Uint16(entry.value)), true
+	case entry.fieldType == 4 && len(entry.value) >= 4:
+		return r.order.Uint32(entry.value), true
+	default:
+		return 0, false
+	}
+}
+
+func (r *tiffReader) ascii(entry ifdEntry) string {
+	if entry.fieldType != 2 {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+}
+
+func (r *tiffReader) rationals(entry ifdEntry) []float64 {
+	if entry.fieldType != 5 {
+		return nil
+	}
+
+	values := make([]float64, 0, entry.count)
+	for i := 0; i+8 <= len(entry.value); i += 8 {
+		numerator := r.order.Uint32(entry.value[i : i+4])
+		denominator := r.order.Uint32(entry.value[i+4 : i+8])
+		if denominator == 0 {
+			return nil
+		}
+
+		values = append(values, float64(numerator)/float64(denominator))
+	}
+
+	return values
+}
+
+// coordinate converts degrees, minutes and seconds to decimal degrees, negative in the given hemisphere
+func (r *tiffReader) coordinate(entry ifdEntry, ref, negativeRef string) *float64 {
+	dms := r.rationals(entry)
+	if len(dms) != 3 || ref == "" {
+		return nil
+	}
+
+	degrees := dms[0] + dms[1]/60 + dms[2]/3600
+	if ref == negativeRef {
+		degrees = -degrees
+	}
+
+	return &degrees
+}
+
+func (r *tiffReader) gpsTime(dateEntry, timeEntry ifdEntry) *time.Time {
+	date := r.ascii(dateEntry)
+	hms := r.rationals(timeEntry)
+	if date == "" || len(hms) != 3 {
+		return nil
+	}
+
+	day, err := time.Parse("2006:01:02", date)
+	if err != nil {
+		return nil
+	}
+
+	t := day.Add(time.Duration(hms[0]*float64(time.Hour) + hms[1]*float64(time.Minute) + hms[2]*float64(time.Second)))
+
+	return &t
+}
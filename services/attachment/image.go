@@ -0,0 +1,210 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	DefaultMaxDimension  = 8000
+	DefaultThumbnailSize = 320
+	DefaultJPEGQuality   = 85
+)
+
+type ImageConfig struct {
+	// Larger images are rejected before they are decoded
+	MaxWidth  int
+	MaxHeight int
+	// Thumbnails fit in a square of this size
+	ThumbnailSize int
+	JPEGQuality   int
+}
+
+// NewImageProcessor re-encodes JPEG and PNG images upright and without EXIF, keeping the capture time and location as
+// metadata, and adds a JPEG thumbnail. Zero config values fall back to the defaults.
+func NewImageProcessor(config ImageConfig) Processor {
+	if config.MaxWidth <= 0 {
+		config.MaxWidth = DefaultMaxDimension
+	}
+
+	if config.MaxHeight <= 0 {
+		config.MaxHeight = DefaultMaxDimension
+	}
+
+	if config.ThumbnailSize <= 0 {
+		config.ThumbnailSize = DefaultThumbnailSize
+	}
+
+	if config.JPEGQuality <= 0 {
+		config.JPEGQuality = DefaultJPEGQuality
+	}
+
+	return &imageProcessor{config}
+}
+
+type imageProcessor struct {
+	config ImageConfig
+}
+
+// Accepts implements Processor.
+func (p *imageProcessor) Accepts(contentType string) bool {
+	return contentType == "image/jpeg" || contentType == "image/png"
+}
+
+// Process implements Processor.
+func (p *imageProcessor) Process(ctx context.Context, data []byte, contentType string) (*Result, error) {
+	// Dimensions are read from the header first, so oversized images are never decoded into memory
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if imageConfig.Width > p.config.MaxWidth || imageConfig.Height > p.config.MaxHeight {
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	img := toRGBA(decoded)
+	metadata := Metadata{}
+	if exif := readExif(data, contentType); exif != nil {
+		// The orientation is lost with the rest of the EXIF, so it is applied to the pixels instead
+		img = orient(img, exif.Orientation)
+		metadata.CapturedAt = exif.CapturedAt
+		metadata.Latitude = exif.Latitude
+		metadata.Longitude = exif.Longitude
+	}
+
+	metadata.Width = img.Bounds().Dx()
+	metadata.Height = img.Bounds().Dy()
+
+	// Encoders only write pixels, which drops every metadata segment of the upload
+	original := Variant{Name: VariantOriginal, ContentType: contentType}
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		original.Extension = ".png"
+		err = png.Encode(&buf, img)
+	} else {
+		original.Extension = ".jpg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.config.JPEGQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	original.Data = buf.Bytes()
+
+	var thumbnailBuf bytes.Buffer
+	err = jpeg.Encode(&thumbnailBuf, resize(img, p.config.ThumbnailSize), &jpeg.Options{Quality: p.config.JPEGQuality})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Variants: []Variant{
+			original,
+			{Name: VariantThumbnail, Data: thumbnailBuf.Bytes(), ContentType: "image/jpeg", Extension: ".jpg"},
+		},
+		Metadata: metadata,
+	}, nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	return rgba
+}
+
+// orient rotates and flips the image as described by the EXIF orientation, so it is displayed upright without it
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dstW, dstH := w, h
+	// Orientations 5 to 8 are rotated by 90 degrees
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 clockwise to display
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 counter-clockwise to display
+				sx, sy = w-1-y, x
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], img.Pix[img.PixOffset(sx, sy):img.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// resize scales the image down to fit in a square of the size, averaging the source pixels covered by each pixel.
+// Smaller images are returned as is.
+func resize(img *image.RGBA, size int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	dstW, dstH := size, h*size/w
+	if h > w {
+		dstW, dstH = w*size/h, size
+	}
+	dstW, dstH = max(dstW, 1), max(dstH, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*h/dstH, max((y+1)*h/dstH, y*h/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*w/dstW, max((x+1)*w/dstW, x*w/dstW+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					offset := img.PixOffset(sx, sy)
+					for c := 0; c < 4; c++ {
+						sum[c] += int(img.Pix[offset+c])
+					}
+				}
+			}
+
+			count := (y1 - y0) * (x1 - x0)
+			offset := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	attachment "loan-service/services/attachment"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Pipeline is an autogenerated mock type for the Pipeline type
+type Pipeline struct {
+	mock.Mock
+}
+
+// Process provides a mock function with given fields: ctx, file, allowedContentTypes
+func (_m *Pipeline) Process(ctx context.Context, file io.Reader, allowedContentTypes ...string) (*attachment.Result, error) {
+	_va := make([]interface{}, len(allowedContentTypes))
+	for _i := range allowedContentTypes {
+		_va[_i] = allowedContentTypes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, file)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *attachment.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, ...string) (*attachment.Result, error)); ok {
+		return rf(ctx, file, allowedContentTypes...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, ...string) *attachment.Result); ok {
+		r0 = rf(ctx, file, allowedContentTypes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*attachment.Result)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, ...string) error); ok {
+		r1 = rf(ctx, file, allowedContentTypes...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPipeline creates a new instance of Pipeline. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPipeline(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Pipeline {
+	mock := &Pipeline{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	attachment "loan-service/services/attachment"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Processor is an autogenerated mock type for the Processor type
+type Processor struct {
+	mock.Mock
+}
+
+// Accepts provides a mock function with given fields: contentType
+func (_m *Processor) Accepts(contentType string) bool {
+	ret := _m.Called(contentType)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(contentType)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Process provides a mock function with given fields: ctx, data, contentType
+func (_m *Processor) Process(ctx context.Context, data []byte, contentType string) (*attachment.Result, error) {
+	ret := _m.Called(ctx, data, contentType)
+
+	var r0 *attachment.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, string) (*attachment.Result, error)); ok {
+		return rf(ctx, data, contentType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, string) *attachment.Result); ok {
+		r0 = rf(ctx, data, contentType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*attachment.Result)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, string) error); ok {
+		r1 = rf(ctx, data, contentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewProcessor creates a new instance of Processor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProcessor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Processor {
+	mock := &Processor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,34 @@
+package attachment
+
+import (
+	"context"
+	"slices"
+)
+
+// NewPassthroughProcessor stores files of the content types as uploaded, e.g. signed PDF agreements
+func NewPassthroughProcessor(extensions map[string]string) Processor {
+	return &passthroughProcessor{extensions}
+}
+
+type passthroughProcessor struct {
+	// Content type to file extension
+	extensions map[string]string
+}
+
+// Accepts implements Processor.
+func (p *passthroughProcessor) Accepts(contentType string) bool {
+	_, ok := p.extensions[contentType]
+	return ok
+}
+
+// Process implements Processor.
+func (p *passthroughProcessor) Process(ctx context.Context, data []byte, contentType string) (*Result, error) {
+	return &Result{
+		Variants: []Variant{{
+			Name:        VariantOriginal,
+			Data:        slices.Clone(data),
+			ContentType: contentType,
+			Extension:   p.extensions[contentType],
+		}},
+	}, nil
+}
@@ -18,6 +18,7 @@ const (
 
 	AuthClaimsCtxKey      = "auth"
 	RefreshTokenCookieKey = "Refresh-Token"
+	APIKeyHeader          = "X-API-Key"
 
 	RoleTypeSuperuser      RoleType = "superuser"
 	RoleTypeStaff          RoleType = "staff"
@@ -26,11 +27,18 @@ const (
 	RoleTypeBorrower       RoleType = "borrower"
 )
 
+// IsPrivileged returns true for the role types that only superusers may hand out or manage
+func (t RoleType) IsPrivileged() bool {
+	return t == RoleTypeSuperuser || t == RoleTypeStaff
+}
+
 type AuthClaims struct {
 	UserID   uint
 	Email    string
 	Name     string
 	RoleType RoleType
+	// Loaded from the user's role on every request, never encoded in the token
+	Permissions []Permission `json:"-"`
 	jwt.StandardClaims
 }
 
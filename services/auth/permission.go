@@ -0,0 +1,145 @@
+package auth
+
+type Permission string
+
+const (
+	// Loan visibility, a user sees loans according to the broadest scope they hold
+	PermissionLoanViewAll        Permission = "loan.view_all"
+	PermissionLoanViewProposed   Permission = "loan.view_proposed"
+	PermissionLoanViewInvestable Permission = "loan.view_investable"
+	PermissionLoanViewOwn        Permission = "loan.view_own"
+
+	// Loan actions
+	PermissionLoanCreate   Permission = "loan.create"
+	PermissionLoanVisit    Permission = "loan.visit"
+	PermissionLoanAssign   Permission = "loan.assign"
+	PermissionLoanApprove  Permission = "loan.approve"
+	PermissionLoanInvest   Permission = "loan.invest"
+	PermissionLoanDisburse Permission = "loan.disburse"
+
+	// Borrower identity (KYC), verified before a borrower can request a loan
+	PermissionKYCSubmit Permission = "kyc.submit"
+	PermissionKYCReview Permission = "kyc.review"
+
+	// Approval policies, who must approve a loan before it opens for investment
+	PermissionApprovalPolicyManage Permission = "approval_policy.manage"
+
+	// Credit scorecard, how loan applications are scored and graded by risk
+	PermissionCreditScorecardManage Permission = "credit_scorecard.manage"
+
+	// Products
+	PermissionProductView   Permission = "product.view"
+	PermissionProductManage Permission = "product.manage"
+
+	// Users and access control
+	PermissionUserView     Permission = "user.view"
+	PermissionUserManage   Permission = "user.manage"
+	PermissionRoleManage   Permission = "role.manage"
+	PermissionAPIKeyManage Permission = "api_key.manage"
+
+	// Notifications
+	PermissionNotificationView   Permission = "notification.view"
+	PermissionNotificationManage Permission = "notification.manage"
+
+	// Integrations
+	PermissionWebhookManage Permission = "webhook.manage"
+)
+
+// AllPermissions lists every permission known to the application
+var AllPermissions = []Permission{
+	PermissionLoanViewAll,
+	PermissionLoanViewProposed,
+	PermissionLoanViewInvestable,
+	PermissionLoanViewOwn,
+	PermissionLoanCreate,
+	PermissionLoanVisit,
+	PermissionLoanAssign,
+	PermissionLoanApprove,
+	PermissionLoanInvest,
+	PermissionLoanDisburse,
+	PermissionKYCSubmit,
+	PermissionKYCReview,
+	PermissionApprovalPolicyManage,
+	PermissionCreditScorecardManage,
+	PermissionProductView,
+	PermissionProductManage,
+	PermissionUserView,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionAPIKeyManage,
+	PermissionNotificationView,
+	PermissionNotificationManage,
+	PermissionWebhookManage,
+}
+
+var PermissionDescriptions = map[Permission]string{
+	PermissionLoanViewAll:           "View all loans",
+	PermissionLoanViewProposed:      "View loans assigned to, visited or disbursed by the user",
+	PermissionLoanViewInvestable:    "View loans open for investment and loans invested by the user",
+	PermissionLoanViewOwn:           "View loans requested by the user",
+	PermissionLoanCreate:            "Request a new loan",
+	PermissionLoanVisit:             "Mark a loan borrower as visited",
+	PermissionLoanAssign:            "Assign borrower visits to field validators",
+	PermissionLoanApprove:           "Approve a visited loan",
+	PermissionLoanInvest:            "Invest in an approved loan",
+	PermissionLoanDisburse:          "Disburse an invested loan",
+	PermissionKYCSubmit:             "Submit the user's identity for verification",
+	PermissionKYCReview:             "Review borrower identities and their ID card and selfie",
+	PermissionApprovalPolicyManage:  "Change how many and which approvers a loan needs",
+	PermissionCreditScorecardManage: "Change the scorecard loan applications are credit scored with",
+	PermissionProductView:           "View loan products",
+	PermissionProductManage:         "Create and modify loan products",
+	PermissionUserView:              "View users",
+	PermissionUserManage:            "Create and modify users",
+	PermissionRoleManage:            "Manage roles and their permissions",
+	PermissionAPIKeyManage:          "Manage service accounts and their API keys",
+	PermissionNotificationView:      "Preview email templates and view the email outbox",
+	PermissionNotificationManage:    "Resend failed emails",
+	PermissionWebhookManage:         "Manage partner webhook subscriptions and replay deliveries",
+}
+
+// DefaultRolePermissions is the initial permission set of each built-in role, used for seeding
+var DefaultRolePermissions = map[RoleType][]Permission{
+	RoleTypeSuperuser: AllPermissions,
+	RoleTypeStaff: {
+		PermissionLoanViewAll,
+		PermissionLoanVisit,
+		PermissionLoanAssign,
+		PermissionLoanApprove,
+		PermissionLoanDisburse,
+		PermissionKYCReview,
+		PermissionProductView,
+		PermissionProductManage,
+		PermissionUserView,
+		PermissionNotificationView,
+		PermissionWebhookManage,
+	},
+	RoleTypeFieldValidator: {
+		PermissionLoanViewProposed,
+		PermissionLoanVisit,
+		PermissionLoanDisburse,
+	},
+	RoleTypeInvestor: {
+		PermissionLoanViewInvestable,
+		PermissionLoanInvest,
+	},
+	RoleTypeBorrower: {
+		PermissionLoanViewOwn,
+		PermissionLoanCreate,
+		PermissionKYCSubmit,
+		PermissionProductView,
+	},
+}
+
+// HasPermission returns true if any of the wanted permissions is in the granted permissions
+func HasPermission(granted []Permission, wanted ...Permission) bool {
+	for _, g := range granted {
+		for _, w := range wanted {
+			if g == w {
+				return true
+			}
+		}
+	}
+
+	return false
+}
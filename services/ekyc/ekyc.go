@@ -0,0 +1,56 @@
+package ekyc
+
+import (
+	"context"
+	"time"
+)
+
+type Decision string
+
+const (
+	DecisionApproved Decision = "approved"
+	DecisionRejected Decision = "rejected"
+	// The provider could not decide, staff review the submission
+	DecisionReview Decision = "review"
+)
+
+// Request is the identity submitted by the borrower, with the images of their ID card and selfie
+type Request struct {
+	NIK         string
+	Name        string
+	DateOfBirth time.Time
+	IDCard      []byte
+	Selfie      []byte
+}
+
+type Result struct {
+	Decision Decision
+	// The provider's ID of the check, to look it up on their side
+	Reference string
+	// Why the identity was rejected or sent to review
+	Reason string
+}
+
+// Provider verifies identities with an e-KYC vendor, e.g. against the population registry and by matching the selfie
+// with the ID card photo
+type Provider interface {
+	Name() string
+	Verify(ctx context.Context, req Request) (*Result, error)
+}
+
+// NewManualProvider leaves every submission to staff review, when no e-KYC vendor is configured
+func NewManualProvider() Provider {
+	return &manualProvider{}
+}
+
+type manualProvider struct{}
+
+// Name implements Provider.
+func (p *manualProvider) Name() string {
+	return "manual"
+}
+
+// Verify implements Provider.
+func (p *manualProvider) Verify(ctx context.Context, req Request) (*Result, error) {
+	return &Result{Decision: DecisionReview, Reason: "no automated verification configured"}, nil
+}
@@ -0,0 +1,15 @@
+package ekyc
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrIncompleteRequest = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "EKYCIncompleteRequest",
+		Err:        errors.New("both the ID card and the selfie are needed to verify an identity"),
+	}
+)
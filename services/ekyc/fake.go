@@ -0,0 +1,42 @@
+package ekyc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// NewFakeProvider decides by the last digits of the NIK, for local development and demos: NIKs ending in 0000 are
+// not found in the registry, those ending in 9999 need review, and the rest are approved
+func NewFakeProvider() Provider {
+	return &fakeProvider{}
+}
+
+type fakeProvider struct{}
+
+// Name implements Provider.
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+// Verify implements Provider.
+func (p *fakeProvider) Verify(ctx context.Context, req Request) (*Result, error) {
+	if len(req.IDCard) == 0 || len(req.Selfie) == 0 {
+		return nil, ErrIncompleteRequest
+	}
+
+	sum := sha256.Sum256([]byte(req.NIK))
+	result := &Result{Decision: DecisionApproved, Reference: "fake_" + hex.EncodeToString(sum[:8])}
+
+	switch {
+	case strings.HasSuffix(req.NIK, "0000"):
+		result.Decision = DecisionRejected
+		result.Reason = "NIK not found in the population registry"
+	case strings.HasSuffix(req.NIK, "9999"):
+		result.Decision = DecisionReview
+		result.Reason = "selfie does not clearly match the ID card photo"
+	}
+
+	return result, nil
+}
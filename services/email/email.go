@@ -14,7 +14,7 @@ import (
 type EmailService interface {
 	DefaultSenderName() string
 	DefaultSenderAddress() string
-	SendMail(ctx context.Context, subject, body string, from mail.Email, to mail.Email, attachment *AttachmentOpts) error
+	SendMail(ctx context.Context, subject string, body Body, from mail.Email, to mail.Email, attachment *AttachmentOpts) error
 }
 
 func NewEmailService(apiKey string, defaultSenderAddress, defaultSenderName string) EmailService {
@@ -55,13 +55,17 @@ type AttachmentOpts struct {
 	Filename    string
 }
 
-func (e *emailService) SendMail(ctx context.Context, subject, body string, from mail.Email, to mail.Email, attachmentOpts *AttachmentOpts) error {
+func (e *emailService) SendMail(ctx context.Context, subject string, body Body, from mail.Email, to mail.Email, attachmentOpts *AttachmentOpts) error {
 	fmt.Printf("[email sending] from:%s to:%s subject:%s\n", from.Address, to.Address, subject)
 
 	// create new mail with origin address and body
 	newMail := mail.NewV3Mail()
 	newMail.SetFrom(&from)
-	newMail.AddContent(mail.NewContent("text/html", body))
+	// plain text must come before html
+	if body.Text != "" {
+		newMail.AddContent(mail.NewContent("text/plain", body.Text))
+	}
+	newMail.AddContent(mail.NewContent("text/html", body.HTML))
 
 	// add destination address and subject
 	personalization := mail.NewPersonalization()
@@ -12,4 +12,10 @@ var (
 		ErrorCode:  "EmailNotSent",
 		Err:        errors.New("a problem occured while sending email"),
 	}
+
+	ErrTemplateNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "TemplateNotFound",
+		Err:        errors.New("email template not found"),
+	}
 )
@@ -0,0 +1,64 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"loan-service/utils/errs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// NewFileDropEmailService writes emails as .eml files to a directory instead of sending them, for local development
+func NewFileDropEmailService(dir string, defaultSenderAddress, defaultSenderName string) EmailService {
+	return &fileDropEmailService{
+		dir:                  dir,
+		defaultSenderAddress: defaultSenderAddress,
+		defaultSenderName:    defaultSenderName,
+	}
+}
+
+type fileDropEmailService struct {
+	dir                  string
+	defaultSenderAddress string
+	defaultSenderName    string
+}
+
+// DefaultSenderAddress implements EmailService.
+func (e *fileDropEmailService) DefaultSenderAddress() string {
+	return e.defaultSenderAddress
+}
+
+// DefaultSenderName implements EmailService.
+func (e *fileDropEmailService) DefaultSenderName() string {
+	return e.defaultSenderName
+}
+
+// SendMail implements EmailService.
+func (e *fileDropEmailService) SendMail(ctx context.Context, subject string, body Body, from mail.Email, to mail.Email, attachmentOpts *AttachmentOpts) error {
+	now := time.Now()
+
+	message, err := buildMessage(subject, body, from, to, attachmentOpts, now)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrEmailNotSent)
+	}
+
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrEmailNotSent)
+	}
+
+	// Sortable by time, and unique for emails sent at the same time
+	filename := filepath.Join(e.dir, fmt.Sprintf("%s-%d-%s.eml", now.Format("20060102T150405"), now.UnixNano(), to.Address))
+	if err := os.WriteFile(filename, message, 0o600); err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrEmailNotSent)
+	}
+
+	fmt.Printf("[email written] to:%s subject:%s file:%s\n", to.Address, subject, filename)
+
+	return nil
+}
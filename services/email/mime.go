@@ -0,0 +1,148 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// buildMessage builds a MIME message with the plain text and HTML body as alternatives, and the optional attachment
+func buildMessage(subject string, body Body, from sgmail.Email, to sgmail.Email, attachmentOpts *AttachmentOpts, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	mixedWriter := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + formatAddress(from),
+		"To: " + formatAddress(to),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: " + messageID,
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q", mixedWriter.Boundary()),
+	}
+
+	// The writer only writes once a part is created, so the message headers go first
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	alternativeBuf := bytes.Buffer{}
+	alternativeWriter := multipart.NewWriter(&alternativeBuf)
+
+	if body.Text != "" {
+		if err := writeQuotedPrintablePart(alternativeWriter, "text/plain; charset=utf-8", body.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeQuotedPrintablePart(alternativeWriter, "text/html; charset=utf-8", body.HTML); err != nil {
+		return nil, err
+	}
+
+	if err := alternativeWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	alternativePart, err := mixedWriter.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=%q", alternativeWriter.Boundary())},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := alternativePart.Write(alternativeBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if attachmentOpts != nil {
+		attachmentBytes, err := io.ReadAll(attachmentOpts.File)
+		if err != nil {
+			return nil, err
+		}
+
+		attachmentPart, err := mixedWriter.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {string(attachmentOpts.ContentType)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachmentOpts.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeBase64Lines(attachmentPart, attachmentBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixedWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qpWriter := quotedprintable.NewWriter(part)
+	if _, err := qpWriter.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return qpWriter.Close()
+}
+
+// writeBase64Lines writes base64 in lines of 76 characters as required by RFC 2045
+func writeBase64Lines(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+
+	_, err := io.WriteString(w, encoded+"\r\n")
+
+	return err
+}
+
+func formatAddress(address sgmail.Email) string {
+	return (&mail.Address{Name: address.Name, Address: address.Address}).String()
+}
+
+func newMessageID(fromAddress string) (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(randomBytes), domain), nil
+}
@@ -45,11 +45,11 @@ func (_m *EmailService) DefaultSenderName() string {
 }
 
 // SendMail provides a mock function with given fields: ctx, subject, body, from, to, attachment
-func (_m *EmailService) SendMail(ctx context.Context, subject string, body string, from mail.Email, to mail.Email, attachment *email.AttachmentOpts) error {
+func (_m *EmailService) SendMail(ctx context.Context, subject string, body email.Body, from mail.Email, to mail.Email, attachment *email.AttachmentOpts) error {
 	ret := _m.Called(ctx, subject, body, from, to, attachment)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, mail.Email, mail.Email, *email.AttachmentOpts) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, email.Body, mail.Email, mail.Email, *email.AttachmentOpts) error); ok {
 		r0 = rf(ctx, subject, body, from, to, attachment)
 	} else {
 		r0 = ret.Error(0)
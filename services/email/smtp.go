@@ -0,0 +1,151 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"loan-service/utils/errs"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+type SMTPTLSMode string
+
+const (
+	SMTPTLSModeStartTLS SMTPTLSMode = "starttls" // upgrade a plain connection, usually on port 587
+	SMTPTLSModeImplicit SMTPTLSMode = "implicit" // TLS from the start, usually on port 465
+	SMTPTLSModeNone     SMTPTLSMode = "none"     // only for local mail catchers
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	TLSMode  SMTPTLSMode
+	// Only for testing against servers with self-signed certificates
+	InsecureSkipVerify bool
+}
+
+func NewSMTPEmailService(smtpConfig SMTPConfig, defaultSenderAddress, defaultSenderName string) EmailService {
+	return &smtpEmailService{
+		config:               smtpConfig,
+		defaultSenderAddress: defaultSenderAddress,
+		defaultSenderName:    defaultSenderName,
+	}
+}
+
+type smtpEmailService struct {
+	config               SMTPConfig
+	defaultSenderAddress string
+	defaultSenderName    string
+}
+
+// DefaultSenderAddress implements EmailService.
+func (e *smtpEmailService) DefaultSenderAddress() string {
+	return e.defaultSenderAddress
+}
+
+// DefaultSenderName implements EmailService.
+func (e *smtpEmailService) DefaultSenderName() string {
+	return e.defaultSenderName
+}
+
+// SendMail implements EmailService.
+func (e *smtpEmailService) SendMail(ctx context.Context, subject string, body Body, from mail.Email, to mail.Email, attachmentOpts *AttachmentOpts) error {
+	fmt.Printf("[email sending] from:%s to:%s subject:%s\n", from.Address, to.Address, subject)
+
+	message, err := buildMessage(subject, body, from, to, attachmentOpts, time.Now())
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrEmailNotSent)
+	}
+
+	err = e.send(ctx, from.Address, to.Address, message)
+	if err != nil {
+		fmt.Println("failed to send email", errs.Wrap(err))
+		return errs.Wrap(ErrEmailNotSent)
+	}
+
+	fmt.Printf("[email sent] via smtp %s\n", e.config.Host)
+
+	return nil
+}
+
+func (e *smtpEmailService) send(ctx context.Context, from, to string, message []byte) error {
+	address := net.JoinHostPort(e.config.Host, e.config.Port)
+	tlsConfig := &tls.Config{
+		ServerName:         e.config.Host,
+		InsecureSkipVerify: e.config.InsecureSkipVerify, //nolint:gosec // opt-in for testing
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if e.config.TLSMode == SMTPTLSModeImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Abort the conversation if the context is done before the server responds
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	client, err := smtp.NewClient(conn, e.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if e.config.TLSMode == SMTPTLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", e.config.Host)
+		}
+
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if e.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
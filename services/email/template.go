@@ -0,0 +1,285 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"loan-service/config"
+	"loan-service/utils/errs"
+	"loan-service/utils/i18n"
+	"loan-service/utils/money"
+	"os"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+type TemplateName string
+
+const (
+	TemplateInvitation     TemplateName = "invitation"
+	TemplateLoanFunded     TemplateName = "loan_funded"
+	TemplateAccountLocked  TemplateName = "account_locked"
+	TemplateNewDeviceLogin TemplateName = "new_device_login"
+	TemplateLoanApproved   TemplateName = "loan_approved"
+	TemplateLoanDisbursed  TemplateName = "loan_disbursed"
+	TemplateInstallmentDue TemplateName = "installment_due"
+	TemplateVisitAssigned  TemplateName = "visit_assigned"
+	TemplateVisitOverdue   TemplateName = "visit_overdue"
+)
+
+// Templates lists every email template, each must exist in all supported locales as
+// <name>.<locale>.html for the HTML body, and <name>.<locale>.txt for the subject and plain text body
+var Templates = []TemplateName{
+	TemplateInvitation,
+	TemplateLoanFunded,
+	TemplateAccountLocked,
+	TemplateNewDeviceLogin,
+	TemplateLoanApproved,
+	TemplateLoanDisbursed,
+	TemplateInstallmentDue,
+	TemplateVisitAssigned,
+	TemplateVisitOverdue,
+}
+
+// ShortTemplates also define a "short" text in their .txt file, sent by SMS and WhatsApp
+var ShortTemplates = []TemplateName{
+	TemplateLoanFunded,
+	TemplateLoanApproved,
+	TemplateLoanDisbursed,
+	TemplateInstallmentDue,
+	TemplateVisitAssigned,
+	TemplateVisitOverdue,
+}
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+type InvitationData struct {
+	Name           string
+	InvitedByName  string
+	RoleName       string
+	InvitationURL  string
+	ExpiresInHours int
+}
+
+type LoanFundedData struct {
+	Name            string
+	LoanName        string
+	BorrowerName    string
+	PrincipalAmount string
+}
+
+type AccountLockedData struct {
+	Name           string
+	FailedAttempts int
+	IPAddress      string
+	LockedUntil    time.Time
+}
+
+type NewDeviceLoginData struct {
+	Name       string
+	LoggedInAt time.Time
+	IPAddress  string
+	UserAgent  string
+}
+
+type LoanApprovedData struct {
+	Name            string
+	LoanName        string
+	PrincipalAmount string
+}
+
+type LoanDisbursedData struct {
+	Name                  string
+	LoanName              string
+	PrincipalAmount       string
+	DisbursedAt           time.Time
+	FirstInstallmentDueAt time.Time
+}
+
+type InstallmentDueData struct {
+	Name              string
+	LoanName          string
+	InstallmentNumber int
+	LoanTerm          int
+	Amount            string
+	DueAt             time.Time
+}
+
+type VisitAssignedData struct {
+	Name         string
+	LoanName     string
+	BorrowerName string
+	Address      string
+	DueAt        time.Time
+	// Zero until the visit is scheduled
+	ScheduledAt time.Time
+}
+
+type VisitOverdueData struct {
+	Name         string
+	LoanName     string
+	BorrowerName string
+	DueAt        time.Time
+}
+
+// TemplateSamples holds example data for previewing each template
+var TemplateSamples = map[TemplateName]any{
+	TemplateInvitation: InvitationData{
+		Name:           "Olaf Scholz",
+		InvitedByName:  "Angela Merkel",
+		RoleName:       "Staff",
+		InvitationURL:  "http://localhost:8080/invitations/accept?token=sample",
+		ExpiresInHours: 72,
+	},
+	TemplateLoanFunded: LoanFundedData{
+		Name:            "Larry Fink",
+		LoanName:        "Warung Sembako",
+		BorrowerName:    "Zulhas Hasan",
+		PrincipalAmount: "5000000",
+	},
+	TemplateAccountLocked: AccountLockedData{
+		Name:           "Larry Fink",
+		FailedAttempts: 5,
+		IPAddress:      "203.0.113.7",
+		LockedUntil:    time.Date(2024, time.August, 17, 10, 15, 0, 0, time.UTC),
+	},
+	TemplateNewDeviceLogin: NewDeviceLoginData{
+		Name:       "Larry Fink",
+		LoggedInAt: time.Date(2024, time.August, 17, 10, 0, 0, 0, time.UTC),
+		IPAddress:  "203.0.113.7",
+		UserAgent:  "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 Safari/605.1.15",
+	},
+	TemplateLoanApproved: LoanApprovedData{
+		Name:            "Zulhas Hasan",
+		LoanName:        "Warung Sembako",
+		PrincipalAmount: "5000000",
+	},
+	TemplateLoanDisbursed: LoanDisbursedData{
+		Name:                  "Zulhas Hasan",
+		LoanName:              "Warung Sembako",
+		PrincipalAmount:       "5000000",
+		DisbursedAt:           time.Date(2024, time.August, 17, 10, 0, 0, 0, time.UTC),
+		FirstInstallmentDueAt: time.Date(2024, time.September, 17, 10, 0, 0, 0, time.UTC),
+	},
+	TemplateInstallmentDue: InstallmentDueData{
+		Name:              "Zulhas Hasan",
+		LoanName:          "Warung Sembako",
+		InstallmentNumber: 1,
+		LoanTerm:          6,
+		Amount:            "883333.33",
+		DueAt:             time.Date(2024, time.September, 17, 10, 0, 0, 0, time.UTC),
+	},
+	TemplateVisitAssigned: VisitAssignedData{
+		Name:         "Silvio Berlusconi",
+		LoanName:     "Warung Sembako",
+		BorrowerName: "Zulhas Hasan",
+		Address:      "Jl. Medan Merdeka Barat No. 1, Jakarta",
+		DueAt:        time.Date(2024, time.August, 20, 10, 0, 0, 0, time.UTC),
+		ScheduledAt:  time.Date(2024, time.August, 19, 9, 0, 0, 0, time.UTC),
+	},
+	TemplateVisitOverdue: VisitOverdueData{
+		Name:         "Silvio Berlusconi",
+		LoanName:     "Warung Sembako",
+		BorrowerName: "Zulhas Hasan",
+		DueAt:        time.Date(2024, time.August, 20, 10, 0, 0, 0, time.UTC),
+	},
+}
+
+// Body holds both the HTML and plain text alternative of an email
+type Body struct {
+	HTML string
+	Text string
+}
+
+type RenderedEmail struct {
+	Subject string
+	Body    Body
+}
+
+var templateFuncs = map[string]any{
+	"money": money.DisplayMoney,
+	"datetime": func(t time.Time) string {
+		return t.Format("02 Jan 2006 15:04 MST")
+	},
+	"date": func(t time.Time) string {
+		return t.Format("02 Jan 2006")
+	},
+}
+
+// Render renders the template in the given locale, falling back to the default locale if unsupported
+func Render(name TemplateName, locale i18n.Locale, data any) (*RenderedEmail, error) {
+	if !slices.Contains(Templates, name) {
+		return nil, ErrTemplateNotFound
+	}
+
+	locale = i18n.Resolve(locale)
+	fsys := templateFS()
+
+	textTmpl, err := texttemplate.New("").Funcs(templateFuncs).ParseFS(fsys, fmt.Sprintf("%s.%s.txt", name, locale))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	htmlTmpl, err := htmltemplate.New("").Funcs(templateFuncs).ParseFS(fsys, fmt.Sprintf("%s.%s.html", name, locale))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if err := textTmpl.ExecuteTemplate(&text, "body", data); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if err := htmlTmpl.ExecuteTemplate(&html, "body", data); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Body: Body{
+			HTML: strings.TrimSpace(html.String()),
+			Text: strings.TrimSpace(text.String()),
+		},
+	}, nil
+}
+
+// RenderShort renders the short text of the template in the given locale, for channels other than email
+func RenderShort(name TemplateName, locale i18n.Locale, data any) (string, error) {
+	if !slices.Contains(ShortTemplates, name) {
+		return "", ErrTemplateNotFound
+	}
+
+	locale = i18n.Resolve(locale)
+
+	textTmpl, err := texttemplate.New("").Funcs(templateFuncs).ParseFS(templateFS(), fmt.Sprintf("%s.%s.txt", name, locale))
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	var short bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&short, "short", data); err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	return strings.TrimSpace(short.String()), nil
+}
+
+// templateFS returns the templates directory from config if set, so templates can be edited without a rebuild
+func templateFS() fs.FS {
+	if config.Data.EmailTemplateDir != "" {
+		return os.DirFS(config.Data.EmailTemplateDir)
+	}
+
+	fsys, _ := fs.Sub(embeddedTemplates, "templates")
+
+	return fsys
+}
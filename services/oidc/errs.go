@@ -0,0 +1,39 @@
+package oidc
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrOIDCDisabled = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "OIDCDisabled",
+		Err:        errors.New("single sign-on is not configured"),
+	}
+
+	ErrOIDCProviderUnavailable = errs.GeneralError{
+		StatusCode: http.StatusBadGateway,
+		ErrorCode:  "OIDCProviderUnavailable",
+		Err:        errors.New("a problem occured while contacting the identity provider"),
+	}
+
+	ErrOIDCInvalidCode = errs.GeneralError{
+		StatusCode: http.StatusUnauthorized,
+		ErrorCode:  "OIDCInvalidCode",
+		Err:        errors.New("the authorization code is invalid or has expired"),
+	}
+
+	ErrOIDCInvalidIDToken = errs.GeneralError{
+		StatusCode: http.StatusUnauthorized,
+		ErrorCode:  "OIDCInvalidIDToken",
+		Err:        errors.New("the identity provider returned an invalid ID token"),
+	}
+
+	ErrOIDCInvalidState = errs.GeneralError{
+		StatusCode: http.StatusUnauthorized,
+		ErrorCode:  "OIDCInvalidState",
+		Err:        errors.New("the login session is invalid or has expired, please try again"),
+	}
+)
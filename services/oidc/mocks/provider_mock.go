@@ -0,0 +1,119 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	oidc "loan-service/services/oidc"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Provider is an autogenerated mock type for the Provider type
+type Provider struct {
+	mock.Mock
+}
+
+// AuthCodeURL provides a mock function with given fields: ctx, authRequest
+func (_m *Provider) AuthCodeURL(ctx context.Context, authRequest *oidc.AuthRequest) (string, error) {
+	ret := _m.Called(ctx, authRequest)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *oidc.AuthRequest) (string, error)); ok {
+		return rf(ctx, authRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *oidc.AuthRequest) string); ok {
+		r0 = rf(ctx, authRequest)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *oidc.AuthRequest) error); ok {
+		r1 = rf(ctx, authRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Enabled provides a mock function with given fields:
+func (_m *Provider) Enabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Exchange provides a mock function with given fields: ctx, code, authRequest
+func (_m *Provider) Exchange(ctx context.Context, code string, authRequest *oidc.AuthRequest) (*oidc.Identity, error) {
+	ret := _m.Called(ctx, code, authRequest)
+
+	var r0 *oidc.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *oidc.AuthRequest) (*oidc.Identity, error)); ok {
+		return rf(ctx, code, authRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *oidc.AuthRequest) *oidc.Identity); ok {
+		r0 = rf(ctx, code, authRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oidc.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *oidc.AuthRequest) error); ok {
+		r1 = rf(ctx, code, authRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuthRequest provides a mock function with given fields:
+func (_m *Provider) NewAuthRequest() (*oidc.AuthRequest, error) {
+	ret := _m.Called()
+
+	var r0 *oidc.AuthRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*oidc.AuthRequest, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *oidc.AuthRequest); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oidc.AuthRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewProvider creates a new instance of Provider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Provider {
+	mock := &Provider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,406 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"loan-service/services/auth"
+	"loan-service/utils/errs"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// Allowed clock difference between us and the identity provider
+	clockSkewLeeway = time.Minute
+)
+
+// rolePriority decides which role wins when a user is in several mapped groups
+var rolePriority = []auth.RoleType{
+	auth.RoleTypeSuperuser,
+	auth.RoleTypeStaff,
+	auth.RoleTypeFieldValidator,
+	auth.RoleTypeInvestor,
+	auth.RoleTypeBorrower,
+}
+
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	GroupsClaim  string
+	// Maps identity provider group names to our role types, in precedence order for roles outside rolePriority
+	RoleMapping []RoleMapping
+}
+
+// RoleMapping maps an identity provider group to one of our role types
+type RoleMapping struct {
+	Group    string
+	RoleType auth.RoleType
+}
+
+// Identity is the verified user identity from an ID token
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+	RoleType      auth.RoleType // empty if none of the groups are mapped
+}
+
+// AuthRequest holds the per-login secrets that must be kept until the callback
+type AuthRequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type Provider interface {
+	Enabled() bool
+	NewAuthRequest() (*AuthRequest, error)
+	AuthCodeURL(ctx context.Context, authRequest *AuthRequest) (string, error)
+	Exchange(ctx context.Context, code string, authRequest *AuthRequest) (*Identity, error)
+}
+
+func NewProvider(config Config, httpClient *http.Client) Provider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &provider{
+		config:     config,
+		httpClient: httpClient,
+		keys:       map[string]*rsa.PublicKey{},
+	}
+}
+
+type provider struct {
+	config     Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]*rsa.PublicKey
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKeySet struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+}
+
+// Enabled implements Provider.
+func (p *provider) Enabled() bool {
+	return p.config.IssuerURL != "" && p.config.ClientID != ""
+}
+
+// NewAuthRequest implements Provider.
+func (p *provider) NewAuthRequest() (*AuthRequest, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	nonce, err := randomString(32)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	codeVerifier, err := randomString(64)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &AuthRequest{State: state, Nonce: nonce, CodeVerifier: codeVerifier}, nil
+}
+
+// AuthCodeURL implements Provider.
+func (p *provider) AuthCodeURL(ctx context.Context, authRequest *AuthRequest) (string, error) {
+	if !p.Enabled() {
+		return "", errs.Wrap(ErrOIDCDisabled)
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {authRequest.State},
+		"nonce":                 {authRequest.Nonce},
+		"code_challenge":        {CodeChallenge(authRequest.CodeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange implements Provider.
+func (p *provider) Exchange(ctx context.Context, code string, authRequest *AuthRequest) (*Identity, error) {
+	if !p.Enabled() {
+		return nil, errs.Wrap(ErrOIDCDisabled)
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {authRequest.CodeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+	defer res.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+
+	if res.StatusCode != http.StatusOK || token.IDToken == "" {
+		fmt.Printf("[oidc] token exchange failed: %d %s\n", res.StatusCode, token.Error)
+		return nil, errs.Wrap(ErrOIDCInvalidCode)
+	}
+
+	return p.verifyIDToken(ctx, discovery, token.IDToken, authRequest.Nonce)
+}
+
+// verifyIDToken checks the ID token signature, issuer, audience, expiry and nonce
+func (p *provider) verifyIDToken(ctx context.Context, discovery *discoveryDocument, rawIDToken, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg()}, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, discovery, kid)
+	})
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrOIDCInvalidIDToken)
+	}
+
+	now := time.Now()
+	valid := claims.VerifyIssuer(discovery.Issuer, true) &&
+		claims.VerifyAudience(p.config.ClientID, true) &&
+		claims.VerifyExpiresAt(now.Add(-clockSkewLeeway).Unix(), true) &&
+		claims.VerifyIssuedAt(now.Add(clockSkewLeeway).Unix(), true) &&
+		claims["nonce"] == nonce
+	if !valid {
+		return nil, errs.Wrap(ErrOIDCInvalidIDToken)
+	}
+
+	identity := &Identity{}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+	identity.Name, _ = claims["name"].(string)
+
+	if identity.Subject == "" {
+		return nil, errs.Wrap(ErrOIDCInvalidIDToken)
+	}
+
+	groupsClaim := p.config.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	switch groups := claims[groupsClaim].(type) {
+	case []interface{}:
+		for _, group := range groups {
+			if groupStr, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, groupStr)
+			}
+		}
+	case string:
+		identity.Groups = []string{groups}
+	}
+
+	identity.RoleType = p.mapRole(identity.Groups)
+
+	return identity, nil
+}
+
+// mapRole returns the highest priority role mapped from the identity provider groups
+func (p *provider) mapRole(groups []string) auth.RoleType {
+	var mappedRoles []auth.RoleType
+	for _, mapping := range p.config.RoleMapping {
+		if slices.Contains(groups, mapping.Group) {
+			mappedRoles = append(mappedRoles, mapping.RoleType)
+		}
+	}
+
+	for _, roleType := range rolePriority {
+		if slices.Contains(mappedRoles, roleType) {
+			return roleType
+		}
+	}
+
+	// Custom roles (e.g. auditor) take the precedence of their mapping order
+	if len(mappedRoles) > 0 {
+		return mappedRoles[0]
+	}
+
+	return ""
+}
+
+// discover fetches and caches the provider's discovery document
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery discoveryDocument
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.IssuerURL, "/")+discoveryPath, &discovery); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if discovery.Issuer != p.config.IssuerURL {
+		fmt.Printf("[oidc] issuer mismatch: expected %s, got %s\n", p.config.IssuerURL, discovery.Issuer)
+		return nil, errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// publicKey returns the signing key by its key ID, refreshing the key set once if the key is unknown
+func (p *provider) publicKey(ctx context.Context, discovery *discoveryDocument, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var keySet jsonWebKeySet
+	if err := p.getJSON(ctx, discovery.JWKSURI, &keySet); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, errs.Wrap(ErrOIDCInvalidIDToken)
+	}
+
+	return key, nil
+}
+
+func (p *provider) getJSON(ctx context.Context, endpoint string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(target); err != nil {
+		return errs.Wrap(ErrOIDCProviderUnavailable)
+	}
+
+	return nil
+}
+
+// CodeChallenge derives the PKCE S256 code challenge from a code verifier
+func CodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func randomString(length int) (string, error) {
+	randomBytes := make([]byte, length)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes)[:length], nil
+}
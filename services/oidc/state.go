@@ -0,0 +1,83 @@
+package oidc
+
+import (
+	"fmt"
+	"loan-service/config"
+	"loan-service/utils/errs"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	StateCookieKey = "OIDC-State"
+
+	// How long the user has to complete the login at the identity provider
+	DefaultStateTTL = time.Minute * 10
+)
+
+type stateClaims struct {
+	AuthRequest
+	jwt.StandardClaims
+}
+
+// StateCookie stores the auth request in a signed cookie so the callback can verify it without server-side sessions
+func StateCookie(authRequest *AuthRequest) (*http.Cookie, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, stateClaims{
+		AuthRequest: *authRequest,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(DefaultStateTTL).Unix(),
+		},
+	})
+
+	signedToken, err := token.SignedString([]byte(config.Data.AppSecret))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &http.Cookie{
+		Name:     StateCookieKey,
+		Value:    signedToken,
+		Path:     "/oidc",
+		Expires:  now.Add(DefaultStateTTL),
+		MaxAge:   int(DefaultStateTTL.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}, nil
+}
+
+func StateRemovalCookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     StateCookieKey,
+		Value:    "",
+		Path:     "/oidc",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ParseStateCookie returns the auth request stored in the cookie if it matches the returned state
+func ParseStateCookie(cookieValue, state string) (*AuthRequest, error) {
+	parsedToken, err := jwt.ParseWithClaims(cookieValue, &stateClaims{},
+		func(t *jwt.Token) (interface{}, error) {
+			return []byte(config.Data.AppSecret), nil
+		})
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, ErrOIDCInvalidState
+	}
+
+	claims, ok := parsedToken.Claims.(*stateClaims)
+	if !ok || state == "" || claims.State != state {
+		return nil, ErrOIDCInvalidState
+	}
+
+	return &claims.AuthRequest, nil
+}
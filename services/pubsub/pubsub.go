@@ -0,0 +1,76 @@
+package pubsub
+
+import "sync"
+
+// DefaultBufferSize is how many messages a subscriber can fall behind before newer messages are dropped for it
+const DefaultBufferSize = 16
+
+// Broker is an in-process publish/subscribe hub, messages are fanned out to every subscriber of a topic.
+// It does not span instances, which is fine while the service runs as a single instance.
+type Broker[K comparable, T any] struct {
+	mu          sync.RWMutex
+	subscribers map[K]map[chan T]struct{}
+	bufferSize  int
+}
+
+func NewBroker[K comparable, T any](bufferSize int) *Broker[K, T] {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	return &Broker[K, T]{
+		subscribers: map[K]map[chan T]struct{}{},
+		bufferSize:  bufferSize,
+	}
+}
+
+// Publish sends the message to the topic's current subscribers without blocking.
+// A subscriber whose buffer is full misses the message, so a slow client never holds up the publisher.
+func (b *Broker[K, T]) Publish(topic K, message T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the topic's messages, and a function to unsubscribe which closes it
+func (b *Broker[K, T]) Subscribe(topic K) (<-chan T, func()) {
+	ch := make(chan T, b.bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[chan T]struct{}{}
+	}
+	b.subscribers[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[topic], ch)
+			if len(b.subscribers[topic]) == 0 {
+				delete(b.subscribers, topic)
+			}
+
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// SubscriberCount returns the number of subscribers of the topic
+func (b *Broker[K, T]) SubscriberCount(topic K) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers[topic])
+}
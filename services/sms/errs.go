@@ -0,0 +1,15 @@
+package sms
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrSMSNotSent = errs.GeneralError{
+		StatusCode: http.StatusBadGateway,
+		ErrorCode:  "SMSNotSent",
+		Err:        errors.New("a problem occured while sending sms"),
+	}
+)
@@ -0,0 +1,42 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SMSService is an autogenerated mock type for the SMSService type
+type SMSService struct {
+	mock.Mock
+}
+
+// SendSMS provides a mock function with given fields: ctx, to, text
+func (_m *SMSService) SendSMS(ctx context.Context, to string, text string) error {
+	ret := _m.Called(ctx, to, text)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, to, text)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSMSService creates a new instance of SMSService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSMSService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SMSService {
+	mock := &SMSService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
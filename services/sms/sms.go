@@ -0,0 +1,33 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type SMSService interface {
+	// SendSMS sends a text message to a phone number in E.164 format, e.g. +6281234567890
+	SendSMS(ctx context.Context, to, text string) error
+}
+
+// NewLogSMSService writes messages to w instead of sending them, for local development
+func NewLogSMSService(w io.Writer) SMSService {
+	return &logSMSService{w: w}
+}
+
+type logSMSService struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// SendSMS implements SMSService.
+func (s *logSMSService) SendSMS(ctx context.Context, to, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, "[sms] to:%s text:%q\n", to, text)
+
+	return err
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"loan-service/utils/errs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twilioBaseURL = "https://api.twilio.com"
+
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// Sender phone number or alphanumeric sender ID
+	From string
+	// Defaults to the Twilio API, overridden in tests
+	BaseURL string
+}
+
+// NewTwilioSMSService sends messages with the Twilio Programmable Messaging API
+func NewTwilioSMSService(config TwilioConfig, httpClient *http.Client) SMSService {
+	if config.BaseURL == "" {
+		config.BaseURL = twilioBaseURL
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: time.Second * 10}
+	}
+
+	return &twilioSMSService{config, httpClient}
+}
+
+type twilioSMSService struct {
+	config     TwilioConfig
+	httpClient *http.Client
+}
+
+// SendSMS implements SMSService.
+func (s *twilioSMSService) SendSMS(ctx context.Context, to, text string) error {
+	form := url.Values{
+		"To":   {to},
+		"From": {s.config.From},
+		"Body": {text},
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.config.BaseURL, url.PathEscape(s.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrSMSNotSent)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		fmt.Printf("[sms failed] %d - %s\n", res.StatusCode, body)
+		return errs.Wrap(ErrSMSNotSent)
+	}
+
+	fmt.Printf("[sms sent] to:%s\n", to)
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultClamdTimeout   = 30 * time.Second
+	DefaultClamdChunkSize = 64 << 10
+)
+
+type ClamdConfig struct {
+	// tcp://host:port or unix:///path/to/clamd.sock
+	Address string
+	// Applies to the whole scan, including the connection
+	Timeout time.Duration
+	// Size of the chunks streamed to clamd, must stay below its StreamMaxLength
+	ChunkSize int
+}
+
+// NewClamdScanner streams files to a ClamAV daemon with the INSTREAM command. Zero config values fall back to the
+// defaults.
+func NewClamdScanner(config ClamdConfig) Scanner {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultClamdTimeout
+	}
+
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = DefaultClamdChunkSize
+	}
+
+	return &clamdScanner{config}
+}
+
+type clamdScanner struct {
+	config ClamdConfig
+}
+
+// Scan implements Scanner.
+func (s *clamdScanner) Scan(ctx context.Context, file io.Reader) (*ScanResult, error) {
+	network, address, ok := strings.Cut(s.config.Address, "://")
+	if !ok || (network != "tcp" && network != "unix") {
+		return nil, fmt.Errorf("invalid clamd address %q", s.config.Address)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := s.stream(conn, file); err != nil {
+		// clamd replies before closing the connection when it rejects the stream, e.g. above its size limit
+		if reply, replyErr := readClamdReply(reader); replyErr == nil {
+			return nil, fmt.Errorf("clamd: %s", reply)
+		}
+
+		return nil, err
+	}
+
+	reply, err := readClamdReply(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseClamdReply(reply)
+}
+
+// stream sends the file as length prefixed chunks, ending with an empty chunk
+func (s *clamdScanner) stream(conn net.Conn, file io.Reader) error {
+	// The z prefix makes replies null terminated
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 4+s.config.ChunkSize)
+	for {
+		n, err := io.ReadFull(file, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, writeErr := conn.Write(chunk[:4+n]); writeErr != nil {
+				return writeErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+
+	return err
+}
+
+func readClamdReply(reader *bufio.Reader) (string, error) {
+	reply, err := reader.ReadString(0)
+	if err != nil && reply == "" {
+		return "", err
+	}
+
+	return strings.TrimSpace(strings.TrimSuffix(reply, "\x00")), nil
+}
+
+// parseClamdReply reads replies such as "stream: OK" and "stream: Eicar-Test-Signature FOUND"
+func parseClamdReply(reply string) (*ScanResult, error) {
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return &ScanResult{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &ScanResult{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
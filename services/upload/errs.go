@@ -0,0 +1,51 @@
+package upload
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrFileNotUploaded = errs.GeneralError{
+		StatusCode: http.StatusBadGateway,
+		ErrorCode:  "FileNotUploaded",
+		Err:        errors.New("a problem occured while uploading file"),
+	}
+
+	ErrFileNotFound = errs.GeneralError{
+		StatusCode: http.StatusNotFound,
+		ErrorCode:  "FileNotFound",
+		Err:        errors.New("file not found"),
+	}
+
+	ErrInvalidFilename = errs.GeneralError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "InvalidFilename",
+		Err:        errors.New("invalid filename"),
+	}
+
+	ErrFileInfected = errs.GeneralError{
+		StatusCode: http.StatusUnprocessableEntity,
+		ErrorCode:  "FileInfected",
+		Err:        errors.New("the file was flagged as malware and has been quarantined"),
+	}
+
+	ErrFileNotScanned = errs.GeneralError{
+		StatusCode: http.StatusServiceUnavailable,
+		ErrorCode:  "FileNotScanned",
+		Err:        errors.New("the file could not be checked for malware, please try again later"),
+	}
+
+	ErrInvalidSignedURL = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "InvalidSignedURL",
+		Err:        errors.New("invalid download link"),
+	}
+
+	ErrSignedURLExpired = errs.GeneralError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  "SignedURLExpired",
+		Err:        errors.New("download link has expired, please request a new one"),
+	}
+)
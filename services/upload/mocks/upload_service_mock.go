@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
+
+	upload "loan-service/services/upload"
 )
 
 // UploadService is an autogenerated mock type for the UploadService type
@@ -13,23 +16,60 @@ type UploadService struct {
 	mock.Mock
 }
 
-// UploadFile provides a mock function with given fields: file, filename, contentType
-func (_m *UploadService) UploadFile(file io.Reader, filename string, contentType string) (string, error) {
-	ret := _m.Called(file, filename, contentType)
+// DownloadFile provides a mock function with given fields: ctx, key
+func (_m *UploadService) DownloadFile(ctx context.Context, key string) (io.ReadCloser, *upload.UploadedFile, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 io.ReadCloser
+	var r1 *upload.UploadedFile
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, *upload.UploadedFile, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *upload.UploadedFile); ok {
+		r1 = rf(ctx, key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*upload.UploadedFile)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UploadFile provides a mock function with given fields: ctx, file, filename, contentType
+func (_m *UploadService) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string) (*upload.UploadedFile, error) {
+	ret := _m.Called(ctx, file, filename, contentType)
 
-	var r0 string
+	var r0 *upload.UploadedFile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(io.Reader, string, string) (string, error)); ok {
-		return rf(file, filename, contentType)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) (*upload.UploadedFile, error)); ok {
+		return rf(ctx, file, filename, contentType)
 	}
-	if rf, ok := ret.Get(0).(func(io.Reader, string, string) string); ok {
-		r0 = rf(file, filename, contentType)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) *upload.UploadedFile); ok {
+		r0 = rf(ctx, file, filename, contentType)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*upload.UploadedFile)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(io.Reader, string, string) error); ok {
-		r1 = rf(file, filename, contentType)
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string, string) error); ok {
+		r1 = rf(ctx, file, filename, contentType)
 	} else {
 		r1 = ret.Error(1)
 	}
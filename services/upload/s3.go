@@ -0,0 +1,255 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"loan-service/utils/errs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service         = "s3"
+	s3SigningAlgo     = "AWS4-HMAC-SHA256"
+	s3AmzDateFormat   = "20060102T150405Z"
+	s3ScopeDateFormat = "20060102"
+	// SHA-256 of an empty body, signed for requests without a payload
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type S3Config struct {
+	// e.g. https://s3.ap-southeast-3.amazonaws.com, or http://localhost:9000 for MinIO
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prepended to every object key, e.g. "attachments"
+	Prefix string
+	// Addresses the bucket in the path instead of the host name, required by MinIO
+	UsePathStyle bool
+}
+
+// NewS3UploadService stores files in an S3-compatible bucket, requests are signed with AWS Signature Version 4
+func NewS3UploadService(config S3Config, httpClient *http.Client) UploadService {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: time.Second * 30}
+	}
+
+	return &s3UploadService{config, httpClient}
+}
+
+type s3UploadService struct {
+	config     S3Config
+	httpClient *http.Client
+}
+
+// UploadFile implements UploadService.
+func (u *s3UploadService) UploadFile(ctx context.Context, sourceFile io.Reader, filename, contentType string) (*UploadedFile, error) {
+	fmt.Printf("[upload started] %s (%s)\n", filename, contentType)
+
+	key, err := objectKey(u.config.Prefix, filename)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	// The payload hash is part of the signature, so the file is buffered before sending
+	var buf bytes.Buffer
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(&buf, hash), sourceFile); err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+	checksum := hash.Sum(nil)
+
+	endpoint, err := u.objectURL(key)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+
+	req.ContentLength = int64(buf.Len())
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(checksum))
+	// Verified by the storage against the received content
+	req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(checksum))
+	req.Header.Set("X-Amz-Meta-Sha256", hex.EncodeToString(checksum))
+	u.sign(req, time.Now().UTC())
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		fmt.Printf("[upload failed] %s %d - %s\n", key, res.StatusCode, body)
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+
+	fmt.Printf("[upload completed] %s/%s (%s)\n", u.config.Bucket, key, contentType)
+
+	return &UploadedFile{
+		Key:         key,
+		ContentType: contentType,
+		Size:        int64(buf.Len()),
+		Checksum:    hex.EncodeToString(checksum),
+	}, nil
+}
+
+// DownloadFile implements UploadService.
+func (u *s3UploadService) DownloadFile(ctx context.Context, key string) (io.ReadCloser, *UploadedFile, error) {
+	if key == "" {
+		return nil, nil, errs.Wrap(ErrInvalidFilename)
+	}
+
+	endpoint, err := u.objectURL(key)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	u.sign(req, time.Now().UTC())
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil, errs.Wrap(ErrFileNotFound)
+	}
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, nil, errs.Wrap("download of %s failed: %d - %s", key, res.StatusCode, body)
+	}
+
+	return res.Body, &UploadedFile{
+		Key:         key,
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        res.ContentLength,
+		Checksum:    res.Header.Get("X-Amz-Meta-Sha256"),
+	}, nil
+}
+
+func (u *s3UploadService) objectURL(key string) (*url.URL, error) {
+	endpoint, err := url.Parse(u.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.config.UsePathStyle {
+		endpoint.Path = "/" + u.config.Bucket + "/" + key
+	} else {
+		endpoint.Host = u.config.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + key
+	}
+	// Signed as is, so it must be encoded the way S3 canonicalizes it
+	endpoint.RawPath = uriEncodePath(endpoint.Path)
+
+	return endpoint, nil
+}
+
+// uriEncodePath percent-encodes everything but unreserved characters and slashes
+func uriEncodePath(p string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+
+	return encoded.String()
+}
+
+// sign adds the AWS Signature Version 4 authorization header, signing the host, content type and x-amz-* headers
+func (u *s3UploadService) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3AmzDateFormat)
+	scopeDate := now.Format(s3ScopeDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := strings.Join([]string{scopeDate, u.config.Region, s3Service, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgo,
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+u.config.SecretAccessKey), scopeDate)
+	signingKey = hmacSHA256(signingKey, u.config.Region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgo, u.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
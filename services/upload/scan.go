@@ -0,0 +1,80 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"loan-service/utils/errs"
+)
+
+// Scanner checks uploaded content for malware
+type Scanner interface {
+	// Scan reads the whole file, an error means the file could not be checked
+	Scan(ctx context.Context, file io.Reader) (*ScanResult, error)
+}
+
+type ScanResult struct {
+	Infected bool
+	// Name of the malware found, e.g. "Eicar-Test-Signature"
+	Signature string
+}
+
+// NewNoopScanner reports every file as clean, for local development and tests
+func NewNoopScanner() Scanner {
+	return &noopScanner{}
+}
+
+type noopScanner struct{}
+
+// Scan implements Scanner.
+func (s *noopScanner) Scan(ctx context.Context, file io.Reader) (*ScanResult, error) {
+	// Drained anyway, so the scanner behaves like a real one towards the caller
+	if _, err := io.Copy(io.Discard, file); err != nil {
+		return nil, err
+	}
+
+	return &ScanResult{}, nil
+}
+
+// NewScanningUploadService scans files before they are stored by the upload service. Infected files are stored by
+// the quarantine service instead, for review, and rejected with ErrFileInfected. Files that cannot be scanned are
+// rejected as well.
+func NewScanningUploadService(uploadService, quarantineService UploadService, scanner Scanner) UploadService {
+	return &scanningUploadService{uploadService, quarantineService, scanner}
+}
+
+type scanningUploadService struct {
+	UploadService
+	quarantineService UploadService
+	scanner           Scanner
+}
+
+// UploadFile implements UploadService.
+func (u *scanningUploadService) UploadFile(ctx context.Context, file io.Reader, filename, contentType string) (*UploadedFile, error) {
+	// The content is read twice, once by the scanner and once by the storage
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	result, err := u.scanner.Scan(ctx, bytes.NewReader(data))
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotScanned)
+	}
+
+	if !result.Infected {
+		return u.UploadService.UploadFile(ctx, bytes.NewReader(data), filename, contentType)
+	}
+
+	quarantined, err := u.quarantineService.UploadFile(ctx, bytes.NewReader(data), filename, contentType)
+	if err != nil {
+		// The file is rejected all the same, it is only lost for review
+		fmt.Println(errs.Wrap(err))
+	} else {
+		fmt.Printf("[upload quarantined] %s (%s)\n", quarantined.Key, result.Signature)
+	}
+
+	return nil, errs.Wrap(ErrFileInfected)
+}
@@ -0,0 +1,57 @@
+package upload
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"loan-service/config"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	QueryExpires   = "expires"
+	QuerySignature = "signature"
+)
+
+// SignURL returns an absolute URL to the path, valid until the TTL passes.
+// The download endpoint still checks that the requester can see the file, the signature only keeps links from being
+// guessed or reused indefinitely.
+func SignURL(path string, ttl time.Duration, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(ttl).Unix(), 10)
+
+	query := url.Values{
+		QueryExpires:   {expires},
+		QuerySignature: {urlSignature(path, expires)},
+	}
+
+	return fmt.Sprintf("%s%s?%s", strings.TrimSuffix(config.Data.AppBaseURL, "/"), path, query.Encode())
+}
+
+// VerifyURL checks the signature and expiry of a URL signed with SignURL
+func VerifyURL(path, expires, signature string, now time.Time) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignedURL
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(urlSignature(path, expires))) {
+		return ErrInvalidSignedURL
+	}
+
+	if now.Unix() > expiresAt {
+		return ErrSignedURLExpired
+	}
+
+	return nil
+}
+
+func urlSignature(path, expires string) string {
+	mac := hmac.New(sha256.New, []byte(config.Data.AppSecret))
+	mac.Write([]byte(path + "\n" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
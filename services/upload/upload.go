@@ -1,39 +1,121 @@
 package upload
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"loan-service/utils/errs"
+	"loan-service/utils/tern"
+	"mime"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 )
 
-// TODO: Implement actual cloud upload
 type UploadService interface {
-	UploadFile(file io.Reader, filename, contentType string) (string, error)
+	// UploadFile stores the file under the configured prefix, and returns its key and metadata
+	UploadFile(ctx context.Context, file io.Reader, filename, contentType string) (*UploadedFile, error)
+	// DownloadFile opens a stored file by its key, the caller must close it
+	DownloadFile(ctx context.Context, key string) (io.ReadCloser, *UploadedFile, error)
 }
 
-func NewUploadService() UploadService {
-	return &uploadService{}
+// UploadedFile describes a stored file, the key is what identifies it in the storage backend
+type UploadedFile struct {
+	Key         string
+	ContentType string
+	Size        int64
+	// Hex encoded SHA-256 of the content
+	Checksum string
 }
 
-type uploadService struct{}
+// NewDiskUploadService writes files to a local directory, for local development
+func NewDiskUploadService(dir, prefix string) UploadService {
+	return &diskUploadService{dir: dir, prefix: prefix}
+}
+
+type diskUploadService struct {
+	dir    string
+	prefix string
+}
 
-func (u *uploadService) UploadFile(sourceFile io.Reader, filename, contentType string) (string, error) {
+// UploadFile implements UploadService.
+func (u *diskUploadService) UploadFile(ctx context.Context, sourceFile io.Reader, filename, contentType string) (*UploadedFile, error) {
 	fmt.Printf("[upload started] %s (%s)\n", filename, contentType)
 
-	// Create new file in public dir
-	destinationFile, err := os.Create(path.Join("tmp", filename))
+	key, err := objectKey(u.prefix, filename)
 	if err != nil {
-		fmt.Println(err)
+		return nil, errs.Wrap(err)
+	}
+
+	destinationPath := filepath.Join(u.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(destinationPath), 0o755); err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+
+	destinationFile, err := os.Create(destinationPath)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return nil, errs.Wrap(ErrFileNotUploaded)
 	}
 	defer destinationFile.Close()
 
-	// Copy source file to destination
-	if _, err := io.Copy(destinationFile, sourceFile); err != nil {
-		fmt.Println(err)
+	// Checksum the content while copying it to the destination
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(destinationFile, hash), sourceFile)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		_ = os.Remove(destinationPath)
+		return nil, errs.Wrap(ErrFileNotUploaded)
+	}
+
+	fmt.Printf("[upload completed] %s (%s)\n", key, contentType)
+
+	return &UploadedFile{
+		Key:         key,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// DownloadFile implements UploadService.
+func (u *diskUploadService) DownloadFile(ctx context.Context, key string) (io.ReadCloser, *UploadedFile, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return nil, nil, errs.Wrap(ErrInvalidFilename)
 	}
 
-	fmt.Printf("[upload completed] %s (%s)\n", filename, contentType)
+	file, err := os.Open(filepath.Join(u.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, errs.Wrap(ErrFileNotFound)
+	} else if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, errs.Wrap(err)
+	}
+
+	// The content type is not kept on disk, so it is derived from the extension set upon upload
+	return file, &UploadedFile{
+		Key:         key,
+		ContentType: tern.String(mime.TypeByExtension(path.Ext(key)), "application/octet-stream"),
+		Size:        info.Size(),
+	}, nil
+}
+
+// objectKey joins the prefix and filename, rejecting filenames escaping the prefix
+func objectKey(prefix, filename string) (string, error) {
+	if filename == "" || strings.Contains(filename, "..") || strings.HasPrefix(filename, "/") {
+		return "", ErrInvalidFilename
+	}
 
-	return destinationFile.Name(), nil
+	return strings.TrimPrefix(path.Join(prefix, filename), "/"), nil
 }
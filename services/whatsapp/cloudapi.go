@@ -0,0 +1,117 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"loan-service/utils/errs"
+	"loan-service/utils/i18n"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const cloudAPIBaseURL = "https://graph.facebook.com/v19.0"
+
+type CloudAPIConfig struct {
+	PhoneNumberID string
+	AccessToken   string
+	// Defaults to the Meta Graph API, overridden in tests
+	BaseURL string
+}
+
+// NewCloudAPIWhatsAppService sends template messages with the WhatsApp Business Cloud API
+func NewCloudAPIWhatsAppService(config CloudAPIConfig, httpClient *http.Client) WhatsAppService {
+	if config.BaseURL == "" {
+		config.BaseURL = cloudAPIBaseURL
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: time.Second * 10}
+	}
+
+	return &cloudAPIWhatsAppService{config, httpClient}
+}
+
+type cloudAPIWhatsAppService struct {
+	config     CloudAPIConfig
+	httpClient *http.Client
+}
+
+type cloudAPITemplateParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type cloudAPITemplateComponent struct {
+	Type       string                      `json:"type"`
+	Parameters []cloudAPITemplateParameter `json:"parameters"`
+}
+
+type cloudAPITemplate struct {
+	Name     string `json:"name"`
+	Language struct {
+		Code string `json:"code"`
+	} `json:"language"`
+	Components []cloudAPITemplateComponent `json:"components"`
+}
+
+type cloudAPIMessage struct {
+	MessagingProduct string           `json:"messaging_product"`
+	To               string           `json:"to"`
+	Type             string           `json:"type"`
+	Template         cloudAPITemplate `json:"template"`
+}
+
+// SendTemplateMessage implements WhatsAppService.
+func (s *cloudAPIWhatsAppService) SendTemplateMessage(ctx context.Context, to, template string, locale i18n.Locale, text string) error {
+	message := cloudAPIMessage{
+		MessagingProduct: "whatsapp",
+		// The API expects the number without the leading plus
+		To:   strings.TrimPrefix(to, "+"),
+		Type: "template",
+		Template: cloudAPITemplate{
+			Name: template,
+			Components: []cloudAPITemplateComponent{{
+				Type: "body",
+				// Template variables cannot contain new lines
+				Parameters: []cloudAPITemplateParameter{{Type: "text", Text: strings.Join(strings.Fields(text), " ")}},
+			}},
+		},
+	}
+	message.Template.Language.Code = string(i18n.Resolve(locale))
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/messages", s.config.BaseURL, url.PathEscape(s.config.PhoneNumberID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.config.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		fmt.Println(errs.Wrap(err))
+		return errs.Wrap(ErrWhatsAppNotSent)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		fmt.Printf("[whatsapp failed] %d - %s\n", res.StatusCode, body)
+		return errs.Wrap(ErrWhatsAppNotSent)
+	}
+
+	fmt.Printf("[whatsapp sent] to:%s template:%s\n", to, template)
+
+	return nil
+}
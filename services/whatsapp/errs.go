@@ -0,0 +1,15 @@
+package whatsapp
+
+import (
+	"errors"
+	"loan-service/utils/errs"
+	"net/http"
+)
+
+var (
+	ErrWhatsAppNotSent = errs.GeneralError{
+		StatusCode: http.StatusBadGateway,
+		ErrorCode:  "WhatsAppNotSent",
+		Err:        errors.New("a problem occured while sending whatsapp message"),
+	}
+)
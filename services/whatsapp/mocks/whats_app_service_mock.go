@@ -0,0 +1,43 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	i18n "loan-service/utils/i18n"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WhatsAppService is an autogenerated mock type for the WhatsAppService type
+type WhatsAppService struct {
+	mock.Mock
+}
+
+// SendTemplateMessage provides a mock function with given fields: ctx, to, template, locale, text
+func (_m *WhatsAppService) SendTemplateMessage(ctx context.Context, to string, template string, locale i18n.Locale, text string) error {
+	ret := _m.Called(ctx, to, template, locale, text)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, i18n.Locale, string) error); ok {
+		r0 = rf(ctx, to, template, locale, text)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWhatsAppService creates a new instance of WhatsAppService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWhatsAppService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WhatsAppService {
+	mock := &WhatsAppService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,35 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"loan-service/utils/i18n"
+	"sync"
+)
+
+type WhatsAppService interface {
+	// SendTemplateMessage sends a pre-approved message template to a phone number in E.164 format.
+	// Business initiated messages must use templates, each template is expected to have a single body variable holding text.
+	SendTemplateMessage(ctx context.Context, to, template string, locale i18n.Locale, text string) error
+}
+
+// NewLogWhatsAppService writes messages to w instead of sending them, for local development
+func NewLogWhatsAppService(w io.Writer) WhatsAppService {
+	return &logWhatsAppService{w: w}
+}
+
+type logWhatsAppService struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// SendTemplateMessage implements WhatsAppService.
+func (s *logWhatsAppService) SendTemplateMessage(ctx context.Context, to, template string, locale i18n.Locale, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, "[whatsapp] to:%s template:%s locale:%s text:%q\n", to, template, locale, text)
+
+	return err
+}
@@ -0,0 +1,225 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"loan-service/app"
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	apiKeyModule "loan-service/modules/apikeys"
+	"loan-service/services/auth"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type apiKeyIntegrationTestSuite struct {
+	suite.Suite
+	db            *gorm.DB
+	rest          *echo.Echo
+	apiKeyUsecase models.APIKeyUsecase
+	models        []interface{}
+	injector      *do.Injector
+}
+
+func TestIntegrationAPIKey(t *testing.T) {
+	suite.Run(t, new(apiKeyIntegrationTestSuite))
+}
+
+func (s *apiKeyIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.injector = app.SetupInjections(s.db, s.rest, nil, nil, nil, nil)
+
+	s.apiKeyUsecase = do.MustInvoke[models.APIKeyUsecase](s.injector)
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+		&models.APIKey{},
+	}
+}
+
+func (s *apiKeyIntegrationTestSuite) TestIntegration_APIKeyAuth() {
+	ctx := context.Background()
+	superuser := &models.User{Model: gorm.Model{ID: 1}, Role: models.Role{RoleType: auth.RoleTypeSuperuser}}
+
+	serviceAccount, err := s.apiKeyUsecase.CreateServiceAccount(ctx, superuser, "Bank reconciliation", auth.RoleTypeStaff)
+	s.Require().NoError(err)
+
+	_, activeKey, err := s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, superuser, "active",
+		[]auth.Permission{auth.PermissionLoanViewAll}, nil)
+	s.Require().NoError(err)
+
+	revokedAPIKey, revokedKey, err := s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, superuser, "revoked",
+		[]auth.Permission{auth.PermissionLoanViewAll}, nil)
+	s.Require().NoError(err)
+	_, err = s.apiKeyUsecase.RevokeAPIKey(ctx, revokedAPIKey.ID)
+	s.Require().NoError(err)
+
+	_, _, err = s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, superuser, "too broad",
+		[]auth.Permission{auth.PermissionRoleManage}, nil)
+	s.ErrorIs(err, apiKeyModule.ErrPermissionNotGranted)
+
+	// Only superusers mint keys for privileged service accounts
+	staff := &models.User{Model: gorm.Model{ID: 2}, Role: models.Role{RoleType: auth.RoleTypeStaff}}
+	_, _, err = s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, staff, "minted by staff",
+		[]auth.Permission{auth.PermissionLoanViewAll}, nil)
+	s.ErrorIs(err, apiKeyModule.ErrPrivilegedRoleForbidden)
+
+	expiresAt := time.Now().Add(time.Hour)
+	expiringAPIKey, expiredKey, err := s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, superuser, "expired",
+		[]auth.Permission{auth.PermissionLoanViewAll}, &expiresAt)
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Model(expiringAPIKey).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	tests := []struct {
+		name     string
+		key      string
+		wantCode int
+	}{
+		{
+			name:     "allows request given active key with required permission",
+			key:      activeKey,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "rejects request given revoked key",
+			key:      revokedKey,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "rejects request given expired key",
+			key:      expiredKey,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "rejects request given unknown key",
+			key:      strings.Join(strings.Split(activeKey, "_")[:2], "_") + "_deadbeef",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			req := httptest.NewRequest(http.MethodGet, "/loans", nil)
+			req.Header.Set(auth.APIKeyHeader, tt.key)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+
+			// Do test and assert
+			handler := authMiddleware.APIKeyAuth(s.apiKeyUsecase)(
+				authMiddleware.RequirePermission(auth.PermissionLoanViewAll)(
+					func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+				),
+			)
+			err := handler(ctx)
+
+			assert.NoError(err)
+			assert.Equal(tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func (s *apiKeyIntegrationTestSuite) TestIntegration_CreateServiceAccount() {
+	ctx := context.Background()
+	var fieldValidatorPermissions []models.Permission
+	for _, code := range auth.DefaultRolePermissions[auth.RoleTypeFieldValidator] {
+		fieldValidatorPermissions = append(fieldValidatorPermissions, models.Permission{Code: code})
+	}
+
+	// Staff holding every field validator permission, and staff holding only one of them
+	manager := &models.User{Model: gorm.Model{ID: 2}, Role: models.Role{
+		RoleType:    auth.RoleTypeStaff,
+		Permissions: fieldValidatorPermissions,
+	}}
+	narrowManager := &models.User{Model: gorm.Model{ID: 3}, Role: models.Role{
+		RoleType:    auth.RoleTypeStaff,
+		Permissions: fieldValidatorPermissions[:1],
+	}}
+
+	_, err := s.apiKeyUsecase.CreateServiceAccount(ctx, narrowManager, "Field app", auth.RoleTypeFieldValidator)
+	s.ErrorIs(err, apiKeyModule.ErrRoleExceedsPermissions)
+
+	serviceAccount, err := s.apiKeyUsecase.CreateServiceAccount(ctx, manager, "Field app", auth.RoleTypeFieldValidator)
+	s.Require().NoError(err)
+
+	// Keys act as the service account, so the narrower actor cannot mint one even when scoped to what they hold
+	_, _, err = s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, narrowManager, "minted by narrow manager",
+		[]auth.Permission{fieldValidatorPermissions[0].Code}, nil)
+	s.ErrorIs(err, apiKeyModule.ErrRoleExceedsPermissions)
+
+	_, _, err = s.apiKeyUsecase.CreateAPIKey(ctx, serviceAccount.ID, manager, "minted by manager",
+		[]auth.Permission{fieldValidatorPermissions[0].Code}, nil)
+	s.NoError(err)
+}
+
+func (s *apiKeyIntegrationTestSuite) SeedData() {
+	var permissions []models.Permission
+	for _, code := range auth.AllPermissions {
+		permissions = append(permissions, models.Permission{Code: code, Description: auth.PermissionDescriptions[code]})
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert permissions: %v", err))
+	}
+
+	roles := []models.Role{
+		{Name: "Superuser", RoleType: auth.RoleTypeSuperuser, Permissions: permissions},
+		{Name: "Staff", RoleType: auth.RoleTypeStaff, Permissions: rolePermissions(permissions, auth.RoleTypeStaff)},
+		{
+			Name:        "Field Validator",
+			RoleType:    auth.RoleTypeFieldValidator,
+			Permissions: rolePermissions(permissions, auth.RoleTypeFieldValidator),
+		},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
+	}
+}
+
+// rolePermissions returns the permissions granted to the role type by default
+func rolePermissions(permissions []models.Permission, roleType auth.RoleType) []models.Permission {
+	var result []models.Permission
+	for _, permission := range permissions {
+		if auth.HasPermission(auth.DefaultRolePermissions[roleType], permission.Code) {
+			result = append(result, permission)
+		}
+	}
+
+	return result
+}
+
+func (s *apiKeyIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *apiKeyIntegrationTestSuite) TearDownTest() {
+	for _, model := range append(s.models, "role_permissions", "api_key_permissions") {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
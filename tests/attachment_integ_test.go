@@ -0,0 +1,249 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"loan-service/services/attachment"
+	"strings"
+	"testing"
+	"time"
+
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentIntegrationTestSuite struct {
+	suite.Suite
+	pipeline attachment.Pipeline
+}
+
+func TestIntegrationAttachment(t *testing.T) {
+	suite.Run(t, new(attachmentIntegrationTestSuite))
+}
+
+func (s *attachmentIntegrationTestSuite) SetupSuite() {
+	s.pipeline = attachment.NewPipeline(
+		64<<10,
+		attachment.NewImageProcessor(attachment.ImageConfig{MaxWidth: 200, MaxHeight: 200, ThumbnailSize: 10}),
+		attachment.NewPassthroughProcessor(map[string]string{"application/pdf": ".pdf"}),
+	)
+}
+
+func (s *attachmentIntegrationTestSuite) TestIntegration_ProcessPhoto() {
+	assert := _assert.New(s.T())
+
+	// Left half red and right half blue, taken with the phone rotated
+	photo := newTestPhoto(s.T(), 40, 20, newTestExif(6, "2024:10:01 09:30:00", "+07:00", "S", [3]uint32{6, 12, 30}, "E",
+		[3]uint32{106, 50, 45}))
+
+	result, err := s.pipeline.Process(context.Background(), bytes.NewReader(photo), "image/jpeg", "image/png")
+	s.Require().NoError(err)
+
+	assert.Equal("image/jpeg", result.Metadata.ContentType)
+	assert.Equal(int64(len(photo)), result.Metadata.Size)
+	s.Require().NotNil(result.Metadata.CapturedAt)
+	assert.True(time.Date(2024, 10, 1, 2, 30, 0, 0, time.UTC).Equal(*result.Metadata.CapturedAt))
+	s.Require().NotNil(result.Metadata.Latitude)
+	s.Require().NotNil(result.Metadata.Longitude)
+	assert.InDelta(-6.208333, *result.Metadata.Latitude, 0.00001)
+	assert.InDelta(106.845833, *result.Metadata.Longitude, 0.00001)
+
+	// Stored upright and without EXIF
+	original := result.Variant(attachment.VariantOriginal)
+	s.Require().NotNil(original)
+	assert.Equal(".jpg", original.Extension)
+	assert.NotContains(string(original.Data), "Exif")
+	assert.Equal(20, result.Metadata.Width)
+	assert.Equal(40, result.Metadata.Height)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(original.Data))
+	s.Require().NoError(err)
+	assert.Equal(image.Rect(0, 0, 20, 40), decoded.Bounds())
+	top, _, topBlue, _ := decoded.At(10, 5).RGBA()
+	bottom, _, bottomBlue, _ := decoded.At(10, 35).RGBA()
+	assert.Greater(top, topBlue)
+	assert.Greater(bottomBlue, bottom)
+
+	thumbnail := result.Variant(attachment.VariantThumbnail)
+	s.Require().NotNil(thumbnail)
+	assert.Equal("image/jpeg", thumbnail.ContentType)
+	thumbnailConfig, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail.Data))
+	s.Require().NoError(err)
+	assert.Equal(5, thumbnailConfig.Width)
+	assert.Equal(10, thumbnailConfig.Height)
+}
+
+func (s *attachmentIntegrationTestSuite) TestIntegration_ProcessPNG() {
+	assert := _assert.New(s.T())
+
+	var buf bytes.Buffer
+	s.Require().NoError(png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+
+	result, err := s.pipeline.Process(context.Background(), &buf)
+	s.Require().NoError(err)
+	assert.Equal("image/png", result.Metadata.ContentType)
+	assert.Equal(".png", result.Variant(attachment.VariantOriginal).Extension)
+	assert.Nil(result.Metadata.CapturedAt)
+	assert.Nil(result.Metadata.Latitude)
+	// Already smaller than a thumbnail
+	assert.NotNil(result.Variant(attachment.VariantThumbnail))
+}
+
+func (s *attachmentIntegrationTestSuite) TestIntegration_RejectAttachments() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	_, err := s.pipeline.Process(ctx, bytes.NewReader(newTestPhoto(s.T(), 201, 20, nil)))
+	assert.ErrorIs(err, attachment.ErrImageTooLarge)
+
+	_, err = s.pipeline.Process(ctx, strings.NewReader(strings.Repeat("a", 64<<10+1)))
+	assert.ErrorIs(err, attachment.ErrFileTooLarge)
+
+	_, err = s.pipeline.Process(ctx, strings.NewReader("just some text"))
+	assert.ErrorIs(err, attachment.ErrUnsupportedFileType)
+
+	// Corrupted after the header
+	photo := newTestPhoto(s.T(), 40, 20, nil)
+	_, err = s.pipeline.Process(ctx, bytes.NewReader(photo[:len(photo)/2]))
+	assert.ErrorIs(err, attachment.ErrInvalidImage)
+
+	// Documents are only accepted where allowed
+	pdf := "%PDF-1.4\n%%EOF\n"
+	_, err = s.pipeline.Process(ctx, strings.NewReader(pdf), "image/jpeg", "image/png")
+	assert.ErrorIs(err, attachment.ErrUnsupportedFileType)
+
+	result, err := s.pipeline.Process(ctx, strings.NewReader(pdf))
+	s.Require().NoError(err)
+	assert.Equal(pdf, string(result.Variant(attachment.VariantOriginal).Data))
+	assert.Nil(result.Variant(attachment.VariantThumbnail))
+}
+
+// newTestPhoto returns a JPEG with a red left half and a blue right half, with the EXIF segment if any
+func newTestPhoto(t *testing.T, width, height int, exif []byte) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := buf.Bytes()
+	if exif == nil {
+		return encoded
+	}
+
+	// APP1 segment right after the start of image marker
+	segment := append([]byte("Exif\x00\x00"), exif...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	photo := append([]byte{}, encoded[:2]...)
+	photo = append(photo, app1...)
+	photo = append(photo, segment...)
+
+	return append(photo, encoded[2:]...)
+}
+
+type testExifEntry struct {
+	tag       uint16
+	fieldType uint16
+	count     uint32
+	data      []byte
+}
+
+// newTestExif builds a little endian TIFF structure with the orientation, capture time and GPS coordinates
+func newTestExif(orientation uint16, dateTime, offset, latRef string, lat [3]uint32, lonRef string, lon [3]uint32) []byte {
+	order := binary.LittleEndian
+	short := func(v uint16) []byte { return order.AppendUint16(nil, v) }
+	long := func(v uint32) []byte { return order.AppendUint32(nil, v) }
+	ascii := func(v string) testExifEntry {
+		return testExifEntry{fieldType: 2, count: uint32(len(v) + 1), data: []byte(v + "\x00")}
+	}
+	rationals := func(values [3]uint32) testExifEntry {
+		var data []byte
+		for _, v := range values {
+			data = append(data, long(v)...)
+			data = append(data, long(1)...)
+		}
+
+		return testExifEntry{fieldType: 5, count: 3, data: data}
+	}
+	withTag := func(tag uint16, entry testExifEntry) testExifEntry {
+		entry.tag = tag
+		return entry
+	}
+
+	ifdSize := func(entries []testExifEntry) int {
+		size := 2 + len(entries)*12 + 4
+		for _, entry := range entries {
+			if len(entry.data) > 4 {
+				size += len(entry.data)
+			}
+		}
+
+		return size
+	}
+
+	exifIFD := []testExifEntry{
+		withTag(0x9003, ascii(dateTime)),
+		withTag(0x9011, ascii(offset)),
+	}
+	gpsIFD := []testExifEntry{
+		withTag(0x01, ascii(latRef)),
+		withTag(0x02, rationals(lat)),
+		withTag(0x03, ascii(lonRef)),
+		withTag(0x04, rationals(lon)),
+	}
+
+	// IFD0 has 3 entries with inline values
+	ifd0Offset := 8
+	exifOffset := ifd0Offset + 2 + 3*12 + 4
+	gpsOffset := exifOffset + ifdSize(exifIFD)
+	ifd0 := []testExifEntry{
+		{tag: 0x0112, fieldType: 3, count: 1, data: short(orientation)},
+		{tag: 0x8769, fieldType: 4, count: 1, data: long(uint32(exifOffset))},
+		{tag: 0x8825, fieldType: 4, count: 1, data: long(uint32(gpsOffset))},
+	}
+
+	tiff := []byte("II")
+	tiff = append(tiff, short(42)...)
+	tiff = append(tiff, long(uint32(ifd0Offset))...)
+
+	for _, entries := range [][]testExifEntry{ifd0, exifIFD, gpsIFD} {
+		start := len(tiff)
+		dataOffset := start + 2 + len(entries)*12 + 4
+		var data []byte
+
+		tiff = append(tiff, short(uint16(len(entries)))...)
+		for _, entry := range entries {
+			tiff = append(tiff, short(entry.tag)...)
+			tiff = append(tiff, short(entry.fieldType)...)
+			tiff = append(tiff, long(entry.count)...)
+			if len(entry.data) <= 4 {
+				tiff = append(tiff, append(entry.data, make([]byte, 4-len(entry.data))...)...)
+			} else {
+				tiff = append(tiff, long(uint32(dataOffset+len(data)))...)
+				data = append(data, entry.data...)
+			}
+		}
+
+		tiff = append(tiff, long(0)...)
+		tiff = append(tiff, data...)
+	}
+
+	return tiff
+}
@@ -0,0 +1,255 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"loan-service/services/email"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeSMTPServer accepts a single plain SMTP conversation with AUTH PLAIN and records the message
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	auth     string
+	mailFrom string
+	rcptTo   string
+	data     string
+}
+
+func newFakeSMTPServer() *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	server := &fakeSMTPServer{listener: listener}
+	go server.serve()
+
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+			s.mu.Unlock()
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.mailFrom = line
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcptTo = line
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+type emailIntegrationTestSuite struct {
+	suite.Suite
+	smtpServer *fakeSMTPServer
+}
+
+func TestIntegrationEmail(t *testing.T) {
+	suite.Run(t, new(emailIntegrationTestSuite))
+}
+
+func (s *emailIntegrationTestSuite) SetupSuite() {
+	s.smtpServer = newFakeSMTPServer()
+}
+
+func (s *emailIntegrationTestSuite) TearDownSuite() {
+	s.smtpServer.listener.Close()
+}
+
+func (s *emailIntegrationTestSuite) TestIntegration_SMTPSendMail() {
+	assert := _assert.New(s.T())
+	host, port, _ := net.SplitHostPort(s.smtpServer.listener.Addr().String())
+
+	emailSvc := email.NewSMTPEmailService(email.SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: "mailer",
+		Password: "s3cret",
+		TLSMode:  email.SMTPTLSModeNone,
+	}, "loanservice.io@proton.me", "noreply - loanservice.io")
+
+	err := emailSvc.SendMail(
+		context.Background(),
+		"Pinjaman telah didanai sepenuhnya!",
+		email.Body{HTML: "<h1>Halo Larry</h1>", Text: "Halo Larry"},
+		sgmail.Email{Name: emailSvc.DefaultSenderName(), Address: emailSvc.DefaultSenderAddress()},
+		sgmail.Email{Name: "Larry Fink", Address: "larryfink@blackrock.com"},
+		&email.AttachmentOpts{
+			File:        strings.NewReader("%PDF-1.4 agreement"),
+			ContentType: email.AttachmentTypePDF,
+			Filename:    "Loan_Agreement_Letter.pdf",
+		},
+	)
+	s.Require().NoError(err)
+
+	s.smtpServer.mu.Lock()
+	defer s.smtpServer.mu.Unlock()
+
+	authBytes, _ := base64.StdEncoding.DecodeString(s.smtpServer.auth)
+	assert.Equal("\x00mailer\x00s3cret", string(authBytes))
+	assert.Equal("MAIL FROM:<loanservice.io@proton.me>", strings.SplitN(s.smtpServer.mailFrom, " BODY", 2)[0])
+	assert.Equal("RCPT TO:<larryfink@blackrock.com>", s.smtpServer.rcptTo)
+
+	s.assertMessage(s.smtpServer.data, "Pinjaman telah didanai sepenuhnya!", "Halo Larry", "<h1>Halo Larry</h1>",
+		"Loan_Agreement_Letter.pdf", "%PDF-1.4 agreement")
+}
+
+func (s *emailIntegrationTestSuite) TestIntegration_FileDropSendMail() {
+	assert := _assert.New(s.T())
+	dir := s.T().TempDir()
+
+	emailSvc := email.NewFileDropEmailService(dir, "loanservice.io@proton.me", "noreply - loanservice.io")
+
+	err := emailSvc.SendMail(
+		context.Background(),
+		"You have been invited to LoanService.io",
+		email.Body{HTML: "<p>Welcome</p>", Text: "Welcome"},
+		sgmail.Email{Name: emailSvc.DefaultSenderName(), Address: emailSvc.DefaultSenderAddress()},
+		sgmail.Email{Name: "Olaf Scholz", Address: "olaf@loanservice.io"},
+		nil,
+	)
+	s.Require().NoError(err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	s.Require().NoError(err)
+	s.Require().Len(files, 1)
+	assert.Contains(files[0], "olaf@loanservice.io")
+
+	content, err := os.ReadFile(files[0])
+	s.Require().NoError(err)
+
+	s.assertMessage(string(content), "You have been invited to LoanService.io", "Welcome", "<p>Welcome</p>", "", "")
+}
+
+// assertMessage parses the MIME message and checks its subject, alternative bodies and attachment
+func (s *emailIntegrationTestSuite) assertMessage(raw, wantSubject, wantText, wantHTML, wantFilename, wantAttachment string) {
+	assert := _assert.New(s.T())
+
+	message, err := mail.ReadMessage(strings.NewReader(raw))
+	s.Require().NoError(err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	s.Require().NoError(err)
+	assert.Equal(wantSubject, subject)
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.Require().NoError(err)
+	assert.Equal("multipart/mixed", mediaType)
+
+	var gotText, gotHTML, gotFilename, gotAttachment string
+	mixedReader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := mixedReader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+
+		partType, partParams, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType != "multipart/alternative" {
+			gotFilename = part.FileName()
+			encoded, _ := io.ReadAll(part)
+			decoded, _ := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+			gotAttachment = string(decoded)
+			continue
+		}
+
+		alternativeReader := multipart.NewReader(part, partParams["boundary"])
+		for {
+			alternative, err := alternativeReader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			s.Require().NoError(err)
+
+			// multipart.Reader decodes quoted-printable transparently
+			content, _ := io.ReadAll(alternative)
+			if strings.HasPrefix(alternative.Header.Get("Content-Type"), "text/plain") {
+				gotText = string(content)
+			} else {
+				gotHTML = string(content)
+			}
+		}
+	}
+
+	assert.Equal(wantText, gotText)
+	assert.Equal(wantHTML, gotHTML)
+	assert.Equal(wantFilename, gotFilename)
+	assert.Equal(wantAttachment, gotAttachment)
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"loan-service/services/email"
+	"loan-service/utils/i18n"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_notificationHandlers "loan-service/modules/notifications/handlers"
+
+	"github.com/labstack/echo/v4"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type emailTemplateIntegrationTestSuite struct {
+	suite.Suite
+	rest                 *echo.Echo
+	emailTemplateHandler *_notificationHandlers.EmailTemplateHandler
+}
+
+func TestIntegrationEmailTemplate(t *testing.T) {
+	suite.Run(t, new(emailTemplateIntegrationTestSuite))
+}
+
+func (s *emailTemplateIntegrationTestSuite) SetupSuite() {
+	s.rest = SetupEcho()
+	s.emailTemplateHandler = &_notificationHandlers.EmailTemplateHandler{}
+}
+
+func (s *emailTemplateIntegrationTestSuite) TestIntegration_RenderAllTemplates() {
+	for _, template := range email.Templates {
+		for _, locale := range i18n.SupportedLocales {
+			s.Run(string(template)+"."+string(locale), func() {
+				assert := _assert.New(s.T())
+
+				rendered, err := email.Render(template, locale, email.TemplateSamples[template])
+
+				s.Require().NoError(err)
+				assert.NotEmpty(rendered.Subject)
+				assert.NotContains(rendered.Subject, "\n")
+				assert.NotEmpty(rendered.Body.HTML)
+				assert.NotEmpty(rendered.Body.Text)
+				assert.NotContains(rendered.Body.Text, "<p>")
+			})
+		}
+	}
+}
+
+func (s *emailTemplateIntegrationTestSuite) TestIntegration_RenderShortTemplates() {
+	for _, template := range email.ShortTemplates {
+		for _, locale := range i18n.SupportedLocales {
+			s.Run(string(template)+"."+string(locale), func() {
+				assert := _assert.New(s.T())
+
+				text, err := email.RenderShort(template, locale, email.TemplateSamples[template])
+
+				s.Require().NoError(err)
+				assert.NotEmpty(text)
+				assert.NotContains(text, "\n")
+				assert.LessOrEqual(len(text), 320)
+			})
+		}
+	}
+
+	_, err := email.RenderShort(email.TemplateInvitation, i18n.DefaultLocale, email.TemplateSamples[email.TemplateInvitation])
+	_assert.ErrorIs(s.T(), err, email.ErrTemplateNotFound)
+}
+
+func (s *emailTemplateIntegrationTestSuite) TestIntegration_PreviewEmailTemplate() {
+	tests := []struct {
+		name     string
+		template string
+		query    string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "returns indonesian subject given id locale",
+			template: "account_locked",
+			query:    "locale=id",
+			wantCode: http.StatusOK,
+			want:     `"subject":"Akun LoanService.io Anda telah dikunci"`,
+		},
+		{
+			name:     "returns english subject given no locale",
+			template: "account_locked",
+			query:    "",
+			wantCode: http.StatusOK,
+			want:     `"subject":"Your LoanService.io account has been locked"`,
+		},
+		{
+			name:     "returns raw html given html format",
+			template: "loan_funded",
+			query:    "format=html",
+			wantCode: http.StatusOK,
+			want:     `<h1>A loan you financed has been fully funded!</h1>`,
+		},
+		{
+			name:     "throws error given unknown template",
+			template: "loan_cancelled",
+			query:    "",
+			wantCode: http.StatusNotFound,
+			want:     `"error_code":"TemplateNotFound"`,
+		},
+		{
+			name:     "throws error given unsupported locale",
+			template: "loan_funded",
+			query:    "locale=fr",
+			wantCode: http.StatusBadRequest,
+			want:     `invalid request parameters`,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			req := httptest.NewRequest(http.MethodGet, "/email-templates/:template/preview?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+			ctx.SetParamNames("template")
+			ctx.SetParamValues(tt.template)
+
+			// Do test and assert
+			err := s.emailTemplateHandler.PreviewEmailTemplate(ctx)
+			got := strings.TrimSpace(rec.Body.String())
+
+			assert.NoError(err)
+			assert.Equal(tt.wantCode, rec.Code)
+			assert.Contains(got, tt.want)
+		})
+	}
+}
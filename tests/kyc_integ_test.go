@@ -0,0 +1,367 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"loan-service/app"
+	"loan-service/models"
+	kycModule "loan-service/modules/kyc"
+	_kycHandlers "loan-service/modules/kyc/handlers"
+	"loan-service/modules/kyc/handlers/dto"
+	"loan-service/services/auth"
+	"loan-service/services/ekyc"
+	"loan-service/services/upload"
+	_uploadMock "loan-service/services/upload/mocks"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type kycIntegrationTestSuite struct {
+	suite.Suite
+	db                 *gorm.DB
+	rest               *echo.Echo
+	borrowerKYCHandler *_kycHandlers.BorrowerKYCHandler
+	staffKYCHandler    *_kycHandlers.StaffKYCHandler
+	documentKYCHandler *_kycHandlers.DocumentKYCHandler
+	models             []interface{}
+	uploadSvc          *_uploadMock.UploadService
+	injector           *do.Injector
+	imageFixture       []byte
+}
+
+func TestIntegrationKYC(t *testing.T) {
+	suite.Run(t, new(kycIntegrationTestSuite))
+}
+
+func (s *kycIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.uploadSvc = _uploadMock.NewUploadService(s.T())
+
+	s.injector = app.SetupInjections(s.db, s.rest, nil, nil, nil, s.uploadSvc)
+	do.OverrideValue[ekyc.Provider](s.injector, ekyc.NewFakeProvider())
+
+	s.borrowerKYCHandler = &_kycHandlers.BorrowerKYCHandler{
+		Usecase:     do.MustInvoke[models.KYCUsecase](s.injector),
+		UserUsecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.staffKYCHandler = &_kycHandlers.StaffKYCHandler{
+		Usecase:     do.MustInvoke[models.KYCUsecase](s.injector),
+		UserUsecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.documentKYCHandler = &_kycHandlers.DocumentKYCHandler{
+		Usecase: do.MustInvoke[models.KYCUsecase](s.injector),
+	}
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+		&models.KYCDocument{},
+		&models.KYCProfile{},
+	}
+
+	s.imageFixture, err = os.ReadFile("fixtures/example-attachment.jpg")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// submit submits the borrower's identity, along with the fixture as both their ID card and selfie
+func (s *kycIntegrationTestSuite) submit(userID uint, form map[string]string) *httptest.ResponseRecorder {
+	body := new(bytes.Buffer)
+	bodyWriter := multipart.NewWriter(body)
+
+	for _, name := range []string{"id_card", "selfie"} {
+		file, err := bodyWriter.CreateFormFile(name, name+".jpg")
+		s.Require().NoError(err)
+
+		_, err = file.Write(s.imageFixture)
+		s.Require().NoError(err)
+	}
+
+	for key, value := range form {
+		s.Require().NoError(bodyWriter.WriteField(key, value))
+	}
+	s.Require().NoError(bodyWriter.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/kyc", body)
+	req.Header.Set(echo.HeaderContentType, bodyWriter.FormDataContentType())
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+		UserID:      userID,
+		Permissions: []auth.Permission{auth.PermissionKYCSubmit},
+	})
+
+	s.Require().NoError(s.borrowerKYCHandler.SubmitKYCProfile(ctx))
+
+	return rec
+}
+
+func (s *kycIntegrationTestSuite) identity(nik string) map[string]string {
+	return map[string]string{
+		"nik":            nik,
+		"date_of_birth":  "1985-08-17",
+		"address":        "Jl. Medan Merdeka Selatan No. 8-9, Jakarta Pusat",
+		"phone_number":   "+6281234567890",
+		"occupation":     "Entrepreneur",
+		"monthly_income": "15000000",
+	}
+}
+
+func (s *kycIntegrationTestSuite) TestIntegration_SubmitKYCProfile() {
+	assert := _assert.New(s.T())
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/kyc.jpg", ContentType: "image/jpeg"}, nil)
+
+	// The provider approves the identity, and the borrower sees their profile without the provider's outcome
+	rec := s.submit(2, s.identity("3171010001001234"))
+	assert.Equal(http.StatusCreated, rec.Code)
+
+	var profileResp struct {
+		Data dto.KYCProfileResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &profileResp))
+	assert.Equal(string(models.KYCStatusVerified), profileResp.Data.Status)
+	assert.Equal("1985-08-17", profileResp.Data.DateOfBirth)
+	assert.Equal("Rp15.000.000,00", profileResp.Data.MonthlyIncome)
+	assert.Contains(profileResp.Data.IDCardURL, "/app/kyc/documents/")
+	assert.Nil(profileResp.Data.Provider)
+
+	// Verified identities cannot be changed, and their NIK cannot be used by another account
+	rec = s.submit(2, s.identity("3171010001001234"))
+	assert.Contains(rec.Body.String(), kycModule.ErrKYCAlreadyVerified.ErrorCode)
+
+	rec = s.submit(3, s.identity("3171010001001234"))
+	assert.Contains(rec.Body.String(), kycModule.ErrKYCNIKTaken.ErrorCode)
+
+	// Invalid identities
+	tooYoung := s.identity("3171010001005678")
+	tooYoung["date_of_birth"] = "2020-01-01"
+	rec = s.submit(3, tooYoung)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "17 years old")
+
+	notADate := s.identity("3171010001005678")
+	notADate["date_of_birth"] = "17-08-1985"
+	rec = s.submit(3, notADate)
+	assert.Contains(rec.Body.String(), "invalid request parameters")
+
+	rec = s.submit(3, s.identity("31710100"))
+	assert.Contains(rec.Body.String(), "invalid request parameters")
+
+	// Rejected identities can be submitted again
+	rec = s.submit(3, s.identity("3171010001000000"))
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"rejected"`)
+
+	rec = s.submit(3, s.identity("3171010001005678"))
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"verified"`)
+
+	var documents int64
+	s.db.Model(&models.KYCDocument{}).Where("user_id = ?", 3).Count(&documents)
+	assert.Equal(int64(4), documents)
+}
+
+func (s *kycIntegrationTestSuite) TestIntegration_ReviewKYCProfile() {
+	assert := _assert.New(s.T())
+	staffClaims := auth.AuthClaims{UserID: 1, Permissions: []auth.Permission{auth.PermissionKYCReview}}
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/kyc.jpg", ContentType: "image/jpeg"}, nil)
+
+	review := func(userID uint, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/kyc/:user_id/review", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, staffClaims)
+		ctx.SetParamNames("user_id")
+		ctx.SetParamValues(fmt.Sprint(userID))
+
+		s.Require().NoError(s.staffKYCHandler.ReviewKYCProfile(ctx))
+
+		return rec
+	}
+
+	// The provider cannot decide, so the identity waits for review
+	rec := s.submit(2, s.identity("3171010001009999"))
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"pending"`)
+
+	req := httptest.NewRequest(http.MethodGet, "/kyc/reviews", nil)
+	rec = httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, staffClaims)
+	s.Require().NoError(s.staffKYCHandler.FetchKYCReviews(ctx))
+
+	var reviewsResp struct {
+		Data []dto.KYCProfileResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &reviewsResp))
+	s.Require().Len(reviewsResp.Data, 1)
+	assert.Equal(uint(2), reviewsResp.Data[0].UserID)
+	s.Require().NotNil(reviewsResp.Data[0].Provider)
+	assert.Equal("fake", reviewsResp.Data[0].Provider.Name)
+	assert.Equal("review", reviewsResp.Data[0].Provider.Decision)
+
+	// Staff need a decision, and can only review pending identities
+	rec = review(2, `{"decision": "maybe"}`)
+	assert.Contains(rec.Body.String(), "invalid request parameters")
+
+	rec = review(2, `{"decision": "approve", "note": "Selfie matches, the photo on the card is old"}`)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"verified"`)
+	assert.Contains(rec.Body.String(), "Emmanuel Macron")
+
+	rec = review(2, `{"decision": "reject"}`)
+	assert.Contains(rec.Body.String(), kycModule.ErrKYCNotPendingReview.ErrorCode)
+
+	rec = review(3, `{"decision": "approve"}`)
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.Contains(rec.Body.String(), kycModule.ErrKYCProfileNotFound.ErrorCode)
+}
+
+func (s *kycIntegrationTestSuite) TestIntegration_DownloadKYCDocument() {
+	assert := _assert.New(s.T())
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/kyc.jpg", ContentType: "image/jpeg"}, nil)
+
+	rec := s.submit(2, s.identity("3171010001001234"))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	var profileResp struct {
+		Data dto.KYCProfileResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &profileResp))
+
+	download := func(claims auth.AuthClaims, signedURL string) *httptest.ResponseRecorder {
+		parsedURL, err := url.Parse(signedURL)
+		s.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodGet, parsedURL.RequestURI(), nil)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, claims)
+		ctx.SetParamNames("document_id", "variant")
+		// e.g. 1/file
+		segments := strings.Split(strings.TrimPrefix(parsedURL.Path, "/app/kyc/documents/"), "/")
+		ctx.SetParamValues(segments[0], segments[1])
+
+		s.Require().NoError(s.documentKYCHandler.DownloadKYCDocument(ctx))
+
+		return rec
+	}
+
+	s.uploadSvc.On("DownloadFile", mock.Anything, "attachments/kyc.jpg").
+		Return(io.NopCloser(strings.NewReader("id card")), &upload.UploadedFile{
+			Key:         "attachments/kyc.jpg",
+			ContentType: "image/jpeg",
+		}, nil).Twice()
+
+	// The borrower and staff reviewing identities can see the photos
+	rec = download(auth.AuthClaims{UserID: 2, Permissions: []auth.Permission{auth.PermissionKYCSubmit}},
+		profileResp.Data.IDCardURL)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("private, no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal("id card", rec.Body.String())
+
+	rec = download(auth.AuthClaims{UserID: 1, Permissions: []auth.Permission{auth.PermissionKYCReview}},
+		profileResp.Data.IDCardURL)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	// Other borrowers cannot, even with a valid link
+	rec = download(auth.AuthClaims{UserID: 3, Permissions: []auth.Permission{auth.PermissionKYCSubmit}},
+		profileResp.Data.IDCardURL)
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.Contains(rec.Body.String(), kycModule.ErrKYCDocumentNotFound.ErrorCode)
+
+	rec = download(auth.AuthClaims{UserID: 2, Permissions: []auth.Permission{auth.PermissionKYCSubmit}},
+		strings.Replace(profileResp.Data.IDCardURL, "/file", "/thumbnail", 1))
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), upload.ErrInvalidSignedURL.ErrorCode)
+}
+
+func (s *kycIntegrationTestSuite) SeedData() {
+	roles := []models.Role{
+		{Name: "Staff", RoleType: auth.RoleTypeStaff},
+		{Name: "Borrower", RoleType: auth.RoleTypeBorrower},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
+	}
+
+	users := []models.User{
+		{
+			Name:     "Emmanuel Macron",
+			Email:    "staff@loanservice.io",
+			Password: "@staff",
+			IsActive: true,
+			RoleID:   1,
+		},
+		{
+			Name:     "Zulhas Hasan",
+			Email:    "zulhashasan@indonesia.go.id",
+			Password: "zulhas@borrower",
+			IsActive: true,
+			RoleID:   2,
+		},
+		{
+			Name:     "Nuhut Bingsar",
+			Email:    "nuhutbingsar@indonesia.go.id",
+			Password: "nuhut@borrower",
+			IsActive: true,
+			RoleID:   2,
+		},
+	}
+
+	for i := range users {
+		users[i].SetNewPassword(users[i].Password)
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert users: %v", err))
+	}
+}
+
+func (s *kycIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *kycIntegrationTestSuite) TearDownTest() {
+	for _, model := range s.models {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package integration
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,17 +11,24 @@ import (
 	"loan-service/models"
 	loanModule "loan-service/modules/loans"
 	_loanHandlers "loan-service/modules/loans/handlers"
+	"loan-service/modules/loans/handlers/dto"
+	_productHandlers "loan-service/modules/products/handlers"
 	"loan-service/services/auth"
+	"loan-service/services/email"
 	_emailMock "loan-service/services/email/mocks"
+	"loan-service/services/pubsub"
+	"loan-service/services/upload"
 	_uploadMock "loan-service/services/upload/mocks"
 	"loan-service/utils/jsonutil"
 	"loan-service/utils/ptr"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
@@ -38,6 +47,7 @@ type loanIntegrationTestSuite struct {
 	fieldValidatorLoanHandler *_loanHandlers.FieldValidatorLoanHandler
 	staffLoanHandler          *_loanHandlers.StaffLoanHandler
 	investorLoanHandler       *_loanHandlers.InvestorLoanHandler
+	documentLoanHandler       *_loanHandlers.DocumentLoanHandler
 	models                    []interface{}
 	emailSvc                  *_emailMock.EmailService
 	uploadSvc                 *_uploadMock.UploadService
@@ -61,7 +71,7 @@ func (s *loanIntegrationTestSuite) SetupSuite() {
 	s.emailSvc = _emailMock.NewEmailService(s.T())
 	s.uploadSvc = _uploadMock.NewUploadService(s.T())
 
-	s.injector = app.SetupInjections(s.db, s.rest, s.emailSvc, s.uploadSvc)
+	s.injector = app.SetupInjections(s.db, s.rest, s.emailSvc, nil, nil, s.uploadSvc)
 
 	s.borrowerLoanHandler = &_loanHandlers.BorrowerLoanHandler{
 		Usecase:        do.MustInvoke[models.LoanUsecase](s.injector),
@@ -100,12 +110,35 @@ func (s *loanIntegrationTestSuite) SetupSuite() {
 		),
 	}
 
+	s.documentLoanHandler = &_loanHandlers.DocumentLoanHandler{
+		Usecase:     do.MustInvoke[models.LoanUsecase](s.injector),
+		UserUsecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
 	s.models = []any{
+		&models.Permission{},
 		&models.Role{},
 		&models.User{},
 		&models.Product{},
+		&models.VisitChecklist{},
 		&models.Loan{},
+		&models.LoanDocument{},
+		&models.KYCDocument{},
+		&models.KYCProfile{},
+		&models.LoanAssignment{},
+		&models.VisitSync{},
+		&models.ApprovalPolicy{},
+		&models.LoanApproval{},
+		&models.CreditScorecard{},
+		&models.CreditAssessment{},
+		&models.LoanPricing{},
 		&models.Investment{},
+		&models.OutboxMessage{},
+		&models.NotificationPreference{},
+		&models.InboxNotification{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryLog{},
 	}
 
 	s.imageFixture, err = os.Open("fixtures/example-attachment.jpg")
@@ -141,7 +174,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_StartLoan() {
 						"remaining_amount": "Rp10.000.000,00",
 						"interest_rate": "8%",
 						"total_interest": "Rp400.000,00",
-						"loan_term": "6 months"
+						"loan_term": "6 months",
+						"risk_grade": "A"
 					}
 				}
 			`,
@@ -158,6 +192,17 @@ func (s *loanIntegrationTestSuite) TestIntegration_StartLoan() {
 				`,
 			wantErr: loanModule.ErrLoanAlreadyExists,
 		},
+		{
+			name:   "throws error given borrower without a verified identity",
+			userID: 10,
+			reqStr: `
+				{
+					"name": "Beli furnitur",
+    			"product_id": 2
+				}
+				`,
+			wantErr: loanModule.ErrKYCNotVerified,
+		},
 		{
 			name:   "throws error given invalid request",
 			userID: 8,
@@ -200,24 +245,90 @@ func (s *loanIntegrationTestSuite) TestIntegration_StartLoan() {
 	}
 }
 
+func (s *loanIntegrationTestSuite) TestIntegration_StartLoanAfterCompletedLoan() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+	product, err := do.MustInvoke[models.ProductUsecase](s.injector).FetchProductByID(ctx, 2)
+	s.Require().NoError(err)
+	borrower := &models.User{Model: gorm.Model{ID: 7}}
+
+	// Disbursed loans are open until their last installment comes due
+	disbursedAt := time.Now().AddDate(0, -2, 0)
+	s.Require().NoError(s.db.Model(&models.Loan{}).Where("id = ?", 1).Updates(map[string]any{
+		"status":       models.LoanStatusDisbursed,
+		"disbursed_at": disbursedAt,
+	}).Error)
+
+	_, err = loanUsecase.StartLoan(ctx, "Modal usaha", product, borrower)
+	assert.ErrorIs(err, loanModule.ErrLoanAlreadyExists)
+
+	// Once the term has ended the borrower may borrow again, and the completed loan counts in their favour
+	disbursedAt = time.Now().AddDate(0, -4, 0)
+	s.Require().NoError(s.db.Model(&models.Loan{}).Where("id = ?", 1).Update("disbursed_at", disbursedAt).Error)
+
+	loan, err := loanUsecase.StartLoan(ctx, "Modal usaha", product, borrower)
+	s.Require().NoError(err)
+
+	assessments, err := loanUsecase.FetchCreditAssessments(ctx, loan)
+	s.Require().NoError(err)
+	s.Require().Len(assessments, 1)
+	assert.Equal(710, assessments[0].Score)
+
+	var previousLoans *models.CreditFactorScore
+	for i := range assessments[0].Factors {
+		if assessments[0].Factors[i].Factor == models.CreditFactorPreviousLoans {
+			previousLoans = &assessments[0].Factors[i]
+		}
+	}
+	s.Require().NotNil(previousLoans)
+	assert.Equal("Previously completed loans: 1, from 1 to below 3, +40 points", previousLoans.Explanation)
+}
+
 func (s *loanIntegrationTestSuite) TestIntegration_MarkLoanBorrowerVisited() {
 	tests := []struct {
 		name    string
 		userID  uint
 		params  map[string]string
+		form    map[string]string
 		wantErr error
 	}{
+		{
+			name:   "throws error given field validator not assigned to the loan",
+			userID: 11,
+			params: map[string]string{
+				"loan_id": "1",
+			},
+			wantErr: loanModule.ErrLoanNotFound,
+		},
 		{
 			name:   "returns results given valid request and existing proposed loan",
-			userID: 7,
+			userID: 2,
 			params: map[string]string{
 				"loan_id": "1",
 			},
+			// A few dozen meters from the borrower's address
+			form: map[string]string{
+				"latitude":    "-6.1756",
+				"longitude":   "106.8270",
+				"captured_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
 			wantErr: nil,
 		},
+		{
+			name:   "throws error given only one coordinate",
+			userID: 2,
+			params: map[string]string{
+				"loan_id": "1",
+			},
+			form: map[string]string{
+				"latitude": "-6.1756",
+			},
+			wantErr: errors.New("invalid request parameters"),
+		},
 		{
 			name:   "throws error given valid request and already visited loan",
-			userID: 9,
+			userID: 2,
 			params: map[string]string{
 				"loan_id": "3",
 			},
@@ -244,6 +355,10 @@ func (s *loanIntegrationTestSuite) TestIntegration_MarkLoanBorrowerVisited() {
 
 			_, err = io.Copy(file, s.imageFixture)
 			assert.NoError(err)
+
+			for k, v := range tt.form {
+				assert.NoError(bodyWriter.WriteField(k, v))
+			}
 			assert.NoError(bodyWriter.Close())
 
 			req := httptest.NewRequest(http.MethodPost, "/loans/:loan_id/visit", body)
@@ -251,7 +366,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_MarkLoanBorrowerVisited() {
 			rec := httptest.NewRecorder()
 			ctx := s.rest.NewContext(req, rec)
 			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
-				UserID: uint(tt.userID),
+				UserID:      uint(tt.userID),
+				Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
 			})
 
 			for k, v := range tt.params {
@@ -260,8 +376,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_MarkLoanBorrowerVisited() {
 			}
 
 			// Mock services
-			s.uploadSvc.On("UploadFile", mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
-				Return("tmp/attachment-path.jpg", nil)
+			s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+				Return(&upload.UploadedFile{Key: "attachments/attachment-path.jpg", ContentType: "image/jpeg"}, nil)
 
 			// Do test and assert
 			err = s.fieldValidatorLoanHandler.MarkLoanBorrowerVisited(ctx)
@@ -270,6 +386,31 @@ func (s *loanIntegrationTestSuite) TestIntegration_MarkLoanBorrowerVisited() {
 			if tt.wantErr == nil {
 				assert.NoError(err)
 				assert.Equal(http.StatusOK, rec.Code)
+				assert.Contains(got, "/app/loans/1/documents/")
+
+				// The photo is kept as the first proof of visit document
+				var documents []models.LoanDocument
+				s.db.Where("loan_id = ? AND document_type = ?", 1, models.LoanDocumentProofOfVisit).Find(&documents)
+				s.Require().Len(documents, 1)
+				assert.Equal(1, documents[0].Version)
+				assert.Equal(ptr.NewUintPtr(tt.userID), documents[0].UploaderID)
+				assert.Equal("attachments/attachment-path.jpg", documents[0].FileKey)
+
+				// The visit was close enough and recent, no review needed
+				var loan models.Loan
+				s.db.First(&loan, 1)
+				assert.Equal(models.VisitReviewVerified, loan.Visit.ReviewStatus)
+				assert.Equal(models.VisitLocationFromRequest, loan.Visit.LocationSource)
+				assert.Empty(loan.Visit.Flags)
+				s.Require().NotNil(loan.Visit.DistanceMeters)
+				assert.Less(*loan.Visit.DistanceMeters, 100.0)
+				assert.NotNil(loan.VisitedAt)
+
+				// The field validator's task is done
+				var assignment models.LoanAssignment
+				s.db.Where("loan_id = ?", 1).First(&assignment)
+				assert.Equal(models.LoanAssignmentCompleted, assignment.Status)
+				assert.NotNil(assignment.CompletedAt)
 			} else {
 				assert.Contains(got, tt.wantErr.Error())
 			}
@@ -286,7 +427,7 @@ func (s *loanIntegrationTestSuite) TestIntegration_ApproveLoan() {
 	}{
 		{
 			name:   "returns results given valid request and existing visited loan",
-			userID: 9,
+			userID: 1,
 			params: map[string]string{
 				"loan_id": "2",
 			},
@@ -294,7 +435,7 @@ func (s *loanIntegrationTestSuite) TestIntegration_ApproveLoan() {
 		},
 		{
 			name:   "throws error given valid request and not visited loan",
-			userID: 6,
+			userID: 1,
 			params: map[string]string{
 				"loan_id": "1",
 			},
@@ -302,7 +443,7 @@ func (s *loanIntegrationTestSuite) TestIntegration_ApproveLoan() {
 		},
 		{
 			name:    "throws error given invalid request",
-			userID:  7,
+			userID:  1,
 			params:  nil,
 			wantErr: errors.New("invalid request parameters"),
 		},
@@ -318,7 +459,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_ApproveLoan() {
 			rec := httptest.NewRecorder()
 			ctx := s.rest.NewContext(req, rec)
 			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
-				UserID: uint(tt.userID),
+				UserID:      uint(tt.userID),
+				Permissions: []auth.Permission{auth.PermissionLoanViewAll, auth.PermissionLoanApprove},
 			})
 
 			for k, v := range tt.params {
@@ -340,6 +482,958 @@ func (s *loanIntegrationTestSuite) TestIntegration_ApproveLoan() {
 	}
 }
 
+func (s *loanIntegrationTestSuite) TestIntegration_ReviewLoanVisitConcurrently() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/attachment-path.jpg", ContentType: "image/jpeg"}, nil)
+
+	rec, err := s.visitLoan(1, 2, map[string]string{
+		"latitude":    "-6.9175",
+		"longitude":   "107.6191",
+		"captured_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var staff, fieldValidator models.User
+	s.Require().NoError(s.db.Preload("Role").First(&staff, 1).Error)
+	s.Require().NoError(s.db.Preload("Role").First(&fieldValidator, 2).Error)
+
+	fetchLoan := func() *models.Loan {
+		loan, err := loanUsecase.FetchLoanByID(ctx, 1, nil)
+		s.Require().NoError(err)
+		return loan
+	}
+
+	// Two staff members review the same flagged visit, the second one sees it was reviewed meanwhile
+	accepting, rejecting := fetchLoan(), fetchLoan()
+	s.Require().NoError(loanUsecase.ReviewLoanVisit(ctx, accepting, &staff, true, "Borrower moved"))
+
+	err = loanUsecase.ReviewLoanVisit(ctx, rejecting, &staff, false, "Photo taken in the wrong city")
+	assert.ErrorIs(err, loanModule.ErrLoanVisitNotPendingReview)
+
+	// A loan fetched before the proof of visit was retaken cannot be approved on the accepted visit
+	approving := fetchLoan()
+	_, err = loanUsecase.UploadLoanDocument(ctx, fetchLoan(), &fieldValidator,
+		[]auth.Permission{auth.PermissionLoanVisit}, models.LoanDocumentProofOfVisit,
+		bytes.NewReader(newTestPhoto(s.T(), 40, 20, nil)))
+	s.Require().NoError(err)
+
+	_, err = loanUsecase.ApproveLoan(ctx, approving, &staff, "")
+	assert.ErrorIs(err, loanModule.ErrLoanVisitNotApprovable)
+
+	var loan models.Loan
+	s.Require().NoError(s.db.First(&loan, 1).Error)
+	assert.Equal(models.LoanStatusProposed, loan.Status)
+	assert.Equal(models.VisitReviewPending, loan.Visit.ReviewStatus)
+	assert.Equal(ptr.NewUintPtr(2), loan.VisitorID)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_ReviewLoanVisit() {
+	assert := _assert.New(s.T())
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/attachment-path.jpg", ContentType: "image/jpeg"}, nil)
+
+	// Taken in Bandung, over a hundred kilometers from the borrower's address
+	tooFar := map[string]string{
+		"latitude":    "-6.9175",
+		"longitude":   "107.6191",
+		"captured_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	}
+
+	rec, err := s.visitLoan(1, 2, tooFar)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"review_status":"pending_review"`)
+	assert.Contains(rec.Body.String(), `"too_far"`)
+
+	// Flagged visits wait in the review queue, and block the approval
+	rec, err = s.callStaffHandler(http.MethodGet, nil, nil, s.staffLoanHandler.FetchVisitReviews)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"id":1`)
+	assert.NotContains(rec.Body.String(), `"id":2`)
+
+	rec, err = s.callStaffHandler(http.MethodPatch, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.ApproveLoan)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanVisitNotApprovable.Error())
+
+	// Rejected visits are redone
+	rec, err = s.callStaffHandler(
+		http.MethodPatch,
+		map[string]string{"loan_id": "1"},
+		map[string]any{"decision": "reject", "note": "Photo taken in the wrong city"},
+		s.staffLoanHandler.ReviewLoanVisit,
+	)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var loan models.Loan
+	s.db.First(&loan, 1)
+	assert.Equal(models.VisitReviewRejected, loan.Visit.ReviewStatus)
+	assert.Equal("Photo taken in the wrong city", loan.Visit.ReviewNote)
+	assert.Equal(ptr.NewUintPtr(1), loan.Visit.ReviewerID)
+	assert.Nil(loan.VisitorID)
+
+	rec, err = s.callStaffHandler(
+		http.MethodPatch,
+		map[string]string{"loan_id": "1"},
+		map[string]any{"decision": "accept"},
+		s.staffLoanHandler.ReviewLoanVisit,
+	)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanVisitNotPendingReview.Error())
+
+	// The second visit is flagged again once the loan is assigned again, but staff accept it this time
+	rec, err = s.callStaffHandler(
+		http.MethodPost,
+		map[string]string{"loan_id": "1"},
+		map[string]any{"field_validator_id": 2, "note": "Please visit again"},
+		s.staffLoanHandler.AssignLoan,
+	)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	rec, err = s.visitLoan(1, 2, tooFar)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	rec, err = s.callStaffHandler(
+		http.MethodPatch,
+		map[string]string{"loan_id": "1"},
+		map[string]any{"decision": "accept", "note": "Borrower moved, address to be updated"},
+		s.staffLoanHandler.ReviewLoanVisit,
+	)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"review_status":"accepted"`)
+
+	rec, err = s.callStaffHandler(http.MethodPatch, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.ApproveLoan)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	var documents []models.LoanDocument
+	s.db.Where("loan_id = ? AND document_type = ?", 1, models.LoanDocumentProofOfVisit).Find(&documents)
+	assert.Len(documents, 2)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_ApprovalPolicy() {
+	assert := _assert.New(s.T())
+
+	callAs := func(userID uint, params map[string]string, body map[string]any, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		payload, err := json.Marshal(body)
+		s.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodPatch, "/", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+			UserID:      userID,
+			Permissions: []auth.Permission{auth.PermissionLoanViewAll, auth.PermissionLoanApprove},
+		})
+
+		for k, v := range params {
+			ctx.SetParamNames(k)
+			ctx.SetParamValues(v)
+		}
+
+		s.Require().NoError(handler(ctx))
+
+		return rec
+	}
+
+	var approvalResp struct {
+		Data dto.LoanApprovalResp `json:"data"`
+	}
+
+	loan2 := map[string]string{"loan_id": "2"}
+	approve := func(userID uint, comment string) *httptest.ResponseRecorder {
+		rec := callAs(userID, loan2, map[string]any{"comment": comment}, s.staffLoanHandler.ApproveLoan)
+		if rec.Code == http.StatusOK {
+			s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &approvalResp))
+		}
+
+		return rec
+	}
+
+	// Tiers starting at the same amount are ambiguous
+	rec, err := s.callStaffHandler(http.MethodPut, nil, map[string]any{
+		"tiers": []map[string]any{
+			{"name": "small", "min_principal_amount": 0, "required_approvals": 1},
+			{"name": "other", "min_principal_amount": 0, "required_approvals": 2},
+		},
+	}, s.staffLoanHandler.SetApprovalPolicies)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "InvalidApprovalPolicy")
+
+	// Loans from 50 million need two approvers, and a superuser on top
+	rec, err = s.callStaffHandler(http.MethodPut, nil, map[string]any{
+		"tiers": []map[string]any{
+			{"name": "large", "min_principal_amount": 50000000, "required_approvals": 2, "require_superuser": true},
+		},
+	}, s.staffLoanHandler.SetApprovalPolicies)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var pendingResp struct {
+		Data []dto.LoanApprovalResp `json:"data"`
+	}
+	rec, err = s.callStaffHandler(http.MethodGet, nil, nil, s.staffLoanHandler.FetchPendingApprovals)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &pendingResp))
+	s.Require().Len(pendingResp.Data, 1)
+	assert.Equal(uint(2), pendingResp.Data[0].ID)
+	assert.Equal("large", pendingResp.Data[0].Approval.Policy)
+	assert.Equal(2, pendingResp.Data[0].Approval.RequiredApprovals)
+
+	// The field validator who visited the borrower cannot approve
+	rec = approve(2, "")
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrApproverIsVisitor.ErrorCode)
+
+	rec = approve(1, "Income checks out")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Equal("proposed", approvalResp.Data.Status)
+	assert.Equal(1, approvalResp.Data.Approval.Approvals)
+	assert.Equal(1, approvalResp.Data.Approval.NextLevel)
+
+	rec = approve(1, "")
+	assert.Equal(http.StatusConflict, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanAlreadyVoted.ErrorCode)
+
+	// Rejections need a comment, and start the approval over
+	rec = callAs(11, loan2, nil, s.staffLoanHandler.RejectLoan)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+
+	rec = callAs(11, loan2, map[string]any{"comment": "Business permit expired"}, s.staffLoanHandler.RejectLoan)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &approvalResp))
+	assert.Equal(1, approvalResp.Data.Approval.Round)
+	assert.Equal(0, approvalResp.Data.Approval.Approvals)
+
+	s.Require().Equal(http.StatusOK, approve(1, "Permit renewed").Code)
+	s.Require().Equal(http.StatusOK, approve(11, "").Code)
+	assert.Equal("proposed", approvalResp.Data.Status)
+	assert.Equal(2, approvalResp.Data.Approval.Approvals)
+	assert.Equal(2, approvalResp.Data.Approval.NextLevel)
+
+	// The second level is left to superusers
+	rec = approve(9, "")
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrSuperuserApprovalRequired.ErrorCode)
+
+	superuser := models.User{Name: "Christine Lagarde", Email: "superuser@loanservice.io", IsActive: true, RoleID: 1}
+	superuser.SetNewPassword("@superuser")
+	s.Require().NoError(s.db.Create(&superuser).Error)
+
+	rec = approve(superuser.ID, "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Equal("approved", approvalResp.Data.Status)
+	assert.True(approvalResp.Data.Approval.Satisfied)
+	assert.Equal("Christine Lagarde", approvalResp.Data.ApprovedBy.Name)
+
+	rec, err = s.callStaffHandler(http.MethodGet, loan2, nil, s.staffLoanHandler.FetchLoanApproval)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &approvalResp))
+	s.Require().Len(approvalResp.Data.Approval.Votes, 5)
+	assert.Equal("reject", approvalResp.Data.Approval.Votes[1].Decision)
+	assert.Equal("Business permit expired", approvalResp.Data.Approval.Votes[1].Comment)
+	assert.Equal(2, approvalResp.Data.Approval.Votes[4].Level)
+
+	var loan models.Loan
+	s.db.First(&loan, 2)
+	assert.Equal(models.LoanStatusApproved, loan.Status)
+	assert.Equal(&superuser.ID, loan.ApproverID)
+
+	// Loans below every tier follow the default policy
+	rec = callAs(1, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.FetchLoanApproval)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &approvalResp))
+	assert.Equal(models.DefaultApprovalPolicy.Name, approvalResp.Data.Approval.Policy)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_CreditScoring() {
+	assert := _assert.New(s.T())
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+
+	var assessmentsResp struct {
+		Data []dto.CreditAssessmentResp `json:"data"`
+	}
+
+	// Applications are scored with the default scorecard until one is stored
+	product, err := do.MustInvoke[models.ProductUsecase](s.injector).FetchProductByID(context.Background(), 2)
+	s.Require().NoError(err)
+	loan, err := loanUsecase.StartLoan(context.Background(), "Modal usaha", product, &models.User{Model: gorm.Model{ID: 6}})
+	s.Require().NoError(err)
+	assert.Equal(models.RiskGradeA, loan.RiskGrade)
+	loanParams := map[string]string{"loan_id": fmt.Sprint(loan.ID)}
+
+	rec, err := s.callStaffHandler(http.MethodGet, loanParams, nil, s.staffLoanHandler.FetchCreditAssessments)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assessmentsResp))
+	s.Require().Len(assessmentsResp.Data, 1)
+	assert.Equal(0, assessmentsResp.Data[0].ScorecardVersion)
+	assert.Equal(670, assessmentsResp.Data[0].Score)
+	assert.Equal("A", assessmentsResp.Data[0].Grade)
+	assert.Nil(assessmentsResp.Data[0].AssessedBy)
+
+	explanations := []string{}
+	for _, factor := range assessmentsResp.Data[0].Factors {
+		explanations = append(explanations, factor.Explanation)
+	}
+	assert.Contains(explanations, "Monthly income: Rp15.000.000,00, from Rp10.000.000,00 to below Rp25.000.000,00, +50 points")
+	assert.Contains(explanations, "Monthly installment to income: 11.6%, below 20.0%, +80 points")
+	assert.Contains(explanations, "Previously completed loans: 0, no loan history, +0 points")
+
+	// Bands of a factor cannot overlap
+	rec, err = s.callStaffHandler(http.MethodPut, nil, map[string]any{
+		"base_score": 400,
+		"factors": []map[string]any{
+			{"factor": "monthly_income", "bands": []map[string]any{
+				{"max": 20000000, "points": 0},
+				{"min": 10000000, "points": 100},
+			}},
+		},
+		"grades": []map[string]any{{"grade": "A", "min_score": 450}, {"grade": "E", "min_score": 0}},
+	}, s.staffLoanHandler.SetCreditScorecard)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "InvalidCreditScorecard")
+
+	// A stricter scorecard only counts incomes from 20 million
+	rec, err = s.callStaffHandler(http.MethodPut, nil, map[string]any{
+		"base_score": 400,
+		"factors": []map[string]any{
+			{"factor": "monthly_income", "bands": []map[string]any{
+				{"max": 20000000, "points": 0, "label": "below the income floor"},
+				{"min": 20000000, "points": 100},
+			}},
+		},
+		"grades": []map[string]any{
+			{"grade": "A", "min_score": 600},
+			{"grade": "B", "min_score": 500},
+			{"grade": "D", "min_score": 400},
+			{"grade": "E", "min_score": 0},
+		},
+	}, s.staffLoanHandler.SetCreditScorecard)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"version":1`)
+
+	// Loans keep their grade until assessed again
+	rec, err = s.callStaffHandler(http.MethodPost, loanParams, nil, s.staffLoanHandler.AssessLoanCredit)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	assert.Contains(rec.Body.String(), `"grade":"D"`)
+	assert.Contains(rec.Body.String(), "Monthly income: Rp15.000.000,00, below the income floor, +0 points")
+	assert.Contains(rec.Body.String(), "Emmanuel Macron")
+
+	rec, err = s.callStaffHandler(http.MethodGet, loanParams, nil, s.staffLoanHandler.FetchCreditAssessments)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assessmentsResp))
+	s.Require().Len(assessmentsResp.Data, 2)
+	assert.Equal(1, assessmentsResp.Data[0].ScorecardVersion)
+	assert.Equal(400, assessmentsResp.Data[0].Score)
+
+	rec, err = s.callStaffHandler(http.MethodGet, loanParams, nil, s.staffLoanHandler.CommonHandler.FetchLoan)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"risk_grade":"D"`)
+
+	// Approved loans keep the grade they were approved with
+	rec, err = s.callStaffHandler(http.MethodPost, map[string]string{"loan_id": "3"}, nil, s.staffLoanHandler.AssessLoanCredit)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusConflict, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanNotProposed.ErrorCode)
+
+	// Investors see the grade of the loans they can invest in
+	req := httptest.NewRequest(http.MethodGet, "/loans/:loan_id", nil)
+	rec = httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: 4, Permissions: []auth.Permission{auth.PermissionLoanViewInvestable}})
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("3")
+
+	s.Require().NoError(s.investorLoanHandler.CommonHandler.FetchLoan(ctx))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"risk_grade":"B"`)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_RiskBasedPricing() {
+	assert := _assert.New(s.T())
+	productHandler := &_productHandlers.StaffProductHandler{Usecase: do.MustInvoke[models.ProductUsecase](s.injector)}
+
+	var pricingsResp struct {
+		Data []dto.LoanPricingResp `json:"data"`
+	}
+
+	// A grade is priced once
+	rec, err := s.callStaffHandler(http.MethodPut, map[string]string{"product_id": "2"}, map[string]any{
+		"rates": []map[string]any{{"grade": "A", "interest_rate": 0.07}, {"grade": "A", "interest_rate": 0.06}},
+	}, productHandler.SetRateGrid)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "InvalidRateGrid")
+
+	rec, err = s.callStaffHandler(http.MethodPut, map[string]string{"product_id": "2"}, map[string]any{
+		"rates": []map[string]any{{"grade": "D", "interest_rate": 0.12}, {"grade": "A", "interest_rate": 0.07}},
+	}, productHandler.SetRateGrid)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"rates":[{"grade":"A","interest_rate":"7%"},{"grade":"D","interest_rate":"12%"}]`)
+
+	// Applications are priced at the rate of their grade
+	product, err := do.MustInvoke[models.ProductUsecase](s.injector).FetchProductByID(context.Background(), 2)
+	s.Require().NoError(err)
+	loan, err := do.MustInvoke[models.LoanUsecase](s.injector).
+		StartLoan(context.Background(), "Modal usaha", product, &models.User{Model: gorm.Model{ID: 6}})
+	s.Require().NoError(err)
+	assert.Equal(models.RiskGradeA, loan.RiskGrade)
+	assert.Equal(0.07, loan.InterestRate)
+	assert.Equal("350000.000000", loan.TotalInterest)
+	assert.Equal("3.50", loan.ROI)
+
+	rec, err = s.callStaffHandler(http.MethodGet, map[string]string{"loan_id": fmt.Sprint(loan.ID)}, nil, s.staffLoanHandler.FetchLoanPricings)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &pricingsResp))
+	s.Require().Len(pricingsResp.Data, 1)
+	assert.Equal("application", pricingsResp.Data[0].Trigger)
+	assert.Equal("rate_grid", pricingsResp.Data[0].Source)
+	assert.Equal("8%", pricingsResp.Data[0].PreviousInterestRate)
+	assert.Equal("7%", pricingsResp.Data[0].InterestRate)
+	assert.Equal("3.50%", pricingsResp.Data[0].ROI)
+	assert.Nil(pricingsResp.Data[0].PricedBy)
+
+	// Loans are priced again on approval, with the grade they were assessed at and the rates set since they were read
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+	staleLoan, err := loanUsecase.FetchLoanByID(context.Background(), 2, nil)
+	s.Require().NoError(err)
+	approver, err := do.MustInvoke[models.UserUsecase](s.injector).FetchUserByID(context.Background(), 1, nil)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.db.Model(&models.Loan{}).Where("id = ?", 2).Update("risk_grade", models.RiskGradeC).Error)
+	rec, err = s.callStaffHandler(http.MethodPut, map[string]string{"product_id": "3"}, map[string]any{
+		"rates": []map[string]any{{"grade": "C", "interest_rate": 0.09}},
+	}, productHandler.SetRateGrid)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	_, err = loanUsecase.ApproveLoan(context.Background(), staleLoan, approver, "")
+	s.Require().NoError(err)
+
+	var approved models.Loan
+	s.db.First(&approved, 2)
+	assert.Equal(models.LoanStatusApproved, approved.Status)
+	assert.Equal(0.09, approved.InterestRate)
+	assert.Equal("9000000.000000", approved.TotalInterest)
+	assert.Equal("9.00", approved.ROI)
+
+	rec, err = s.callStaffHandler(http.MethodGet, map[string]string{"loan_id": "2"}, nil, s.staffLoanHandler.FetchLoanPricings)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &pricingsResp))
+	s.Require().Len(pricingsResp.Data, 1)
+	assert.Equal("approval", pricingsResp.Data[0].Trigger)
+	assert.Equal("C", pricingsResp.Data[0].RiskGrade)
+	assert.Equal("6.942%", pricingsResp.Data[0].PreviousInterestRate)
+	assert.Equal("9%", pricingsResp.Data[0].InterestRate)
+	s.Require().NotNil(pricingsResp.Data[0].PricedBy)
+	assert.Equal("Emmanuel Macron", pricingsResp.Data[0].PricedBy.Name)
+
+	// Grades missing from the grid get the product's rate
+	rec, err = s.callStaffHandler(http.MethodGet, map[string]string{"product_id": "1"}, nil, productHandler.FetchRateGrid)
+	s.Require().NoError(err)
+	assert.JSONEq(`{"data":{"product_id":1,"interest_rate":"10%","rates":[]}}`, rec.Body.String())
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_AssignLoan() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+
+	callFieldValidatorHandler := func(userID uint, params map[string]string, body map[string]any, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		payload, err := json.Marshal(body)
+		s.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+			UserID:      userID,
+			Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
+		})
+
+		for k, v := range params {
+			ctx.SetParamNames(k)
+			ctx.SetParamValues(v)
+		}
+
+		s.Require().NoError(handler(ctx))
+
+		return rec
+	}
+
+	var assignmentsResp struct {
+		Data []dto.LoanAssignmentResp `json:"data"`
+	}
+
+	// Nothing is left to assign, loan 1 is assigned and the others were visited
+	rec, err := s.callStaffHandler(http.MethodPost, nil, nil, s.staffLoanHandler.AutoAssignLoans)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assignmentsResp))
+	assert.Empty(assignmentsResp.Data)
+
+	// Reassigned without a field validator, the one covering the borrower's region is picked
+	rec, err = s.callStaffHandler(http.MethodPost, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.AssignLoan)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	var assignmentResp struct {
+		Data dto.LoanAssignmentResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assignmentResp))
+	assert.Equal("Mario Draghi", assignmentResp.Data.FieldValidator.Name)
+	assert.Equal(string(models.AssignmentRegion), assignmentResp.Data.Strategy)
+	assert.Equal("Emmanuel Macron", assignmentResp.Data.AssignedBy.Name)
+
+	var previous models.LoanAssignment
+	s.Require().NoError(s.db.First(&previous, 1).Error)
+	assert.Equal(models.LoanAssignmentReassigned, previous.Status)
+
+	var queued []models.OutboxMessage
+	s.db.Where("recipient_id = ? AND template = ?", 11, email.TemplateVisitAssigned).Find(&queued)
+	assert.Len(queued, 1)
+
+	// Only the assigned field validator sees and visits the loan
+	rec = callFieldValidatorHandler(2, nil, nil, s.fieldValidatorLoanHandler.CommonHandler.FetchLoans)
+	assert.NotContains(rec.Body.String(), `"id":1,`)
+	rec = callFieldValidatorHandler(11, nil, nil, s.fieldValidatorLoanHandler.CommonHandler.FetchLoans)
+	assert.Contains(rec.Body.String(), `"id":1,`)
+	assert.Contains(rec.Body.String(), `"assigned_to":{"name":"Mario Draghi"`)
+
+	rec, err = s.visitLoan(1, 2, nil)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanNotFound.ErrorCode)
+
+	// The field validator schedules the visit from their task list
+	rec = callFieldValidatorHandler(11, nil, nil, s.fieldValidatorLoanHandler.FetchAssignments)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assignmentsResp))
+	s.Require().Len(assignmentsResp.Data, 1)
+	assert.Equal("Jl. Medan Merdeka Barat No. 1, Jakarta", assignmentsResp.Data[0].Borrower.Address)
+	assert.Nil(assignmentsResp.Data[0].ScheduledAt)
+
+	assignmentID := fmt.Sprint(assignmentsResp.Data[0].ID)
+	scheduledAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	rec = callFieldValidatorHandler(
+		11,
+		map[string]string{"assignment_id": assignmentID},
+		map[string]any{"scheduled_at": scheduledAt},
+		s.fieldValidatorLoanHandler.ScheduleLoanVisit,
+	)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assignmentResp))
+	assert.True(scheduledAt.Equal(*assignmentResp.Data.ScheduledAt))
+	assert.True(scheduledAt.Add(models.DefaultVisitSlotDuration).Equal(*assignmentResp.Data.ScheduledUntil))
+
+	rec = callFieldValidatorHandler(
+		11,
+		map[string]string{"assignment_id": assignmentID},
+		map[string]any{"scheduled_at": time.Now().Add(-time.Hour)},
+		s.fieldValidatorLoanHandler.ScheduleLoanVisit,
+	)
+	assert.Contains(rec.Body.String(), loanModule.ErrVisitSlotInvalid.ErrorCode)
+
+	rec = callFieldValidatorHandler(
+		2,
+		map[string]string{"assignment_id": assignmentID},
+		map[string]any{"scheduled_at": scheduledAt},
+		s.fieldValidatorLoanHandler.ScheduleLoanVisit,
+	)
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	// Overdue visits are reported once, and listed for staff to follow up
+	s.Require().NoError(s.db.Model(&models.LoanAssignment{}).Where("id = ?", assignmentID).
+		Update("due_at", time.Now().Add(-time.Hour)).Error)
+
+	reported, err := loanUsecase.ReportOverdueVisits(ctx)
+	s.Require().NoError(err)
+	assert.Equal(1, reported)
+
+	reported, err = loanUsecase.ReportOverdueVisits(ctx)
+	s.Require().NoError(err)
+	assert.Equal(0, reported)
+
+	s.db.Where("recipient_id = ? AND template = ?", 11, email.TemplateVisitOverdue).Find(&queued)
+	assert.Len(queued, 1)
+
+	rec, err = s.callStaffHandler(http.MethodGet, nil, nil, func(c echo.Context) error {
+		c.QueryParams().Set("overdue", "true")
+		return s.staffLoanHandler.FetchLoanAssignments(c)
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &assignmentsResp))
+	s.Require().Len(assignmentsResp.Data, 1)
+	assert.True(assignmentsResp.Data[0].Overdue)
+	assert.Equal(uint(1), assignmentsResp.Data[0].LoanID)
+
+	// Only proposed loans waiting for a visit go to field validators
+	rec, err = s.callStaffHandler(
+		http.MethodPost,
+		map[string]string{"loan_id": "1"},
+		map[string]any{"field_validator_id": 5},
+		s.staffLoanHandler.AssignLoan,
+	)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), loanModule.ErrInvalidFieldValidator.ErrorCode)
+
+	rec, err = s.callStaffHandler(
+		http.MethodPost,
+		map[string]string{"loan_id": "3"},
+		map[string]any{"field_validator_id": 2},
+		s.staffLoanHandler.AssignLoan,
+	)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanNotAssignable.ErrorCode)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_VisitReport() {
+	assert := _assert.New(s.T())
+	productHandler := &_productHandlers.StaffProductHandler{Usecase: do.MustInvoke[models.ProductUsecase](s.injector)}
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/attachment-path.jpg", ContentType: "image/jpeg"}, nil)
+
+	// Visits loan 1 as its assigned field validator, with the files named in the form along with the proof of visit
+	visit := func(form map[string]string, files ...string) *httptest.ResponseRecorder {
+		body := new(bytes.Buffer)
+		bodyWriter := multipart.NewWriter(body)
+
+		for _, name := range append([]string{"attachment"}, files...) {
+			file, err := bodyWriter.CreateFormFile(name, name+".jpg")
+			s.Require().NoError(err)
+
+			_, err = file.Write(newTestPhoto(s.T(), 40, 20, nil))
+			s.Require().NoError(err)
+		}
+
+		for k, v := range form {
+			s.Require().NoError(bodyWriter.WriteField(k, v))
+		}
+		s.Require().NoError(bodyWriter.Close())
+
+		req := httptest.NewRequest(http.MethodPatch, "/loan/:loan_id/visit", body)
+		req.Header.Set(echo.HeaderContentType, bodyWriter.FormDataContentType())
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+			UserID:      2,
+			Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
+		})
+		ctx.SetParamNames("loan_id")
+		ctx.SetParamValues("1")
+
+		s.Require().NoError(s.fieldValidatorLoanHandler.MarkLoanBorrowerVisited(ctx))
+
+		return rec
+	}
+
+	// Products without a checklist take no report
+	rec := visit(map[string]string{"report": `{"business_open": true}`})
+	assert.Contains(rec.Body.String(), loanModule.ErrVisitChecklistMissing.ErrorCode)
+
+	// Staff define the product's checklist
+	rec, err := s.callStaffHandler(http.MethodPut, map[string]string{"product_id": "1"}, map[string]any{
+		"questions": []map[string]any{
+			{"key": "business_open", "prompt": "Is the business open?", "type": "yes_no"},
+			{"key": "business_open", "prompt": "Is the business open today?", "type": "yes_no"},
+		},
+	}, productHandler.SetVisitChecklist)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "InvalidVisitChecklist")
+
+	questions := []map[string]any{
+		{"key": "business_open", "prompt": "Is the business open?", "type": "yes_no", "required": true},
+		{"key": "employees", "prompt": "How many people work there?", "type": "number", "required": true},
+		{"key": "remarks", "prompt": "Anything else worth noting?", "type": "text"},
+		{"key": "shop_front", "prompt": "Photo of the shop front", "type": "photo", "required": true},
+	}
+	rec, err = s.callStaffHandler(
+		http.MethodPut,
+		map[string]string{"product_id": "1"},
+		map[string]any{"questions": questions},
+		productHandler.SetVisitChecklist,
+	)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"version":1`)
+
+	// The field validator sees the questions on the loan, and must answer them
+	rec = httptest.NewRecorder()
+	ctx := s.rest.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+		UserID:      2,
+		Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
+	})
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("1")
+	s.Require().NoError(s.fieldValidatorLoanHandler.CommonHandler.FetchLoan(ctx))
+	assert.Contains(rec.Body.String(), `"visit_checklist":[{"key":"business_open"`)
+
+	invalidReports := []struct {
+		name    string
+		form    map[string]string
+		files   []string
+		wantErr string
+	}{
+		{
+			name:    "missing required answer",
+			form:    map[string]string{"report": `{"business_open": true}`},
+			files:   []string{"report_photo_shop_front"},
+			wantErr: "question `employees` is required",
+		},
+		{
+			name:    "answer of the wrong type",
+			form:    map[string]string{"report": `{"business_open": "yes", "employees": 3}`},
+			files:   []string{"report_photo_shop_front"},
+			wantErr: "question `business_open` expects a yes_no answer",
+		},
+		{
+			name:    "missing required photo",
+			form:    map[string]string{"report": `{"business_open": true, "employees": 3}`},
+			wantErr: "question `shop_front` is required",
+		},
+		{
+			name:    "unknown question",
+			form:    map[string]string{"report": `{"business_open": true, "employees": 3, "owner_present": true}`},
+			files:   []string{"report_photo_shop_front"},
+			wantErr: "unknown question `owner_present`",
+		},
+		{
+			name:    "report is not a JSON object",
+			form:    map[string]string{"report": `[true, 3]`},
+			wantErr: "InvalidBody",
+		},
+	}
+
+	for _, tt := range invalidReports {
+		rec := visit(tt.form, tt.files...)
+		assert.Equal(http.StatusBadRequest, rec.Code, tt.name)
+		assert.Contains(rec.Body.String(), tt.wantErr, tt.name)
+	}
+
+	rec = visit(
+		map[string]string{"report": `{"business_open": true, "employees": 3, "remarks": " Busy market stall "}`},
+		"report_photo_shop_front",
+	)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var visitResp struct {
+		Data dto.FetchLoanDetailResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &visitResp))
+	assert.Empty(visitResp.Data.VisitChecklist)
+	s.Require().NotNil(visitResp.Data.VisitReport)
+
+	answers := visitResp.Data.VisitReport.Answers
+	s.Require().Len(answers, 4)
+	assert.True(*answers[0].YesNo)
+	assert.Equal(3.0, *answers[1].Number)
+	assert.Equal("Busy market stall", *answers[2].Text)
+	assert.Equal("Photo of the shop front", answers[3].Prompt)
+	assert.NotEmpty(answers[3].PhotoURL)
+
+	var photo models.LoanDocument
+	s.Require().NoError(s.db.Where("loan_id = ? AND document_type = ?", 1, models.LoanDocumentVisitReportPhoto).First(&photo).Error)
+	assert.Equal("shop_front", photo.Label)
+	assert.False(photo.DocumentType.VisibleTo([]auth.Permission{auth.PermissionLoanViewInvestable}))
+
+	// Approvers see the report, the borrower does not
+	rec, err = s.callStaffHandler(http.MethodGet, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.CommonHandler.FetchLoan)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"visit_report":{"checklist_version":1`)
+
+	rec = httptest.NewRecorder()
+	ctx = s.rest.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: 7, Permissions: []auth.Permission{auth.PermissionLoanViewOwn}})
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("1")
+	s.Require().NoError(s.borrowerLoanHandler.CommonHandler.FetchLoan(ctx))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.NotContains(rec.Body.String(), "visit_report")
+
+	// A new version of the checklist leaves the report as answered
+	rec, err = s.callStaffHandler(
+		http.MethodPut,
+		map[string]string{"product_id": "1"},
+		map[string]any{"questions": questions[:2]},
+		productHandler.SetVisitChecklist,
+	)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"version":2`)
+
+	rec, err = s.callStaffHandler(http.MethodGet, map[string]string{"loan_id": "1"}, nil, s.staffLoanHandler.CommonHandler.FetchLoan)
+	s.Require().NoError(err)
+	assert.Contains(rec.Body.String(), `"visit_report":{"checklist_version":1`)
+	assert.Contains(rec.Body.String(), `"key":"shop_front"`)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_SyncVisits() {
+	assert := _assert.New(s.T())
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/attachment-path.jpg", ContentType: "image/jpeg"}, nil)
+
+	capturedAt := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	visits := []map[string]any{
+		{"client_id": "visit-a", "loan_id": 1, "latitude": -6.1754, "longitude": 106.8272, "captured_at": capturedAt, "attachment": "a"},
+		// Already visited by this field validator, and no longer proposed
+		{"client_id": "visit-b", "loan_id": 2, "attachment": "b"},
+		{"client_id": "visit-c", "loan_id": 3, "attachment": "b"},
+		{"client_id": "visit-d", "loan_id": 404, "attachment": "b"},
+	}
+
+	// Syncs the visits as the field validator, sending along the named files
+	sync := func(userID uint, visits []map[string]any, files ...string) (*httptest.ResponseRecorder, []dto.VisitSyncResultResp) {
+		manifest, err := json.Marshal(map[string]any{"visits": visits})
+		s.Require().NoError(err)
+
+		body := new(bytes.Buffer)
+		bodyWriter := multipart.NewWriter(body)
+		s.Require().NoError(bodyWriter.WriteField("manifest", string(manifest)))
+
+		for _, name := range files {
+			file, err := bodyWriter.CreateFormFile(name, name+".jpg")
+			s.Require().NoError(err)
+
+			_, err = file.Write(newTestPhoto(s.T(), 40, 20, nil))
+			s.Require().NoError(err)
+		}
+		s.Require().NoError(bodyWriter.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/visits/sync", body)
+		req.Header.Set(echo.HeaderContentType, bodyWriter.FormDataContentType())
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+			UserID:      userID,
+			Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
+		})
+
+		s.Require().NoError(s.fieldValidatorLoanHandler.SyncVisits(ctx))
+
+		var syncResp struct {
+			Data []dto.VisitSyncResultResp `json:"data"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &syncResp)
+
+		return rec, syncResp.Data
+	}
+
+	rec, results := sync(2, visits, "a", "b")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().Len(results, 4)
+
+	assert.Equal(string(models.VisitSyncApplied), results[0].Status)
+	assert.False(results[0].Replayed)
+	s.Require().NotNil(results[0].Loan)
+	assert.Equal("Silvio Berlusconi", results[0].Loan.VisitedBy.Name)
+	assert.Equal("verified", results[0].Loan.Visit.ReviewStatus)
+
+	assert.Equal(string(models.VisitSyncConflict), results[1].Status)
+	assert.Equal(loanModule.ErrLoanAlreadyVisited.ErrorCode, results[1].ErrorCode)
+	assert.Equal(string(models.VisitSyncConflict), results[2].Status)
+	assert.Equal(loanModule.ErrLoanNotProposed.ErrorCode, results[2].ErrorCode)
+	assert.Equal(string(models.LoanStatusApproved), results[2].LoanStatus)
+	assert.Equal(string(models.VisitSyncRejected), results[3].Status)
+	assert.Equal(loanModule.ErrLoanNotFound.ErrorCode, results[3].ErrorCode)
+
+	// Syncing again after a lost response gives the same outcomes, without applying the visit twice
+	rec, results = sync(2, visits[:3], "a", "b")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().Len(results, 3)
+	for i, status := range []models.VisitSyncStatus{models.VisitSyncApplied, models.VisitSyncConflict, models.VisitSyncConflict} {
+		assert.Equal(string(status), results[i].Status)
+		assert.True(results[i].Replayed)
+		assert.Nil(results[i].Loan)
+	}
+
+	var proofs int64
+	s.db.Model(&models.LoanDocument{}).Where("loan_id = ? AND document_type = ?", 1, models.LoanDocumentProofOfVisit).Count(&proofs)
+	assert.Equal(int64(1), proofs)
+
+	// Another field validator's queued visit of the same loan is dropped
+	_, results = sync(11, []map[string]any{{"client_id": "visit-a", "loan_id": 1, "attachment": "a"}}, "a")
+	s.Require().Len(results, 1)
+	assert.Equal(string(models.VisitSyncConflict), results[0].Status)
+	assert.False(results[0].Replayed)
+	assert.Equal(loanModule.ErrLoanAlreadyVisited.ErrorCode, results[0].ErrorCode)
+
+	// Files the manifest refers to must be sent along
+	rec, _ = sync(2, []map[string]any{{"client_id": "visit-e", "loan_id": 1, "attachment": "missing"}}, "a")
+	assert.Equal(http.StatusBadRequest, rec.Code)
+}
+
+// visitLoan marks the loan as visited with a photo without location, the form reports where it was taken
+func (s *loanIntegrationTestSuite) visitLoan(loanID, userID uint, form map[string]string) (*httptest.ResponseRecorder, error) {
+	body := new(bytes.Buffer)
+	bodyWriter := multipart.NewWriter(body)
+
+	file, err := bodyWriter.CreateFormFile("attachment", "visit.jpg")
+	s.Require().NoError(err)
+
+	_, err = file.Write(newTestPhoto(s.T(), 40, 20, nil))
+	s.Require().NoError(err)
+
+	for k, v := range form {
+		s.Require().NoError(bodyWriter.WriteField(k, v))
+	}
+	s.Require().NoError(bodyWriter.Close())
+
+	req := httptest.NewRequest(http.MethodPatch, "/loan/:loan_id/visit", body)
+	req.Header.Set(echo.HeaderContentType, bodyWriter.FormDataContentType())
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+		UserID:      userID,
+		Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit},
+	})
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues(fmt.Sprint(loanID))
+
+	return rec, s.fieldValidatorLoanHandler.MarkLoanBorrowerVisited(ctx)
+}
+
+// callStaffHandler calls the handler as the staff user, with a JSON body
+func (s *loanIntegrationTestSuite) callStaffHandler(
+	method string,
+	params map[string]string,
+	body map[string]any,
+	handler echo.HandlerFunc,
+) (*httptest.ResponseRecorder, error) {
+	payload, err := json.Marshal(body)
+	s.Require().NoError(err)
+
+	req := httptest.NewRequest(method, "/", bytes.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+		UserID:      1,
+		Permissions: []auth.Permission{auth.PermissionLoanViewAll, auth.PermissionLoanApprove, auth.PermissionLoanAssign},
+	})
+
+	for k, v := range params {
+		ctx.SetParamNames(k)
+		ctx.SetParamValues(v)
+	}
+
+	return rec, handler(ctx)
+}
+
 func (s *loanIntegrationTestSuite) TestIntegration_InvestInLoan() {
 	tests := []struct {
 		name    string
@@ -379,7 +1473,7 @@ func (s *loanIntegrationTestSuite) TestIntegration_InvestInLoan() {
 			name:   "throws error given valid request and an unapproved loan",
 			userID: 4,
 			params: map[string]string{
-				"loan_id": "1",
+				"loan_id": "4",
 			},
 			reqStr: `
 				{
@@ -411,7 +1505,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_InvestInLoan() {
 			rec := httptest.NewRecorder()
 			ctx := s.rest.NewContext(req, rec)
 			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
-				UserID: uint(tt.userID),
+				UserID:      uint(tt.userID),
+				Permissions: []auth.Permission{auth.PermissionLoanViewInvestable, auth.PermissionLoanInvest},
 			})
 
 			for k, v := range tt.params {
@@ -474,7 +1569,8 @@ func (s *loanIntegrationTestSuite) TestIntegration_DisburseLoan() {
 			rec := httptest.NewRecorder()
 			ctx := s.rest.NewContext(req, rec)
 			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
-				UserID: uint(tt.userID),
+				UserID:      uint(tt.userID),
+				Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanDisburse},
 			})
 
 			for k, v := range tt.params {
@@ -489,6 +1585,12 @@ func (s *loanIntegrationTestSuite) TestIntegration_DisburseLoan() {
 			if tt.wantErr == nil {
 				assert.NoError(err)
 				assert.Equal(http.StatusOK, rec.Code)
+
+				// Borrower is notified by email, the default channel
+				var queued []models.OutboxMessage
+				s.db.Where("template = ?", email.TemplateLoanDisbursed).Find(&queued)
+				s.Require().Len(queued, 1)
+				assert.Equal(models.NotificationChannelEmail, queued[0].Channel)
 			} else {
 				assert.Contains(got, tt.wantErr.Error())
 			}
@@ -496,6 +1598,353 @@ func (s *loanIntegrationTestSuite) TestIntegration_DisburseLoan() {
 	}
 }
 
+func (s *loanIntegrationTestSuite) TestIntegration_StreamLoan() {
+	assert := _assert.New(s.T())
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+	broker := do.MustInvoke[models.LoanEventBroker](s.injector).(*pubsub.Broker[uint, models.LoanEvent])
+
+	// Committed investments are published to the loan's subscribers
+	events, unsubscribe := loanUsecase.SubscribeLoanEvents(3)
+	defer unsubscribe()
+
+	loan, err := loanUsecase.FetchLoanByID(context.Background(), 3, nil)
+	s.Require().NoError(err)
+	investor := &models.User{Model: gorm.Model{ID: 4}}
+	s.Require().NoError(loanUsecase.InvestInLoan(context.Background(), loan, investor, 5000000))
+
+	select {
+	case event := <-events:
+		assert.Equal(models.LoanEventFundingProgress, event.Type)
+		assert.Equal("10000000.00", event.RemainingAmount)
+		assert.Equal("90%", dto.LoanEventToDto(event).FundedPercentage)
+	case <-time.After(time.Second):
+		s.Fail("no funding progress event")
+	}
+
+	// Rejected investments are not
+	s.Require().Error(loanUsecase.InvestInLoan(context.Background(), loan, investor, 50000000))
+	assert.Empty(events)
+
+	// The stream starts with the current state, and ends when the loan is disbursed
+	req := httptest.NewRequest(http.MethodGet, "/loans/:loan_id/stream", nil)
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: 5, Permissions: []auth.Permission{auth.PermissionLoanViewInvestable}})
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("4")
+
+	done := make(chan error)
+	go func() {
+		done <- s.investorLoanHandler.StreamLoan(ctx)
+	}()
+
+	assert.Eventually(func() bool { return broker.SubscriberCount(4) == 1 }, time.Second, time.Millisecond*10)
+
+	loan, err = loanUsecase.FetchLoanByID(context.Background(), 4, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(loanUsecase.DisburseLoan(context.Background(), loan, &models.User{Model: gorm.Model{ID: 2}}))
+
+	select {
+	case err := <-done:
+		assert.NoError(err)
+	case <-time.After(time.Second):
+		s.FailNow("stream did not end after disbursement")
+	}
+
+	assert.Equal("text/event-stream", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(0, broker.SubscriberCount(4))
+
+	body := rec.Body.String()
+	assert.Contains(body, "event: snapshot\ndata: {\"type\":\"snapshot\",\"loan_id\":4,\"status\":\"invested\"")
+	assert.Contains(body, "event: status_changed\ndata: {\"type\":\"status_changed\",\"loan_id\":4,\"status\":\"disbursed\"")
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_DownloadLoanDocument() {
+	assert := _assert.New(s.T())
+	borrowerClaims := auth.AuthClaims{UserID: 8, Permissions: []auth.Permission{auth.PermissionLoanViewOwn}}
+	fieldValidatorClaims := auth.AuthClaims{UserID: 2, Permissions: []auth.Permission{auth.PermissionLoanViewProposed}}
+
+	download := func(claims auth.AuthClaims, signedURL string) (*httptest.ResponseRecorder, error) {
+		parsedURL, err := url.Parse(signedURL)
+		s.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodGet, parsedURL.RequestURI(), nil)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, claims)
+		ctx.SetParamNames("loan_id", "document_id", "variant")
+		// e.g. 2/documents/1/file
+		segments := strings.Split(strings.TrimPrefix(parsedURL.Path, "/app/loans/"), "/")
+		ctx.SetParamValues(segments[0], segments[2], segments[3])
+
+		return rec, s.documentLoanHandler.DownloadLoanDocument(ctx)
+	}
+
+	// Loan responses carry a signed link instead of the storage key
+	req := httptest.NewRequest(http.MethodGet, "/loan/:loan_id", nil)
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, fieldValidatorClaims)
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("2")
+
+	s.Require().NoError(s.borrowerLoanHandler.CommonHandler.FetchLoan(ctx))
+	assert.NotContains(rec.Body.String(), "picsum.photos")
+	assert.NotContains(rec.Body.String(), "agreement_url")
+	assert.NotContains(rec.Body.String(), "proof_of_visit_thumbnail_url")
+
+	var loanResp struct {
+		Data dto.FetchMyLoansResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &loanResp))
+	signedURL := loanResp.Data.ProofOfVisitURL
+	assert.Contains(signedURL, "/app/loans/2/documents/1/file?expires=")
+
+	s.uploadSvc.On("DownloadFile", mock.Anything, "https://picsum.photos/seed/loanservice/900/1600").
+		Return(io.NopCloser(strings.NewReader("proof of visit")), &upload.UploadedFile{
+			Key:         "https://picsum.photos/seed/loanservice/900/1600",
+			ContentType: "image/jpeg",
+		}, nil).Once()
+
+	rec, err := download(fieldValidatorClaims, signedURL)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("image/jpeg", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("private, no-store", rec.Header().Get("Cache-Control"))
+	assert.Equal("proof of visit", rec.Body.String())
+
+	// Other field validators cannot see the loan, even with a valid link
+	_, err = download(auth.AuthClaims{UserID: 11, Permissions: []auth.Permission{auth.PermissionLoanViewProposed}}, signedURL)
+	assert.Error(err)
+
+	// The proof of visit shows the borrower's premises, so only staff and field validators see it
+	req = httptest.NewRequest(http.MethodGet, "/loan/:loan_id", nil)
+	rec = httptest.NewRecorder()
+	ctx = s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, borrowerClaims)
+	ctx.SetParamNames("loan_id")
+	ctx.SetParamValues("2")
+
+	s.Require().NoError(s.borrowerLoanHandler.CommonHandler.FetchLoan(ctx))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.NotContains(rec.Body.String(), "proof_of_visit_url")
+
+	rec, err = download(borrowerClaims, signedURL)
+	s.Require().NoError(err)
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	// Tampered and expired links are rejected
+	rec, err = download(fieldValidatorClaims, strings.Replace(signedURL, "/file", "/thumbnail", 1))
+	s.Require().NoError(err)
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), upload.ErrInvalidSignedURL.ErrorCode)
+
+	rec, err = download(fieldValidatorClaims, upload.SignURL("/app/loans/2/documents/1/file", -time.Minute, time.Now()))
+	s.Require().NoError(err)
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), upload.ErrSignedURLExpired.ErrorCode)
+
+	// Documents without the variant, and documents of other loans
+	rec, err = download(fieldValidatorClaims, upload.SignURL("/app/loans/2/documents/1/thumbnail", time.Minute, time.Now()))
+	s.Require().NoError(err)
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	rec, err = download(fieldValidatorClaims, upload.SignURL("/app/loans/2/documents/2/file", time.Minute, time.Now()))
+	s.Require().NoError(err)
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	// ID cards are hidden from investors
+	investorClaims := auth.AuthClaims{UserID: 5, Permissions: []auth.Permission{auth.PermissionLoanViewInvestable}}
+	rec, err = download(investorClaims, upload.SignURL("/app/loans/3/documents/4/file", time.Minute, time.Now()))
+	s.Require().NoError(err)
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanDocumentNotFound.ErrorCode)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_UploadLoanDocument() {
+	assert := _assert.New(s.T())
+	borrowerClaims := auth.AuthClaims{
+		UserID:      9,
+		Permissions: []auth.Permission{auth.PermissionLoanViewOwn, auth.PermissionLoanCreate},
+	}
+	fieldValidatorClaims := auth.AuthClaims{
+		UserID:      2,
+		Permissions: []auth.Permission{auth.PermissionLoanViewProposed, auth.PermissionLoanVisit, auth.PermissionLoanDisburse},
+	}
+
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/document.jpg", ContentType: "image/jpeg"}, nil)
+
+	uploadDocument := func(claims auth.AuthClaims, loanID, documentType string) *httptest.ResponseRecorder {
+		body := new(bytes.Buffer)
+		bodyWriter := multipart.NewWriter(body)
+		s.Require().NoError(bodyWriter.WriteField("document_type", documentType))
+		file, err := bodyWriter.CreateFormFile("file", "document.jpg")
+		s.Require().NoError(err)
+		_, err = file.Write(newTestPhoto(s.T(), 40, 20, nil))
+		s.Require().NoError(err)
+		s.Require().NoError(bodyWriter.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/loans/:loan_id/documents", body)
+		req.Header.Set(echo.HeaderContentType, bodyWriter.FormDataContentType())
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, claims)
+		ctx.SetParamNames("loan_id")
+		ctx.SetParamValues(loanID)
+
+		s.Require().NoError(s.documentLoanHandler.UploadLoanDocument(ctx))
+
+		return rec
+	}
+
+	fetchDocuments := func(claims auth.AuthClaims, loanID, query string) []dto.LoanDocumentResp {
+		req := httptest.NewRequest(http.MethodGet, "/loans/:loan_id/documents?"+query, nil)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, claims)
+		ctx.SetParamNames("loan_id")
+		ctx.SetParamValues(loanID)
+
+		s.Require().NoError(s.documentLoanHandler.FetchLoanDocuments(ctx))
+		s.Require().Equal(http.StatusOK, rec.Code)
+
+		var documentsResp struct {
+			Data []dto.LoanDocumentResp `json:"data"`
+		}
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &documentsResp))
+
+		return documentsResp.Data
+	}
+
+	// A new ID card supersedes the previous one
+	rec := uploadDocument(borrowerClaims, "3", string(models.LoanDocumentIDCard))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	var documentResp struct {
+		Data dto.LoanDocumentResp `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &documentResp))
+	assert.Equal(2, documentResp.Data.Version)
+	assert.Equal(ptr.NewUintPtr(4), documentResp.Data.SupersedesID)
+	assert.Equal("Rayfe Hamid", documentResp.Data.UploadedBy.Name)
+	assert.Contains(documentResp.Data.URL, fmt.Sprintf("/app/loans/3/documents/%d/file?expires=", documentResp.Data.ID))
+	assert.NotEmpty(documentResp.Data.ThumbnailURL)
+
+	var previous models.LoanDocument
+	s.Require().NoError(s.db.First(&previous, 4).Error)
+	assert.Equal(&documentResp.Data.ID, previous.SupersededByID)
+
+	// Borrowers see their ID cards, but not the proof of visit of their premises
+	documents := fetchDocuments(borrowerClaims, "3", "")
+	s.Require().Len(documents, 1)
+	assert.Equal(string(models.LoanDocumentIDCard), documents[0].DocumentType)
+	assert.Equal(2, documents[0].Version)
+	assert.Len(fetchDocuments(borrowerClaims, "3", "include_superseded=true"), 2)
+
+	// Investors see neither personal data nor the proof of visit
+	investorClaims := auth.AuthClaims{UserID: 5, Permissions: []auth.Permission{auth.PermissionLoanViewInvestable}}
+	assert.Empty(fetchDocuments(investorClaims, "3", "include_superseded=true"))
+
+	// Each document type can only be uploaded by the roles handling it
+	rec = uploadDocument(borrowerClaims, "3", string(models.LoanDocumentDisbursementReceipt))
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanDocumentNotAllowed.ErrorCode)
+
+	rec = uploadDocument(borrowerClaims, "3", string(models.LoanDocumentProofOfVisit))
+	assert.Equal(http.StatusForbidden, rec.Code)
+
+	rec = uploadDocument(borrowerClaims, "3", "selfie")
+	assert.Equal(http.StatusBadRequest, rec.Code)
+
+	// The proof of visit can be retaken until the loan is approved. A reviewed visit goes back to review, keeping the
+	// location reported with it, and votes on the previous proof no longer count.
+	s.Require().NoError(s.db.Model(&models.Loan{}).Where("id = ?", 2).Updates(map[string]any{
+		"visit_latitude":        -6.2,
+		"visit_longitude":       106.8,
+		"visit_location_source": models.VisitLocationFromRequest,
+		"visit_review_status":   models.VisitReviewAccepted,
+		"visit_reviewer_id":     1,
+		"visit_review_note":     "Borrower moved to the shop next door",
+	}).Error)
+
+	rec = uploadDocument(fieldValidatorClaims, "2", string(models.LoanDocumentProofOfVisit))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &documentResp))
+	assert.Equal(2, documentResp.Data.Version)
+
+	// Staff may hold the visit permission, but only the visitor retakes the proof
+	staffClaims := auth.AuthClaims{
+		UserID:      1,
+		Permissions: []auth.Permission{auth.PermissionLoanViewAll, auth.PermissionLoanVisit},
+	}
+	rec = uploadDocument(staffClaims, "2", string(models.LoanDocumentProofOfVisit))
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanDocumentNotAllowed.ErrorCode)
+
+	var retaken models.Loan
+	s.Require().NoError(s.db.First(&retaken, 2).Error)
+	assert.Equal(models.VisitReviewPending, retaken.Visit.ReviewStatus)
+	assert.Equal(models.VisitLocationFromRequest, retaken.Visit.LocationSource)
+	assert.Equal(ptr.NewFloat64Ptr(-6.2), retaken.Visit.Latitude)
+	assert.Equal(ptr.NewUintPtr(1), retaken.Visit.ReviewerID)
+	assert.Equal(1, retaken.ApprovalRound)
+
+	rec = uploadDocument(fieldValidatorClaims, "3", string(models.LoanDocumentProofOfVisit))
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), loanModule.ErrLoanDocumentLocked.ErrorCode)
+}
+
+func (s *loanIntegrationTestSuite) TestIntegration_MigrateLoanDocuments() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	// Attachments as they were stored before loan documents
+	s.Require().NoError(s.db.Exec(`
+		ALTER TABLE loans
+			ADD COLUMN proof_of_visit_attachment_file text DEFAULT '',
+			ADD COLUMN agreement_attachment_file text DEFAULT ''`).Error)
+	s.Require().NoError(s.db.Exec(`UPDATE loans SET proof_of_visit_attachment_file = 'attachments/legacy.jpg' WHERE id = 1`).Error)
+	s.Require().NoError(s.db.Exec(`UPDATE loans SET agreement_attachment_file = 'attachments/agreement.pdf' WHERE id = 4`).Error)
+
+	s.Require().NoError(loanModule.MigrateLoanDocuments(ctx, s.db))
+
+	var migrated []models.LoanDocument
+	s.Require().NoError(s.db.Where("file_key IN ?", []string{"attachments/legacy.jpg", "attachments/agreement.pdf"}).
+		Order("loan_id").Find(&migrated).Error)
+	s.Require().Len(migrated, 2)
+	assert.Equal(uint(1), migrated[0].LoanID)
+	assert.Equal(models.LoanDocumentProofOfVisit, migrated[0].DocumentType)
+	assert.Equal(1, migrated[0].Version)
+	assert.Nil(migrated[0].UploaderID)
+	assert.Equal(uint(4), migrated[1].LoanID)
+	assert.Equal(models.LoanDocumentSignedAgreement, migrated[1].DocumentType)
+
+	assert.False(s.db.Migrator().HasColumn(&models.Loan{}, "proof_of_visit_attachment_file"))
+	assert.False(s.db.Migrator().HasColumn(&models.Loan{}, "agreement_attachment_file"))
+
+	// Nothing is left to migrate on later runs
+	var count int64
+	s.Require().NoError(loanModule.MigrateLoanDocuments(ctx, s.db))
+	s.Require().NoError(s.db.Model(&models.LoanDocument{}).Count(&count).Error)
+	assert.Equal(int64(6), count)
+
+	// Migrated documents are superseded by the next upload of their type
+	s.uploadSvc.On("UploadFile", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "image/jpeg").
+		Return(&upload.UploadedFile{Key: "attachments/agreement.jpg", ContentType: "image/jpeg"}, nil)
+
+	loanUsecase := do.MustInvoke[models.LoanUsecase](s.injector)
+	loan, err := loanUsecase.FetchLoanByID(ctx, 4, nil)
+	s.Require().NoError(err)
+
+	document, err := loanUsecase.UploadLoanDocument(ctx, loan, &models.User{Model: gorm.Model{ID: 1}},
+		[]auth.Permission{auth.PermissionLoanDisburse}, models.LoanDocumentSignedAgreement,
+		bytes.NewReader(newTestPhoto(s.T(), 40, 20, nil)))
+	s.Require().NoError(err)
+	assert.Equal(2, document.Version)
+	assert.Equal(&migrated[1].ID, document.SupersedesID)
+}
+
 func (s *loanIntegrationTestSuite) SeedData() {
 	products := []models.Product{
 		{
@@ -578,11 +2027,15 @@ func (s *loanIntegrationTestSuite) SeedData() {
 			RoleID:   5,
 		},
 		{
-			Name:     "Nuhut Bingsar",
-			Email:    "nuhutbingsar@indonesia.go.id",
-			Password: "nuhut@borrower",
-			IsActive: true,
-			RoleID:   5,
+			Name:             "Nuhut Bingsar",
+			Email:            "nuhutbingsar@indonesia.go.id",
+			Password:         "nuhut@borrower",
+			IsActive:         true,
+			RoleID:           5,
+			Address:          "Jl. Medan Merdeka Barat No. 1, Jakarta",
+			AddressLatitude:  ptr.NewFloat64Ptr(-6.1754),
+			AddressLongitude: ptr.NewFloat64Ptr(106.8272),
+			Region:           "Jakarta Pusat",
 		},
 		{
 			Name:     "Gibro Rakbro",
@@ -605,6 +2058,14 @@ func (s *loanIntegrationTestSuite) SeedData() {
 			IsActive: true,
 			RoleID:   5,
 		},
+		{
+			Name:     "Mario Draghi",
+			Email:    "mario.draghi@loanservice.io",
+			Password: "@field.validator",
+			IsActive: true,
+			RoleID:   3,
+			Region:   "Jakarta Pusat",
+		},
 	}
 
 	for i := range users {
@@ -615,6 +2076,31 @@ func (s *loanIntegrationTestSuite) SeedData() {
 		panic(fmt.Errorf("cannot bulk insert users: %v", err))
 	}
 
+	// Every borrower but the last one is verified, the last one is waiting for review
+	profiles := []models.KYCProfile{}
+	for userID := uint(6); userID <= 10; userID++ {
+		status := models.KYCStatusVerified
+		if userID == 10 {
+			status = models.KYCStatusPending
+		}
+
+		profiles = append(profiles, models.KYCProfile{
+			UserID:        userID,
+			NIK:           fmt.Sprintf("31710100010000%02d", userID),
+			DateOfBirth:   time.Now().AddDate(-40, 0, 0),
+			Address:       "Jl. Medan Merdeka Selatan No. 8-9, Jakarta Pusat",
+			PhoneNumber:   "+6281234567890",
+			Occupation:    "Entrepreneur",
+			MonthlyIncome: "15000000.00",
+			Status:        status,
+			SubmittedAt:   time.Now(),
+		})
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&profiles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert KYC profiles: %v", err))
+	}
+
 	loans := []models.Loan{
 		{
 			Name:            "Crowdfunding bayar kosan sama cicilan",
@@ -629,48 +2115,47 @@ func (s *loanIntegrationTestSuite) SeedData() {
 			LoanTerm:        int(models.TermLength3Month),
 		},
 		{
-			Name:                       "Pinjem dulu seratus",
-			Status:                     models.LoanStatusProposed,
-			BorrowerID:                 8,
-			ProductID:                  3,
-			PrincipalAmount:            "100000000.0",
-			RemainingAmount:            "15000000.0",
-			InterestRate:               0.06942,
-			TotalInterest:              "6942000.0",
-			ROI:                        "6.94",
-			LoanTerm:                   int(models.TermLength12Month),
-			VisitorID:                  ptr.NewUintPtr(2),
-			ProofOfVisitAttachmentFile: "https://picsum.photos/seed/loanservice/900/1600",
-		},
-		{
-			Name:                       "Beli gelar",
-			Status:                     models.LoanStatusApproved,
-			BorrowerID:                 9,
-			ProductID:                  3,
-			PrincipalAmount:            "100000000.0",
-			RemainingAmount:            "15000000.0",
-			InterestRate:               0.06942,
-			TotalInterest:              "6942000.0",
-			ROI:                        "6.94",
-			LoanTerm:                   int(models.TermLength12Month),
-			VisitorID:                  ptr.NewUintPtr(2),
-			ProofOfVisitAttachmentFile: "https://picsum.photos/seed/loanservice/900/1600",
-			ApproverID:                 ptr.NewUintPtr(1),
-		},
-		{
-			Name:                       "Biaya rekaman album baru",
-			Status:                     models.LoanStatusInvested,
-			BorrowerID:                 10,
-			ProductID:                  2,
-			PrincipalAmount:            "10000000.0",
-			RemainingAmount:            "10000000.0",
-			InterestRate:               0.08,
-			TotalInterest:              "800000.0",
-			ROI:                        "8",
-			LoanTerm:                   int(models.TermLength6Month),
-			VisitorID:                  ptr.NewUintPtr(2),
-			ProofOfVisitAttachmentFile: "https://picsum.photos/seed/loanservice/900/1600",
-			ApproverID:                 ptr.NewUintPtr(1),
+			Name:            "Pinjem dulu seratus",
+			Status:          models.LoanStatusProposed,
+			BorrowerID:      8,
+			ProductID:       3,
+			PrincipalAmount: "100000000.0",
+			RemainingAmount: "15000000.0",
+			InterestRate:    0.06942,
+			TotalInterest:   "6942000.0",
+			ROI:             "6.94",
+			LoanTerm:        int(models.TermLength12Month),
+			VisitorID:       ptr.NewUintPtr(2),
+		},
+		{
+			Name:            "Beli gelar",
+			Status:          models.LoanStatusApproved,
+			BorrowerID:      9,
+			ProductID:       3,
+			PrincipalAmount: "100000000.0",
+			RemainingAmount: "15000000.0",
+			InterestRate:    0.06942,
+			TotalInterest:   "6942000.0",
+			ROI:             "6.94",
+			LoanTerm:        int(models.TermLength12Month),
+			VisitorID:       ptr.NewUintPtr(2),
+			ApproverID:      ptr.NewUintPtr(1),
+			RiskGrade:       models.RiskGradeB,
+		},
+		{
+			Name:            "Biaya rekaman album baru",
+			Status:          models.LoanStatusInvested,
+			BorrowerID:      10,
+			ProductID:       2,
+			PrincipalAmount: "10000000.0",
+			RemainingAmount: "10000000.0",
+			InterestRate:    0.08,
+			TotalInterest:   "800000.0",
+			ROI:             "8",
+			LoanTerm:        int(models.TermLength6Month),
+			VisitorID:       ptr.NewUintPtr(2),
+			ApproverID:      ptr.NewUintPtr(1),
+			RiskGrade:       models.RiskGradeB,
 		},
 	}
 
@@ -678,6 +2163,56 @@ func (s *loanIntegrationTestSuite) SeedData() {
 		panic(fmt.Errorf("cannot bulk insert loans: %v", err))
 	}
 
+	documents := []models.LoanDocument{
+		{
+			LoanID:       2,
+			DocumentType: models.LoanDocumentProofOfVisit,
+			Version:      1,
+			UploaderID:   ptr.NewUintPtr(2),
+			FileKey:      "https://picsum.photos/seed/loanservice/900/1600",
+		},
+		{
+			LoanID:       3,
+			DocumentType: models.LoanDocumentProofOfVisit,
+			Version:      1,
+			UploaderID:   ptr.NewUintPtr(2),
+			FileKey:      "https://picsum.photos/seed/loanservice/900/1600",
+		},
+		{
+			LoanID:       4,
+			DocumentType: models.LoanDocumentProofOfVisit,
+			Version:      1,
+			UploaderID:   ptr.NewUintPtr(2),
+			FileKey:      "https://picsum.photos/seed/loanservice/900/1600",
+		},
+		{
+			LoanID:       3,
+			DocumentType: models.LoanDocumentIDCard,
+			Version:      1,
+			UploaderID:   ptr.NewUintPtr(9),
+			FileKey:      "attachments/id_card_3.jpg",
+		},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&documents).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert loan documents: %v", err))
+	}
+
+	assignments := []models.LoanAssignment{
+		{
+			LoanID:           1,
+			FieldValidatorID: 2,
+			Status:           models.LoanAssignmentActive,
+			Strategy:         models.AssignmentManual,
+			AssignedByID:     ptr.NewUintPtr(1),
+			DueAt:            time.Now().Add(models.DefaultVisitSLA),
+		},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignments).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert loan assignments: %v", err))
+	}
+
 	investments := []models.Investment{
 		{
 			InvestorID: 6,
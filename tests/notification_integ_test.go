@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"loan-service/models"
+	"loan-service/services/sms"
+	"loan-service/services/whatsapp"
+	"loan-service/utils/i18n"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type notificationIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func TestIntegrationNotification(t *testing.T) {
+	suite.Run(t, new(notificationIntegrationTestSuite))
+}
+
+func (s *notificationIntegrationTestSuite) TestIntegration_TwilioSMSService() {
+	assert := _assert.New(s.T())
+
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "AC123" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal("/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		s.Require().NoError(r.ParseForm())
+		form = r.PostForm
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	smsSvc := sms.NewTwilioSMSService(sms.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "token",
+		From:       "LoanSvc",
+		BaseURL:    server.URL,
+	}, server.Client())
+
+	err := smsSvc.SendSMS(context.Background(), "+6281234567890", "Your loan is approved")
+	s.Require().NoError(err)
+	assert.Equal("+6281234567890", form.Get("To"))
+	assert.Equal("LoanSvc", form.Get("From"))
+	assert.Equal("Your loan is approved", form.Get("Body"))
+
+	smsSvc = sms.NewTwilioSMSService(sms.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "wrong",
+		BaseURL:    server.URL,
+	}, server.Client())
+
+	err = smsSvc.SendSMS(context.Background(), "+6281234567890", "Your loan is approved")
+	assert.ErrorIs(err, sms.ErrSMSNotSent)
+}
+
+func (s *notificationIntegrationTestSuite) TestIntegration_CloudAPIWhatsAppService() {
+	assert := _assert.New(s.T())
+
+	var message map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal("/1065/messages", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		s.Require().NoError(json.Unmarshal(body, &message))
+
+		_, _ = w.Write([]byte(`{"messages": [{"id": "wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	whatsAppSvc := whatsapp.NewCloudAPIWhatsAppService(whatsapp.CloudAPIConfig{
+		PhoneNumberID: "1065",
+		AccessToken:   "token",
+		BaseURL:       server.URL,
+	}, server.Client())
+
+	err := whatsAppSvc.SendTemplateMessage(
+		context.Background(), "+6281234567890", "loan_approved", i18n.LocaleIndonesian, "Pinjaman\n  disetujui",
+	)
+	s.Require().NoError(err)
+	assert.Equal("whatsapp", message["messaging_product"])
+	assert.Equal("6281234567890", message["to"])
+	assert.Equal("template", message["type"])
+
+	template := message["template"].(map[string]any)
+	assert.Equal("loan_approved", template["name"])
+	assert.Equal(map[string]any{"code": "id"}, template["language"])
+	assert.Contains(string(mustMarshal(template["components"])), `"text":"Pinjaman disetujui"`)
+
+	whatsAppSvc = whatsapp.NewCloudAPIWhatsAppService(whatsapp.CloudAPIConfig{
+		PhoneNumberID: "1065",
+		AccessToken:   "wrong",
+		BaseURL:       server.URL,
+	}, server.Client())
+
+	err = whatsAppSvc.SendTemplateMessage(context.Background(), "+6281234567890", "loan_approved", i18n.LocaleEnglish, "Approved")
+	assert.ErrorIs(err, whatsapp.ErrWhatsAppNotSent)
+}
+
+func (s *notificationIntegrationTestSuite) TestIntegration_NextInstallmentReminder() {
+	disbursedAt := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		remindersSent int
+		now           time.Time
+		want          int
+		wantOK        bool
+	}{
+		{"not disbursed", 0, time.Time{}, 0, false},
+		{"too early", 0, disbursedAt.AddDate(0, 0, 20), 0, false},
+		{"within lead time", 0, time.Date(2024, time.February, 13, 12, 0, 0, 0, time.UTC), 1, true},
+		{"already reminded", 1, time.Date(2024, time.February, 13, 12, 0, 0, 0, time.UTC), 0, false},
+		{"skips past due installments", 0, time.Date(2024, time.April, 13, 12, 0, 0, 0, time.UTC), 3, true},
+		{"all installments reminded", 6, disbursedAt.AddDate(0, 6, -1), 0, false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			loan := &models.Loan{Status: models.LoanStatusDisbursed, LoanTerm: 6, InstallmentRemindersSent: tt.remindersSent}
+			if !tt.now.IsZero() {
+				loan.DisbursedAt = &disbursedAt
+			}
+
+			got, ok := loan.NextInstallmentReminder(tt.now)
+			assert.Equal(tt.wantOK, ok)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func mustMarshal(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
@@ -0,0 +1,408 @@
+package integration
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"loan-service/app"
+	"loan-service/config"
+	"loan-service/models"
+	userModule "loan-service/modules/users"
+	_userHandlers "loan-service/modules/users/handlers"
+	"loan-service/services/auth"
+	"loan-service/services/oidc"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	fakeIdPClientID     = "loan-service"
+	fakeIdPClientSecret = "client-secret"
+	fakeIdPKeyID        = "test-key"
+)
+
+// fakeIdentityProvider is a minimal in-process OIDC provider issuing RS256 ID tokens
+type fakeIdentityProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthCode
+}
+
+type fakeAuthCode struct {
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+func newFakeIdentityProvider() *fakeIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	idp := &fakeIdentityProvider{key: key, codes: map[string]fakeAuthCode{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kid": fakeIdPKeyID,
+					"kty": "RSA",
+					"alg": "RS256",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+
+	idp.server = httptest.NewServer(mux)
+
+	return idp
+}
+
+func (idp *fakeIdentityProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != fakeIdPClientID || clientSecret != fakeIdPClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	idp.mu.Lock()
+	authCode, ok := idp.codes[r.FormValue("code")]
+	delete(idp.codes, r.FormValue("code"))
+	idp.mu.Unlock()
+
+	if !ok || oidc.CodeChallenge(r.FormValue("code_verifier")) != authCode.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, authCode.claims)
+	token.Header["kid"] = fakeIdPKeyID
+
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+// authorize simulates the user signing in at the provider, returning the authorization code
+func (idp *fakeIdentityProvider) authorize(authURL string, claims jwt.MapClaims) string {
+	parsedURL, err := url.Parse(authURL)
+	if err != nil {
+		panic(err)
+	}
+
+	query := parsedURL.Query()
+	now := time.Now()
+
+	idTokenClaims := jwt.MapClaims{
+		"iss":   idp.server.URL,
+		"aud":   query.Get("client_id"),
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		idTokenClaims[k] = v
+	}
+
+	code := fmt.Sprintf("code-%d", now.UnixNano())
+
+	idp.mu.Lock()
+	idp.codes[code] = fakeAuthCode{codeChallenge: query.Get("code_challenge"), claims: idTokenClaims}
+	idp.mu.Unlock()
+
+	return code
+}
+
+type oidcIntegrationTestSuite struct {
+	suite.Suite
+	db          *gorm.DB
+	rest        *echo.Echo
+	idp         *fakeIdentityProvider
+	oidcHandler *_userHandlers.OIDCHandler
+	models      []interface{}
+	injector    *do.Injector
+}
+
+func TestIntegrationOIDC(t *testing.T) {
+	suite.Run(t, new(oidcIntegrationTestSuite))
+}
+
+func (s *oidcIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.injector = app.SetupInjections(s.db, s.rest, nil, nil, nil, nil)
+
+	s.idp = newFakeIdentityProvider()
+
+	if config.Data.AppSecret == "" {
+		config.Data.AppSecret = "secret"
+	}
+
+	s.oidcHandler = &_userHandlers.OIDCHandler{
+		Usecase: do.MustInvoke[models.UserUsecase](s.injector),
+		Provider: oidc.NewProvider(oidc.Config{
+			IssuerURL:    s.idp.server.URL,
+			ClientID:     fakeIdPClientID,
+			ClientSecret: fakeIdPClientSecret,
+			RedirectURL:  "http://localhost:8080/oidc/callback",
+			GroupsClaim:  "groups",
+			RoleMapping: []oidc.RoleMapping{
+				{Group: "loan-admins", RoleType: auth.RoleTypeSuperuser},
+				{Group: "loan-staff", RoleType: auth.RoleTypeStaff},
+				{Group: "loan-auditors", RoleType: "auditor"},
+				{Group: "loan-reviewers", RoleType: "reviewer"},
+			},
+		}, s.idp.server.Client()),
+	}
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+	}
+}
+
+func (s *oidcIntegrationTestSuite) TearDownSuite() {
+	s.idp.server.Close()
+}
+
+func (s *oidcIntegrationTestSuite) TestIntegration_OIDCCallback() {
+	tests := []struct {
+		name         string
+		claims       jwt.MapClaims
+		tamperState  bool
+		wantCode     int
+		wantErr      string
+		wantRoleType auth.RoleType
+	}{
+		{
+			name: "provisions user given first login with mapped group",
+			claims: jwt.MapClaims{
+				"sub":            "idp|olaf",
+				"email":          "olaf@loanservice.io",
+				"email_verified": true,
+				"name":           "Olaf Scholz",
+				"groups":         []string{"everyone", "loan-staff"},
+			},
+			wantCode:     http.StatusOK,
+			wantRoleType: auth.RoleTypeStaff,
+		},
+		{
+			name: "links existing user and syncs role given verified email",
+			claims: jwt.MapClaims{
+				"sub":            "idp|emmanuel",
+				"email":          "staff@loanservice.io",
+				"email_verified": true,
+				"name":           "Emmanuel Macron",
+				"groups":         []string{"loan-staff", "loan-admins"},
+			},
+			wantCode:     http.StatusOK,
+			wantRoleType: auth.RoleTypeSuperuser,
+		},
+		{
+			name: "picks the earlier mapping given several mapped custom roles",
+			claims: jwt.MapClaims{
+				"sub":            "idp|angela",
+				"email":          "angela@loanservice.io",
+				"email_verified": true,
+				"name":           "Angela Merkel",
+				"groups":         []string{"loan-reviewers", "loan-auditors"},
+			},
+			wantCode:     http.StatusOK,
+			wantRoleType: "auditor",
+		},
+		{
+			name: "throws error given no mapped group",
+			claims: jwt.MapClaims{
+				"sub":            "idp|mario",
+				"email":          "mario@loanservice.io",
+				"email_verified": true,
+				"groups":         []string{"everyone"},
+			},
+			wantCode: http.StatusForbidden,
+			wantErr:  userModule.ErrSSORoleNotMapped.ErrorCode,
+		},
+		{
+			name: "throws error given ID token for another client",
+			claims: jwt.MapClaims{
+				"sub":    "idp|olaf",
+				"aud":    "another-client",
+				"groups": []string{"loan-staff"},
+			},
+			wantCode: http.StatusUnauthorized,
+			wantErr:  oidc.ErrOIDCInvalidIDToken.ErrorCode,
+		},
+		{
+			name: "throws error given ID token with another nonce",
+			claims: jwt.MapClaims{
+				"sub":    "idp|olaf",
+				"nonce":  "replayed-nonce",
+				"groups": []string{"loan-staff"},
+			},
+			wantCode: http.StatusUnauthorized,
+			wantErr:  oidc.ErrOIDCInvalidIDToken.ErrorCode,
+		},
+		{
+			name: "throws error given mismatched state",
+			claims: jwt.MapClaims{
+				"sub":    "idp|olaf",
+				"groups": []string{"loan-staff"},
+			},
+			tamperState: true,
+			wantCode:    http.StatusUnauthorized,
+			wantErr:     oidc.ErrOIDCInvalidState.ErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Start login and follow the redirect to the identity provider
+			loginReq := httptest.NewRequest(http.MethodGet, "/oidc/login", nil)
+			loginRec := httptest.NewRecorder()
+			err := s.oidcHandler.Login(s.rest.NewContext(loginReq, loginRec))
+			s.Require().NoError(err)
+			s.Require().Equal(http.StatusFound, loginRec.Code)
+
+			authURL := loginRec.Header().Get(echo.HeaderLocation)
+			state := s.mustQueryParam(authURL, "state")
+			if tt.tamperState {
+				state = "tampered"
+			}
+			code := s.idp.authorize(authURL, tt.claims)
+
+			// Build callback request and its context
+			req := httptest.NewRequest(http.MethodGet, "/oidc/callback?"+url.Values{
+				"code":  {code},
+				"state": {state},
+			}.Encode(), nil)
+			for _, cookie := range loginRec.Result().Cookies() {
+				req.AddCookie(cookie)
+			}
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+
+			// Do test and assert
+			err = s.oidcHandler.Callback(ctx)
+			got := strings.TrimSpace(rec.Body.String())
+
+			assert.NoError(err)
+			assert.Equal(tt.wantCode, rec.Code)
+
+			if tt.wantErr != "" {
+				assert.Contains(got, tt.wantErr)
+				return
+			}
+
+			claims, err := auth.ParseAccessToken(rec.Header().Get("Authorization"))
+			s.Require().NoError(err)
+			assert.Equal(tt.wantRoleType, claims.RoleType)
+
+			var user models.User
+			s.Require().NoError(s.db.Preload("Role").First(&user, claims.UserID).Error)
+			assert.Equal(tt.claims["sub"], *user.OIDCSubject)
+			assert.Equal(tt.wantRoleType, user.Role.RoleType)
+			assert.True(user.IsActive)
+		})
+	}
+}
+
+func (s *oidcIntegrationTestSuite) mustQueryParam(rawURL, key string) string {
+	parsedURL, err := url.Parse(rawURL)
+	s.Require().NoError(err)
+
+	return parsedURL.Query().Get(key)
+}
+
+func (s *oidcIntegrationTestSuite) SeedData() {
+	roles := []models.Role{
+		{Name: "Superuser", RoleType: auth.RoleTypeSuperuser},
+		{Name: "Staff", RoleType: auth.RoleTypeStaff},
+		{Name: "Auditor", RoleType: "auditor"},
+		{Name: "Reviewer", RoleType: "reviewer"},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
+	}
+
+	users := []models.User{
+		{
+			Name:     "Emmanuel Macron",
+			Email:    "staff@loanservice.io",
+			Password: "@staff",
+			IsActive: true,
+			RoleID:   2,
+		},
+	}
+
+	for i := range users {
+		users[i].SetNewPassword(users[i].Password)
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert users: %v", err))
+	}
+}
+
+func (s *oidcIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *oidcIntegrationTestSuite) TearDownTest() {
+	for _, model := range s.models {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"loan-service/app"
+	"loan-service/models"
+	notificationModule "loan-service/modules/notifications"
+	_notificationHandlers "loan-service/modules/notifications/handlers"
+	"loan-service/services/auth"
+	"loan-service/services/email"
+	_emailMock "loan-service/services/email/mocks"
+	_smsMock "loan-service/services/sms/mocks"
+	_whatsAppMock "loan-service/services/whatsapp/mocks"
+	"loan-service/utils/i18n"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxIntegrationTestSuite struct {
+	suite.Suite
+	db                  *gorm.DB
+	rest                *echo.Echo
+	outboxUsecase       models.OutboxMessageUsecase
+	notificationUsecase models.NotificationUsecase
+	outboxHandler       *_notificationHandlers.OutboxHandler
+	preferenceHandler   *_notificationHandlers.NotificationPreferenceHandler
+	inboxHandler        *_notificationHandlers.InboxHandler
+	models              []interface{}
+	emailSvc            *_emailMock.EmailService
+	smsSvc              *_smsMock.SMSService
+	whatsAppSvc         *_whatsAppMock.WhatsAppService
+	injector            *do.Injector
+}
+
+func TestIntegrationOutbox(t *testing.T) {
+	suite.Run(t, new(outboxIntegrationTestSuite))
+}
+
+func (s *outboxIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.emailSvc = _emailMock.NewEmailService(s.T())
+	s.smsSvc = _smsMock.NewSMSService(s.T())
+	s.whatsAppSvc = _whatsAppMock.NewWhatsAppService(s.T())
+
+	s.injector = app.SetupInjections(s.db, s.rest, s.emailSvc, s.smsSvc, s.whatsAppSvc, nil)
+
+	s.outboxUsecase = do.MustInvoke[models.OutboxMessageUsecase](s.injector)
+	s.notificationUsecase = do.MustInvoke[models.NotificationUsecase](s.injector)
+	s.outboxHandler = &_notificationHandlers.OutboxHandler{
+		Usecase: s.outboxUsecase,
+	}
+	s.preferenceHandler = &_notificationHandlers.NotificationPreferenceHandler{
+		Usecase:     s.notificationUsecase,
+		UserUsecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.inboxHandler = &_notificationHandlers.InboxHandler{
+		Usecase: s.notificationUsecase,
+	}
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+		&models.OutboxMessage{},
+		&models.NotificationPreference{},
+		&models.InboxNotification{},
+	}
+}
+
+func (s *outboxIntegrationTestSuite) TestIntegration_DispatchDueMessages() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	// Mock services
+	s.emailSvc.On("DefaultSenderName").Return("noreply - loanservice.io").Maybe()
+	s.emailSvc.On("DefaultSenderAddress").Return("loanservice.io@proton.me").Maybe()
+	s.emailSvc.On("SendMail", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		sgmail.Email{Name: "Larry Fink", Address: "larryfink@blackrock.com"}, mock.Anything).
+		Return(nil).Once()
+	s.emailSvc.On("SendMail", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		sgmail.Email{Name: "Olaf Scholz", Address: "olaf@loanservice.io"}, mock.Anything).
+		Return(errors.New("451 temporary failure")).Once()
+
+	sentEmail := s.newOutboxMessage("Larry Fink", "larryfink@blackrock.com")
+	failedEmail := s.newOutboxMessage("Olaf Scholz", "olaf@loanservice.io")
+	s.Require().NoError(s.outboxUsecase.EnqueueMessages(ctx, sentEmail, failedEmail))
+
+	sent, err := s.outboxUsecase.DispatchDueMessages(ctx)
+	s.Require().NoError(err)
+	assert.Equal(1, sent)
+
+	sentEmail, err = s.outboxUsecase.FetchOutboxMessageByID(ctx, sentEmail.ID)
+	s.Require().NoError(err)
+	assert.Equal(models.OutboxMessageStatusSent, sentEmail.Status)
+	assert.NotNil(sentEmail.SentAt)
+
+	// Failed email is retried later, not on the next dispatch
+	failedEmail, err = s.outboxUsecase.FetchOutboxMessageByID(ctx, failedEmail.ID)
+	s.Require().NoError(err)
+	assert.Equal(models.OutboxMessageStatusPending, failedEmail.Status)
+	assert.Equal(1, failedEmail.Attempts)
+	assert.Equal("451 temporary failure", failedEmail.LastError)
+	assert.True(failedEmail.NextAttemptAt.After(time.Now()))
+
+	sent, err = s.outboxUsecase.DispatchDueMessages(ctx)
+	s.Require().NoError(err)
+	assert.Zero(sent)
+}
+
+func (s *outboxIntegrationTestSuite) TestIntegration_DispatchClaimedMessages() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	repo := do.MustInvoke[models.OutboxMessageRepository](s.injector)
+
+	outboxMessage := s.newOutboxMessage("Larry Fink", "larryfink@blackrock.com")
+	s.Require().NoError(s.outboxUsecase.EnqueueMessages(ctx, outboxMessage))
+
+	// Another instance claims the message first, it is not sent twice
+	now := time.Now()
+	claimed, err := repo.ClaimDueOutboxMessages(ctx, now, now.Add(time.Minute), 10)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+
+	claimedAgain, err := repo.ClaimDueOutboxMessages(ctx, now, now.Add(time.Minute), 10)
+	s.Require().NoError(err)
+	assert.Empty(claimedAgain)
+
+	sent, err := s.outboxUsecase.DispatchDueMessages(ctx)
+	s.Require().NoError(err)
+	assert.Zero(sent)
+
+	// Claims of a dispatcher that stopped expire
+	claimedAgain, err = repo.ClaimDueOutboxMessages(ctx, now.Add(2*time.Minute), now.Add(3*time.Minute), 10)
+	s.Require().NoError(err)
+	assert.Len(claimedAgain, 1)
+}
+
+func (s *outboxIntegrationTestSuite) TestIntegration_DeadLetterAndResend() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	// Mock services
+	s.emailSvc.On("DefaultSenderName").Return("noreply - loanservice.io").Maybe()
+	s.emailSvc.On("DefaultSenderAddress").Return("loanservice.io@proton.me").Maybe()
+	s.emailSvc.On("SendMail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("550 mailbox unavailable")).Once()
+
+	outboxMessage := s.newOutboxMessage("Larry Fink", "larryfink@blackrock.com")
+	s.Require().NoError(s.outboxUsecase.EnqueueMessages(ctx, outboxMessage))
+	s.Require().NoError(s.db.Model(outboxMessage).Update("attempts", models.MaxOutboxMessageAttempts-1).Error)
+
+	_, err := s.outboxUsecase.DispatchDueMessages(ctx)
+	s.Require().NoError(err)
+
+	outboxMessage, err = s.outboxUsecase.FetchOutboxMessageByID(ctx, outboxMessage.ID)
+	s.Require().NoError(err)
+	assert.Equal(models.OutboxMessageStatusDead, outboxMessage.Status)
+
+	// Dead emails are listed without their body
+	req := httptest.NewRequest(http.MethodGet, "/outbox?status=dead", nil)
+	rec := httptest.NewRecorder()
+
+	err = s.outboxHandler.FetchOutboxMessages(s.rest.NewContext(req, rec))
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"dead"`)
+	assert.Contains(rec.Body.String(), `"last_error":"550 mailbox unavailable"`)
+	assert.NotContains(rec.Body.String(), "Welcome")
+
+	// Resending queues the email for immediate delivery
+	resend := func(messageID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/outbox/:message_id/resend", nil)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.SetParamNames("message_id")
+		ctx.SetParamValues(strconv.Itoa(int(messageID)))
+
+		assert.NoError(s.outboxHandler.ResendOutboxMessage(ctx))
+
+		return rec
+	}
+
+	rec = resend(outboxMessage.ID)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"status":"pending"`)
+	assert.Contains(rec.Body.String(), `"attempts":0`)
+
+	// Pending emails cannot be resent while a dispatcher may hold their lease
+	rec = resend(outboxMessage.ID)
+	assert.Equal(http.StatusOK, rec.Code, "lease-free pending email can be resent")
+
+	s.Require().NoError(s.db.Model(outboxMessage).Update("next_attempt_at", time.Now().Add(time.Minute)).Error)
+	rec = resend(outboxMessage.ID)
+	assert.Equal(http.StatusConflict, rec.Code)
+	assert.Contains(rec.Body.String(), notificationModule.ErrOutboxMessageNotResendable.ErrorCode)
+
+	// Sent emails cannot be resent
+	s.Require().NoError(s.db.Model(outboxMessage).Update("status", models.OutboxMessageStatusSent).Error)
+	rec = resend(outboxMessage.ID)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), notificationModule.ErrOutboxMessageAlreadySent.Error())
+}
+
+func (s *outboxIntegrationTestSuite) TestIntegration_NotifyPreferredChannels() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	updatePreferences := func(userID uint, reqStr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/app/user/notification-preferences", strings.NewReader(reqStr))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: userID})
+
+		assert.NoError(s.preferenceHandler.UpdateNotificationPreferences(ctx))
+
+		return rec
+	}
+
+	// SMS and WhatsApp need a phone number
+	rec := updatePreferences(2, `{"preferences": {"installment_due": ["sms"]}}`)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), notificationModule.ErrPhoneNumberRequired.Error())
+
+	rec = updatePreferences(1, `{"preferences": {"installment_due": ["sms", "whatsapp", "sms"]}}`)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"installment_due":["sms","whatsapp"]`)
+	assert.Contains(rec.Body.String(), `"loan_approved":["email"]`)
+
+	// Installment reminder goes out on both preferred channels in the borrower's language
+	borrower, err := do.MustInvoke[models.UserUsecase](s.injector).FetchUserByID(ctx, 1, nil)
+	s.Require().NoError(err)
+
+	disbursedAt := time.Date(2024, time.August, 17, 10, 0, 0, 0, time.UTC)
+	loan := &models.Loan{
+		Name:            "Warung Sembako",
+		Status:          models.LoanStatusDisbursed,
+		Borrower:        *borrower,
+		PrincipalAmount: "5000000.00",
+		TotalInterest:   "250000.00",
+		LoanTerm:        6,
+		DisbursedAt:     &disbursedAt,
+	}
+
+	s.Require().NoError(s.notificationUsecase.Notify(ctx, borrower, loan.NewInstallmentDueNotification(1)))
+
+	s.smsSvc.On("SendSMS", mock.Anything, "+6281234567890", mock.MatchedBy(func(text string) bool {
+		return strings.Contains(text, "cicilan 1/6") && strings.Contains(text, "17 Sep 2024")
+	})).Return(nil).Once()
+	s.whatsAppSvc.On("SendTemplateMessage", mock.Anything, "+6281234567890", "installment_due", i18n.Locale("id"),
+		mock.Anything).Return(nil).Once()
+
+	sent, err := s.outboxUsecase.DispatchDueMessages(ctx)
+	s.Require().NoError(err)
+	assert.Equal(2, sent)
+}
+
+func (s *outboxIntegrationTestSuite) TestIntegration_Inbox() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+
+	var users []models.User
+	s.Require().NoError(s.db.Order("id").Find(&users).Error)
+	s.Require().Len(users, 2)
+
+	disbursedAt := time.Date(2024, time.August, 17, 10, 0, 0, 0, time.UTC)
+	loan := &models.Loan{
+		Model:           gorm.Model{ID: 7},
+		Name:            "Warung Sembako",
+		Borrower:        users[0],
+		PrincipalAmount: "5000000.00",
+		TotalInterest:   "250000.00",
+		LoanTerm:        6,
+		DisbursedAt:     &disbursedAt,
+	}
+
+	// Inbox is filled regardless of channel preferences, and never sends anything
+	s.Require().NoError(s.notificationUsecase.Notify(ctx, &users[0], loan.NewApprovedNotification()))
+	s.Require().NoError(s.notificationUsecase.Notify(ctx, &users[0], loan.NewDisbursedNotification()))
+	s.Require().NoError(s.notificationUsecase.Notify(ctx, &users[1], loan.NewApprovedNotification()))
+
+	call := func(method, target string, userID uint, handler echo.HandlerFunc, paramValues ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: userID})
+		if len(paramValues) > 0 {
+			ctx.SetParamNames("notification_id")
+			ctx.SetParamValues(paramValues...)
+		}
+
+		assert.NoError(handler(ctx))
+
+		return rec
+	}
+
+	rec := call(http.MethodGet, "/app/notifications?limit=1", users[0].ID, s.inboxHandler.FetchInbox)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"total":2`)
+	assert.Contains(rec.Body.String(), `"unread_count":2`)
+	assert.Contains(rec.Body.String(), `"event":"loan_disbursed"`)
+	assert.Contains(rec.Body.String(), `"loan_id":7`)
+	assert.Contains(rec.Body.String(), "Pinjaman")
+	assert.NotContains(rec.Body.String(), `"event":"loan_approved"`)
+
+	var otherUsersNotification models.InboxNotification
+	s.Require().NoError(s.db.Where("user_id = ?", users[1].ID).First(&otherUsersNotification).Error)
+
+	rec = call(http.MethodPatch, "/app/notifications/:notification_id/read", users[0].ID, s.inboxHandler.MarkNotificationRead,
+		strconv.Itoa(int(otherUsersNotification.ID)))
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	var ownNotification models.InboxNotification
+	s.Require().NoError(s.db.Where("user_id = ?", users[0].ID).Order("id").First(&ownNotification).Error)
+
+	rec = call(http.MethodPatch, "/app/notifications/:notification_id/read", users[0].ID, s.inboxHandler.MarkNotificationRead,
+		strconv.Itoa(int(ownNotification.ID)))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"is_read":true`)
+
+	rec = call(http.MethodGet, "/app/notifications/unread-count", users[0].ID, s.inboxHandler.CountUnreadNotifications)
+	assert.Contains(rec.Body.String(), `"unread_count":1`)
+
+	rec = call(http.MethodPatch, "/app/notifications/read", users[0].ID, s.inboxHandler.MarkAllNotificationsRead)
+	assert.Contains(rec.Body.String(), `"marked":1`)
+
+	rec = call(http.MethodGet, "/app/notifications?unread=true", users[0].ID, s.inboxHandler.FetchInbox)
+	assert.Contains(rec.Body.String(), `"notifications":[]`)
+	assert.Contains(rec.Body.String(), `"unread_count":0`)
+
+	// Other users' inboxes are left alone
+	rec = call(http.MethodGet, "/app/notifications/unread-count", users[1].ID, s.inboxHandler.CountUnreadNotifications)
+	assert.Contains(rec.Body.String(), `"unread_count":1`)
+}
+
+func (s *outboxIntegrationTestSuite) newOutboxMessage(name, address string) *models.OutboxMessage {
+	return &models.OutboxMessage{
+		RecipientName:    name,
+		RecipientAddress: address,
+		Template:         email.TemplateInvitation,
+		Subject:          "You have been invited to LoanService.io",
+		HTMLBody:         "<p>Welcome</p>",
+		TextBody:         "Welcome",
+	}
+}
+
+func (s *outboxIntegrationTestSuite) SeedData() {
+	role := models.Role{Name: "Borrower", RoleType: auth.RoleTypeBorrower}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&role).Error; err != nil {
+		panic(fmt.Errorf("cannot insert role: %v", err))
+	}
+
+	users := []models.User{
+		{
+			Name:        "Zulhas Hasan",
+			Email:       "zulhashasan@indonesia.go.id",
+			IsActive:    true,
+			RoleID:      role.ID,
+			Locale:      i18n.LocaleIndonesian,
+			PhoneNumber: "+6281234567890",
+		},
+		{
+			Name:     "Olaf Scholz",
+			Email:    "olaf@loanservice.io",
+			IsActive: true,
+			RoleID:   role.ID,
+		},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert users: %v", err))
+	}
+}
+
+func (s *outboxIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *outboxIntegrationTestSuite) TearDownTest() {
+	s.emailSvc.ExpectedCalls = nil
+	s.smsSvc.ExpectedCalls = nil
+	s.whatsAppSvc.ExpectedCalls = nil
+
+	for _, model := range s.models {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"loan-service/app"
+	authMiddleware "loan-service/app/web/middleware"
+	"loan-service/models"
+	roleModule "loan-service/modules/roles"
+	_roleHandlers "loan-service/modules/roles/handlers"
+	"loan-service/services/auth"
+	"loan-service/utils/jsonutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleIntegrationTestSuite struct {
+	suite.Suite
+	db          *gorm.DB
+	rest        *echo.Echo
+	roleHandler *_roleHandlers.RoleHandler
+	models      []interface{}
+	injector    *do.Injector
+}
+
+func TestIntegrationRole(t *testing.T) {
+	suite.Run(t, new(roleIntegrationTestSuite))
+}
+
+func (s *roleIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.injector = app.SetupInjections(s.db, s.rest, nil, nil, nil, nil)
+
+	s.roleHandler = &_roleHandlers.RoleHandler{
+		Usecase:     do.MustInvoke[models.RoleUsecase](s.injector),
+		UserUsecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+	}
+}
+
+func (s *roleIntegrationTestSuite) TestIntegration_SetRolePermissions() {
+	tests := []struct {
+		name    string
+		userID  uint
+		params  map[string]string
+		reqStr  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "returns results given valid request and existing role",
+			userID: 1,
+			params: map[string]string{
+				"role_id": "3",
+			},
+			reqStr: `
+				{
+					"permissions": ["loan.view_all"]
+				}
+			`,
+			want: `
+				{
+					"data": {
+						"id": 3,
+						"name": "Auditor",
+						"role_type": "auditor",
+						"permissions": ["loan.view_all"]
+					}
+				}
+			`,
+			wantErr: nil,
+		},
+		{
+			name:   "throws error given permissions the actor does not have",
+			userID: 2,
+			params: map[string]string{
+				"role_id": "3",
+			},
+			reqStr: `
+				{
+					"permissions": ["loan.view_all", "user.manage"]
+				}
+			`,
+			wantErr: roleModule.ErrPermissionNotGrantable,
+		},
+		{
+			name:   "throws error given unknown permission",
+			userID: 1,
+			params: map[string]string{
+				"role_id": "2",
+			},
+			reqStr: `
+				{
+					"permissions": ["loan.view_all", "loan.delete"]
+				}
+			`,
+			wantErr: roleModule.ErrUnknownPermission,
+		},
+		{
+			name:   "throws error given superuser role",
+			userID: 1,
+			params: map[string]string{
+				"role_id": "1",
+			},
+			reqStr: `
+				{
+					"permissions": []
+				}
+			`,
+			wantErr: roleModule.ErrSuperuserRoleImmutable,
+		},
+		{
+			name:    "throws error given invalid request",
+			userID:  1,
+			params:  nil,
+			reqStr:  `{}`,
+			wantErr: errors.New("invalid request parameters"),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			bodyReader := strings.NewReader(tt.reqStr)
+			req := httptest.NewRequest(http.MethodPut, "/roles/:role_id/permissions", bodyReader)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: tt.userID})
+
+			for k, v := range tt.params {
+				ctx.SetParamNames(k)
+				ctx.SetParamValues(v)
+			}
+
+			// Do test and assert
+			want, _ := jsonutil.Compact(tt.want)
+			err := s.roleHandler.SetRolePermissions(ctx)
+			got := strings.TrimSpace(rec.Body.String())
+
+			if tt.wantErr == nil {
+				assert.NoError(err)
+				assert.Equal(http.StatusOK, rec.Code)
+				assert.Equal(want, got)
+			} else {
+				assert.Contains(got, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func (s *roleIntegrationTestSuite) TestIntegration_CreateRole() {
+	assert := _assert.New(s.T())
+
+	createRole := func(userID uint, reqStr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/roles", strings.NewReader(reqStr))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: userID})
+
+		s.Require().NoError(s.roleHandler.CreateRole(ctx))
+
+		return rec
+	}
+
+	// Staff managing roles cannot create one granting more than they have, then assign it to themselves
+	rec := createRole(2, `{"name": "Disburser", "role_type": "disburser", "permissions": ["loan.disburse"]}`)
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), roleModule.ErrPermissionNotGrantable.ErrorCode)
+
+	rec = createRole(2, `{"name": "Reader", "role_type": "reader", "permissions": ["loan.view_all"]}`)
+	assert.Equal(http.StatusCreated, rec.Code)
+
+	rec = createRole(1, `{"name": "Disburser", "role_type": "disburser", "permissions": ["loan.disburse"]}`)
+	assert.Equal(http.StatusCreated, rec.Code)
+}
+
+func (s *roleIntegrationTestSuite) TestIntegration_RequirePermission() {
+	tests := []struct {
+		name        string
+		permissions []auth.Permission
+		wantCode    int
+	}{
+		{
+			name:        "allows request given one of the required permissions",
+			permissions: []auth.Permission{auth.PermissionLoanViewOwn},
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "forbids request given none of the required permissions",
+			permissions: []auth.Permission{auth.PermissionLoanInvest},
+			wantCode:    http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			req := httptest.NewRequest(http.MethodGet, "/loans", nil)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+				UserID:      1,
+				Permissions: tt.permissions,
+			})
+
+			// Do test and assert
+			handler := authMiddleware.RequirePermission(auth.PermissionLoanViewAll, auth.PermissionLoanViewOwn)(
+				func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+			)
+			err := handler(ctx)
+
+			assert.NoError(err)
+			assert.Equal(tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func (s *roleIntegrationTestSuite) SeedData() {
+	var permissions []models.Permission
+	for _, code := range auth.AllPermissions {
+		permissions = append(permissions, models.Permission{Code: code, Description: auth.PermissionDescriptions[code]})
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert permissions: %v", err))
+	}
+
+	var staffPermissions []models.Permission
+	for _, permission := range permissions {
+		if permission.Code == auth.PermissionRoleManage || permission.Code == auth.PermissionLoanViewAll {
+			staffPermissions = append(staffPermissions, permission)
+		}
+	}
+
+	roles := []models.Role{
+		{Name: "Superuser", RoleType: auth.RoleTypeSuperuser, Permissions: permissions},
+		{Name: "Staff", RoleType: auth.RoleTypeStaff, Permissions: staffPermissions},
+		{Name: "Auditor", RoleType: auth.RoleType("auditor")},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
+	}
+
+	users := []models.User{
+		{Name: "Christine Lagarde", Email: "christinelagarde@ecb.europa.eu", IsActive: true, RoleID: roles[0].ID},
+		{Name: "Emmanuel Macron", Email: "emmanuelmacron@elysee.fr", IsActive: true, RoleID: roles[1].ID},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert users: %v", err))
+	}
+}
+
+func (s *roleIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *roleIntegrationTestSuite) TearDownTest() {
+	for _, model := range append(s.models, "role_permissions") {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
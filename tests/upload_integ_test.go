@@ -0,0 +1,283 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"loan-service/services/upload"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type uploadIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func TestIntegrationUpload(t *testing.T) {
+	suite.Run(t, new(uploadIntegrationTestSuite))
+}
+
+func (s *uploadIntegrationTestSuite) TestIntegration_DiskUploadService() {
+	assert := _assert.New(s.T())
+	dir := s.T().TempDir()
+	content := "proof of visit"
+	checksum := sha256.Sum256([]byte(content))
+
+	uploadSvc := upload.NewDiskUploadService(dir, "attachments")
+	uploaded, err := uploadSvc.UploadFile(context.Background(), strings.NewReader(content), "ProofOfVisit_1.jpg", "image/jpeg")
+	s.Require().NoError(err)
+	assert.Equal("attachments/ProofOfVisit_1.jpg", uploaded.Key)
+	assert.Equal("image/jpeg", uploaded.ContentType)
+	assert.Equal(int64(len(content)), uploaded.Size)
+	assert.Equal(hex.EncodeToString(checksum[:]), uploaded.Checksum)
+
+	stored, err := os.ReadFile(filepath.Join(dir, "attachments", "ProofOfVisit_1.jpg"))
+	s.Require().NoError(err)
+	assert.Equal(content, string(stored))
+
+	file, fileInfo, err := uploadSvc.DownloadFile(context.Background(), uploaded.Key)
+	s.Require().NoError(err)
+	downloaded, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(content, string(downloaded))
+	assert.Equal("image/jpeg", fileInfo.ContentType)
+
+	_, _, err = uploadSvc.DownloadFile(context.Background(), "attachments/missing.jpg")
+	assert.ErrorIs(err, upload.ErrFileNotFound)
+
+	_, err = uploadSvc.UploadFile(context.Background(), strings.NewReader(content), "../ProofOfVisit_1.jpg", "image/jpeg")
+	assert.ErrorIs(err, upload.ErrInvalidFilename)
+
+	// Unwritable directory
+	blocker := filepath.Join(dir, "blocker")
+	s.Require().NoError(os.WriteFile(blocker, nil, 0o600))
+	uploadSvc = upload.NewDiskUploadService(blocker, "attachments")
+	_, err = uploadSvc.UploadFile(context.Background(), strings.NewReader(content), "ProofOfVisit_1.jpg", "image/jpeg")
+	assert.ErrorIs(err, upload.ErrFileNotUploaded)
+}
+
+func (s *uploadIntegrationTestSuite) TestIntegration_S3UploadService() {
+	assert := _assert.New(s.T())
+	content := "proof of visit"
+	checksum := sha256.Sum256([]byte(content))
+
+	var gotReq *http.Request
+	var gotBody []byte
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	uploadSvc := upload.NewS3UploadService(upload.S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "loan-service",
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		Prefix:          "attachments",
+		UsePathStyle:    true,
+	}, server.Client())
+
+	uploaded, err := uploadSvc.UploadFile(context.Background(), strings.NewReader(content), "ProofOfVisit_2024-01-01T00:00:00.jpg", "image/jpeg")
+	s.Require().NoError(err)
+	assert.Equal("attachments/ProofOfVisit_2024-01-01T00:00:00.jpg", uploaded.Key)
+	assert.Equal(hex.EncodeToString(checksum[:]), uploaded.Checksum)
+	assert.Equal(int64(len(content)), uploaded.Size)
+
+	assert.Equal(http.MethodPut, gotReq.Method)
+	assert.Equal("/loan-service/attachments/ProofOfVisit_2024-01-01T00%3A00%3A00.jpg", gotReq.URL.EscapedPath())
+	assert.Equal(content, string(gotBody))
+	assert.Equal("image/jpeg", gotReq.Header.Get("Content-Type"))
+	assert.Equal(hex.EncodeToString(checksum[:]), gotReq.Header.Get("X-Amz-Content-Sha256"))
+	assert.Equal(base64.StdEncoding.EncodeToString(checksum[:]), gotReq.Header.Get("X-Amz-Checksum-Sha256"))
+	assert.Equal(hex.EncodeToString(checksum[:]), gotReq.Header.Get("X-Amz-Meta-Sha256"))
+	assert.NotEmpty(gotReq.Header.Get("X-Amz-Date"))
+
+	authorization := gotReq.Header.Get("Authorization")
+	assert.True(strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=minioadmin/"))
+	assert.Contains(authorization, "/us-east-1/s3/aws4_request")
+	assert.Contains(authorization,
+		"SignedHeaders=content-type;host;x-amz-checksum-sha256;x-amz-content-sha256;x-amz-date;x-amz-meta-sha256")
+
+	// Errors from the storage are propagated
+	statusCode = http.StatusForbidden
+	_, err = uploadSvc.UploadFile(context.Background(), strings.NewReader(content), "ProofOfVisit_2.jpg", "image/jpeg")
+	assert.ErrorIs(err, upload.ErrFileNotUploaded)
+}
+
+func (s *uploadIntegrationTestSuite) TestIntegration_S3DownloadFile() {
+	assert := _assert.New(s.T())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if r.URL.Path != "/loan-service/attachments/ProofOfVisit_1.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("X-Amz-Meta-Sha256", "checksum")
+		_, _ = w.Write([]byte("proof of visit"))
+	}))
+	defer server.Close()
+
+	uploadSvc := upload.NewS3UploadService(upload.S3Config{
+		Endpoint:     server.URL,
+		Region:       "us-east-1",
+		Bucket:       "loan-service",
+		UsePathStyle: true,
+	}, server.Client())
+
+	file, fileInfo, err := uploadSvc.DownloadFile(context.Background(), "attachments/ProofOfVisit_1.jpg")
+	s.Require().NoError(err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	s.Require().NoError(err)
+	assert.Equal("proof of visit", string(content))
+	assert.Equal("image/jpeg", fileInfo.ContentType)
+	assert.Equal("checksum", fileInfo.Checksum)
+
+	_, _, err = uploadSvc.DownloadFile(context.Background(), "attachments/ProofOfVisit_2.jpg")
+	assert.ErrorIs(err, upload.ErrFileNotFound)
+}
+
+func (s *uploadIntegrationTestSuite) TestIntegration_SignURL() {
+	assert := _assert.New(s.T())
+	now := time.Now()
+	path := "/app/loans/1/attachments/proof-of-visit"
+
+	signedURL, err := url.Parse(upload.SignURL(path, time.Minute, now))
+	s.Require().NoError(err)
+	assert.Equal(path, signedURL.Path)
+
+	expires := signedURL.Query().Get(upload.QueryExpires)
+	signature := signedURL.Query().Get(upload.QuerySignature)
+	assert.NoError(upload.VerifyURL(path, expires, signature, now))
+	assert.ErrorIs(upload.VerifyURL(path, expires, signature, now.Add(time.Minute*2)), upload.ErrSignedURLExpired)
+	assert.ErrorIs(upload.VerifyURL("/app/loans/2/attachments/proof-of-visit", expires, signature, now), upload.ErrInvalidSignedURL)
+	assert.ErrorIs(upload.VerifyURL(path, expires+"0", signature, now), upload.ErrInvalidSignedURL)
+	assert.ErrorIs(upload.VerifyURL(path, "never", signature, now), upload.ErrInvalidSignedURL)
+}
+
+func (s *uploadIntegrationTestSuite) TestIntegration_ScanUploads() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	dir := s.T().TempDir()
+	eicar := `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+	clamdAddress := newFakeClamd(s.T(), func(content string) string {
+		if strings.Contains(content, "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+			return "stream: Eicar-Test-Signature FOUND"
+		}
+
+		return "stream: OK"
+	})
+
+	scanner := upload.NewClamdScanner(upload.ClamdConfig{Address: clamdAddress, ChunkSize: 4})
+	uploadSvc := upload.NewScanningUploadService(
+		upload.NewDiskUploadService(dir, "attachments"),
+		upload.NewDiskUploadService(dir, "quarantine"),
+		scanner,
+	)
+
+	// Clean files are stored
+	uploaded, err := uploadSvc.UploadFile(ctx, strings.NewReader("signed agreement"), "agreement.pdf", "application/pdf")
+	s.Require().NoError(err)
+	assert.Equal("attachments/agreement.pdf", uploaded.Key)
+
+	// Infected files are quarantined and rejected, the signature spans many of the streamed chunks
+	_, err = uploadSvc.UploadFile(ctx, strings.NewReader(eicar), "receipt.pdf", "application/pdf")
+	assert.ErrorIs(err, upload.ErrFileInfected)
+	assert.NoFileExists(filepath.Join(dir, "attachments", "receipt.pdf"))
+	quarantined, err := os.ReadFile(filepath.Join(dir, "quarantine", "receipt.pdf"))
+	s.Require().NoError(err)
+	assert.Equal(eicar, string(quarantined))
+
+	// Downloads go to the storage as is
+	file, _, err := uploadSvc.DownloadFile(ctx, "attachments/agreement.pdf")
+	s.Require().NoError(err)
+	defer file.Close()
+	stored, err := io.ReadAll(file)
+	s.Require().NoError(err)
+	assert.Equal("signed agreement", string(stored))
+
+	// Files that cannot be scanned are not stored either
+	unreachable := upload.NewScanningUploadService(
+		upload.NewDiskUploadService(dir, "attachments"),
+		upload.NewDiskUploadService(dir, "quarantine"),
+		upload.NewClamdScanner(upload.ClamdConfig{Address: "tcp://127.0.0.1:1", Timeout: time.Second}),
+	)
+	_, err = unreachable.UploadFile(ctx, strings.NewReader("photo"), "photo.jpg", "image/jpeg")
+	assert.ErrorIs(err, upload.ErrFileNotScanned)
+	assert.NoFileExists(filepath.Join(dir, "attachments", "photo.jpg"))
+
+	result, err := upload.NewNoopScanner().Scan(ctx, strings.NewReader(eicar))
+	s.Require().NoError(err)
+	assert.False(result.Infected)
+}
+
+// newFakeClamd serves the clamd INSTREAM command, replying with the verdict on the reassembled stream
+func newFakeClamd(t *testing.T, verdict func(content string) string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			reader := bufio.NewReader(conn)
+			command, err := reader.ReadString(0)
+			if err != nil || command != "zINSTREAM\x00" {
+				conn.Close()
+				continue
+			}
+
+			var content []byte
+			for {
+				var size uint32
+				if err := binary.Read(reader, binary.BigEndian, &size); err != nil || size == 0 {
+					break
+				}
+
+				chunk := make([]byte, size)
+				if _, err := io.ReadFull(reader, chunk); err != nil {
+					break
+				}
+				content = append(content, chunk...)
+			}
+
+			_, _ = conn.Write([]byte(verdict(string(content)) + "\x00"))
+			conn.Close()
+		}
+	}()
+
+	return "tcp://" + listener.Addr().String()
+}
@@ -0,0 +1,481 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"loan-service/app"
+	"loan-service/models"
+	userModule "loan-service/modules/users"
+	_userHandlers "loan-service/modules/users/handlers"
+	"loan-service/services/auth"
+	"loan-service/services/email"
+	_emailMock "loan-service/services/email/mocks"
+	"loan-service/utils/jsonutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	_assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userIntegrationTestSuite struct {
+	suite.Suite
+	db                *gorm.DB
+	rest              *echo.Echo
+	adminUserHandler  *_userHandlers.AdminUserHandler
+	commonUserHandler *_userHandlers.CommonUserHandler
+	models            []interface{}
+	emailSvc          *_emailMock.EmailService
+	injector          *do.Injector
+}
+
+func TestIntegrationUser(t *testing.T) {
+	suite.Run(t, new(userIntegrationTestSuite))
+}
+
+func (s *userIntegrationTestSuite) SetupSuite() {
+	var err error
+	s.db, err = InitDB()
+	if err != nil {
+		panic(err)
+	}
+
+	s.rest = SetupEcho()
+
+	s.emailSvc = _emailMock.NewEmailService(s.T())
+
+	s.injector = app.SetupInjections(s.db, s.rest, s.emailSvc, nil, nil, nil)
+
+	s.adminUserHandler = &_userHandlers.AdminUserHandler{
+		Usecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.commonUserHandler = &_userHandlers.CommonUserHandler{
+		Usecase: do.MustInvoke[models.UserUsecase](s.injector),
+	}
+
+	s.models = []any{
+		&models.Permission{},
+		&models.Role{},
+		&models.User{},
+		&models.LoginAttempt{},
+		&models.OutboxMessage{},
+	}
+}
+
+func (s *userIntegrationTestSuite) TestIntegration_FetchUsers() {
+	tests := []struct {
+		name        string
+		query       string
+		permissions []auth.Permission
+		want        string
+	}{
+		{
+			name:        "returns filtered results given role and search filter",
+			query:       "role_type=investor&search=LARRY",
+			permissions: []auth.Permission{auth.PermissionUserManage},
+			want: `
+				{
+					"data": [
+						{
+							"id": 4,
+							"name": "Larry Fink",
+							"email": "larryfink@blackrock.com",
+							"is_active": true,
+							"role_type": "investor",
+							"role": "Investor"
+						}
+					]
+				}
+			`,
+		},
+		{
+			name:        "returns inactive users given active filter",
+			query:       "is_active=false",
+			permissions: []auth.Permission{auth.PermissionUserManage},
+			want: `
+				{
+					"data": [
+						{
+							"id": 5,
+							"name": "Zulhas Hasan",
+							"email": "zulhashasan@indonesia.go.id",
+							"is_active": false,
+							"role_type": "borrower",
+							"role": "Borrower"
+						}
+					]
+				}
+			`,
+		},
+		{
+			name:        "matches wildcards literally given search filter",
+			query:       "search=%25_",
+			permissions: []auth.Permission{auth.PermissionUserManage},
+			want: `
+				{
+					"data": null
+				}
+			`,
+		},
+		{
+			name:        "returns no privileged users given view permission only",
+			query:       "role_type=staff",
+			permissions: []auth.Permission{auth.PermissionUserView},
+			want: `
+				{
+					"data": null
+				}
+			`,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			req := httptest.NewRequest(http.MethodGet, "/users?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+				UserID:      1,
+				Permissions: tt.permissions,
+			})
+
+			// Do test and assert
+			want, _ := jsonutil.Compact(tt.want)
+			err := s.adminUserHandler.FetchUsers(ctx)
+			got := strings.TrimSpace(rec.Body.String())
+
+			assert.NoError(err)
+			assert.Equal(http.StatusOK, rec.Code)
+			assert.Equal(want, got)
+		})
+	}
+}
+
+func (s *userIntegrationTestSuite) TestIntegration_InviteUser() {
+	tests := []struct {
+		name    string
+		userID  uint
+		reqStr  string
+		wantErr error
+	}{
+		{
+			name:   "returns results given superuser inviting staff",
+			userID: 1,
+			reqStr: `
+				{
+					"name": "Olaf Scholz",
+					"email": "olaf@loanservice.io",
+					"role_type": "staff"
+				}
+			`,
+			wantErr: nil,
+		},
+		{
+			name:   "returns results given staff inviting field validator",
+			userID: 2,
+			reqStr: `
+				{
+					"name": "Mario Draghi",
+					"email": "mario@loanservice.io",
+					"role_type": "field_validator"
+				}
+			`,
+			wantErr: nil,
+		},
+		{
+			name:   "throws error given staff inviting staff",
+			userID: 2,
+			reqStr: `
+				{
+					"name": "Olaf Scholz",
+					"email": "olaf@loanservice.io",
+					"role_type": "staff"
+				}
+			`,
+			wantErr: userModule.ErrPrivilegedRoleForbidden,
+		},
+		{
+			name:   "throws error given already registered email",
+			userID: 1,
+			reqStr: `
+				{
+					"name": "Larry Fink",
+					"email": "larryfink@blackrock.com",
+					"role_type": "investor"
+				}
+			`,
+			wantErr: userModule.ErrEmailAlreadyRegistered,
+		},
+		{
+			name:   "throws error given invalid request",
+			userID: 1,
+			reqStr: `
+				{
+					"name": "",
+				}
+			`,
+			wantErr: errors.New("invalid request parameters"),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			assert := _assert.New(s.T())
+
+			// Build request and its context
+			bodyReader := strings.NewReader(tt.reqStr)
+			req := httptest.NewRequest(http.MethodPost, "/users", bodyReader)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			ctx := s.rest.NewContext(req, rec)
+			ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+				UserID: uint(tt.userID),
+			})
+
+			// Do test and assert
+			err := s.adminUserHandler.InviteUser(ctx)
+			got := strings.TrimSpace(rec.Body.String())
+
+			if tt.wantErr == nil {
+				assert.NoError(err)
+				assert.Equal(http.StatusCreated, rec.Code)
+				assert.Contains(got, `"is_active":false`)
+
+				// Invitation is queued in the outbox with the user
+				var queued int64
+				s.db.Model(&models.OutboxMessage{}).Where("template = ?", email.TemplateInvitation).Count(&queued)
+				assert.NotZero(queued)
+			} else {
+				assert.Contains(got, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func (s *userIntegrationTestSuite) TestIntegration_InviteUserWithCustomRole() {
+	assert := _assert.New(s.T())
+
+	invite := func(userID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`
+			{
+				"name": "Christine Lagarde",
+				"email": "christine@loanservice.io",
+				"role_type": "user_admin"
+			}
+		`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := s.rest.NewContext(req, rec)
+		ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: userID})
+
+		assert.NoError(s.adminUserHandler.InviteUser(ctx))
+
+		return rec
+	}
+
+	permissions := []models.Permission{{Code: auth.PermissionUserManage}, {Code: auth.PermissionRoleManage}}
+	s.Require().NoError(s.db.Create(&permissions).Error)
+	s.Require().NoError(s.db.Create(&models.Role{
+		Name:        "User Admin",
+		RoleType:    "user_admin",
+		Permissions: permissions,
+	}).Error)
+
+	// Staff cannot grant permissions they do not hold
+	rec := invite(2)
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), userModule.ErrRoleExceedsPermissions.ErrorCode)
+
+	var staff models.Role
+	s.Require().NoError(s.db.First(&staff, 2).Error)
+	s.Require().NoError(s.db.Model(&staff).Association("Permissions").Append(permissions))
+
+	rec = invite(2)
+	assert.Equal(http.StatusCreated, rec.Code)
+}
+
+func (s *userIntegrationTestSuite) TestIntegration_LoginLockout() {
+	assert := _assert.New(s.T())
+
+	login := func(password string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.SetBasicAuth("larryfink@blackrock.com", password)
+		rec := httptest.NewRecorder()
+
+		err := s.commonUserHandler.Login(s.rest.NewContext(req, rec))
+		assert.NoError(err)
+
+		return rec.Code
+	}
+
+	// Failing past the progressive delay, one attempt short of the lockout
+	lastFailedLoginAt := time.Now().Add(-time.Minute)
+	s.Require().NoError(s.db.Model(&models.User{}).Where("id = ?", 4).Updates(map[string]any{
+		"failed_login_attempts": models.MaxFailedLoginAttempts - 1,
+		"last_failed_login_at":  lastFailedLoginAt,
+	}).Error)
+
+	assert.Equal(http.StatusUnauthorized, login("wrong-password"))
+	assert.Equal(http.StatusTooManyRequests, login("larry@investor"), "locked account rejects correct password")
+
+	// Lockout email is queued once in the outbox
+	var lockedEmails []models.OutboxMessage
+	s.Require().NoError(s.db.Where("template = ?", email.TemplateAccountLocked).Find(&lockedEmails).Error)
+	s.Require().Len(lockedEmails, 1)
+	assert.Equal("Your LoanService.io account has been locked", lockedEmails[0].Subject)
+	assert.Equal(models.OutboxMessageStatusPending, lockedEmails[0].Status)
+
+	// Locked accounts are listed for superusers
+	req := httptest.NewRequest(http.MethodGet, "/users/locked", nil)
+	rec := httptest.NewRecorder()
+	ctx := s.rest.NewContext(req, rec)
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{
+		UserID:      1,
+		Permissions: []auth.Permission{auth.PermissionUserManage},
+	})
+
+	err := s.adminUserHandler.FetchLockedUsers(ctx)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"email":"larryfink@blackrock.com"`)
+	assert.Contains(rec.Body.String(), `"locked_until"`)
+
+	// Unlocking allows the user to log in again
+	req = httptest.NewRequest(http.MethodPatch, "/users/:user_id/unlock", nil)
+	rec = httptest.NewRecorder()
+	ctx = s.rest.NewContext(req, rec)
+	ctx.SetParamNames("user_id")
+	ctx.SetParamValues("4")
+	ctx.Set(auth.AuthClaimsCtxKey, auth.AuthClaims{UserID: 1})
+
+	err = s.adminUserHandler.UnlockUser(ctx)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	assert.Equal(http.StatusOK, login("larry@investor"))
+
+	// A lockout that expired without a successful login is not a current one, locking again sends another email
+	expiredAt := time.Now().Add(-time.Minute)
+	s.Require().NoError(s.db.Model(&models.User{}).Where("id = ?", 4).Updates(map[string]any{
+		"failed_login_attempts": models.MaxFailedLoginAttempts - 1,
+		"last_failed_login_at":  expiredAt,
+		"locked_until":          expiredAt,
+	}).Error)
+
+	assert.Equal(http.StatusUnauthorized, login("wrong-password"))
+	s.Require().NoError(s.db.Where("template = ?", email.TemplateAccountLocked).Find(&lockedEmails).Error)
+	assert.Len(lockedEmails, 2)
+}
+
+func (s *userIntegrationTestSuite) TestIntegration_UpdateProfileKeepsLoginState() {
+	assert := _assert.New(s.T())
+	ctx := context.Background()
+	usecase := do.MustInvoke[models.UserUsecase](s.injector)
+
+	user, err := usecase.FetchUserByID(ctx, 4, nil)
+	s.Require().NoError(err)
+
+	// The account is locked by failed logins after the profile was read
+	lockedUntil := time.Now().Add(models.LoginLockoutDuration)
+	s.Require().NoError(s.db.Model(&models.User{}).Where("id = ?", 4).Updates(map[string]any{
+		"failed_login_attempts": models.MaxFailedLoginAttempts,
+		"locked_until":          lockedUntil,
+	}).Error)
+
+	user.Name = "Laurence Fink"
+	s.Require().NoError(usecase.UpdateProfile(ctx, user))
+
+	var got models.User
+	s.Require().NoError(s.db.First(&got, 4).Error)
+	assert.Equal("Laurence Fink", got.Name)
+	assert.Equal(models.MaxFailedLoginAttempts, got.FailedLoginAttempts)
+	assert.True(got.IsLocked(time.Now()), "stale profile save does not undo the lockout")
+}
+
+func (s *userIntegrationTestSuite) SeedData() {
+	roles := []models.Role{
+		{Name: "Superuser", RoleType: auth.RoleTypeSuperuser},
+		{Name: "Staff", RoleType: auth.RoleTypeStaff},
+		{Name: "Field Validator", RoleType: auth.RoleTypeFieldValidator},
+		{Name: "Investor", RoleType: auth.RoleTypeInvestor},
+		{Name: "Borrower", RoleType: auth.RoleTypeBorrower},
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&roles).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert roles: %v", err))
+	}
+
+	users := []models.User{
+		{
+			Name:     "Angela Merkel",
+			Email:    "admin@loanservice.io",
+			Password: "@superuser",
+			IsActive: true,
+			RoleID:   1,
+		},
+		{
+			Name:     "Emmanuel Macron",
+			Email:    "staff@loanservice.io",
+			Password: "@staff",
+			IsActive: true,
+			RoleID:   2,
+		},
+		{
+			Name:     "Silvio Berlusconi",
+			Email:    "field.validator@loanservice.io",
+			Password: "@field.validator",
+			IsActive: true,
+			RoleID:   3,
+		},
+		{
+			Name:     "Larry Fink",
+			Email:    "larryfink@blackrock.com",
+			Password: "larry@investor",
+			IsActive: true,
+			RoleID:   4,
+		},
+		{
+			Name:     "Zulhas Hasan",
+			Email:    "zulhashasan@indonesia.go.id",
+			Password: "zulhas@borrower",
+			IsActive: false,
+			RoleID:   5,
+		},
+	}
+
+	for i := range users {
+		users[i].SetNewPassword(users[i].Password)
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		panic(fmt.Errorf("cannot bulk insert users: %v", err))
+	}
+}
+
+func (s *userIntegrationTestSuite) SetupTest() {
+	AutoMigrate(s.db, s.models...)
+	s.SeedData()
+}
+
+func (s *userIntegrationTestSuite) TearDownTest() {
+	s.emailSvc.ExpectedCalls = nil
+
+	for _, model := range append(s.models, "role_permissions") {
+		err := s.db.Migrator().DropTable(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
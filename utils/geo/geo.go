@@ -0,0 +1,20 @@
+package geo
+
+import "math"
+
+// Mean radius of the Earth, in meters
+const earthRadius = 6371000.0
+
+// Distance returns the great-circle distance in meters between two coordinates in decimal degrees
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	// Haversine formula, accurate enough at the scale of a visit
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
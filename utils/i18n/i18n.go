@@ -0,0 +1,32 @@
+package i18n
+
+type Locale string
+
+const (
+	LocaleEnglish    Locale = "en"
+	LocaleIndonesian Locale = "id"
+
+	DefaultLocale = LocaleEnglish
+)
+
+var SupportedLocales = []Locale{LocaleEnglish, LocaleIndonesian}
+
+// IsSupported returns true if messages are available in the locale
+func IsSupported(locale Locale) bool {
+	for _, supported := range SupportedLocales {
+		if locale == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the locale if supported, otherwise the default locale
+func Resolve(locale Locale) Locale {
+	if IsSupported(locale) {
+		return locale
+	}
+
+	return DefaultLocale
+}
@@ -3,3 +3,7 @@ package ptr
 func NewUintPtr(val uint) *uint {
 	return &val
 }
+
+func NewFloat64Ptr(val float64) *float64 {
+	return &val
+}
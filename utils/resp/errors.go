@@ -4,6 +4,7 @@ const (
 	InvalidAuthToken  = "InvalidAuthToken"
 	InvalidPagination = "InvalidPagination"
 	Unauthorized      = "Unauthorized"
+	Forbidden         = "Forbidden"
 	ValidationError   = "ValidationError"
 	ServerError       = "ServerError"
 	NotFound          = "NotFound"
@@ -0,0 +1,52 @@
+package resp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SSEStart begins a Server-Sent Events stream, the response must then only be written with SSEEvent and SSEComment
+func SSEStart(c echo.Context) {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	// Stops reverse proxies such as nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+	logByStatusCode(c, http.StatusOK)
+}
+
+// SSEEvent writes an event with JSON data to the stream and flushes it to the client
+func SSEEvent(c echo.Context, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event, payload)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Flush()
+
+	return nil
+}
+
+// SSEComment writes a comment line, ignored by clients but keeping idle connections open
+func SSEComment(c echo.Context, comment string) error {
+	_, err := fmt.Fprintf(c.Response(), ": %s\n\n", comment)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Flush()
+
+	return nil
+}